@@ -72,6 +72,7 @@ func main() {
 		authSvc := service.NewAuthService(cfg.JWTSecret, usecase.DefaultTokenTTL)
 
 		deviceTokenRepo := postgres.NewDeviceTokenRepository(db)
+		pushTopicRepo := postgres.NewPushTopicRepository(db)
 		notificationPrefsRepo := postgres.NewNotificationPreferencesRepository(db)
 		notificationLogRepo := postgres.NewNotificationLogRepository(db)
 		notificationTemplateRepo := postgres.NewNotificationTemplateRepository(db)
@@ -91,6 +92,8 @@ func main() {
 		inboxRepo := postgres.NewInboxRepository(db)
 		suggestionRepo := postgres.NewAiSuggestionRepository(db)
 		taskRepo := postgres.NewTaskRepository(db)
+		taskChecklistRepo := postgres.NewTaskChecklistItemRepository(db)
+		projectRepo := postgres.NewProjectRepository(db)
 		reminderRepo := postgres.NewReminderRepository(db)
 		eventRepo := postgres.NewEventRepository(db)
 		shoppingListRepo := postgres.NewShoppingListRepository(db)
@@ -100,11 +103,15 @@ func main() {
 		routineCompletionRepo := postgres.NewRoutineCompletionRepository(db)
 		agendaRepo := postgres.NewAgendaRepository(db)
 		homeRepo := postgres.NewHomeRepository(db)
+		awayRepo := postgres.NewAwayPeriodRepository(db)
 
 		flagUC := &usecase.FlagUsecase{Flags: flagRepo}
 		subflagUC := &usecase.SubflagUsecase{Subflags: subflagRepo, Flags: flagRepo}
 		ruleUC := &usecase.ContextRuleUsecase{Rules: ruleRepo, Flags: flagRepo, Subflags: subflagRepo}
-		taskUC := &usecase.TaskUsecase{Tasks: taskRepo, Flags: flagRepo, Subflags: subflagRepo}
+		txRunner := postgres.NewTxRunner(db)
+		taskUC := &usecase.TaskUsecase{Tasks: taskRepo, Flags: flagRepo, Subflags: subflagRepo, Projects: projectRepo, Users: userRepo, Checklist: taskChecklistRepo, NotificationLogs: notificationLogRepo, TxRunner: txRunner}
+		projectUC := &usecase.ProjectUsecase{Projects: projectRepo, Flags: flagRepo, Subflags: subflagRepo}
+		taskChecklistUC := &usecase.TaskChecklistUsecase{Items: taskChecklistRepo, Tasks: taskRepo}
 		reminderUC := &usecase.ReminderUsecase{
 			Reminders: reminderRepo,
 			Flags:     flagRepo,
@@ -124,16 +131,26 @@ func main() {
 			Users:       userRepo,
 			Flags:       flagRepo,
 			Subflags:    subflagRepo,
+
+			NotificationLogs: notificationLogRepo,
+			Away:             awayRepo,
+		}
+		awayUC := &usecase.AwayUsecase{
+			Away:  awayRepo,
+			Users: userRepo,
+			Flags: flagRepo,
+
+			NotificationLogs: notificationLogRepo,
 		}
+		holidayUC := &usecase.HolidayUsecase{Users: userRepo}
 		agendaUC := usecase.NewAgendaUsecase(agendaRepo)
+		agendaUC.Users = userRepo
 		homeUC := &usecase.HomeUsecase{
 			Home:     homeRepo,
 			Agenda:   agendaRepo,
 			Routines: routineUC,
 			Users:    userRepo,
 		}
-		deviceTokenUC := &usecase.DeviceTokenUsecase{DeviceTokens: deviceTokenRepo}
-		txRunner := postgres.NewTxRunner(db)
 
 		var aiClient service.AIClient
 		if cfg.AIAPIKey != "" || cfg.AIBaseURL != "" || cfg.AIModel != "" || cfg.AIProvider != "" {
@@ -162,6 +179,8 @@ func main() {
 			Events:           eventRepo,
 			ShoppingLists:    shoppingListRepo,
 			ShoppingItems:    shoppingItemRepo,
+			TaskChecklist:    taskChecklistRepo,
+			Projects:         projectRepo,
 			TasksUsecase:     taskUC,
 			RemindersUsecase: reminderUC,
 			EventsUsecase:    eventUC,
@@ -173,9 +192,27 @@ func main() {
 			TxRunner:         txRunner,
 		}
 
-		// ntfy.sh client
-		ntfyClient := push.NewNtfyClient("") // default baseURL: https://ntfy.sh
-		log.Info("ntfy_client_ready")
+		// ntfy client (self-hosted ou ntfy.sh)
+		ntfyClient := push.NewNtfyClientWithConfig(push.NtfyConfig{
+			BaseURL:    cfg.NtfyBaseURL,
+			Token:      cfg.NtfyToken,
+			Username:   cfg.NtfyUsername,
+			Password:   cfg.NtfyPassword,
+			Encrypt:    cfg.NtfyEncrypt,
+			Priorities: cfg.NtfyPriorities,
+		})
+		log.Info("ntfy_client_ready", slog.String("base_url", ntfyClient.BaseURL), slog.Bool("encrypt", cfg.NtfyEncrypt))
+		deviceTokenUC := &usecase.DeviceTokenUsecase{
+			DeviceTokens: deviceTokenRepo,
+			PushTopics:   pushTopicRepo,
+			Ntfy:         ntfyClient,
+		}
+
+		notificationRenderer := service.NewNotificationRenderer()
+		notificationTemplateUC := &usecase.NotificationTemplateUsecase{
+			Templates: notificationTemplateRepo,
+			Renderer:  notificationRenderer,
+		}
 
 		notificationUC := &usecase.NotificationUsecase{
 			Prefs:  notificationPrefsRepo,
@@ -183,10 +220,24 @@ func main() {
 			Tokens: deviceTokenRepo,
 			Config: appConfigRepo,
 			Ntfy:   ntfyClient,
+			Users:  userRepo,
 		}
 
 		var digestHandler *handler.DigestHandler
-		resendMailer := mailer.NewResendMailer(cfg.ResendAPIKey, cfg.ResendFrom)
+		var emailHandler *handler.EmailHandler
+		var mailClient mailer.Mailer = mailer.NewResendMailer(cfg.ResendAPIKey, cfg.ResendFrom)
+		if cfg.MailProvider == "smtp" {
+			mailClient = mailer.NewSMTPMailer(mailer.SMTPConfig{
+				Host:     cfg.SMTPHost,
+				Port:     cfg.SMTPPort,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.SMTPFrom,
+				StartTLS: cfg.SMTPStartTLS,
+			})
+		}
+		emailSuppressionRepo := postgres.NewEmailSuppressionRepository(db)
+		mailClient = mailer.NewSuppressingMailer(mailClient, emailSuppressionRepo)
 		digestSvc, err := digest.NewDigestService(
 			userRepo,
 			notificationPrefsRepo,
@@ -198,13 +249,25 @@ func main() {
 			shoppingItemRepo,
 			flagRepo,
 			subflagRepo,
-			resendMailer,
+			mailClient,
 		)
 		if err != nil {
 			log.Error("digest_service_init_error", slog.String("error", err.Error()))
 		} else {
 			digestSvc.SetLogger(log)
+			digestSvc.SetMaxAttempts(cfg.DigestMaxAttempts)
+			digestSvc.SetSettingsRepository(postgres.NewDigestSettingsRepository(db))
+			digestSvc.SetSuppressionRepository(emailSuppressionRepo)
+			digestSvc.SetAwayRepository(awayRepo)
+			digestSvc.SetRoutineActivitySources(routineCompletionRepo, routineExceptionRepo)
+			digestSvc.SetUnsubscribe(cfg.PublicBaseURL, cfg.UnsubscribeSecret)
+			digestSvc.SetWeeklySources(digest.WeeklyDigestSources{
+				Streaks: routineUC,
+				Inbox:   inboxRepo,
+				Home:    homeRepo,
+			})
 			digestHandler = handler.NewDigestHandler(digestSvc)
+			emailHandler = handler.NewEmailHandler(digestSvc, cfg.ResendWebhookSecret, log)
 
 			// Digest Scheduler (every configured interval)
 			go func() {
@@ -239,10 +302,20 @@ func main() {
 			Events:    eventRepo,
 			Tasks:     taskRepo,
 			Routines:  routineRepo,
+			Flags:     flagRepo,
 			Templates: notificationTemplateRepo,
 			Config:    appConfigRepo,
 			Ntfy:      ntfyClient,
 			Logger:    log,
+			Renderer:  notificationRenderer,
+			Lock:      postgres.NewAdvisoryLock(db, scheduler.SchedulerLockKey()),
+			Mailer:    mailClient,
+
+			RoutineExceptions: routineExceptionRepo,
+			Away:              awayRepo,
+		}
+		if digestSvc != nil {
+			notifScheduler.Briefings = digestSvc
 		}
 		go notifScheduler.Run(ctx)
 
@@ -254,15 +327,21 @@ func main() {
 			Inbox:         handler.NewInboxHandler(inboxUC, flagUC, subflagUC),
 			Agenda:        handler.NewAgendaHandler(agendaUC),
 			Home:          handler.NewHomeHandler(homeUC),
-			Tasks:         handler.NewTasksHandler(taskUC, inboxUC, flagUC, subflagUC),
+			Tasks:         handler.NewTasksHandler(taskUC, inboxUC, flagUC, subflagUC, taskChecklistUC, projectUC),
+			Projects:      handler.NewProjectsHandler(projectUC, flagUC, subflagUC),
 			Reminders:     handler.NewRemindersHandler(reminderUC, inboxUC, flagUC, subflagUC),
 			Events:        handler.NewEventsHandler(eventUC, inboxUC, flagUC, subflagUC),
 			ShoppingLists: handler.NewShoppingListsHandler(shoppingListUC, inboxUC),
 			ShoppingItems: handler.NewShoppingItemsHandler(shoppingItemUC, shoppingListUC),
 			Routines:      handler.NewRoutinesHandler(routineUC, flagUC, subflagUC),
+			Away:          handler.NewAwayHandler(awayUC, flagUC),
+			Holidays:      handler.NewHolidaysHandler(holidayUC),
 			Devices:       handler.NewDevicesHandler(deviceTokenUC),
 			Notifications: handler.NewNotificationsHandler(notificationUC),
 			Digest:        digestHandler,
+			Email:         emailHandler,
+
+			NotificationTemplates: handler.NewNotificationTemplatesHandler(notificationTemplateUC),
 		}
 	}
 
@@ -0,0 +1,49 @@
+package digest
+
+import (
+	"context"
+	"log/slog"
+
+	"inbota/backend/internal/app/domain"
+)
+
+// RoutineCompletionsByDate lists the routine completions of a day.
+type RoutineCompletionsByDate interface {
+	GetByDate(ctx context.Context, userID, date string) ([]domain.RoutineCompletion, error)
+}
+
+// RoutineExceptionsByDate lists the routine exceptions (skip, reschedule) of a day.
+type RoutineExceptionsByDate interface {
+	ListByDate(ctx context.Context, userID, date string) ([]domain.RoutineException, error)
+}
+
+// SetRoutineActivitySources makes completions and exceptions of the day count towards
+// LastModified; neither touches the routine's updated_at (nil = ignored).
+func (s *DigestService) SetRoutineActivitySources(completions RoutineCompletionsByDate, exceptions RoutineExceptionsByDate) {
+	s.completionRepo = completions
+	s.exceptionRepo = exceptions
+}
+
+// touchRoutineActivity avança LastModified com as conclusões e exceções do dia. Considera
+// todas as rotinas do usuário: uma exceção "skip" tira a rotina do resumo, e contar demais
+// só antecipa a revalidação. Falhas de leitura ficam no log; o ETag continua valendo.
+func (s *DigestService) touchRoutineActivity(ctx context.Context, data *DigestData, userID, date string) {
+	if s.completionRepo != nil {
+		completions, err := s.completionRepo.GetByDate(ctx, userID, date)
+		if err != nil {
+			s.log.Warn("digest_completion_lookup_failed", slog.String("user_id", userID), slog.String("error", err.Error()))
+		}
+		for _, c := range completions {
+			data.touch(c.CompletedAt)
+		}
+	}
+	if s.exceptionRepo != nil {
+		exceptions, err := s.exceptionRepo.ListByDate(ctx, userID, date)
+		if err != nil {
+			s.log.Warn("digest_exception_lookup_failed", slog.String("user_id", userID), slog.String("error", err.Error()))
+		}
+		for _, e := range exceptions {
+			data.touch(e.CreatedAt)
+		}
+	}
+}
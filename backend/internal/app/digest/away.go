@@ -0,0 +1,34 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"inbota/backend/internal/app/recurrence"
+	"inbota/backend/internal/app/repository"
+)
+
+// errUserAway pausa os digests enquanto a conta inteira está de férias.
+var errUserAway = errors.New("digest skipped: user away")
+
+// SetAwayRepository enables vacation mode for the digests (nil = always send).
+func (s *DigestService) SetAwayRepository(repo repository.AwayPeriodRepository) {
+	s.awayRepo = repo
+}
+
+// isAway reports whether a period without flag covers date. Flag scoped periods only hide
+// their routines, which ListByWeekday already does.
+func (s *DigestService) isAway(ctx context.Context, userID string, date time.Time) bool {
+	if s.awayRepo == nil {
+		return false
+	}
+	day := date.Format("2006-01-02")
+	periods, err := s.awayRepo.ListOverlapping(ctx, []string{userID}, day, day)
+	if err != nil {
+		s.log.Warn("digest_away_lookup_failed", slog.String("user_id", userID), slog.String("error", err.Error()))
+		return false
+	}
+	return recurrence.IsAway(periods, nil, day)
+}
@@ -0,0 +1,79 @@
+package digest
+
+import (
+	"fmt"
+	"strings"
+
+	"inbota/backend/internal/app/i18n"
+)
+
+// maxBriefingLines keeps briefing pushes short enough for a notification body.
+const maxBriefingLines = 4
+
+// MorningBriefing turns the digest data into a compact push (title, body).
+// ok is false when there is nothing planned for the day.
+func MorningBriefing(data DigestData) (title, body string, ok bool) {
+	l := i18n.For(data.Locale)
+	counts := make([]string, 0, 3)
+	if n := len(data.Agenda); n > 0 {
+		counts = append(counts, l.N("briefing.count.agenda", n))
+	}
+	if n := len(data.Tasks); n > 0 {
+		counts = append(counts, l.N("briefing.count.tasks", n))
+	}
+	if n := len(data.Schedule); n > 0 {
+		counts = append(counts, l.N("briefing.count.routines", n))
+	}
+	if len(counts) == 0 {
+		return "", "", false
+	}
+
+	lines := make([]string, 0, len(data.Agenda)+len(data.Tasks)+len(data.Schedule))
+	for _, item := range data.Agenda {
+		lines = append(lines, joinTimeTitle(item.Time, item.Title))
+	}
+	for _, task := range data.Tasks {
+		lines = append(lines, joinTimeTitle(task.DueTime, task.Title))
+	}
+	for _, routine := range data.Schedule {
+		lines = append(lines, joinTimeTitle(routine.Time, routine.Title))
+	}
+
+	return l.T("briefing.morning.title", i18n.Vars{"counts": strings.Join(counts, ", ")}), truncateLines(lines), true
+}
+
+// EveningReview lists what is still unfinished today: open tasks due today and
+// routines not completed. ok is false when everything is done.
+func EveningReview(data DigestData) (title, body string, ok bool) {
+	l := i18n.For(data.Locale)
+	lines := make([]string, 0, len(data.Tasks)+len(data.Schedule))
+	for _, task := range data.Tasks {
+		lines = append(lines, l.T("briefing.task_line", i18n.Vars{"title": task.Title}))
+	}
+	for _, routine := range data.Schedule {
+		if routine.IsCompleted {
+			continue
+		}
+		lines = append(lines, l.T("briefing.routine_line", i18n.Vars{"title": routine.Title}))
+	}
+	if len(lines) == 0 {
+		return "", "", false
+	}
+
+	return l.N("briefing.evening.title", len(lines)), truncateLines(lines), true
+}
+
+func joinTimeTitle(timeLabel, title string) string {
+	timeLabel = strings.TrimSpace(timeLabel)
+	if timeLabel == "" {
+		return title
+	}
+	return timeLabel + " " + title
+}
+
+func truncateLines(lines []string) string {
+	if len(lines) <= maxBriefingLines {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines[:maxBriefingLines], "\n") + fmt.Sprintf("\n+%d", len(lines)-maxBriefingLines)
+}
@@ -0,0 +1,238 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+)
+
+// Formatos aceitos pelo resumo diário público.
+const (
+	SummaryFormatJSON     = "json"
+	SummaryFormatHTML     = "html"
+	SummaryFormatText     = "text"
+	SummaryFormatMarkdown = "markdown"
+	SummaryFormatICS      = "ics"
+
+	// maxSummaryDateOffsetDays limita o parâmetro date= do resumo público.
+	maxSummaryDateOffsetDays = 31
+)
+
+var ErrInvalidSummaryDate = errors.New("invalid_summary_date")
+
+var summaryContentTypes = map[string]string{
+	SummaryFormatJSON:     "application/json; charset=utf-8",
+	SummaryFormatHTML:     "text/html; charset=utf-8",
+	SummaryFormatText:     "text/plain; charset=utf-8",
+	SummaryFormatMarkdown: "text/markdown; charset=utf-8",
+	SummaryFormatICS:      "text/calendar; charset=utf-8",
+}
+
+// SummaryContentType returns the Content-Type for a format, or false when the format is unknown.
+func SummaryContentType(format string) (string, bool) {
+	ct, ok := summaryContentTypes[format]
+	return ct, ok
+}
+
+// ResolveSummaryDate interpreta date= no timezone do usuário: vazio/"today", "tomorrow",
+// "yesterday" ou YYYY-MM-DD (até 31 dias de distância de hoje).
+func (s *DigestService) ResolveSummaryDate(ctx context.Context, userID, raw string) (time.Time, error) {
+	user, err := s.userRepo.Get(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tz, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		tz = time.UTC
+	}
+
+	now := s.now().In(tz)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "today":
+		return now, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(raw), tz)
+	if err != nil {
+		return time.Time{}, ErrInvalidSummaryDate
+	}
+	if date.Before(today.AddDate(0, 0, -maxSummaryDateOffsetDays)) || date.After(today.AddDate(0, 0, maxSummaryDateOffsetDays)) {
+		return time.Time{}, ErrInvalidSummaryDate
+	}
+	if date.Equal(today) {
+		return now, nil
+	}
+	return date, nil
+}
+
+// RenderSummary renders the daily summary in the given format, reusing the e-mail templates.
+func (s *DigestService) RenderSummary(data DigestData, format string) ([]byte, error) {
+	switch format {
+	case SummaryFormatJSON:
+		return json.Marshal(data)
+	case SummaryFormatHTML:
+		var buf bytes.Buffer
+		if err := s.htmlTemplate.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("render digest html: %w", err)
+		}
+		return buf.Bytes(), nil
+	case SummaryFormatText:
+		var buf bytes.Buffer
+		if err := s.textTemplate.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("render digest text: %w", err)
+		}
+		return buf.Bytes(), nil
+	case SummaryFormatMarkdown:
+		var buf bytes.Buffer
+		if err := s.markdownTemplate.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("render digest markdown: %w", err)
+		}
+		return buf.Bytes(), nil
+	case SummaryFormatICS:
+		return renderICS(data), nil
+	default:
+		return nil, fmt.Errorf("unsupported summary format %q", format)
+	}
+}
+
+func (d *DigestData) touch(t time.Time) {
+	if t.After(d.LastModified) {
+		d.LastModified = t
+	}
+}
+
+func agendaCalendarEntry(item repository.AgendaItem, scheduledAt time.Time, contextLabel string) CalendarEntry {
+	entry := CalendarEntry{
+		UID:     item.ItemType + "-" + item.ID,
+		Kind:    item.ItemType,
+		Title:   item.Title,
+		Start:   scheduledAt,
+		Context: contextLabel,
+	}
+	if item.ItemType == "event" {
+		entry.AllDay = item.AllDay != nil && *item.AllDay
+		if item.EndAt != nil && item.EndAt.After(item.ScheduledAt) {
+			end := item.EndAt.In(scheduledAt.Location())
+			entry.End = &end
+		}
+	}
+	if item.Location != nil {
+		entry.Location = strings.TrimSpace(*item.Location)
+	}
+	return entry
+}
+
+// routineCalendarEntry monta a ocorrência da rotina no dia; rotinas sem horário viram evento de dia inteiro.
+func routineCalendarEntry(routine domain.Routine, day time.Time, contextLabel string) (CalendarEntry, bool) {
+	if strings.TrimSpace(routine.ID) == "" {
+		return CalendarEntry{}, false
+	}
+	entry := CalendarEntry{
+		UID:     "routine-" + routine.ID + "-" + day.Format("20060102"),
+		Kind:    "routine",
+		Title:   routine.Title,
+		Start:   day,
+		Context: contextLabel,
+	}
+
+	start, okStart := clockOnDay(routine.StartTime, day)
+	if !okStart {
+		entry.AllDay = true
+		return entry, true
+	}
+	entry.Start = start
+	if end, ok := clockOnDay(routine.EndTime, day); ok && end.After(start) {
+		entry.End = &end
+	}
+	return entry, true
+}
+
+func clockOnDay(clock string, day time.Time) (time.Time, bool) {
+	clock = normalizeClock(clock)
+	if clock == "" {
+		return time.Time{}, false
+	}
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), parsed.Hour(), parsed.Minute(), 0, 0, day.Location()), true
+}
+
+// renderICS gera um VCALENDAR com os itens com horário do dia. DTSTAMP usa LastModified
+// para que a mesma agenda produza sempre o mesmo corpo (e o mesmo ETag).
+func renderICS(data DigestData) []byte {
+	stamp := data.LastModified.UTC()
+	if stamp.IsZero() {
+		stamp = time.Unix(0, 0).UTC()
+	}
+
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Inbota//Daily Summary//PT-BR")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "X-WR-CALNAME:"+icsEscape("Inbota — "+data.Date))
+
+	for _, entry := range data.Calendar {
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+icsEscape(entry.UID)+"@inbota")
+		writeICSLine(&b, "DTSTAMP:"+stamp.Format("20060102T150405Z"))
+		if entry.AllDay {
+			writeICSLine(&b, "DTSTART;VALUE=DATE:"+entry.Start.Format("20060102"))
+			writeICSLine(&b, "DTEND;VALUE=DATE:"+entry.Start.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			writeICSLine(&b, "DTSTART:"+entry.Start.UTC().Format("20060102T150405Z"))
+			if entry.End != nil {
+				writeICSLine(&b, "DTEND:"+entry.End.UTC().Format("20060102T150405Z"))
+			}
+		}
+		writeICSLine(&b, "SUMMARY:"+icsEscape(entry.Title))
+		writeICSLine(&b, "CATEGORIES:"+icsEscape(entry.Kind))
+		if entry.Context != "" {
+			writeICSLine(&b, "DESCRIPTION:"+icsEscape(entry.Context))
+		}
+		if entry.Location != "" {
+			writeICSLine(&b, "LOCATION:"+icsEscape(entry.Location))
+		}
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icsEscape(value string) string {
+	return icsEscaper.Replace(value)
+}
+
+// writeICSLine quebra linhas em 75 octetos (RFC 5545 §3.1) sem cortar caracteres UTF-8.
+func writeICSLine(b *strings.Builder, line string) {
+	const limit = 75
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+}
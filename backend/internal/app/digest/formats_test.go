@@ -0,0 +1,167 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+)
+
+func newFormatsTestService(t *testing.T, routines []domain.Routine, agenda []repository.AgendaItem) *DigestService {
+	t.Helper()
+	return newTestService(t,
+		withUsers(&fakeUserRepo{users: map[string]domain.User{"u1": {ID: "u1", Timezone: "America/Sao_Paulo"}}}),
+		withRoutines(&fakeRoutineLister{items: routines}),
+		withAgenda(&fakeAgendaRepo{items: agenda}),
+	)
+}
+
+func TestRenderSummaryFormats(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	target := time.Date(2026, 3, 9, 8, 0, 0, 0, loc)
+	updated := time.Date(2026, 3, 8, 20, 0, 0, 0, time.UTC)
+
+	svc := newFormatsTestService(t,
+		[]domain.Routine{{ID: "r1", Title: "Treino", StartTime: "06:30", EndTime: "07:30", UpdatedAt: updated}},
+		[]repository.AgendaItem{{
+			ItemType:    "event",
+			ID:          "e1",
+			Title:       "Reunião; planejamento, Q2",
+			ScheduledAt: time.Date(2026, 3, 9, 10, 0, 0, 0, loc),
+			EndAt:       ptrTime(time.Date(2026, 3, 9, 11, 0, 0, 0, loc)),
+			UpdatedAt:   updated.Add(-time.Hour),
+		}},
+	)
+
+	data, err := svc.BuildDigestData(context.Background(), "u1", target)
+	if err != nil {
+		t.Fatalf("build digest data: %v", err)
+	}
+	if !data.LastModified.Equal(updated) {
+		t.Fatalf("expected last modified %v, got %v", updated, data.LastModified)
+	}
+
+	ics, err := svc.RenderSummary(data, SummaryFormatICS)
+	if err != nil {
+		t.Fatalf("render ics: %v", err)
+	}
+	body := string(ics)
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:routine-r1-20260309@inbota\r\n",
+		"DTSTART:20260309T093000Z\r\n",
+		"DTEND:20260309T103000Z\r\n",
+		"UID:event-e1@inbota\r\n",
+		`SUMMARY:Reunião\; planejamento\, Q2`,
+		"DTSTAMP:20260308T200000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected ics to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Index(body, "routine-r1") > strings.Index(body, "event-e1") {
+		t.Fatalf("expected calendar entries ordered by start time")
+	}
+
+	again, _ := svc.RenderSummary(data, SummaryFormatICS)
+	if string(again) != body {
+		t.Fatalf("expected deterministic ics output")
+	}
+
+	md, err := svc.RenderSummary(data, SummaryFormatMarkdown)
+	if err != nil {
+		t.Fatalf("render markdown: %v", err)
+	}
+	if !strings.Contains(string(md), "## Cronograma do dia") || !strings.Contains(string(md), "- [ ] **06:30 - 07:30** Treino") {
+		t.Fatalf("unexpected markdown:\n%s", md)
+	}
+}
+
+func TestResolveSummaryDate(t *testing.T) {
+	svc := newFormatsTestService(t, nil, nil)
+	svc.SetNow(func() time.Time {
+		return time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC) // 09/03 23:00 em America/Sao_Paulo
+	})
+
+	tomorrow, err := svc.ResolveSummaryDate(context.Background(), "u1", "tomorrow")
+	if err != nil {
+		t.Fatalf("resolve tomorrow: %v", err)
+	}
+	if tomorrow.Format("2006-01-02") != "2026-03-10" {
+		t.Fatalf("expected tomorrow in user timezone, got %s", tomorrow)
+	}
+
+	explicit, err := svc.ResolveSummaryDate(context.Background(), "u1", "2026-03-20")
+	if err != nil || explicit.Format("2006-01-02") != "2026-03-20" {
+		t.Fatalf("expected explicit date, got %v err=%v", explicit, err)
+	}
+
+	for _, raw := range []string{"2026-13-01", "2026-06-01", "amanha"} {
+		if _, err := svc.ResolveSummaryDate(context.Background(), "u1", raw); !errors.Is(err, ErrInvalidSummaryDate) {
+			t.Fatalf("expected invalid date for %q, got %v", raw, err)
+		}
+	}
+}
+
+type fakeCompletionsByDate struct {
+	items []domain.RoutineCompletion
+	dates []string
+}
+
+func (f *fakeCompletionsByDate) GetByDate(_ context.Context, _ string, date string) ([]domain.RoutineCompletion, error) {
+	f.dates = append(f.dates, date)
+	return f.items, nil
+}
+
+type fakeExceptionsByDate struct {
+	items []domain.RoutineException
+	err   error
+}
+
+func (f *fakeExceptionsByDate) ListByDate(context.Context, string, string) ([]domain.RoutineException, error) {
+	return f.items, f.err
+}
+
+func TestLastModifiedCountsRoutineActivity(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	target := time.Date(2026, 3, 9, 8, 0, 0, 0, loc)
+	updated := time.Date(2026, 3, 8, 20, 0, 0, 0, time.UTC)
+	completedAt := updated.Add(11 * time.Hour)
+	skippedAt := updated.Add(12 * time.Hour)
+
+	svc := newFormatsTestService(t, []domain.Routine{{ID: "r1", Title: "Treino", StartTime: "06:30", UpdatedAt: updated}}, nil)
+	completions := &fakeCompletionsByDate{items: []domain.RoutineCompletion{{RoutineID: "r1", CompletedOn: "2026-03-09", CompletedAt: completedAt}}}
+	exceptions := &fakeExceptionsByDate{}
+	svc.SetRoutineActivitySources(completions, exceptions)
+
+	data, err := svc.BuildDigestData(context.Background(), "u1", target)
+	if err != nil {
+		t.Fatalf("build digest data: %v", err)
+	}
+	if !data.LastModified.Equal(completedAt) {
+		t.Fatalf("expected completion to advance last modified to %v, got %v", completedAt, data.LastModified)
+	}
+	if len(completions.dates) != 1 || completions.dates[0] != "2026-03-09" {
+		t.Fatalf("expected completions of the local day, got %v", completions.dates)
+	}
+
+	// A rotina pulada some do resumo, mas a exceção ainda conta.
+	exceptions.items = []domain.RoutineException{{RoutineID: "r2", ExceptionDate: "2026-03-09", Action: "skip", CreatedAt: skippedAt}}
+	data, err = svc.BuildDigestData(context.Background(), "u1", target)
+	if err != nil {
+		t.Fatalf("build digest data: %v", err)
+	}
+	if !data.LastModified.Equal(skippedAt) {
+		t.Fatalf("expected exception to advance last modified to %v, got %v", skippedAt, data.LastModified)
+	}
+
+	exceptions.err = errors.New("timeout")
+	if _, err := svc.BuildDigestData(context.Background(), "u1", target); err != nil {
+		t.Fatalf("expected lookup failures to be ignored, got %v", err)
+	}
+}
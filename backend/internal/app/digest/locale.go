@@ -0,0 +1,47 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+
+	"inbota/backend/internal/app/i18n"
+)
+
+// userLocalizer picks the catalogue for the user's locale; lookup failures fall back to pt-BR.
+func (s *DigestService) userLocalizer(ctx context.Context, userID string) i18n.Localizer {
+	if s.userRepo == nil || userID == "" {
+		return i18n.For(i18n.DefaultLocale)
+	}
+	user, err := s.userRepo.Get(ctx, userID)
+	if err != nil {
+		return i18n.For(i18n.DefaultLocale)
+	}
+	return i18n.For(user.Locale)
+}
+
+// T renders a catalogue message in the digest locale. Templates pass placeholders as
+// name/value pairs: {{$.T "weekly.section.routines" "rate" .RoutineCompletionRate}}.
+func (d DigestData) T(key string, pairs ...any) string {
+	return i18n.For(d.Locale).T(key, pairVars(pairs))
+}
+
+// N renders the plural form of a catalogue message for n.
+func (d DigestData) N(key string, n int, pairs ...any) string {
+	return i18n.For(d.Locale).N(key, n, pairVars(pairs))
+}
+
+func (d WeeklyDigestData) T(key string, pairs ...any) string {
+	return i18n.For(d.Locale).T(key, pairVars(pairs))
+}
+
+func (d WeeklyDigestData) N(key string, n int, pairs ...any) string {
+	return i18n.For(d.Locale).N(key, n, pairVars(pairs))
+}
+
+func pairVars(pairs []any) i18n.Vars {
+	vars := make(i18n.Vars, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		vars[fmt.Sprint(pairs[i])] = pairs[i+1]
+	}
+	return vars
+}
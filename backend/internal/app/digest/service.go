@@ -3,9 +3,12 @@ package digest
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	htmltemplate "html/template"
 	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/i18n"
+	"inbota/backend/internal/app/recurrence"
 	"inbota/backend/internal/app/repository"
 	"inbota/backend/internal/infra/mailer"
 	"log/slog"
@@ -17,9 +20,12 @@ import (
 )
 
 const (
-	digestTypeDaily      = "daily_digest"
-	maxDigestPageSize    = 200
-	defaultDigestSubject = "Seu dia no Inbota"
+	digestTypeDaily   = "daily_digest"
+	maxDigestPageSize = 200
+
+	defaultDigestMaxAttempts = 3
+	digestRetryBaseDelay     = 15 * time.Minute
+	maxDigestRetriesPerRun   = 100
 )
 
 type RoutineWeekdayLister interface {
@@ -40,6 +46,17 @@ type DigestService struct {
 	mailer           mailer.Mailer
 	htmlTemplate     *htmltemplate.Template
 	textTemplate     *texttemplate.Template
+	markdownTemplate *texttemplate.Template
+	weeklyHTML       *htmltemplate.Template
+	weeklyText       *texttemplate.Template
+	weekly           WeeklyDigestSources
+	settingsRepo     repository.DigestSettingsRepository
+	suppressionRepo  repository.EmailSuppressionRepository
+	awayRepo         repository.AwayPeriodRepository
+	completionRepo   RoutineCompletionsByDate
+	exceptionRepo    RoutineExceptionsByDate
+	unsubscribe      unsubscribeConfig
+	maxAttempts      int
 	now              func() time.Time
 	log              *slog.Logger
 }
@@ -61,6 +78,14 @@ func NewDigestService(
 	if err != nil {
 		return nil, fmt.Errorf("parse digest templates: %w", err)
 	}
+	markdownTmpl, err := mailer.ParseDailyDigestMarkdownTemplate()
+	if err != nil {
+		return nil, fmt.Errorf("parse digest markdown template: %w", err)
+	}
+	weeklyHTML, weeklyText, err := mailer.ParseWeeklyDigestTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("parse weekly digest templates: %w", err)
+	}
 
 	return &DigestService{
 		userRepo:         userRepo,
@@ -76,13 +101,19 @@ func NewDigestService(
 		mailer:           mailClient,
 		htmlTemplate:     htmlTmpl,
 		textTemplate:     textTmpl,
+		markdownTemplate: markdownTmpl,
+		weeklyHTML:       weeklyHTML,
+		weeklyText:       weeklyText,
+		maxAttempts:      defaultDigestMaxAttempts,
 		now:              time.Now,
 		log:              slog.Default(),
 	}, nil
 }
 
 type DigestData struct {
+	Locale           string             `json:"locale"`
 	Date             string             `json:"date"`
+	Sections         []string           `json:"sections"`
 	Detail           DigestDetail       `json:"detail"`
 	HasSchedule      bool               `json:"hasSchedule"`
 	Schedule         []ScheduleItemData `json:"schedule"`
@@ -96,6 +127,15 @@ type DigestData struct {
 	OpenTasks        []TaskItemData     `json:"openTasks"`
 	HasShoppingLists bool               `json:"hasShoppingLists"`
 	ShoppingLists    []ShoppingListData `json:"shoppingLists"`
+
+	// Calendar holds the timed entries of the day for the ICS export.
+	Calendar []CalendarEntry `json:"-"`
+	// LastModified is the latest change among the items of the summary: updated_at of items and
+	// routines plus the day's routine completions and exceptions. It feeds the public summary's
+	// Last-Modified header and the ICS DTSTAMP.
+	LastModified time.Time `json:"-"`
+	// UnsubscribeURL is only set for e-mails; the public summary leaves it empty.
+	UnsubscribeURL string `json:"-"`
 }
 
 type DigestDetail struct {
@@ -135,6 +175,17 @@ type TaskItemData struct {
 	Title   string `json:"title"`
 }
 
+type CalendarEntry struct {
+	UID      string
+	Kind     string // event | task | reminder | routine
+	Title    string
+	Start    time.Time
+	End      *time.Time
+	AllDay   bool
+	Context  string
+	Location string
+}
+
 type ShoppingListData struct {
 	Title        string   `json:"title"`
 	PendingCount int      `json:"pendingCount"`
@@ -153,25 +204,51 @@ func (s *DigestService) SetLogger(logger *slog.Logger) {
 	}
 }
 
+// SetMaxAttempts limits how many times a digest is sent (first try included) before giving up.
+func (s *DigestService) SetMaxAttempts(n int) {
+	if n > 0 {
+		s.maxAttempts = n
+	}
+}
+
+// BuildDigestData builds the daily digest honouring the user's digest settings.
 func (s *DigestService) BuildDigestData(ctx context.Context, userID string, targetDate time.Time) (DigestData, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return DigestData{}, err
+	}
+	return s.buildDigestData(ctx, userID, targetDate, settings)
+}
+
+func (s *DigestService) buildDigestData(ctx context.Context, userID string, targetDate time.Time, settings domain.DigestSettings) (DigestData, error) {
 	if targetDate.IsZero() {
 		targetDate = s.now()
 	}
 	loc := targetDate.Location()
+	l := s.userLocalizer(ctx, userID)
 
 	data := DigestData{
-		Date:   targetDate.Format("02/01/2006"),
-		Detail: defaultDigestDetail(),
+		Locale: l.Locale(),
+		Date:   l.Date(targetDate),
+		Detail: defaultDigestDetail(l),
 	}
 
 	startOfDay := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, loc)
 	endOfDay := startOfDay.Add(24 * time.Hour)
+	filter := s.newFlagFilter(userID, settings)
 
-	scheduleItems, err := s.buildScheduleItems(ctx, userID, targetDate)
+	scheduleItems, routines, err := s.buildScheduleItems(ctx, l, userID, targetDate, filter)
 	if err != nil {
 		return DigestData{}, err
 	}
 	data.Schedule = scheduleItems
+	for i, routine := range routines {
+		data.touch(routine.UpdatedAt)
+		if entry, ok := routineCalendarEntry(routine, startOfDay, scheduleItems[i].Context); ok {
+			data.Calendar = append(data.Calendar, entry)
+		}
+	}
+	s.touchRoutineActivity(ctx, &data, userID, startOfDay.Format("2006-01-02"))
 
 	agendaItems, err := s.agendaRepo.List(ctx, userID, repository.ListOptions{
 		Limit:   maxDigestPageSize,
@@ -187,11 +264,20 @@ func (s *DigestService) BuildDigestData(ctx context.Context, userID string, targ
 		if scheduledAt.Before(startOfDay) || !scheduledAt.Before(endOfDay) {
 			continue
 		}
-		typeLabel, typeKey := agendaType(item.ItemType)
+		resolvedFlagID := item.ResolvedFlagID
+		if resolvedFlagID == nil {
+			resolvedFlagID = item.FlagID
+		}
+		if !filter.allows(ctx, resolvedFlagID, item.SubflagID) {
+			continue
+		}
+		typeLabel, typeKey := agendaType(l, item.ItemType)
 		contextLabel := contextPath(item.FlagName, item.SubflagName)
+		data.touch(item.UpdatedAt)
+		data.Calendar = append(data.Calendar, agendaCalendarEntry(item, scheduledAt, contextLabel))
 
 		data.Agenda = append(data.Agenda, AgendaItemData{
-			Time:    agendaTimeLabel(item, scheduledAt),
+			Time:    agendaTimeLabel(l, item, scheduledAt),
 			Type:    typeLabel,
 			TypeKey: typeKey,
 			Title:   item.Title,
@@ -200,7 +286,7 @@ func (s *DigestService) BuildDigestData(ctx context.Context, userID string, targ
 
 		if item.ItemType == "reminder" {
 			data.Reminders = append(data.Reminders, ReminderItemData{
-				Time:  agendaTimeLabel(item, scheduledAt),
+				Time:  agendaTimeLabel(l, item, scheduledAt),
 				Title: item.Title,
 			})
 		}
@@ -215,6 +301,10 @@ func (s *DigestService) BuildDigestData(ctx context.Context, userID string, targ
 		if t.Status != domain.TaskStatusOpen {
 			continue
 		}
+		if !filter.allows(ctx, t.FlagID, t.SubflagID) {
+			continue
+		}
+		data.touch(t.UpdatedAt)
 		if t.DueAt == nil {
 			data.OpenTasks = append(data.OpenTasks, TaskItemData{Title: t.Title})
 			continue
@@ -245,7 +335,9 @@ func (s *DigestService) BuildDigestData(ctx context.Context, userID string, targ
 		}
 
 		pendingItems := make([]string, 0)
+		data.touch(list.UpdatedAt)
 		for _, item := range items {
+			data.touch(item.UpdatedAt)
 			if !item.Checked {
 				pendingItems = append(pendingItems, shoppingItemLabel(item))
 			}
@@ -267,27 +359,25 @@ func (s *DigestService) BuildDigestData(ctx context.Context, userID string, targ
 	sort.Slice(data.Reminders, func(i, j int) bool {
 		return data.Reminders[i].Time < data.Reminders[j].Time
 	})
+	sort.SliceStable(data.Calendar, func(i, j int) bool {
+		return data.Calendar[i].Start.Before(data.Calendar[j].Start)
+	})
 
-	data.HasSchedule = len(data.Schedule) > 0
-	data.HasAgenda = len(data.Agenda) > 0
-	data.HasReminders = len(data.Reminders) > 0
-	data.HasTasks = len(data.Tasks) > 0
-	data.HasOpenTasks = len(data.OpenTasks) > 0
-	data.HasShoppingLists = len(data.ShoppingLists) > 0
+	applyDigestSettings(&data, settings)
 
 	return data, nil
 }
 
-func defaultDigestDetail() DigestDetail {
+func defaultDigestDetail(l i18n.Localizer) DigestDetail {
 	return DigestDetail{
-		Purpose:       "Resumo consolidado do dia do usuario para exibicao e interpretacao por IA.",
-		Schedule:      "Itens de rotina planejados para hoje (habitos/rotinas recorrentes), com janela de horario, recorrencia, contexto e status de conclusao.",
-		Agenda:        "Linha do tempo dos compromissos do dia (eventos, lembretes e outros itens com horario), com tipo, titulo e contexto.",
-		Reminders:     "Subconjunto da agenda contendo apenas lembretes com horario e titulo, util para destaque rapido.",
-		Tasks:         "Tarefas abertas com prazo para hoje, incluindo horario limite (dueTime) quando disponivel.",
-		OpenTasks:     "Tarefas abertas sem prazo definido (backlog), priorize por relevancia/contexto na exibicao.",
-		ShoppingLists: "Listas de compras abertas com contagem de itens pendentes e nomes dos itens ainda nao marcados.",
-		Flags:         "Campos booleanos 'has*' indicam se cada secao possui dados para permitir renderizacao condicional na interface.",
+		Purpose:       l.T("digest.detail.purpose"),
+		Schedule:      l.T("digest.detail.schedule"),
+		Agenda:        l.T("digest.detail.agenda"),
+		Reminders:     l.T("digest.detail.reminders"),
+		Tasks:         l.T("digest.detail.tasks"),
+		OpenTasks:     l.T("digest.detail.open_tasks"),
+		ShoppingLists: l.T("digest.detail.shopping_lists"),
+		Flags:         l.T("digest.detail.flags"),
 	}
 }
 
@@ -302,9 +392,10 @@ func (s *DigestService) ValidateUser(ctx context.Context, userID, email string)
 	return strings.EqualFold(user.Email, strings.TrimSpace(email)), nil
 }
 
-func (s *DigestService) buildScheduleItems(ctx context.Context, userID string, targetDate time.Time) ([]ScheduleItemData, error) {
+// buildScheduleItems returns the schedule items and the routines they came from (same order).
+func (s *DigestService) buildScheduleItems(ctx context.Context, l i18n.Localizer, userID string, targetDate time.Time, filter *flagFilter) ([]ScheduleItemData, []domain.Routine, error) {
 	if s.routineLister == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	weekday := int(targetDate.Weekday())
@@ -312,10 +403,17 @@ func (s *DigestService) buildScheduleItems(ctx context.Context, userID string, t
 
 	routines, err := s.routineLister.ListByWeekday(ctx, userID, weekday, date)
 	if err != nil {
-		return nil, fmt.Errorf("list routines: %w", err)
+		return nil, nil, fmt.Errorf("list routines: %w", err)
+	}
+	allowed := routines[:0:0]
+	for _, routine := range routines {
+		if filter.allows(ctx, routine.FlagID, routine.SubflagID) {
+			allowed = append(allowed, routine)
+		}
 	}
+	routines = allowed
 	if len(routines) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	flagNames, subflagNames := s.loadRoutineContextMaps(ctx, userID, routines)
@@ -323,15 +421,15 @@ func (s *DigestService) buildScheduleItems(ctx context.Context, userID string, t
 	items := make([]ScheduleItemData, 0, len(routines))
 	for _, routine := range routines {
 		items = append(items, ScheduleItemData{
-			Time:        routineTimeLabel(routine.StartTime, routine.EndTime),
+			Time:        routineTimeLabel(l, routine.StartTime, routine.EndTime),
 			Title:       routine.Title,
-			Recurrence:  routineRecurrenceLabel(routine.RecurrenceType),
+			Recurrence:  routineRecurrenceLabel(l, routine),
 			Context:     routineContextPath(routine.FlagID, routine.SubflagID, flagNames, subflagNames),
 			IsCompleted: routine.IsCompletedToday,
 		})
 	}
 
-	return items, nil
+	return items, routines, nil
 }
 
 func (s *DigestService) loadRoutineContextMaps(ctx context.Context, userID string, routines []domain.Routine) (map[string]string, map[string]string) {
@@ -386,13 +484,13 @@ func collectIDs(routines []domain.Routine, selector func(domain.Routine) *string
 	return ids
 }
 
-func routineTimeLabel(startTime, endTime string) string {
+func routineTimeLabel(l i18n.Localizer, startTime, endTime string) string {
 	start := normalizeClock(startTime)
 	end := normalizeClock(endTime)
 
 	switch {
 	case start == "" && end == "":
-		return "Dia todo"
+		return l.T("digest.routine.all_day")
 	case start == "":
 		return end
 	case end == "" || end == start:
@@ -422,18 +520,44 @@ func normalizeClock(raw string) string {
 	return fmt.Sprintf("%02d:%02d", hour, minute)
 }
 
-func routineRecurrenceLabel(recurrenceType string) string {
-	switch recurrenceType {
-	case "", "weekly":
-		return "Semanal"
-	case "biweekly":
-		return "Quinzenal"
-	case "triweekly":
-		return "A cada 3 semanas"
-	case "monthly_week":
-		return "Mensal"
+func routineRecurrenceLabel(l i18n.Localizer, routine domain.Routine) string {
+	switch routine.RecurrenceType {
+	case "":
+		return l.T("digest.recurrence.weekly")
+	case recurrence.TypeWeekly, recurrence.TypeBiweekly, recurrence.TypeTriweekly, recurrence.TypeMonthlyWeek:
+		return l.T("digest.recurrence." + routine.RecurrenceType)
+	case recurrence.TypeRRule:
+		rule, err := recurrence.RoutineRule(routine)
+		if err != nil {
+			return l.T("digest.recurrence.custom")
+		}
+		return ruleRecurrenceLabel(l, rule)
 	default:
-		return recurrenceType
+		return routine.RecurrenceType
+	}
+}
+
+// ruleRecurrenceLabel names the simple RRULE cadences; anything else reads as custom.
+func ruleRecurrenceLabel(l i18n.Localizer, rule recurrence.Rule) string {
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	switch {
+	case rule.Freq == recurrence.Daily && interval == 1:
+		return l.T("digest.recurrence.daily")
+	case rule.Freq == recurrence.Weekly && interval == 1:
+		return l.T("digest.recurrence.weekly")
+	case rule.Freq == recurrence.Weekly && interval == 2:
+		return l.T("digest.recurrence.biweekly")
+	case rule.Freq == recurrence.Weekly && interval == 3:
+		return l.T("digest.recurrence.triweekly")
+	case rule.Freq == recurrence.Monthly && interval == 1:
+		return l.T("digest.recurrence.monthly")
+	case rule.Freq == recurrence.Yearly && interval == 1:
+		return l.T("digest.recurrence.yearly")
+	default:
+		return l.T("digest.recurrence.custom")
 	}
 }
 
@@ -453,16 +577,12 @@ func routineContextPath(flagID, subflagID *string, flagNames, subflagNames map[s
 	}
 }
 
-func agendaType(itemType string) (string, string) {
+func agendaType(l i18n.Localizer, itemType string) (string, string) {
 	switch itemType {
-	case "event":
-		return "Evento", "event"
-	case "task":
-		return "Task", "task"
-	case "reminder":
-		return "Lembrete", "reminder"
+	case "event", "task", "reminder":
+		return l.T("digest.type." + itemType), itemType
 	default:
-		return "Item", "item"
+		return l.T("digest.type.item"), "item"
 	}
 }
 
@@ -482,9 +602,9 @@ func contextPath(flagName, subflagName *string) string {
 	}
 }
 
-func agendaTimeLabel(item repository.AgendaItem, scheduledAt time.Time) string {
+func agendaTimeLabel(l i18n.Localizer, item repository.AgendaItem, scheduledAt time.Time) string {
 	if item.ItemType == "event" && item.AllDay != nil && *item.AllDay {
-		return "Dia inteiro"
+		return l.T("digest.agenda.all_day")
 	}
 	if item.ItemType == "event" && item.EndAt != nil {
 		endAt := item.EndAt.In(scheduledAt.Location())
@@ -523,6 +643,8 @@ func (s *DigestService) ProcessPendingDigests(ctx context.Context) error {
 
 	nowUTC := s.now().UTC()
 
+	s.retryFailedDigests(ctx, nowUTC)
+
 	for _, p := range prefs {
 		if p.DailyDigestHour < 0 || p.DailyDigestHour > 23 {
 			s.log.Warn("invalid_daily_digest_hour", slog.String("user_id", p.UserID), slog.Int("hour", p.DailyDigestHour))
@@ -551,6 +673,8 @@ func (s *DigestService) ProcessPendingDigests(ctx context.Context) error {
 		}
 	}
 
+	s.processWeeklyDigests(ctx, nowUTC)
+
 	return nil
 }
 
@@ -581,20 +705,53 @@ func (s *DigestService) SendTestDigestForUserID(ctx context.Context, userID stri
 }
 
 func (s *DigestService) sendDigest(ctx context.Context, user domain.User, date time.Time, trackDelivery bool) error {
+	if date.IsZero() {
+		date = s.now()
+	}
+	return s.deliver(ctx, user, date, digestTypeDaily, trackDelivery, func() (mailer.SendRequest, error) {
+		if trackDelivery && s.isAway(ctx, user.ID, date) {
+			return mailer.SendRequest{}, errUserAway
+		}
+		settings, err := s.GetSettings(ctx, user.ID)
+		if err != nil {
+			return mailer.SendRequest{}, err
+		}
+		data, err := s.buildDigestData(ctx, user.ID, date, settings)
+		if err != nil {
+			return mailer.SendRequest{}, err
+		}
+		if trackDelivery && settings.SkipEmpty && data.IsEmpty() {
+			return mailer.SendRequest{}, errDigestSkipped
+		}
+		data.UnsubscribeURL = s.unsubscribeURL(user.ID, digestTypeDaily)
+
+		htmlBody, textBody, err := s.renderDigest(data)
+		if err != nil {
+			return mailer.SendRequest{}, err
+		}
+
+		return mailer.SendRequest{
+			Subject: buildDigestSubject(i18n.For(data.Locale), date),
+			Html:    htmlBody,
+			Text:    textBody,
+		}, nil
+	})
+}
+
+// deliver reserves the email_digests row (when tracking), builds the message and sends it.
+// A digest already reserved for the same user, date and type is skipped.
+func (s *DigestService) deliver(ctx context.Context, user domain.User, date time.Time, digestType string, trackDelivery bool, build func() (mailer.SendRequest, error)) error {
 	email := strings.TrimSpace(user.Email)
 	if email == "" {
 		return fmt.Errorf("user email is empty")
 	}
-	if date.IsZero() {
-		date = s.now()
-	}
 
 	var digestRecord *domain.EmailDigest
 	if trackDelivery {
 		digestRecord = &domain.EmailDigest{
 			UserID:     user.ID,
 			DigestDate: date,
-			Type:       digestTypeDaily,
+			Type:       digestType,
 			Status:     domain.EmailDigestStatusPending,
 		}
 
@@ -607,22 +764,31 @@ func (s *DigestService) sendDigest(ctx context.Context, user domain.User, date t
 		}
 	}
 
-	data, err := s.BuildDigestData(ctx, user.ID, date)
-	if err != nil {
-		return s.failDigest(ctx, digestRecord, err)
+	req, err := build()
+	if errors.Is(err, errDigestSkipped) {
+		return s.skipDigest(ctx, digestRecord, nil)
+	}
+	if errors.Is(err, errUserAway) {
+		reason := err.Error()
+		return s.skipDigest(ctx, digestRecord, &reason)
 	}
-
-	htmlBody, textBody, err := s.renderDigest(data)
 	if err != nil {
 		return s.failDigest(ctx, digestRecord, err)
 	}
+	req.To = []string{email}
+	if url := s.unsubscribeURL(user.ID, digestType); url != "" {
+		// RFC 8058: o provedor de e-mail faz POST na URL para o cancelamento em um clique.
+		req.Headers = map[string]string{
+			"List-Unsubscribe":      "<" + url + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
 
-	msgID, err := s.mailer.Send(ctx, mailer.SendRequest{
-		To:      []string{email},
-		Subject: buildDigestSubject(date),
-		Html:    htmlBody,
-		Text:    textBody,
-	})
+	msgID, err := s.mailer.Send(ctx, req)
+	if errors.Is(err, mailer.ErrRecipientSuppressed) {
+		reason := err.Error()
+		return s.skipDigest(ctx, digestRecord, &reason)
+	}
 	if err != nil {
 		return s.failDigest(ctx, digestRecord, err)
 	}
@@ -638,6 +804,16 @@ func (s *DigestService) sendDigest(ctx context.Context, user domain.User, date t
 	return s.emailDigestRepo.Update(ctx, digestRecord)
 }
 
+// skipDigest closes the reserved row without sending and without scheduling a retry.
+func (s *DigestService) skipDigest(ctx context.Context, digestRecord *domain.EmailDigest, reason *string) error {
+	if digestRecord == nil {
+		return nil
+	}
+	digestRecord.Status = domain.EmailDigestStatusSkipped
+	digestRecord.ErrorMsg = reason
+	return s.emailDigestRepo.Update(ctx, digestRecord)
+}
+
 func (s *DigestService) renderDigest(data DigestData) (string, string, error) {
 	var html bytes.Buffer
 	if err := s.htmlTemplate.Execute(&html, data); err != nil {
@@ -660,6 +836,11 @@ func (s *DigestService) failDigest(ctx context.Context, digestRecord *domain.Ema
 	digestRecord.Status = domain.EmailDigestStatusFailed
 	msg := cause.Error()
 	digestRecord.ErrorMsg = &msg
+	digestRecord.NextAttemptAt = nil
+	if digestRecord.Attempts < s.maxAttempts {
+		next := s.now().UTC().Add(digestRetryDelay(digestRecord.Attempts))
+		digestRecord.NextAttemptAt = &next
+	}
 
 	if err := s.emailDigestRepo.Update(ctx, digestRecord); err != nil {
 		return fmt.Errorf("%w (and failed to persist digest error: %v)", cause, err)
@@ -667,6 +848,67 @@ func (s *DigestService) failDigest(ctx context.Context, digestRecord *domain.Ema
 	return cause
 }
 
+// digestRetryDelay dobra a espera a cada tentativa: 15m, 30m, 1h...
+func digestRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 6 {
+		attempts = 6
+	}
+	return digestRetryBaseDelay << (attempts - 1)
+}
+
+// retryFailedDigests reenvia digests com falha cujo próximo envio já venceu, inclusive
+// de dias anteriores (o fluxo normal só olha o dia corrente do usuário).
+func (s *DigestService) retryFailedDigests(ctx context.Context, nowUTC time.Time) {
+	failed, err := s.emailDigestRepo.ListRetryable(ctx, nowUTC, maxDigestRetriesPerRun)
+	if err != nil {
+		s.log.Error("digest_retry_list_failed", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, d := range failed {
+		user, err := s.userRepo.Get(ctx, d.UserID)
+		if err != nil {
+			s.log.Error("digest_user_load_failed", slog.String("user_id", d.UserID), slog.String("error", err.Error()))
+			continue
+		}
+
+		tz, err := time.LoadLocation(user.Timezone)
+		if err != nil {
+			tz = time.UTC
+		}
+
+		date := retryDigestDate(d.DigestDate, nowUTC.In(tz))
+		switch d.Type {
+		case digestTypeDaily:
+			err = s.sendDigest(ctx, user, date, true)
+		case digestTypeWeekly:
+			err = s.sendWeeklyDigest(ctx, user, date, true)
+		default:
+			s.log.Warn("digest_retry_unknown_type", slog.String("digest_id", d.ID), slog.String("type", d.Type))
+			continue
+		}
+		if err != nil {
+			s.log.Error("digest_retry_failed", slog.String("user_id", user.ID), slog.String("type", d.Type), slog.Int("attempt", d.Attempts+1), slog.String("error", err.Error()))
+			continue
+		}
+		s.log.Info("digest_retry_sent", slog.String("user_id", user.ID), slog.String("type", d.Type), slog.Int("attempt", d.Attempts+1))
+	}
+}
+
+// retryDigestDate mantém o dia original do digest no timezone do usuário; para dias
+// anteriores usa o fim do dia, para que o conteúdo reflita o dia inteiro.
+func retryDigestDate(digestDate, userNow time.Time) time.Time {
+	day := time.Date(digestDate.Year(), digestDate.Month(), digestDate.Day(), 0, 0, 0, 0, userNow.Location())
+	endOfDay := day.AddDate(0, 0, 1).Add(-time.Minute)
+	if endOfDay.After(userNow) {
+		return userNow
+	}
+	return endOfDay
+}
+
 func (s *DigestService) listAllTasks(ctx context.Context, userID string) ([]domain.Task, error) {
 	cursor := ""
 	all := make([]domain.Task, 0)
@@ -721,30 +963,9 @@ func (s *DigestService) listAllShoppingItems(ctx context.Context, userID, listID
 	}
 }
 
-func buildDigestSubject(date time.Time) string {
+func buildDigestSubject(l i18n.Localizer, date time.Time) string {
 	if date.IsZero() {
-		return defaultDigestSubject
-	}
-	return fmt.Sprintf("%s — %s (%s)", defaultDigestSubject, date.Format("02/01"), weekdayPTBR(date.Weekday()))
-}
-
-func weekdayPTBR(w time.Weekday) string {
-	switch w {
-	case time.Monday:
-		return "Segunda"
-	case time.Tuesday:
-		return "Terça"
-	case time.Wednesday:
-		return "Quarta"
-	case time.Thursday:
-		return "Quinta"
-	case time.Friday:
-		return "Sexta"
-	case time.Saturday:
-		return "Sábado"
-	case time.Sunday:
-		return "Domingo"
-	default:
-		return ""
+		return l.T("digest.title")
 	}
+	return l.T("digest.subject", i18n.Vars{"date": l.DayMonth(date), "weekday": l.Weekday(date.Weekday())})
 }
@@ -6,6 +6,7 @@ import (
 	"inbota/backend/internal/app/domain"
 	"inbota/backend/internal/app/repository"
 	"inbota/backend/internal/infra/mailer"
+	"strings"
 	"testing"
 	"time"
 )
@@ -30,22 +31,53 @@ func (f *fakeUserRepo) FindByEmail(ctx context.Context, email string) (domain.Us
 	return domain.User{}, fmt.Errorf("not implemented")
 }
 
+func (f *fakeUserRepo) ListByIDs(ctx context.Context, ids []string) ([]domain.User, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeUserRepo) UpdateHolidayCalendar(ctx context.Context, id, code string) error {
+	return fmt.Errorf("not implemented")
+}
+
 type fakePrefsRepo struct {
-	prefs []domain.NotificationPreferences
+	prefs       []domain.NotificationPreferences
+	weeklyPrefs []domain.NotificationPreferences
+	byUser      map[string]domain.NotificationPreferences
+	upserts     int
 }
 
 func (f *fakePrefsRepo) GetByUserID(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	if p, ok := f.byUser[userID]; ok {
+		return p, nil
+	}
 	return domain.NotificationPreferences{}, fmt.Errorf("not implemented")
 }
 
 func (f *fakePrefsRepo) Upsert(ctx context.Context, prefs domain.NotificationPreferences) error {
-	return fmt.Errorf("not implemented")
+	if f.byUser == nil {
+		return fmt.Errorf("not implemented")
+	}
+	f.upserts++
+	f.byUser[prefs.UserID] = prefs
+	return nil
+}
+
+func (f *fakePrefsRepo) ListByUserIDs(ctx context.Context, userIDs []string) ([]domain.NotificationPreferences, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakePrefsRepo) ListWithBriefings(ctx context.Context) ([]domain.NotificationPreferences, error) {
+	return nil, fmt.Errorf("not implemented")
 }
 
 func (f *fakePrefsRepo) ListEnabled(ctx context.Context) ([]domain.NotificationPreferences, error) {
 	return f.prefs, nil
 }
 
+func (f *fakePrefsRepo) ListWeeklyDigestEnabled(ctx context.Context) ([]domain.NotificationPreferences, error) {
+	return f.weeklyPrefs, nil
+}
+
 func (f *fakePrefsRepo) GetDailySummaryTokenByUserID(ctx context.Context, userID string) (string, error) {
 	return "", fmt.Errorf("not implemented")
 }
@@ -63,16 +95,32 @@ type fakeEmailDigestRepo struct {
 	createCalls  int
 	updateCalls  int
 	lastUpdated  *domain.EmailDigest
+	retryable    []domain.EmailDigest
+	// providerStatus guarda o último status recebido por provider_id.
+	providerStatus map[string]string
 }
 
 func (f *fakeEmailDigestRepo) Create(ctx context.Context, digest *domain.EmailDigest) (bool, error) {
 	f.createCalls++
 	if f.createResult {
 		digest.ID = "digest-id"
+		digest.Attempts++
 	}
 	return f.createResult, nil
 }
 
+func (f *fakeEmailDigestRepo) ListRetryable(ctx context.Context, now time.Time, limit int) ([]domain.EmailDigest, error) {
+	return f.retryable, nil
+}
+
+func (f *fakeEmailDigestRepo) UpdateProviderStatus(ctx context.Context, providerID, status string, at time.Time) error {
+	if f.providerStatus == nil {
+		f.providerStatus = make(map[string]string)
+	}
+	f.providerStatus[providerID] = status
+	return nil
+}
+
 func (f *fakeEmailDigestRepo) Update(ctx context.Context, digest *domain.EmailDigest) error {
 	f.updateCalls++
 	copy := *digest
@@ -126,6 +174,26 @@ func (f *fakeTaskRepo) List(ctx context.Context, userID string, opts repository.
 	return f.items, nil, nil
 }
 
+func (f *fakeTaskRepo) ListOpen(ctx context.Context, userID string, limit int) ([]domain.Task, error) {
+	return f.items, nil
+}
+
+func (f *fakeTaskRepo) ListSeries(ctx context.Context, userID, seriesID string) ([]domain.Task, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeTaskRepo) UpdateIfOpen(ctx context.Context, task domain.Task) (domain.Task, bool, error) {
+	return domain.Task{}, false, fmt.Errorf("not implemented")
+}
+
+func (f *fakeTaskRepo) ListDependencyGraph(ctx context.Context, userID string, ids []string) (map[string]repository.TaskDependencyNode, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeTaskRepo) ListUnblocked(ctx context.Context, since time.Time) ([]domain.Task, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (f *fakeTaskRepo) ListUpcoming(ctx context.Context, start, end time.Time) ([]domain.Task, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -180,13 +248,96 @@ func (f *fakeShoppingItemRepo) ListByList(ctx context.Context, userID, listID st
 
 type fakeMailer struct {
 	sendCalls int
+	err       error
+	subjects  []string
+	last      mailer.SendRequest
 }
 
 func (f *fakeMailer) Send(ctx context.Context, req mailer.SendRequest) (string, error) {
 	f.sendCalls++
+	f.subjects = append(f.subjects, req.Subject)
+	f.last = req
+	if f.err != nil {
+		return "", f.err
+	}
 	return "message-id", nil
 }
 
+// testServiceDeps holds the fakes newTestService wires into NewDigestService.
+type testServiceDeps struct {
+	users    repository.UserRepository
+	prefs    repository.NotificationPreferencesRepository
+	digests  repository.EmailDigestRepository
+	routines RoutineWeekdayLister
+	agenda   repository.AgendaRepository
+	tasks    repository.TaskRepository
+	mail     mailer.Mailer
+}
+
+type testServiceOption func(*testServiceDeps)
+
+func withUsers(users repository.UserRepository) testServiceOption {
+	return func(d *testServiceDeps) { d.users = users }
+}
+
+func withPrefs(prefs repository.NotificationPreferencesRepository) testServiceOption {
+	return func(d *testServiceDeps) { d.prefs = prefs }
+}
+
+func withDigests(digests repository.EmailDigestRepository) testServiceOption {
+	return func(d *testServiceDeps) { d.digests = digests }
+}
+
+func withRoutines(routines RoutineWeekdayLister) testServiceOption {
+	return func(d *testServiceDeps) { d.routines = routines }
+}
+
+func withAgenda(agenda repository.AgendaRepository) testServiceOption {
+	return func(d *testServiceDeps) { d.agenda = agenda }
+}
+
+func withTasks(tasks repository.TaskRepository) testServiceOption {
+	return func(d *testServiceDeps) { d.tasks = tasks }
+}
+
+func withMailer(mail mailer.Mailer) testServiceOption {
+	return func(d *testServiceDeps) { d.mail = mail }
+}
+
+// newTestService builds a DigestService over empty fakes; options replace the ones a test needs.
+func newTestService(t *testing.T, opts ...testServiceOption) *DigestService {
+	t.Helper()
+	deps := testServiceDeps{
+		users:    &fakeUserRepo{},
+		prefs:    &fakePrefsRepo{},
+		digests:  &fakeEmailDigestRepo{},
+		routines: &fakeRoutineLister{},
+		agenda:   &fakeAgendaRepo{},
+		tasks:    &fakeTaskRepo{},
+		mail:     &fakeMailer{},
+	}
+	for _, opt := range opts {
+		opt(&deps)
+	}
+	svc, err := NewDigestService(
+		deps.users,
+		deps.prefs,
+		deps.digests,
+		deps.routines,
+		deps.agenda,
+		deps.tasks,
+		&fakeShoppingListRepo{},
+		&fakeShoppingItemRepo{itemsByList: map[string][]domain.ShoppingItem{}},
+		nil,
+		nil,
+		deps.mail,
+	)
+	if err != nil {
+		t.Fatalf("new digest service: %v", err)
+	}
+	return svc
+}
+
 func TestBuildDigestData(t *testing.T) {
 	loc := time.FixedZone("BRT", -3*3600)
 	target := time.Date(2026, 3, 9, 4, 0, 0, 0, loc)
@@ -314,6 +465,61 @@ func TestSendDigestSkipsWhenAlreadyReserved(t *testing.T) {
 	}
 }
 
+type fakeAwayRepo struct {
+	repository.AwayPeriodRepository
+	periods []domain.AwayPeriod
+}
+
+func (f *fakeAwayRepo) ListOverlapping(ctx context.Context, userIDs []string, from, to string) ([]domain.AwayPeriod, error) {
+	return f.periods, nil
+}
+
+func TestSendDigestPausesWhileAway(t *testing.T) {
+	mail := &fakeMailer{}
+	digests := &fakeEmailDigestRepo{createResult: true}
+
+	svc, err := NewDigestService(
+		&fakeUserRepo{},
+		&fakePrefsRepo{},
+		digests,
+		&fakeRoutineLister{},
+		&fakeAgendaRepo{},
+		&fakeTaskRepo{},
+		&fakeShoppingListRepo{},
+		&fakeShoppingItemRepo{itemsByList: map[string][]domain.ShoppingItem{}},
+		nil,
+		nil,
+		mail,
+	)
+	if err != nil {
+		t.Fatalf("new digest service: %v", err)
+	}
+	work := "flag-work"
+	away := &fakeAwayRepo{periods: []domain.AwayPeriod{{StartsOn: "2026-07-01", EndsOn: "2026-07-15", FlagID: &work}}}
+	svc.SetAwayRepository(away)
+	user := domain.User{ID: "u1", Email: "u1@example.com"}
+	date := time.Date(2026, 7, 10, 8, 0, 0, 0, time.UTC)
+
+	// Férias só de uma flag não pausam o digest.
+	if err := svc.SendDigest(context.Background(), user, date); err != nil {
+		t.Fatalf("send digest: %v", err)
+	}
+	if mail.sendCalls != 1 {
+		t.Fatalf("expected digest during flag scoped away period, got %d sends", mail.sendCalls)
+	}
+
+	away.periods = append(away.periods, domain.AwayPeriod{StartsOn: "2026-07-05", EndsOn: "2026-07-20"})
+	if err := svc.SendDigest(context.Background(), user, date); err != nil {
+		t.Fatalf("send digest: %v", err)
+	}
+	if mail.sendCalls != 1 {
+		t.Fatalf("expected no e-mail while away, got %d sends", mail.sendCalls)
+	}
+	if digests.lastUpdated == nil || digests.lastUpdated.Status != domain.EmailDigestStatusSkipped {
+		t.Fatalf("expected skipped digest, got %+v", digests.lastUpdated)
+	}
+}
+
 func TestSendTestDigestBypassesTracking(t *testing.T) {
 	mail := &fakeMailer{}
 	digests := &fakeEmailDigestRepo{createResult: false}
@@ -393,6 +599,169 @@ func TestProcessPendingDigestsRespectsHour(t *testing.T) {
 	}
 }
 
+func TestFailedDigestSchedulesBoundedRetry(t *testing.T) {
+	mail := &fakeMailer{err: fmt.Errorf("provider down")}
+	digests := &fakeEmailDigestRepo{createResult: true}
+	now := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+
+	svc, err := NewDigestService(
+		&fakeUserRepo{},
+		&fakePrefsRepo{},
+		digests,
+		&fakeRoutineLister{},
+		&fakeAgendaRepo{},
+		&fakeTaskRepo{},
+		&fakeShoppingListRepo{},
+		&fakeShoppingItemRepo{itemsByList: map[string][]domain.ShoppingItem{}},
+		nil,
+		nil,
+		mail,
+	)
+	if err != nil {
+		t.Fatalf("new digest service: %v", err)
+	}
+	svc.SetNow(func() time.Time { return now })
+
+	user := domain.User{ID: "u1", Email: "u1@example.com"}
+	if err := svc.SendDigest(context.Background(), user, now); err == nil {
+		t.Fatalf("expected send error")
+	}
+	if digests.lastUpdated == nil || digests.lastUpdated.Status != domain.EmailDigestStatusFailed {
+		t.Fatalf("expected failed digest, got %+v", digests.lastUpdated)
+	}
+	if digests.lastUpdated.NextAttemptAt == nil || !digests.lastUpdated.NextAttemptAt.Equal(now.Add(15*time.Minute)) {
+		t.Fatalf("expected retry in 15 minutes, got %v", digests.lastUpdated.NextAttemptAt)
+	}
+
+	svc.SetMaxAttempts(1)
+	_ = svc.SendDigest(context.Background(), user, now)
+	if digests.lastUpdated.NextAttemptAt != nil {
+		t.Fatalf("expected no retry after max attempts, got %v", digests.lastUpdated.NextAttemptAt)
+	}
+}
+
+func TestProcessPendingDigestsRetriesPreviousDay(t *testing.T) {
+	mail := &fakeMailer{}
+	digests := &fakeEmailDigestRepo{
+		createResult: true,
+		retryable: []domain.EmailDigest{
+			{ID: "d1", UserID: "u1", DigestDate: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), Type: digestTypeDaily, Status: domain.EmailDigestStatusFailed, Attempts: 1},
+		},
+	}
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"u1": {ID: "u1", Email: "u1@example.com", Timezone: "America/Sao_Paulo"},
+	}}
+
+	svc, err := NewDigestService(
+		users,
+		&fakePrefsRepo{},
+		digests,
+		&fakeRoutineLister{},
+		&fakeAgendaRepo{},
+		&fakeTaskRepo{},
+		&fakeShoppingListRepo{},
+		&fakeShoppingItemRepo{itemsByList: map[string][]domain.ShoppingItem{}},
+		nil,
+		nil,
+		mail,
+	)
+	if err != nil {
+		t.Fatalf("new digest service: %v", err)
+	}
+	svc.SetNow(func() time.Time {
+		return time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	})
+
+	if err := svc.ProcessPendingDigests(context.Background()); err != nil {
+		t.Fatalf("process pending digests: %v", err)
+	}
+
+	if mail.sendCalls != 1 || mail.subjects[0] != "Seu dia no Inbota — 08/03 (Domingo)" {
+		t.Fatalf("expected retry of the 08/03 digest, got calls=%d subjects=%v", mail.sendCalls, mail.subjects)
+	}
+	if digests.lastUpdated == nil || digests.lastUpdated.Status != domain.EmailDigestStatusSuccess {
+		t.Fatalf("expected retried digest marked as success, got %+v", digests.lastUpdated)
+	}
+}
+
 func ptrTime(t time.Time) *time.Time {
 	return &t
 }
+
+func TestMorningBriefingAndEveningReview(t *testing.T) {
+	data := DigestData{
+		Agenda:   []AgendaItemData{{Time: "09:00", Title: "Reuniao"}},
+		Tasks:    []TaskItemData{{DueTime: "18:00", Title: "Enviar relatorio"}},
+		Schedule: []ScheduleItemData{{Time: "07:00", Title: "Correr", IsCompleted: true}, {Time: "20:00", Title: "Ler"}},
+	}
+
+	title, body, ok := MorningBriefing(data)
+	if !ok {
+		t.Fatalf("expected morning briefing")
+	}
+	if title != "Bom dia! Hoje: 1 compromisso, 1 tarefa, 2 rotinas" {
+		t.Fatalf("unexpected title: %q", title)
+	}
+	if body != "09:00 Reuniao\n18:00 Enviar relatorio\n07:00 Correr\n20:00 Ler" {
+		t.Fatalf("unexpected body: %q", body)
+	}
+
+	title, body, ok = EveningReview(data)
+	if !ok {
+		t.Fatalf("expected evening review")
+	}
+	if title != "Fechamento do dia: 2 itens pendentes" || body != "Tarefa: Enviar relatorio\nRotina: Ler" {
+		t.Fatalf("unexpected review: %q / %q", title, body)
+	}
+
+	if _, _, ok := EveningReview(DigestData{}); ok {
+		t.Fatalf("expected no review when nothing is pending")
+	}
+}
+
+func TestSendDigestUsesUserLocale(t *testing.T) {
+	mail := &fakeMailer{}
+	user := domain.User{ID: "u1", Email: "u1@example.com", Locale: "en-US"}
+	svc, err := NewDigestService(
+		&fakeUserRepo{users: map[string]domain.User{"u1": user}},
+		&fakePrefsRepo{},
+		&fakeEmailDigestRepo{createResult: true},
+		&fakeRoutineLister{items: []domain.Routine{{ID: "r1", Title: "Run", RecurrenceType: "biweekly"}}},
+		&fakeAgendaRepo{},
+		&fakeTaskRepo{},
+		&fakeShoppingListRepo{},
+		&fakeShoppingItemRepo{itemsByList: map[string][]domain.ShoppingItem{}},
+		nil,
+		nil,
+		mail,
+	)
+	if err != nil {
+		t.Fatalf("new digest service: %v", err)
+	}
+
+	date := time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)
+	if err := svc.SendDigest(context.Background(), user, date); err != nil {
+		t.Fatalf("send digest: %v", err)
+	}
+
+	if mail.last.Subject != "Your day on Inbota — 03/08 (Sunday)" {
+		t.Fatalf("unexpected subject: %q", mail.last.Subject)
+	}
+	if !strings.Contains(mail.last.Html, `lang="en"`) || !strings.Contains(mail.last.Html, "Today&#39;s schedule") {
+		t.Fatalf("expected english html, got %s", mail.last.Html)
+	}
+	if !strings.Contains(mail.last.Text, "[All day] Run [Every 2 weeks]") {
+		t.Fatalf("expected localized routine labels, got %s", mail.last.Text)
+	}
+
+	data, err := svc.BuildDigestData(context.Background(), "u1", date)
+	if err != nil {
+		t.Fatalf("build digest data: %v", err)
+	}
+	if data.Locale != "en" || data.Date != "03/08/2026" {
+		t.Fatalf("unexpected locale/date: %q %q", data.Locale, data.Date)
+	}
+	if title, _, _ := MorningBriefing(data); title != "Good morning! Today: 1 routine" {
+		t.Fatalf("unexpected briefing title: %q", title)
+	}
+}
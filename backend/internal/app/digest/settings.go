@@ -0,0 +1,253 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+)
+
+const maxDigestSectionItems = 50
+
+var ErrInvalidDigestSettings = errors.New("invalid_digest_settings")
+
+// errDigestSkipped interrompe o envio quando o usuário pediu para pular dias vazios.
+var errDigestSkipped = errors.New("digest skipped: empty day")
+
+func (s *DigestService) SetSettingsRepository(repo repository.DigestSettingsRepository) {
+	s.settingsRepo = repo
+}
+
+// DefaultDigestSettings is what users without saved settings get: every section, no limits.
+func DefaultDigestSettings(userID string) domain.DigestSettings {
+	return domain.DigestSettings{
+		UserID:         userID,
+		Sections:       append([]string(nil), domain.DefaultDigestSections...),
+		IncludeFlagIDs: []string{},
+		ExcludeFlagIDs: []string{},
+	}
+}
+
+func (s *DigestService) GetSettings(ctx context.Context, userID string) (domain.DigestSettings, error) {
+	if s.settingsRepo == nil {
+		return DefaultDigestSettings(userID), nil
+	}
+	settings, err := s.settingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return domain.DigestSettings{}, err
+	}
+	if settings == nil {
+		return DefaultDigestSettings(userID), nil
+	}
+	return *settings, nil
+}
+
+func (s *DigestService) UpdateSettings(ctx context.Context, settings domain.DigestSettings) (domain.DigestSettings, error) {
+	if s.settingsRepo == nil {
+		return domain.DigestSettings{}, errors.New("digest settings repository is not configured")
+	}
+	if err := s.validateSettings(ctx, &settings); err != nil {
+		return domain.DigestSettings{}, err
+	}
+	return s.settingsRepo.Upsert(ctx, settings)
+}
+
+func (s *DigestService) validateSettings(ctx context.Context, settings *domain.DigestSettings) error {
+	known := make(map[string]struct{}, len(domain.DefaultDigestSections))
+	for _, section := range domain.DefaultDigestSections {
+		known[section] = struct{}{}
+	}
+	seen := make(map[string]struct{}, len(settings.Sections))
+	for _, section := range settings.Sections {
+		if _, ok := known[section]; !ok {
+			return ErrInvalidDigestSettings
+		}
+		if _, dup := seen[section]; dup {
+			return ErrInvalidDigestSettings
+		}
+		seen[section] = struct{}{}
+	}
+
+	if settings.MaxItems < 0 || settings.MaxItems > maxDigestSectionItems {
+		return ErrInvalidDigestSettings
+	}
+
+	settings.IncludeFlagIDs = uniqueIDs(settings.IncludeFlagIDs)
+	settings.ExcludeFlagIDs = uniqueIDs(settings.ExcludeFlagIDs)
+	for _, id := range settings.ExcludeFlagIDs {
+		for _, included := range settings.IncludeFlagIDs {
+			if id == included {
+				return ErrInvalidDigestSettings
+			}
+		}
+	}
+
+	flagIDs := append(append([]string{}, settings.IncludeFlagIDs...), settings.ExcludeFlagIDs...)
+	if len(flagIDs) > 0 && s.flagRepo != nil {
+		flags, err := s.flagRepo.GetByIDs(ctx, settings.UserID, flagIDs)
+		if err != nil {
+			return err
+		}
+		if len(flags) != len(flagIDs) {
+			return ErrInvalidDigestSettings
+		}
+	}
+	return nil
+}
+
+func uniqueIDs(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
+
+// flagFilter aplica include/exclude de flags; itens em subflag herdam a flag pai.
+// Com include definido, itens sem flag ficam de fora.
+type flagFilter struct {
+	svc        *DigestService
+	userID     string
+	include    map[string]struct{}
+	exclude    map[string]struct{}
+	subflagMap map[string]string
+}
+
+func (s *DigestService) newFlagFilter(userID string, settings domain.DigestSettings) *flagFilter {
+	if len(settings.IncludeFlagIDs) == 0 && len(settings.ExcludeFlagIDs) == 0 {
+		return nil
+	}
+	f := &flagFilter{
+		svc:        s,
+		userID:     userID,
+		include:    make(map[string]struct{}, len(settings.IncludeFlagIDs)),
+		exclude:    make(map[string]struct{}, len(settings.ExcludeFlagIDs)),
+		subflagMap: make(map[string]string),
+	}
+	for _, id := range settings.IncludeFlagIDs {
+		f.include[id] = struct{}{}
+	}
+	for _, id := range settings.ExcludeFlagIDs {
+		f.exclude[id] = struct{}{}
+	}
+	return f
+}
+
+func (f *flagFilter) allows(ctx context.Context, flagID, subflagID *string) bool {
+	if f == nil {
+		return true
+	}
+
+	resolved := ""
+	if flagID != nil {
+		resolved = *flagID
+	} else if subflagID != nil {
+		resolved = f.parentFlag(ctx, *subflagID)
+	}
+
+	if resolved != "" {
+		if _, excluded := f.exclude[resolved]; excluded {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	_, included := f.include[resolved]
+	return included
+}
+
+func (f *flagFilter) parentFlag(ctx context.Context, subflagID string) string {
+	if parent, ok := f.subflagMap[subflagID]; ok {
+		return parent
+	}
+	parent := ""
+	if f.svc.subflagRepo != nil {
+		subflags, err := f.svc.subflagRepo.GetByIDs(ctx, f.userID, []string{subflagID})
+		if err != nil {
+			f.svc.log.Warn("digest_subflag_lookup_failed", slog.String("user_id", f.userID), slog.String("error", err.Error()))
+		} else if len(subflags) > 0 {
+			parent = subflags[0].FlagID
+		}
+	}
+	f.subflagMap[subflagID] = parent
+	return parent
+}
+
+// applyDigestSettings remove seções desativadas, aplica o limite por seção e registra a ordem.
+func applyDigestSettings(data *DigestData, settings domain.DigestSettings) {
+	enabled := make(map[string]bool, len(settings.Sections))
+	for _, section := range settings.Sections {
+		enabled[section] = true
+	}
+	data.Sections = append([]string{}, settings.Sections...)
+
+	if !enabled[domain.DigestSectionSchedule] {
+		data.Schedule, data.Detail.Schedule = nil, ""
+	}
+	if !enabled[domain.DigestSectionAgenda] {
+		data.Agenda, data.Detail.Agenda = nil, ""
+	}
+	if !enabled[domain.DigestSectionReminders] {
+		data.Reminders, data.Detail.Reminders = nil, ""
+	}
+	if !enabled[domain.DigestSectionTasks] {
+		data.Tasks, data.Detail.Tasks = nil, ""
+	}
+	if !enabled[domain.DigestSectionOpenTasks] {
+		data.OpenTasks, data.Detail.OpenTasks = nil, ""
+	}
+	if !enabled[domain.DigestSectionShoppingLists] {
+		data.ShoppingLists, data.Detail.ShoppingLists = nil, ""
+	}
+
+	if !enabled[domain.DigestSectionSchedule] || !enabled[domain.DigestSectionAgenda] {
+		kept := data.Calendar[:0:0]
+		for _, entry := range data.Calendar {
+			if entry.Kind == "routine" && enabled[domain.DigestSectionSchedule] || entry.Kind != "routine" && enabled[domain.DigestSectionAgenda] {
+				kept = append(kept, entry)
+			}
+		}
+		data.Calendar = kept
+	}
+
+	if limit := settings.MaxItems; limit > 0 {
+		data.Schedule = truncate(data.Schedule, limit)
+		data.Agenda = truncate(data.Agenda, limit)
+		data.Reminders = truncate(data.Reminders, limit)
+		data.Tasks = truncate(data.Tasks, limit)
+		data.OpenTasks = truncate(data.OpenTasks, limit)
+		data.ShoppingLists = truncate(data.ShoppingLists, limit)
+	}
+
+	data.HasSchedule = len(data.Schedule) > 0
+	data.HasAgenda = len(data.Agenda) > 0
+	data.HasReminders = len(data.Reminders) > 0
+	data.HasTasks = len(data.Tasks) > 0
+	data.HasOpenTasks = len(data.OpenTasks) > 0
+	data.HasShoppingLists = len(data.ShoppingLists) > 0
+}
+
+func truncate[T any](items []T, limit int) []T {
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+// IsEmpty reports whether no included section has items.
+func (d DigestData) IsEmpty() bool {
+	return !(d.HasSchedule || d.HasAgenda || d.HasReminders || d.HasTasks || d.HasOpenTasks || d.HasShoppingLists)
+}
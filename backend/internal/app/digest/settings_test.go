@@ -0,0 +1,193 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeDigestSettingsRepo struct {
+	settings *domain.DigestSettings
+	upserts  int
+}
+
+func (f *fakeDigestSettingsRepo) GetByUserID(ctx context.Context, userID string) (*domain.DigestSettings, error) {
+	return f.settings, nil
+}
+
+func (f *fakeDigestSettingsRepo) Upsert(ctx context.Context, settings domain.DigestSettings) (domain.DigestSettings, error) {
+	f.upserts++
+	f.settings = &settings
+	return settings, nil
+}
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func newSettingsTestService(t *testing.T, settings *domain.DigestSettings, digests *fakeEmailDigestRepo, mail *fakeMailer) *DigestService {
+	t.Helper()
+	loc := time.FixedZone("BRT", -3*3600)
+	routines := &fakeRoutineLister{items: []domain.Routine{
+		{ID: "r1", Title: "Treino", StartTime: "06:30", EndTime: "07:30", RecurrenceType: "weekly", FlagID: strPtr("flag-health")},
+		{ID: "r2", Title: "Estudo", StartTime: "20:00", EndTime: "21:00", RecurrenceType: "weekly"},
+	}}
+	agenda := &fakeAgendaRepo{items: []repository.AgendaItem{
+		{ID: "e1", ItemType: "event", Title: "Reunião", ScheduledAt: time.Date(2026, 3, 9, 10, 0, 0, 0, loc), FlagID: strPtr("flag-work")},
+		{ID: "e2", ItemType: "event", Title: "Dentista", ScheduledAt: time.Date(2026, 3, 9, 15, 0, 0, 0, loc), FlagID: strPtr("flag-health")},
+		{ID: "m1", ItemType: "reminder", Title: "Tomar água", ScheduledAt: time.Date(2026, 3, 9, 9, 0, 0, 0, loc)},
+	}}
+	tasks := &fakeTaskRepo{items: []domain.Task{
+		{Title: "Relatório", Status: domain.TaskStatusOpen, FlagID: strPtr("flag-work"), DueAt: ptrTime(time.Date(2026, 3, 9, 11, 0, 0, 0, loc))},
+		{Title: "Sem data", Status: domain.TaskStatusOpen},
+	}}
+	if digests == nil {
+		digests = &fakeEmailDigestRepo{}
+	}
+	if mail == nil {
+		mail = &fakeMailer{}
+	}
+
+	svc := newTestService(t, withDigests(digests), withRoutines(routines), withAgenda(agenda), withTasks(tasks), withMailer(mail))
+	svc.SetSettingsRepository(&fakeDigestSettingsRepo{settings: settings})
+	return svc
+}
+
+func TestBuildDigestDataHonoursSectionsAndLimits(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	svc := newSettingsTestService(t, &domain.DigestSettings{
+		UserID:   "u1",
+		Sections: []string{domain.DigestSectionTasks, domain.DigestSectionAgenda},
+		MaxItems: 1,
+	}, nil, nil)
+
+	data, err := svc.BuildDigestData(context.Background(), "u1", time.Date(2026, 3, 9, 4, 0, 0, 0, loc))
+	if err != nil {
+		t.Fatalf("build digest data: %v", err)
+	}
+
+	if len(data.Sections) != 2 || data.Sections[0] != domain.DigestSectionTasks || data.Sections[1] != domain.DigestSectionAgenda {
+		t.Fatalf("expected sections in saved order, got %v", data.Sections)
+	}
+	if data.HasSchedule || data.HasReminders || data.HasOpenTasks {
+		t.Fatalf("expected disabled sections to be empty, got schedule=%v reminders=%v openTasks=%v", data.HasSchedule, data.HasReminders, data.HasOpenTasks)
+	}
+	if len(data.Agenda) != 1 || data.Agenda[0].Title != "Reunião" {
+		t.Fatalf("expected agenda truncated to first item, got %+v", data.Agenda)
+	}
+	for _, entry := range data.Calendar {
+		if entry.Kind == "routine" {
+			t.Fatalf("expected no routine calendar entries when schedule is disabled, got %+v", entry)
+		}
+	}
+
+	body, err := svc.RenderSummary(data, SummaryFormatText)
+	if err != nil {
+		t.Fatalf("render text: %v", err)
+	}
+	text := string(body)
+	tasksIdx, agendaIdx := strings.Index(text, "Relatório"), strings.Index(text, "Reunião")
+	if tasksIdx < 0 || agendaIdx < 0 || tasksIdx > agendaIdx {
+		t.Fatalf("expected tasks rendered before agenda, got:\n%s", text)
+	}
+}
+
+func TestBuildDigestDataFiltersFlags(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	target := time.Date(2026, 3, 9, 4, 0, 0, 0, loc)
+
+	svc := newSettingsTestService(t, &domain.DigestSettings{
+		UserID:         "u1",
+		Sections:       domain.DefaultDigestSections,
+		ExcludeFlagIDs: []string{"flag-work"},
+	}, nil, nil)
+	data, err := svc.BuildDigestData(context.Background(), "u1", target)
+	if err != nil {
+		t.Fatalf("build digest data: %v", err)
+	}
+	for _, item := range data.Agenda {
+		if item.Title == "Reunião" {
+			t.Fatalf("expected work event excluded, got %+v", data.Agenda)
+		}
+	}
+	if data.HasTasks {
+		t.Fatalf("expected work task excluded, got %+v", data.Tasks)
+	}
+	if len(data.Schedule) != 2 {
+		t.Fatalf("expected both routines kept, got %+v", data.Schedule)
+	}
+
+	svc = newSettingsTestService(t, &domain.DigestSettings{
+		UserID:         "u1",
+		Sections:       domain.DefaultDigestSections,
+		IncludeFlagIDs: []string{"flag-health"},
+	}, nil, nil)
+	data, err = svc.BuildDigestData(context.Background(), "u1", target)
+	if err != nil {
+		t.Fatalf("build digest data: %v", err)
+	}
+	if len(data.Schedule) != 1 || data.Schedule[0].Title != "Treino" {
+		t.Fatalf("expected only flagged routine, got %+v", data.Schedule)
+	}
+	if len(data.Agenda) != 1 || data.Agenda[0].Title != "Dentista" {
+		t.Fatalf("expected only health event, got %+v", data.Agenda)
+	}
+	if data.HasReminders || data.HasOpenTasks {
+		t.Fatalf("expected unflagged items excluded when include list is set")
+	}
+}
+
+func TestSendDigestSkipsEmptyDay(t *testing.T) {
+	mail := &fakeMailer{}
+	digests := &fakeEmailDigestRepo{createResult: true}
+	svc := newSettingsTestService(t, &domain.DigestSettings{
+		UserID:    "u1",
+		Sections:  []string{domain.DigestSectionShoppingLists},
+		SkipEmpty: true,
+	}, digests, mail)
+
+	err := svc.SendDigest(context.Background(), domain.User{ID: "u1", Email: "u1@example.com"}, time.Date(2026, 3, 9, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("send digest: %v", err)
+	}
+	if mail.sendCalls != 0 {
+		t.Fatalf("expected no e-mail for empty day, got %d sends", mail.sendCalls)
+	}
+	if digests.lastUpdated == nil || digests.lastUpdated.Status != domain.EmailDigestStatusSkipped {
+		t.Fatalf("expected digest marked skipped, got %+v", digests.lastUpdated)
+	}
+}
+
+func TestUpdateSettingsValidates(t *testing.T) {
+	svc := newSettingsTestService(t, nil, nil, nil)
+	ctx := context.Background()
+
+	cases := []domain.DigestSettings{
+		{UserID: "u1", Sections: []string{"weather"}},
+		{UserID: "u1", Sections: []string{domain.DigestSectionTasks, domain.DigestSectionTasks}},
+		{UserID: "u1", Sections: domain.DefaultDigestSections, MaxItems: 51},
+		{UserID: "u1", Sections: domain.DefaultDigestSections, IncludeFlagIDs: []string{"f1"}, ExcludeFlagIDs: []string{"f1"}},
+	}
+	for i, settings := range cases {
+		if _, err := svc.UpdateSettings(ctx, settings); !errors.Is(err, ErrInvalidDigestSettings) {
+			t.Fatalf("case %d: expected ErrInvalidDigestSettings, got %v", i, err)
+		}
+	}
+
+	saved, err := svc.UpdateSettings(ctx, domain.DigestSettings{
+		UserID:         "u1",
+		Sections:       []string{domain.DigestSectionAgenda},
+		MaxItems:       3,
+		IncludeFlagIDs: []string{" f1 ", "f1"},
+	})
+	if err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	if len(saved.IncludeFlagIDs) != 1 || saved.IncludeFlagIDs[0] != "f1" {
+		t.Fatalf("expected flag ids normalized, got %v", saved.IncludeFlagIDs)
+	}
+}
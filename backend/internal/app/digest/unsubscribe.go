@@ -0,0 +1,191 @@
+package digest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/mailer"
+	"inbota/backend/internal/infra/postgres"
+)
+
+const unsubscribePath = "/v1/email/unsubscribe"
+
+var ErrInvalidUnsubscribeToken = errors.New("invalid_unsubscribe_token")
+
+// Listas que aceitam descadastro; o valor vai dentro do token assinado.
+var unsubscribeLists = map[string]string{
+	digestTypeDaily:  "daily",
+	digestTypeWeekly: "weekly",
+}
+
+type unsubscribeConfig struct {
+	baseURL string
+	secret  []byte
+}
+
+// SetUnsubscribe enables List-Unsubscribe headers and links. baseURL is the public API origin
+// (e.g. https://api.inbota.app); without it or the secret, e-mails go out without unsubscribe links.
+func (s *DigestService) SetUnsubscribe(baseURL, secret string) {
+	s.unsubscribe = unsubscribeConfig{
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		secret:  []byte(secret),
+	}
+}
+
+func (s *DigestService) SetSuppressionRepository(repo repository.EmailSuppressionRepository) {
+	s.suppressionRepo = repo
+}
+
+func (s *DigestService) unsubscribeURL(userID, digestType string) string {
+	token := s.UnsubscribeToken(userID, digestType)
+	if token == "" || s.unsubscribe.baseURL == "" {
+		return ""
+	}
+	return s.unsubscribe.baseURL + unsubscribePath + "?token=" + url.QueryEscape(token)
+}
+
+// UnsubscribeToken signs userID and list with HMAC-SHA256. The token does not expire:
+// links in old e-mails must keep working.
+func (s *DigestService) UnsubscribeToken(userID, digestType string) string {
+	list, ok := unsubscribeLists[digestType]
+	if !ok || len(s.unsubscribe.secret) == 0 || strings.TrimSpace(userID) == "" {
+		return ""
+	}
+	payload := userID + ":" + list
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(s.signUnsubscribe(payload))
+}
+
+func (s *DigestService) signUnsubscribe(payload string) []byte {
+	mac := hmac.New(sha256.New, s.unsubscribe.secret)
+	mac.Write([]byte("unsubscribe:" + payload))
+	return mac.Sum(nil)[:16]
+}
+
+func (s *DigestService) parseUnsubscribeToken(token string) (userID, digestType string, err error) {
+	if len(s.unsubscribe.secret) == 0 {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	encodedPayload, encodedSig, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil || !hmac.Equal(sig, s.signUnsubscribe(string(payload))) {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+
+	userID, list, ok := strings.Cut(string(payload), ":")
+	if !ok || userID == "" {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	for digestType, name := range unsubscribeLists {
+		if name == list {
+			return userID, digestType, nil
+		}
+	}
+	return "", "", ErrInvalidUnsubscribeToken
+}
+
+// Unsubscribe disables the digest named in the token. Repeating it is harmless.
+func (s *DigestService) Unsubscribe(ctx context.Context, token string) error {
+	userID, digestType, err := s.parseUnsubscribeToken(token)
+	if err != nil {
+		return err
+	}
+
+	prefs, err := s.notifPrefsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	switch digestType {
+	case digestTypeDaily:
+		if !prefs.DailyDigestEnabled {
+			return nil
+		}
+		prefs.DailyDigestEnabled = false
+	case digestTypeWeekly:
+		if !prefs.WeeklyDigestEnabled {
+			return nil
+		}
+		prefs.WeeklyDigestEnabled = false
+	}
+
+	if err := s.notifPrefsRepo.Upsert(ctx, prefs); err != nil {
+		return err
+	}
+	s.log.Info("digest_unsubscribed", slog.String("user_id", userID), slog.String("type", digestType))
+	return nil
+}
+
+// HandleEmailEvent registra o status do provedor no email_digests e suprime o endereço
+// em hard bounces e reclamações de spam.
+func (s *DigestService) HandleEmailEvent(ctx context.Context, event mailer.EmailEvent) error {
+	if event.Type == "" {
+		return nil
+	}
+
+	if event.ProviderID != "" {
+		at := event.OccurredAt
+		if at.IsZero() {
+			at = s.now().UTC()
+		}
+		if err := s.emailDigestRepo.UpdateProviderStatus(ctx, event.ProviderID, event.Type, at); err != nil {
+			return err
+		}
+	}
+
+	var reason string
+	switch {
+	case event.Type == mailer.EmailEventBounced && event.HardBounce:
+		reason = domain.EmailSuppressionReasonHardBounce
+	case event.Type == mailer.EmailEventComplained:
+		reason = domain.EmailSuppressionReasonComplaint
+	default:
+		return nil
+	}
+
+	if s.suppressionRepo == nil {
+		s.log.Warn("email_suppression_not_configured", slog.String("provider_id", event.ProviderID), slog.String("reason", reason))
+		return nil
+	}
+
+	var providerID, detail *string
+	if event.ProviderID != "" {
+		providerID = &event.ProviderID
+	}
+	if event.Reason != "" {
+		detail = &event.Reason
+	}
+	for _, email := range event.Recipients {
+		if strings.TrimSpace(email) == "" {
+			continue
+		}
+		err := s.suppressionRepo.Suppress(ctx, domain.EmailSuppression{
+			Email:      email,
+			Reason:     reason,
+			ProviderID: providerID,
+			Detail:     detail,
+		})
+		if err != nil {
+			return err
+		}
+		s.log.Info("email_suppressed", slog.String("provider_id", event.ProviderID), slog.String("reason", reason))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/infra/mailer"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeSuppressionRepo struct {
+	suppressed map[string]string
+}
+
+func (f *fakeSuppressionRepo) Suppress(ctx context.Context, suppression domain.EmailSuppression) error {
+	if f.suppressed == nil {
+		f.suppressed = make(map[string]string)
+	}
+	if _, ok := f.suppressed[suppression.Email]; !ok {
+		f.suppressed[suppression.Email] = suppression.Reason
+	}
+	return nil
+}
+
+func (f *fakeSuppressionRepo) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	_, ok := f.suppressed[email]
+	return ok, nil
+}
+
+func newUnsubscribeTestService(t *testing.T, prefs *fakePrefsRepo, digests *fakeEmailDigestRepo, mail mailer.Mailer) *DigestService {
+	t.Helper()
+	svc := newTestService(t, withPrefs(prefs), withDigests(digests), withMailer(mail))
+	svc.SetUnsubscribe("https://api.example.com/", "secret")
+	return svc
+}
+
+func TestUnsubscribeTokenDisablesDigest(t *testing.T) {
+	prefs := &fakePrefsRepo{byUser: map[string]domain.NotificationPreferences{
+		"u1": {UserID: "u1", DailyDigestEnabled: true, WeeklyDigestEnabled: true},
+	}}
+	svc := newUnsubscribeTestService(t, prefs, &fakeEmailDigestRepo{}, &fakeMailer{})
+
+	token := svc.UnsubscribeToken("u1", digestTypeDaily)
+	if token == "" {
+		t.Fatalf("expected token")
+	}
+	if err := svc.Unsubscribe(context.Background(), token[:len(token)-2]+"xx"); !errors.Is(err, ErrInvalidUnsubscribeToken) {
+		t.Fatalf("expected tampered token rejected, got %v", err)
+	}
+
+	other := newUnsubscribeTestService(t, prefs, &fakeEmailDigestRepo{}, &fakeMailer{})
+	other.SetUnsubscribe("https://api.example.com", "another-secret")
+	if err := other.Unsubscribe(context.Background(), token); !errors.Is(err, ErrInvalidUnsubscribeToken) {
+		t.Fatalf("expected token signed with another secret rejected, got %v", err)
+	}
+
+	if err := svc.Unsubscribe(context.Background(), token); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if err := svc.Unsubscribe(context.Background(), token); err != nil {
+		t.Fatalf("repeated unsubscribe: %v", err)
+	}
+	got := prefs.byUser["u1"]
+	if got.DailyDigestEnabled || !got.WeeklyDigestEnabled {
+		t.Fatalf("expected only daily digest disabled, got daily=%v weekly=%v", got.DailyDigestEnabled, got.WeeklyDigestEnabled)
+	}
+	if prefs.upserts != 1 {
+		t.Fatalf("expected one preferences update, got %d", prefs.upserts)
+	}
+}
+
+func TestDigestEmailCarriesListUnsubscribe(t *testing.T) {
+	mail := &fakeMailer{}
+	svc := newUnsubscribeTestService(t, &fakePrefsRepo{}, &fakeEmailDigestRepo{createResult: true}, mail)
+
+	err := svc.SendDigest(context.Background(), domain.User{ID: "u1", Email: "u1@example.com"}, time.Date(2026, 3, 9, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("send digest: %v", err)
+	}
+
+	link := mail.last.Headers["List-Unsubscribe"]
+	if !strings.HasPrefix(link, "<https://api.example.com/v1/email/unsubscribe?token=") {
+		t.Fatalf("unexpected List-Unsubscribe header %q", link)
+	}
+	if mail.last.Headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+		t.Fatalf("expected one-click header, got %+v", mail.last.Headers)
+	}
+	url := strings.Trim(link, "<>")
+	if !strings.Contains(mail.last.Html, strings.ReplaceAll(url, "&", "&amp;")) || !strings.Contains(mail.last.Text, url) {
+		t.Fatalf("expected unsubscribe link in html and text bodies")
+	}
+}
+
+func TestSuppressedRecipientSkipsDigestWithoutRetry(t *testing.T) {
+	digests := &fakeEmailDigestRepo{createResult: true}
+	suppressions := &fakeSuppressionRepo{suppressed: map[string]string{"u1@example.com": domain.EmailSuppressionReasonHardBounce}}
+	mail := &fakeMailer{}
+	svc := newUnsubscribeTestService(t, &fakePrefsRepo{}, digests, mailer.NewSuppressingMailer(mail, suppressions))
+
+	err := svc.SendDigest(context.Background(), domain.User{ID: "u1", Email: "u1@example.com"}, time.Date(2026, 3, 9, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("send digest: %v", err)
+	}
+	if mail.sendCalls != 0 {
+		t.Fatalf("expected suppressed address not to be sent, got %d sends", mail.sendCalls)
+	}
+	if digests.lastUpdated == nil || digests.lastUpdated.Status != domain.EmailDigestStatusSkipped || digests.lastUpdated.NextAttemptAt != nil {
+		t.Fatalf("expected digest skipped without retry, got %+v", digests.lastUpdated)
+	}
+}
+
+func TestHandleEmailEvent(t *testing.T) {
+	digests := &fakeEmailDigestRepo{}
+	suppressions := &fakeSuppressionRepo{}
+	svc := newUnsubscribeTestService(t, &fakePrefsRepo{}, digests, &fakeMailer{})
+	svc.SetSuppressionRepository(suppressions)
+	ctx := context.Background()
+
+	events := []mailer.EmailEvent{
+		{Type: mailer.EmailEventDelivered, ProviderID: "m1", Recipients: []string{"a@example.com"}},
+		{Type: mailer.EmailEventBounced, ProviderID: "m2", Recipients: []string{"b@example.com"}, HardBounce: false},
+		{Type: mailer.EmailEventBounced, ProviderID: "m3", Recipients: []string{"c@example.com"}, HardBounce: true},
+		{Type: mailer.EmailEventComplained, ProviderID: "m4", Recipients: []string{"d@example.com"}},
+	}
+	for _, event := range events {
+		if err := svc.HandleEmailEvent(ctx, event); err != nil {
+			t.Fatalf("handle %s: %v", event.Type, err)
+		}
+	}
+
+	if digests.providerStatus["m1"] != "delivered" || digests.providerStatus["m3"] != "bounced" || digests.providerStatus["m4"] != "complained" {
+		t.Fatalf("unexpected provider statuses %+v", digests.providerStatus)
+	}
+	if len(suppressions.suppressed) != 2 {
+		t.Fatalf("expected hard bounce and complaint suppressed, got %+v", suppressions.suppressed)
+	}
+	if suppressions.suppressed["c@example.com"] != domain.EmailSuppressionReasonHardBounce || suppressions.suppressed["d@example.com"] != domain.EmailSuppressionReasonComplaint {
+		t.Fatalf("unexpected suppression reasons %+v", suppressions.suppressed)
+	}
+}
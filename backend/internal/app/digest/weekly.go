@@ -0,0 +1,439 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/i18n"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/mailer"
+)
+
+const (
+	digestTypeWeekly      = "weekly_digest"
+	maxWeeklyInboxItems   = 10
+	maxWeeklyInboxTextLen = 120
+)
+
+// RoutineStreakReader returns the current streak with the routine's grace misses, freezes and
+// rest days applied.
+type RoutineStreakReader interface {
+	CurrentStreak(ctx context.Context, userID, routineID string) (int, error)
+}
+
+type InboxLister interface {
+	List(ctx context.Context, userID string, filter repository.InboxListFilter, opts repository.ListOptions) ([]domain.InboxItem, *string, error)
+}
+
+type WeekOccurrenceLister interface {
+	ListWeekOccurrences(ctx context.Context, userID string, start, end time.Time) ([]time.Time, error)
+}
+
+// WeeklyDigestSources are the extra repositories read only by the weekly digest.
+// Any of them may be nil; the matching section is then left empty.
+type WeeklyDigestSources struct {
+	Streaks RoutineStreakReader
+	Inbox   InboxLister
+	Home    WeekOccurrenceLister
+}
+
+func (s *DigestService) SetWeeklySources(sources WeeklyDigestSources) {
+	s.weekly = sources
+}
+
+// WeeklyDigestData is the weekly review: the last 7 days (ending on the send date)
+// and a look-ahead over the next 7 days.
+type WeeklyDigestData struct {
+	Locale      string `json:"locale"`
+	PeriodLabel string `json:"periodLabel"`
+	NextLabel   string `json:"nextLabel"`
+
+	HasCompletedTasks bool           `json:"hasCompletedTasks"`
+	CompletedTasks    []TaskItemData `json:"completedTasks"`
+	HasMissedTasks    bool           `json:"hasMissedTasks"`
+	MissedTasks       []TaskItemData `json:"missedTasks"`
+
+	HasRoutines           bool              `json:"hasRoutines"`
+	Routines              []RoutineWeekData `json:"routines"`
+	RoutineCompletionRate int               `json:"routineCompletionRate"`
+
+	HasPendingInbox   bool               `json:"hasPendingInbox"`
+	PendingInbox      []InboxPendingData `json:"pendingInbox"`
+	PendingInboxCount int                `json:"pendingInboxCount"`
+
+	HasNextEvents bool             `json:"hasNextEvents"`
+	NextEvents    []AgendaItemData `json:"nextEvents"`
+	HasNextLoad   bool             `json:"hasNextLoad"`
+	NextLoad      []DayLoadData    `json:"nextLoad"`
+
+	UnsubscribeURL string `json:"-"`
+}
+
+type RoutineWeekData struct {
+	Title         string `json:"title"`
+	Scheduled     int    `json:"scheduled"`
+	Completed     int    `json:"completed"`
+	Rate          int    `json:"rate"` // 0-100
+	CurrentStreak int    `json:"currentStreak"`
+}
+
+type InboxPendingData struct {
+	Text string `json:"text"`
+	Age  string `json:"age"`
+}
+
+type DayLoadData struct {
+	Day      string `json:"day"`
+	Items    int    `json:"items"`
+	Routines int    `json:"routines"`
+}
+
+// BuildWeeklyDigestData monta a revisão da semana terminando em targetDate (inclusive).
+func (s *DigestService) BuildWeeklyDigestData(ctx context.Context, userID string, targetDate time.Time) (WeeklyDigestData, error) {
+	if targetDate.IsZero() {
+		targetDate = s.now()
+	}
+	loc := targetDate.Location()
+	today := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, loc)
+	periodStart := today.AddDate(0, 0, -6)
+	periodEnd := today.AddDate(0, 0, 1)
+	nextEnd := periodEnd.AddDate(0, 0, 7)
+	l := s.userLocalizer(ctx, userID)
+
+	data := WeeklyDigestData{
+		Locale:      l.Locale(),
+		PeriodLabel: weekRangeLabel(l, periodStart, today),
+		NextLabel:   weekRangeLabel(l, periodEnd, nextEnd.AddDate(0, 0, -1)),
+	}
+
+	if err := s.fillWeeklyTasks(ctx, l, userID, &data, periodStart, periodEnd, targetDate); err != nil {
+		return WeeklyDigestData{}, err
+	}
+	if err := s.fillWeeklyRoutines(ctx, userID, &data, periodStart); err != nil {
+		return WeeklyDigestData{}, err
+	}
+	if err := s.fillWeeklyInbox(ctx, l, userID, &data, targetDate); err != nil {
+		return WeeklyDigestData{}, err
+	}
+	if err := s.fillWeeklyLookAhead(ctx, l, userID, &data, periodEnd, nextEnd); err != nil {
+		return WeeklyDigestData{}, err
+	}
+
+	data.HasCompletedTasks = len(data.CompletedTasks) > 0
+	data.HasMissedTasks = len(data.MissedTasks) > 0
+	data.HasRoutines = len(data.Routines) > 0
+	data.HasPendingInbox = len(data.PendingInbox) > 0
+	data.HasNextEvents = len(data.NextEvents) > 0
+	data.HasNextLoad = len(data.NextLoad) > 0
+
+	return data, nil
+}
+
+// fillWeeklyTasks: concluídas = DONE com prazo na semana (ou sem prazo e atualizadas na semana);
+// perdidas = ainda OPEN com prazo vencido dentro da semana.
+func (s *DigestService) fillWeeklyTasks(ctx context.Context, l i18n.Localizer, userID string, data *WeeklyDigestData, start, end, now time.Time) error {
+	tasks, err := s.listAllTasks(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list tasks: %w", err)
+	}
+	loc := start.Location()
+	inPeriod := func(t time.Time) bool {
+		t = t.In(loc)
+		return !t.Before(start) && t.Before(end)
+	}
+
+	for _, t := range tasks {
+		switch {
+		case t.Status == domain.TaskStatusDone && t.DueAt != nil && inPeriod(*t.DueAt):
+			data.CompletedTasks = append(data.CompletedTasks, weeklyTaskItem(l, t, loc))
+		case t.Status == domain.TaskStatusDone && t.DueAt == nil && inPeriod(t.UpdatedAt):
+			data.CompletedTasks = append(data.CompletedTasks, weeklyTaskItem(l, t, loc))
+		case t.Status == domain.TaskStatusOpen && t.DueAt != nil && inPeriod(*t.DueAt) && t.DueAt.Before(now):
+			data.MissedTasks = append(data.MissedTasks, weeklyTaskItem(l, t, loc))
+		}
+	}
+
+	sort.SliceStable(data.MissedTasks, func(i, j int) bool {
+		return data.MissedTasks[i].DueTime < data.MissedTasks[j].DueTime
+	})
+	return nil
+}
+
+func weeklyTaskItem(l i18n.Localizer, t domain.Task, loc *time.Location) TaskItemData {
+	item := TaskItemData{Title: t.Title}
+	if t.DueAt != nil {
+		item.DueTime = t.DueAt.In(loc).Format(l.T("format.day_month_time"))
+	}
+	return item
+}
+
+// fillWeeklyRoutines calcula a taxa de conclusão por rotina nos 7 dias e a sequência atual.
+func (s *DigestService) fillWeeklyRoutines(ctx context.Context, userID string, data *WeeklyDigestData, start time.Time) error {
+	if s.routineLister == nil {
+		return nil
+	}
+
+	byID := make(map[string]*RoutineWeekData)
+	order := make([]string, 0)
+	scheduled, completed := 0, 0
+	for i := 0; i < 7; i++ {
+		day := start.AddDate(0, 0, i)
+		routines, err := s.routineLister.ListByWeekday(ctx, userID, int(day.Weekday()), day.Format("2006-01-02"))
+		if err != nil {
+			return fmt.Errorf("list routines: %w", err)
+		}
+		for _, r := range routines {
+			item, ok := byID[r.ID]
+			if !ok {
+				item = &RoutineWeekData{Title: r.Title}
+				byID[r.ID] = item
+				order = append(order, r.ID)
+			}
+			item.Scheduled++
+			scheduled++
+			if r.IsCompletedToday {
+				item.Completed++
+				completed++
+			}
+		}
+	}
+
+	for _, id := range order {
+		item := byID[id]
+		item.Rate = percent(item.Completed, item.Scheduled)
+		if s.weekly.Streaks != nil {
+			streak, err := s.weekly.Streaks.CurrentStreak(ctx, userID, id)
+			if err != nil {
+				s.log.Warn("weekly_digest_streak_failed", slog.String("user_id", userID), slog.String("routine_id", id), slog.String("error", err.Error()))
+			} else {
+				item.CurrentStreak = streak
+			}
+		}
+		data.Routines = append(data.Routines, *item)
+	}
+	sort.SliceStable(data.Routines, func(i, j int) bool {
+		return data.Routines[i].Rate > data.Routines[j].Rate
+	})
+	data.RoutineCompletionRate = percent(completed, scheduled)
+	return nil
+}
+
+// fillWeeklyInbox lista itens do inbox que ainda aguardam revisão do usuário.
+func (s *DigestService) fillWeeklyInbox(ctx context.Context, l i18n.Localizer, userID string, data *WeeklyDigestData, now time.Time) error {
+	if s.weekly.Inbox == nil {
+		return nil
+	}
+
+	pending := make([]domain.InboxItem, 0)
+	for _, status := range []domain.InboxStatus{domain.InboxStatusNeedsReview, domain.InboxStatusSuggested} {
+		status := status
+		items, _, err := s.weekly.Inbox.List(ctx, userID, repository.InboxListFilter{Status: &status}, repository.ListOptions{Limit: maxDigestPageSize})
+		if err != nil {
+			return fmt.Errorf("list inbox: %w", err)
+		}
+		pending = append(pending, items...)
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+
+	data.PendingInboxCount = len(pending)
+	for i, item := range pending {
+		if i >= maxWeeklyInboxItems {
+			break
+		}
+		data.PendingInbox = append(data.PendingInbox, InboxPendingData{
+			Text: truncateText(item.RawText, maxWeeklyInboxTextLen),
+			Age:  ageLabel(l, now.Sub(item.CreatedAt)),
+		})
+	}
+	return nil
+}
+
+// fillWeeklyLookAhead lista os eventos dos próximos 7 dias e a carga por dia
+// (mesma base do buildWeekDensity da home, mais as rotinas previstas).
+func (s *DigestService) fillWeeklyLookAhead(ctx context.Context, l i18n.Localizer, userID string, data *WeeklyDigestData, start, end time.Time) error {
+	loc := start.Location()
+
+	agendaItems, err := s.agendaRepo.List(ctx, userID, repository.ListOptions{
+		Limit:   maxDigestPageSize,
+		StartAt: &start,
+		EndAt:   &end,
+	})
+	if err != nil {
+		return fmt.Errorf("list agenda: %w", err)
+	}
+	for _, item := range agendaItems {
+		if item.ItemType != "event" {
+			continue
+		}
+		scheduledAt := item.ScheduledAt.In(loc)
+		if scheduledAt.Before(start) || !scheduledAt.Before(end) {
+			continue
+		}
+		typeLabel, typeKey := agendaType(l, item.ItemType)
+		data.NextEvents = append(data.NextEvents, AgendaItemData{
+			Time:    dayLabel(l, scheduledAt) + " " + agendaTimeLabel(l, item, scheduledAt),
+			Type:    typeLabel,
+			TypeKey: typeKey,
+			Title:   item.Title,
+			Context: contextPath(item.FlagName, item.SubflagName),
+		})
+	}
+
+	itemsByDay := make(map[string]int, 7)
+	if s.weekly.Home != nil {
+		occurrences, err := s.weekly.Home.ListWeekOccurrences(ctx, userID, start.UTC(), end.UTC())
+		if err != nil {
+			return fmt.Errorf("list week occurrences: %w", err)
+		}
+		for _, at := range occurrences {
+			itemsByDay[at.In(loc).Format("2006-01-02")]++
+		}
+	}
+
+	for i := 0; i < 7; i++ {
+		day := start.AddDate(0, 0, i)
+		load := DayLoadData{
+			Day:   dayLabel(l, day),
+			Items: itemsByDay[day.Format("2006-01-02")],
+		}
+		if s.routineLister != nil {
+			routines, err := s.routineLister.ListByWeekday(ctx, userID, int(day.Weekday()), day.Format("2006-01-02"))
+			if err != nil {
+				return fmt.Errorf("list routines: %w", err)
+			}
+			load.Routines = len(routines)
+		}
+		data.NextLoad = append(data.NextLoad, load)
+	}
+	return nil
+}
+
+func (s *DigestService) processWeeklyDigests(ctx context.Context, nowUTC time.Time) {
+	prefs, err := s.notifPrefsRepo.ListWeeklyDigestEnabled(ctx)
+	if err != nil {
+		s.log.Error("weekly_digest_list_failed", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, p := range prefs {
+		if p.WeeklyDigestWeekday < 0 || p.WeeklyDigestWeekday > 6 || p.WeeklyDigestHour < 0 || p.WeeklyDigestHour > 23 {
+			s.log.Warn("invalid_weekly_digest_schedule", slog.String("user_id", p.UserID), slog.Int("weekday", p.WeeklyDigestWeekday), slog.Int("hour", p.WeeklyDigestHour))
+			continue
+		}
+
+		user, err := s.userRepo.Get(ctx, p.UserID)
+		if err != nil {
+			s.log.Error("digest_user_load_failed", slog.String("user_id", p.UserID), slog.String("error", err.Error()))
+			continue
+		}
+
+		tz, err := time.LoadLocation(user.Timezone)
+		if err != nil {
+			s.log.Warn("digest_invalid_user_timezone", slog.String("user_id", user.ID), slog.String("timezone", user.Timezone))
+			tz = time.UTC
+		}
+
+		userNow := nowUTC.In(tz)
+		if int(userNow.Weekday()) != p.WeeklyDigestWeekday || userNow.Hour() < p.WeeklyDigestHour {
+			continue
+		}
+
+		if err := s.SendWeeklyDigest(ctx, user, userNow); err != nil {
+			s.log.Error("weekly_digest_send_failed", slog.String("user_id", user.ID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (s *DigestService) SendWeeklyDigest(ctx context.Context, user domain.User, date time.Time) error {
+	return s.sendWeeklyDigest(ctx, user, date, true)
+}
+
+func (s *DigestService) SendTestWeeklyDigestForUserID(ctx context.Context, userID string) error {
+	if strings.TrimSpace(userID) == "" {
+		return fmt.Errorf("user id is empty")
+	}
+
+	user, err := s.userRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tz, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		tz = time.UTC
+	}
+
+	return s.sendWeeklyDigest(ctx, user, s.now().In(tz), false)
+}
+
+func (s *DigestService) sendWeeklyDigest(ctx context.Context, user domain.User, date time.Time, trackDelivery bool) error {
+	if date.IsZero() {
+		date = s.now()
+	}
+	return s.deliver(ctx, user, date, digestTypeWeekly, trackDelivery, func() (mailer.SendRequest, error) {
+		if trackDelivery && s.isAway(ctx, user.ID, date) {
+			return mailer.SendRequest{}, errUserAway
+		}
+		data, err := s.BuildWeeklyDigestData(ctx, user.ID, date)
+		if err != nil {
+			return mailer.SendRequest{}, err
+		}
+		data.UnsubscribeURL = s.unsubscribeURL(user.ID, digestTypeWeekly)
+
+		var html bytes.Buffer
+		if err := s.weeklyHTML.Execute(&html, data); err != nil {
+			return mailer.SendRequest{}, fmt.Errorf("render weekly digest html: %w", err)
+		}
+		var text bytes.Buffer
+		if err := s.weeklyText.Execute(&text, data); err != nil {
+			return mailer.SendRequest{}, fmt.Errorf("render weekly digest text: %w", err)
+		}
+
+		return mailer.SendRequest{
+			Subject: i18n.For(data.Locale).T("weekly.subject", i18n.Vars{"period": data.PeriodLabel}),
+			Html:    html.String(),
+			Text:    text.String(),
+		}, nil
+	})
+}
+
+func percent(part, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return part * 100 / total
+}
+
+func truncateText(value string, max int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return strings.TrimSpace(string(runes[:max])) + "…"
+}
+
+func ageLabel(l i18n.Localizer, d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days <= 0 {
+		return l.T("weekly.age.today")
+	}
+	return l.N("weekly.age.days", days)
+}
+
+func weekRangeLabel(l i18n.Localizer, from, to time.Time) string {
+	return l.T("weekly.range", i18n.Vars{"from": l.DayMonth(from), "to": l.DayMonth(to)})
+}
+
+// dayLabel formats a day as "Seg 16/03" / "Mon 03/16".
+func dayLabel(l i18n.Localizer, day time.Time) string {
+	return l.WeekdayShort(day.Weekday()) + " " + l.DayMonth(day)
+}
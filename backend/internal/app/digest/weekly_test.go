@@ -0,0 +1,130 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+)
+
+type fakeStreakReader struct {
+	streaks map[string]int
+}
+
+func (f *fakeStreakReader) CurrentStreak(ctx context.Context, userID, routineID string) (int, error) {
+	return f.streaks[routineID], nil
+}
+
+type fakeInboxRepo struct {
+	itemsByStatus map[domain.InboxStatus][]domain.InboxItem
+}
+
+func (f *fakeInboxRepo) List(ctx context.Context, userID string, filter repository.InboxListFilter, opts repository.ListOptions) ([]domain.InboxItem, *string, error) {
+	if filter.Status == nil {
+		return nil, nil, nil
+	}
+	return f.itemsByStatus[*filter.Status], nil, nil
+}
+
+type fakeHomeRepo struct {
+	occurrences []time.Time
+}
+
+func (f *fakeHomeRepo) ListWeekOccurrences(ctx context.Context, userID string, start, end time.Time) ([]time.Time, error) {
+	return f.occurrences, nil
+}
+
+func TestBuildWeeklyDigestData(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	target := time.Date(2026, 3, 15, 18, 0, 0, 0, loc) // domingo
+
+	routines := &fakeRoutineLister{items: []domain.Routine{
+		{ID: "r-done", Title: "Treino", IsCompletedToday: true},
+		{ID: "r-missed", Title: "Leitura"},
+	}}
+	tasks := &fakeTaskRepo{items: []domain.Task{
+		{Title: "Feita", Status: domain.TaskStatusDone, DueAt: ptrTime(time.Date(2026, 3, 10, 9, 0, 0, 0, loc))},
+		{Title: "Feita sem prazo", Status: domain.TaskStatusDone, UpdatedAt: time.Date(2026, 3, 14, 9, 0, 0, 0, loc)},
+		{Title: "Perdida", Status: domain.TaskStatusOpen, DueAt: ptrTime(time.Date(2026, 3, 12, 9, 0, 0, 0, loc))},
+		{Title: "Futura", Status: domain.TaskStatusOpen, DueAt: ptrTime(time.Date(2026, 3, 17, 9, 0, 0, 0, loc))},
+		{Title: "Antiga", Status: domain.TaskStatusDone, DueAt: ptrTime(time.Date(2026, 3, 1, 9, 0, 0, 0, loc))},
+	}}
+	agenda := &fakeAgendaRepo{items: []repository.AgendaItem{
+		{ItemType: "event", Title: "Consulta", ScheduledAt: time.Date(2026, 3, 17, 14, 0, 0, 0, loc)},
+		{ItemType: "reminder", Title: "Pagar conta", ScheduledAt: time.Date(2026, 3, 17, 9, 0, 0, 0, loc)},
+	}}
+
+	svc := newTestService(t, withRoutines(routines), withAgenda(agenda), withTasks(tasks))
+	svc.SetWeeklySources(WeeklyDigestSources{
+		Streaks: &fakeStreakReader{streaks: map[string]int{"r-done": 12}},
+		Inbox: &fakeInboxRepo{itemsByStatus: map[domain.InboxStatus][]domain.InboxItem{
+			domain.InboxStatusNeedsReview: {{RawText: "comprar   presente", CreatedAt: time.Date(2026, 3, 12, 9, 0, 0, 0, loc)}},
+			domain.InboxStatusSuggested:   {{RawText: "ligar pro banco", CreatedAt: time.Date(2026, 3, 15, 9, 0, 0, 0, loc)}},
+		}},
+		Home: &fakeHomeRepo{occurrences: []time.Time{
+			time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 16, 15, 0, 0, 0, time.UTC),
+		}},
+	})
+
+	data, err := svc.BuildWeeklyDigestData(context.Background(), "u1", target)
+	if err != nil {
+		t.Fatalf("build weekly digest data: %v", err)
+	}
+
+	if data.PeriodLabel != "09/03 a 15/03" || data.NextLabel != "16/03 a 22/03" {
+		t.Fatalf("unexpected period labels: %q / %q", data.PeriodLabel, data.NextLabel)
+	}
+	if len(data.CompletedTasks) != 2 || len(data.MissedTasks) != 1 || data.MissedTasks[0].Title != "Perdida" {
+		t.Fatalf("expected 2 completed and 1 missed task, got %+v / %+v", data.CompletedTasks, data.MissedTasks)
+	}
+	if len(data.Routines) != 2 || data.RoutineCompletionRate != 50 {
+		t.Fatalf("expected 2 routines at 50%%, got %+v rate=%d", data.Routines, data.RoutineCompletionRate)
+	}
+	if data.Routines[0].Title != "Treino" || data.Routines[0].Rate != 100 || data.Routines[0].Scheduled != 7 || data.Routines[0].CurrentStreak != 12 {
+		t.Fatalf("unexpected routine stats: %+v", data.Routines[0])
+	}
+	if data.PendingInboxCount != 2 || data.PendingInbox[0].Text != "comprar presente" || data.PendingInbox[0].Age != "há 3 dias" {
+		t.Fatalf("unexpected pending inbox: %+v", data.PendingInbox)
+	}
+	if len(data.NextEvents) != 1 || data.NextEvents[0].Title != "Consulta" {
+		t.Fatalf("expected only next week's event, got %+v", data.NextEvents)
+	}
+	if len(data.NextLoad) != 7 || data.NextLoad[0].Items != 2 || data.NextLoad[0].Routines != 2 {
+		t.Fatalf("unexpected next week load: %+v", data.NextLoad)
+	}
+}
+
+func TestProcessWeeklyDigestsRespectsWeekdayAndHour(t *testing.T) {
+	mail := &fakeMailer{}
+	digests := &fakeEmailDigestRepo{createResult: true}
+	prefs := &fakePrefsRepo{weeklyPrefs: []domain.NotificationPreferences{
+		{UserID: "u-due", WeeklyDigestEnabled: true, WeeklyDigestWeekday: 0, WeeklyDigestHour: 18},
+		{UserID: "u-early", WeeklyDigestEnabled: true, WeeklyDigestWeekday: 0, WeeklyDigestHour: 21},
+		{UserID: "u-monday", WeeklyDigestEnabled: true, WeeklyDigestWeekday: 1, WeeklyDigestHour: 8},
+	}}
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"u-due":    {ID: "u-due", Email: "due@example.com", Timezone: "America/Sao_Paulo"},
+		"u-early":  {ID: "u-early", Email: "early@example.com", Timezone: "America/Sao_Paulo"},
+		"u-monday": {ID: "u-monday", Email: "monday@example.com", Timezone: "America/Sao_Paulo"},
+	}}
+
+	svc := newTestService(t, withUsers(users), withPrefs(prefs), withDigests(digests), withMailer(mail))
+
+	svc.SetNow(func() time.Time {
+		return time.Date(2026, 3, 15, 22, 0, 0, 0, time.UTC) // domingo 19:00 em America/Sao_Paulo
+	})
+
+	if err := svc.ProcessPendingDigests(context.Background()); err != nil {
+		t.Fatalf("process pending digests: %v", err)
+	}
+
+	if mail.sendCalls != 1 {
+		t.Fatalf("expected one weekly digest send, got %d", mail.sendCalls)
+	}
+	if digests.lastUpdated == nil || digests.lastUpdated.Type != digestTypeWeekly || digests.lastUpdated.Status != domain.EmailDigestStatusSuccess {
+		t.Fatalf("expected successful weekly digest tracking, got %+v", digests.lastUpdated)
+	}
+}
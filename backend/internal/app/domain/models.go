@@ -42,6 +42,20 @@ const (
 	TaskStatusDone TaskStatus = "DONE"
 )
 
+// TaskPriority follows the Eisenhower quadrants: P1 urgent and important, P2 important,
+// P3 urgent, P4 neither.
+type TaskPriority string
+
+const (
+	TaskPriorityP1 TaskPriority = "P1"
+	TaskPriorityP2 TaskPriority = "P2"
+	TaskPriorityP3 TaskPriority = "P3"
+	TaskPriorityP4 TaskPriority = "P4"
+)
+
+// MaxTaskEffortMins caps task effort estimates at one week of work, for the API and the AI alike.
+const MaxTaskEffortMins = 7 * 24 * 60
+
 type ReminderStatus string
 
 const (
@@ -57,15 +71,24 @@ const (
 	ShoppingListStatusArchived ShoppingListStatus = "ARCHIVED"
 )
 
+type ProjectStatus string
+
+const (
+	ProjectStatusOpen     ProjectStatus = "OPEN"
+	ProjectStatusDone     ProjectStatus = "DONE"
+	ProjectStatusArchived ProjectStatus = "ARCHIVED"
+)
+
 type User struct {
-	ID          string
-	Email       string
-	DisplayName string
-	Password    string
-	Locale      string
-	Timezone    string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID              string
+	Email           string
+	DisplayName     string
+	Password        string
+	Locale          string
+	Timezone        string
+	HolidayCalendar string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 type Flag struct {
@@ -134,10 +157,58 @@ type Task struct {
 	FlagID            *string
 	SubflagID         *string
 	SourceInboxItemID *string
+	ProjectID         *string
+	Priority          *TaskPriority
+	EffortMins        *int
+	Recurrence        *TaskRecurrence
+	SeriesID          *string // first task of a recurring series; nil until the task repeats
+	BlockedBy         []string
+	Blocked           bool // some task in BlockedBy is not done yet; computed on read
+	Notification      NotificationOverride
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
 
+// TaskRecurrence repeats a task when it is completed: Rule follows a fixed RRULE schedule,
+// AfterDays schedules the next instance N days after the completion. Only one is set.
+type TaskRecurrence struct {
+	Rule      *string
+	AfterDays *int
+}
+
+// MaxTaskRecurrenceAfterDays caps "N days after completion" at ten years, for the API and the AI alike.
+const MaxTaskRecurrenceAfterDays = 3650
+
+type Project struct {
+	ID          string
+	UserID      string
+	Name        string
+	Description *string
+	Status      ProjectStatus
+	TargetDate  *string // YYYY-MM-DD
+	FlagID      *string
+	SubflagID   *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ProjectProgress counts the tasks of a project by status.
+type ProjectProgress struct {
+	Done  int
+	Total int
+}
+
+type TaskChecklistItem struct {
+	ID        string
+	UserID    string
+	TaskID    string
+	Title     string
+	Checked   bool
+	SortOrder int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 type Reminder struct {
 	ID                string
 	UserID            string
@@ -147,6 +218,8 @@ type Reminder struct {
 	FlagID            *string
 	SubflagID         *string
 	SourceInboxItemID *string
+	Notification      NotificationOverride
+	Escalation        ReminderEscalation
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
@@ -162,6 +235,7 @@ type Event struct {
 	FlagID            *string
 	SubflagID         *string
 	SourceInboxItemID *string
+	Notification      NotificationOverride
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
@@ -198,18 +272,33 @@ type Routine struct {
 	StartTime         string
 	EndTime           string
 	WeekOfMonth       *int
+	RRule             *string
+	TargetKind        *string
+	TargetValue       *float64
+	TargetUnit        *string
 	StartsOn          string
 	EndsOn            *string
 	Color             *string
 	IsActive          bool
 	IsCompletedToday  bool
+	ProgressToday     float64
 	FlagID            *string
 	SubflagID         *string
 	SourceInboxItemID *string
+	Notification      NotificationOverride
+	StreakProtection  RoutineStreakProtection
+	SkipHolidays      bool
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
 
+// RoutineStreakProtection evita que uma falta zere a sequência: GracePerMonth faltas perdoadas
+// por mês e um congelamento ganho a cada FreezeEvery dias seguidos feitos (0 = desligado).
+type RoutineStreakProtection struct {
+	GracePerMonth int
+	FreezeEvery   int
+}
+
 type RoutineException struct {
 	ID            string
 	RoutineID     string
@@ -221,17 +310,39 @@ type RoutineException struct {
 	CreatedAt     time.Time
 }
 
+// AwayPeriod is a vacation-mode range (inclusive dates in the user's timezone). FlagID limits
+// the pause to the routines and tasks of that flag; nil pauses the whole account.
+type AwayPeriod struct {
+	ID        string
+	UserID    string
+	StartsOn  string
+	EndsOn    string
+	FlagID    *string
+	Reason    *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 type RoutineCompletion struct {
 	ID          string
 	RoutineID   string
 	CompletedOn string
+	Value       float64
 	CompletedAt time.Time
 }
 
+// Metas quantitativas de rotina. Sem meta, a rotina é binária (feita ou não).
+const (
+	RoutineTargetCount    = "count"
+	RoutineTargetDuration = "duration"
+	RoutineTargetAmount   = "amount"
+)
+
 type NotificationTemplate struct {
 	ID            string
 	Type          NotificationType
 	TriggerKey    string
+	Locale        string
 	TitleTemplate string
 	BodyTemplate  string
 	IsActive      bool
@@ -249,9 +360,15 @@ const (
 type RoutineActivityDay struct {
 	Date         string
 	IsCompleted  bool
+	IsPartial    bool
+	Progress     float64
 	IsScheduled  bool
 	IsToday      bool
 	IsSkipped    bool
+	IsRest       bool
+	IsFrozen     bool
+	IsAway       bool
+	IsHoliday    bool
 	WeekdayLabel string
 }
 
@@ -263,6 +380,7 @@ const (
 	NotificationStatusFailed    NotificationStatus = "failed"
 	NotificationStatusDelivered NotificationStatus = "delivered"
 	NotificationStatusRead      NotificationStatus = "read"
+	NotificationStatusCancelled NotificationStatus = "cancelled"
 )
 
 type NotificationType string
@@ -272,8 +390,14 @@ const (
 	NotificationTypeEvent    NotificationType = "event"
 	NotificationTypeTask     NotificationType = "task"
 	NotificationTypeRoutine  NotificationType = "routine"
+	NotificationTypeBriefing NotificationType = "briefing"
+	NotificationTypeReview   NotificationType = "review"
 )
 
+// TaskUnblockedLeadMins marks the "task unblocked" notification in notification_log. Tasks never
+// use negative lead minutes otherwise, so the unique index keeps one notice per task.
+const TaskUnblockedLeadMins = -1
+
 type DeviceToken struct {
 	ID         string
 	UserID     string
@@ -285,6 +409,48 @@ type DeviceToken struct {
 	IsActive   bool
 	LastSeenAt time.Time
 	CreatedAt  time.Time
+
+	// EncryptionKey vem do PushTopic do usuário (nil para tópicos antigos por dispositivo).
+	EncryptionKey *string
+}
+
+// PushTopic is the server-managed ntfy topic shared by all devices of a user.
+type PushTopic struct {
+	UserID        string
+	Topic         string
+	EncryptionKey string
+	CreatedAt     time.Time
+	RotatedAt     *time.Time
+}
+
+// Seções do digest diário, na ordem padrão.
+const (
+	DigestSectionSchedule      = "schedule"
+	DigestSectionAgenda        = "agenda"
+	DigestSectionReminders     = "reminders"
+	DigestSectionTasks         = "tasks"
+	DigestSectionOpenTasks     = "openTasks"
+	DigestSectionShoppingLists = "shoppingLists"
+)
+
+var DefaultDigestSections = []string{
+	DigestSectionSchedule,
+	DigestSectionAgenda,
+	DigestSectionReminders,
+	DigestSectionTasks,
+	DigestSectionOpenTasks,
+	DigestSectionShoppingLists,
+}
+
+// DigestSettings customizes the daily digest: Sections lists the included sections in display order.
+type DigestSettings struct {
+	UserID         string
+	Sections       []string
+	MaxItems       int // por seção; 0 = sem limite
+	IncludeFlagIDs []string
+	ExcludeFlagIDs []string
+	SkipEmpty      bool
+	UpdatedAt      time.Time
 }
 
 type NotificationPreferences struct {
@@ -305,21 +471,58 @@ type NotificationPreferences struct {
 	QuietHoursEnabled bool
 	QuietStart        *string // format HH:MM
 	QuietEnd          *string // format HH:MM
+	// BundleWindowMins collapses notifications due within this many minutes into one push (0 = off).
+	BundleWindowMins int
+
+	// Push briefings built from the daily digest data, at the user's local time.
+	MorningBriefingEnabled bool
+	MorningBriefingTime    string // format HH:MM
+	EveningReviewEnabled   bool
+	EveningReviewTime      string // format HH:MM
 
 	DailyDigestEnabled bool
 	DailyDigestHour    int
 	DailySummaryToken  string
 
+	// Weekly review e-mail, sent on WeeklyDigestWeekday (0 = Sunday) from WeeklyDigestHour on.
+	WeeklyDigestEnabled bool
+	WeeklyDigestWeekday int
+	WeeklyDigestHour    int
+
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
 
+// NotificationOverride replaces the user's NotificationPreferences for a single item.
+// LeadMins nil keeps the defaults; a 0 inside LeadMins means "at time".
+type NotificationOverride struct {
+	LeadMins []int
+	Disabled bool
+}
+
+// MaxNotificationLeadMins caps per-item lead times at one week, for the API and the AI alike.
+const MaxNotificationLeadMins = 7 * 24 * 60
+
+// MaxRoutineLeadMins caps routine lead times at one day: the scheduler only expands today's and
+// tomorrow's occurrences.
+const MaxRoutineLeadMins = 24 * 60
+
+// ReminderEscalation ("nag" mode) re-sends a reminder every IntervalMins, up to MaxRepeats,
+// until it is DONE or one of its notifications is read. From repeat EmailFromRepeat on
+// (0 = never) the repeats go by e-mail instead of push. IntervalMins 0 disables it.
+type ReminderEscalation struct {
+	IntervalMins    int
+	MaxRepeats      int
+	EmailFromRepeat int
+}
+
 type EmailDigestStatus string
 
 const (
 	EmailDigestStatusPending EmailDigestStatus = "pending"
 	EmailDigestStatusSuccess EmailDigestStatus = "success"
 	EmailDigestStatusFailed  EmailDigestStatus = "failed"
+	EmailDigestStatusSkipped EmailDigestStatus = "skipped"
 )
 
 type EmailDigest struct {
@@ -331,8 +534,28 @@ type EmailDigest struct {
 	SentAt     *time.Time
 	ErrorMsg   *string
 	ProviderID *string
+	// Attempts counts send attempts; NextAttemptAt is set while a failed digest may still be retried.
+	Attempts      int
+	NextAttemptAt *time.Time
+	// ProviderStatus is the last delivery event reported by the provider webhook (delivered, bounced, ...).
+	ProviderStatus   *string
+	ProviderStatusAt *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+const (
+	EmailSuppressionReasonHardBounce = "hard_bounce"
+	EmailSuppressionReasonComplaint  = "complaint"
+)
+
+// EmailSuppression marks an address that must not receive e-mail anymore.
+type EmailSuppression struct {
+	Email      string
+	Reason     string
+	ProviderID *string
+	Detail     *string
 	CreatedAt  time.Time
-	UpdatedAt  time.Time
 }
 
 type NotificationLog struct {
@@ -348,5 +571,14 @@ type NotificationLog struct {
 	SentAt       *time.Time
 	ReadAt       *time.Time
 	ErrorMsg     *string
+	BundleID     *string
+	Channel      NotificationChannel
 	CreatedAt    time.Time
 }
+
+type NotificationChannel string
+
+const (
+	NotificationChannelPush  NotificationChannel = "push"
+	NotificationChannelEmail NotificationChannel = "email"
+)
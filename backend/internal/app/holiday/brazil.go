@@ -0,0 +1,269 @@
+package holiday
+
+import (
+	"sort"
+	"time"
+)
+
+// fixedHoliday falls on the same day every year, from the year Since on (0 = always).
+type fixedHoliday struct {
+	Month time.Month
+	Day   int
+	Name  string
+	Since int
+}
+
+// easterHoliday falls Offset days after Easter Sunday.
+type easterHoliday struct {
+	Offset int
+	Name   string
+}
+
+var brNational = []fixedHoliday{
+	{Month: time.January, Day: 1, Name: "Confraternização Universal"},
+	{Month: time.April, Day: 21, Name: "Tiradentes"},
+	{Month: time.May, Day: 1, Name: "Dia do Trabalho"},
+	{Month: time.September, Day: 7, Name: "Independência do Brasil"},
+	{Month: time.October, Day: 12, Name: "Nossa Senhora Aparecida"},
+	{Month: time.November, Day: 2, Name: "Finados"},
+	{Month: time.November, Day: 15, Name: "Proclamação da República"},
+	{Month: time.November, Day: 20, Name: "Dia Nacional de Zumbi e da Consciência Negra", Since: 2024},
+	{Month: time.December, Day: 25, Name: "Natal"},
+}
+
+// Carnaval e Corpus Christi são pontos facultativos federais, mas param o comércio e os
+// serviços na maior parte do país, então contam como feriado.
+var brNationalEaster = []easterHoliday{
+	{Offset: -48, Name: "Carnaval"},
+	{Offset: -47, Name: "Carnaval"},
+	{Offset: -2, Name: "Sexta-feira Santa"},
+	{Offset: 60, Name: "Corpus Christi"},
+}
+
+type brState struct {
+	Name     string
+	Holidays []fixedHoliday
+}
+
+// brStates lista as 27 UFs; as que só seguem o calendário nacional ficam sem feriados próprios.
+var brStates = map[string]brState{
+	"AC": {Name: "Acre", Holidays: []fixedHoliday{
+		{Month: time.January, Day: 23, Name: "Dia do Evangélico"},
+		{Month: time.June, Day: 15, Name: "Aniversário do Acre"},
+		{Month: time.September, Day: 5, Name: "Dia da Amazônia"},
+		{Month: time.November, Day: 17, Name: "Tratado de Petrópolis"},
+	}},
+	"AL": {Name: "Alagoas", Holidays: []fixedHoliday{
+		{Month: time.June, Day: 24, Name: "São João"},
+		{Month: time.June, Day: 29, Name: "São Pedro"},
+		{Month: time.September, Day: 16, Name: "Emancipação Política de Alagoas"},
+	}},
+	"AM": {Name: "Amazonas", Holidays: []fixedHoliday{
+		{Month: time.September, Day: 5, Name: "Elevação do Amazonas à Categoria de Província"},
+	}},
+	"AP": {Name: "Amapá", Holidays: []fixedHoliday{
+		{Month: time.March, Day: 19, Name: "São José"},
+		{Month: time.October, Day: 5, Name: "Criação do Estado do Amapá"},
+	}},
+	"BA": {Name: "Bahia", Holidays: []fixedHoliday{
+		{Month: time.July, Day: 2, Name: "Independência da Bahia"},
+	}},
+	"CE": {Name: "Ceará", Holidays: []fixedHoliday{
+		{Month: time.March, Day: 19, Name: "São José"},
+		{Month: time.March, Day: 25, Name: "Data Magna do Ceará"},
+	}},
+	"DF": {Name: "Distrito Federal", Holidays: []fixedHoliday{
+		{Month: time.November, Day: 30, Name: "Dia do Evangélico"},
+	}},
+	"ES": {Name: "Espírito Santo"},
+	"GO": {Name: "Goiás"},
+	"MA": {Name: "Maranhão", Holidays: []fixedHoliday{
+		{Month: time.July, Day: 28, Name: "Adesão do Maranhão à Independência"},
+	}},
+	"MG": {Name: "Minas Gerais"},
+	"MS": {Name: "Mato Grosso do Sul", Holidays: []fixedHoliday{
+		{Month: time.October, Day: 11, Name: "Criação do Estado de Mato Grosso do Sul"},
+	}},
+	"MT": {Name: "Mato Grosso"},
+	"PA": {Name: "Pará", Holidays: []fixedHoliday{
+		{Month: time.August, Day: 15, Name: "Adesão do Pará à Independência"},
+	}},
+	"PB": {Name: "Paraíba", Holidays: []fixedHoliday{
+		{Month: time.August, Day: 5, Name: "Fundação do Estado da Paraíba"},
+	}},
+	"PE": {Name: "Pernambuco", Holidays: []fixedHoliday{
+		{Month: time.March, Day: 6, Name: "Revolução Pernambucana"},
+	}},
+	"PI": {Name: "Piauí", Holidays: []fixedHoliday{
+		{Month: time.October, Day: 19, Name: "Dia do Piauí"},
+	}},
+	"PR": {Name: "Paraná", Holidays: []fixedHoliday{
+		{Month: time.December, Day: 19, Name: "Emancipação Política do Paraná"},
+	}},
+	"RJ": {Name: "Rio de Janeiro", Holidays: []fixedHoliday{
+		{Month: time.April, Day: 23, Name: "São Jorge"},
+	}},
+	"RN": {Name: "Rio Grande do Norte", Holidays: []fixedHoliday{
+		{Month: time.October, Day: 3, Name: "Mártires de Cunhaú e Uruaçu"},
+	}},
+	"RO": {Name: "Rondônia", Holidays: []fixedHoliday{
+		{Month: time.January, Day: 4, Name: "Criação do Estado de Rondônia"},
+		{Month: time.June, Day: 18, Name: "Dia do Evangélico"},
+	}},
+	"RR": {Name: "Roraima", Holidays: []fixedHoliday{
+		{Month: time.October, Day: 5, Name: "Criação do Estado de Roraima"},
+	}},
+	"RS": {Name: "Rio Grande do Sul", Holidays: []fixedHoliday{
+		{Month: time.September, Day: 20, Name: "Revolução Farroupilha"},
+	}},
+	"SC": {Name: "Santa Catarina"},
+	"SE": {Name: "Sergipe", Holidays: []fixedHoliday{
+		{Month: time.July, Day: 8, Name: "Emancipação Política de Sergipe"},
+	}},
+	"SP": {Name: "São Paulo", Holidays: []fixedHoliday{
+		{Month: time.July, Day: 9, Name: "Revolução Constitucionalista"},
+	}},
+	"TO": {Name: "Tocantins", Holidays: []fixedHoliday{
+		{Month: time.October, Day: 5, Name: "Criação do Estado do Tocantins"},
+	}},
+}
+
+type brCity struct {
+	State    string
+	Slug     string
+	Name     string
+	Holidays []fixedHoliday
+	Easter   []easterHoliday
+}
+
+// brCities cobre as capitais com feriados municipais próprios.
+var brCities = []brCity{
+	{State: "BA", Slug: "SALVADOR", Name: "Salvador", Holidays: []fixedHoliday{
+		{Month: time.June, Day: 24, Name: "São João"},
+		{Month: time.December, Day: 8, Name: "Nossa Senhora da Conceição da Praia"},
+	}},
+	{State: "CE", Slug: "FORTALEZA", Name: "Fortaleza", Holidays: []fixedHoliday{
+		{Month: time.April, Day: 13, Name: "Aniversário de Fortaleza"},
+		{Month: time.August, Day: 15, Name: "Nossa Senhora da Assunção"},
+	}},
+	{State: "MG", Slug: "BELO-HORIZONTE", Name: "Belo Horizonte", Holidays: []fixedHoliday{
+		{Month: time.August, Day: 15, Name: "Assunção de Nossa Senhora"},
+		{Month: time.December, Day: 8, Name: "Imaculada Conceição"},
+	}},
+	{State: "PE", Slug: "RECIFE", Name: "Recife", Holidays: []fixedHoliday{
+		{Month: time.June, Day: 24, Name: "São João"},
+		{Month: time.July, Day: 16, Name: "Nossa Senhora do Carmo"},
+		{Month: time.December, Day: 8, Name: "Nossa Senhora da Conceição"},
+	}},
+	{State: "PR", Slug: "CURITIBA", Name: "Curitiba", Holidays: []fixedHoliday{
+		{Month: time.September, Day: 8, Name: "Nossa Senhora da Luz dos Pinhais"},
+	}},
+	{State: "RJ", Slug: "RIO-DE-JANEIRO", Name: "Rio de Janeiro", Holidays: []fixedHoliday{
+		{Month: time.January, Day: 20, Name: "São Sebastião"},
+	}},
+	{State: "RS", Slug: "PORTO-ALEGRE", Name: "Porto Alegre", Holidays: []fixedHoliday{
+		{Month: time.February, Day: 2, Name: "Nossa Senhora dos Navegantes"},
+	}},
+	{State: "SP", Slug: "SAO-PAULO", Name: "São Paulo", Holidays: []fixedHoliday{
+		{Month: time.January, Day: 25, Name: "Aniversário de São Paulo"},
+	}},
+}
+
+// brazilCalendar is the national calendar plus, optionally, a state and a municipality.
+type brazilCalendar struct {
+	code  string
+	name  string
+	state *brState
+	city  *brCity
+}
+
+func (c *brazilCalendar) Code() string { return c.code }
+func (c *brazilCalendar) Name() string { return c.name }
+
+func (c *brazilCalendar) Holidays(year int) []Holiday {
+	easter := easterSunday(year)
+	out := make([]Holiday, 0, len(brNational)+len(brNationalEaster)+4)
+	out = appendFixed(out, year, brNational, ScopeNational)
+	out = appendEaster(out, easter, brNationalEaster, ScopeNational)
+	if c.state != nil {
+		out = appendFixed(out, year, c.state.Holidays, ScopeState)
+	}
+	if c.city != nil {
+		out = appendFixed(out, year, c.city.Holidays, ScopeMunicipal)
+		out = appendEaster(out, easter, c.city.Easter, ScopeMunicipal)
+	}
+	return dedupeByDate(out)
+}
+
+func appendFixed(out []Holiday, year int, items []fixedHoliday, scope string) []Holiday {
+	for _, h := range items {
+		if h.Since > 0 && year < h.Since {
+			continue
+		}
+		date := time.Date(year, h.Month, h.Day, 0, 0, 0, 0, time.UTC)
+		out = append(out, Holiday{Date: date.Format("2006-01-02"), Name: h.Name, Scope: scope})
+	}
+	return out
+}
+
+func appendEaster(out []Holiday, easter time.Time, items []easterHoliday, scope string) []Holiday {
+	for _, h := range items {
+		out = append(out, Holiday{Date: easter.AddDate(0, 0, h.Offset).Format("2006-01-02"), Name: h.Name, Scope: scope})
+	}
+	return out
+}
+
+// dedupeByDate keeps the widest-scoped holiday when a state or city repeats a national date.
+func dedupeByDate(items []Holiday) []Holiday {
+	seen := make(map[string]bool, len(items))
+	out := items[:0]
+	for _, h := range items {
+		if seen[h.Date] {
+			continue
+		}
+		seen[h.Date] = true
+		out = append(out, h)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// easterSunday uses the anonymous Gregorian algorithm (Meeus/Jones/Butcher).
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+func init() {
+	Register(&brazilCalendar{code: "BR", name: "Brasil"})
+	for uf, state := range brStates {
+		state := state
+		Register(&brazilCalendar{code: "BR-" + uf, name: state.Name, state: &state})
+	}
+	for i := range brCities {
+		city := &brCities[i]
+		var state *brState
+		if s, ok := brStates[city.State]; ok {
+			state = &s
+		}
+		Register(&brazilCalendar{
+			code:  "BR-" + city.State + "-" + city.Slug,
+			name:  city.Name + " (" + city.State + ")",
+			state: state,
+			city:  city,
+		})
+	}
+}
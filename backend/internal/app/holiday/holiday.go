@@ -0,0 +1,132 @@
+// Package holiday holds the offline public-holiday calendars used for business-day logic:
+// Brazilian national holidays plus state and municipal extensions. Calendars for other
+// countries plug in through Register.
+package holiday
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultCode is the calendar used when the user has not picked one.
+const DefaultCode = "BR"
+
+const (
+	ScopeNational  = "national"
+	ScopeState     = "state"
+	ScopeMunicipal = "municipal"
+)
+
+// Holiday is a public holiday on Date (YYYY-MM-DD).
+type Holiday struct {
+	Date  string
+	Name  string
+	Scope string
+}
+
+// Calendar lists the holidays of a place. Codes follow ISO 3166 ("BR", "BR-SP") with the
+// municipality appended in upper-case slug form ("BR-SP-SAO-PAULO").
+type Calendar interface {
+	Code() string
+	Name() string
+	Holidays(year int) []Holiday
+}
+
+var registry = make(map[string]Calendar)
+
+// Register adds a calendar to the registry. It panics on duplicate codes, like the
+// database/sql drivers.
+func Register(cal Calendar) {
+	code := normalizeCode(cal.Code())
+	if _, ok := registry[code]; ok {
+		panic(fmt.Sprintf("holiday: calendar %s registered twice", code))
+	}
+	registry[code] = cal
+}
+
+// Lookup returns the calendar registered under code (case-insensitive).
+func Lookup(code string) (Calendar, bool) {
+	cal, ok := registry[normalizeCode(code)]
+	return cal, ok
+}
+
+// For returns the calendar for code, falling back to DefaultCode for empty or unknown codes.
+func For(code string) Calendar {
+	if cal, ok := Lookup(code); ok {
+		return cal
+	}
+	return registry[DefaultCode]
+}
+
+// Calendars returns every registered calendar sorted by code.
+func Calendars() []Calendar {
+	out := make([]Calendar, 0, len(registry))
+	for _, cal := range registry {
+		out = append(out, cal)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code() < out[j].Code() })
+	return out
+}
+
+// On returns the holiday on date, if any. A nil calendar has no holidays.
+func On(cal Calendar, date time.Time) (Holiday, bool) {
+	if cal == nil {
+		return Holiday{}, false
+	}
+	key := date.Format("2006-01-02")
+	for _, h := range cal.Holidays(date.Year()) {
+		if h.Date == key {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// Between returns the holidays from from to to, both inclusive, sorted by date.
+func Between(cal Calendar, from, to time.Time) []Holiday {
+	out := make([]Holiday, 0)
+	if cal == nil || to.Before(from) {
+		return out
+	}
+	fromKey, toKey := from.Format("2006-01-02"), to.Format("2006-01-02")
+	for year := from.Year(); year <= to.Year(); year++ {
+		for _, h := range cal.Holidays(year) {
+			if h.Date >= fromKey && h.Date <= toKey {
+				out = append(out, h)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// IsBusinessDay reports whether date is a weekday that is not a holiday.
+func IsBusinessDay(cal Calendar, date time.Time) bool {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	_, isHoliday := On(cal, date)
+	return !isHoliday
+}
+
+// AddBusinessDays moves n business days from date ("em 3 dias úteis"), keeping the time of day.
+// Negative n walks backwards; n = 0 returns date unchanged.
+func AddBusinessDays(cal Calendar, date time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if IsBusinessDay(cal, date) {
+			n--
+		}
+	}
+	return date
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
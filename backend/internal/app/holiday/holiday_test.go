@@ -0,0 +1,77 @@
+package holiday
+
+import (
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestBrazilNationalEasterHolidays(t *testing.T) {
+	cal := For("BR")
+	cases := map[string]string{
+		"2026-02-16": "Carnaval",
+		"2026-02-17": "Carnaval",
+		"2026-04-03": "Sexta-feira Santa",
+		"2026-06-04": "Corpus Christi",
+		"2026-11-20": "Dia Nacional de Zumbi e da Consciência Negra",
+	}
+	for day, name := range cases {
+		h, ok := On(cal, date(day))
+		if !ok || h.Name != name || h.Scope != ScopeNational {
+			t.Fatalf("expected %s on %s, got %+v (ok=%v)", name, day, h, ok)
+		}
+	}
+	if _, ok := On(cal, date("2023-11-20")); ok {
+		t.Fatalf("expected no national holiday on 2023-11-20")
+	}
+}
+
+func TestMunicipalCalendarIncludesStateAndNational(t *testing.T) {
+	cal, ok := Lookup("br-sp-sao-paulo")
+	if !ok {
+		t.Fatalf("expected the São Paulo calendar")
+	}
+	for day, scope := range map[string]string{
+		"2026-01-01": ScopeNational,
+		"2026-01-25": ScopeMunicipal,
+		"2026-07-09": ScopeState,
+	} {
+		h, ok := On(cal, date(day))
+		if !ok || h.Scope != scope {
+			t.Fatalf("expected a %s holiday on %s, got %+v (ok=%v)", scope, day, h, ok)
+		}
+	}
+	if _, ok := On(For("BR"), date("2026-07-09")); ok {
+		t.Fatalf("expected state holidays outside the national calendar")
+	}
+}
+
+func TestAddBusinessDaysSkipsWeekendsAndHolidays(t *testing.T) {
+	cal := For("BR")
+	// Quinta 2026-04-02: sexta é Sexta-feira Santa, depois fim de semana.
+	got := AddBusinessDays(cal, date("2026-04-02"), 3)
+	if want := date("2026-04-08"); !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want.Format("2006-01-02"), got.Format("2006-01-02"))
+	}
+	got = AddBusinessDays(cal, date("2026-04-06"), -1)
+	if want := date("2026-04-02"); !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want.Format("2006-01-02"), got.Format("2006-01-02"))
+	}
+}
+
+func TestForFallsBackToDefault(t *testing.T) {
+	if got := For("XX").Code(); got != DefaultCode {
+		t.Fatalf("expected fallback to %s, got %s", DefaultCode, got)
+	}
+	holidays := Between(For(""), date("2026-12-20"), date("2027-01-02"))
+	if len(holidays) != 2 || holidays[0].Date != "2026-12-25" || holidays[1].Date != "2027-01-01" {
+		t.Fatalf("unexpected holidays across years: %+v", holidays)
+	}
+}
@@ -0,0 +1,242 @@
+// Package i18n holds the message catalogue for server-generated text (e-mails,
+// push fallbacks, home insights) in pt-BR, en and es.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is used for unknown locales and for keys missing from a bundle.
+const DefaultLocale = "pt-BR"
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// Vars fills the {name} placeholders of a message.
+type Vars map[string]any
+
+// message is either a plain string or plural forms ({"one": ..., "other": ...}).
+type message struct {
+	One   string
+	Other string
+}
+
+type bundle struct {
+	locale   string
+	messages map[string]message
+	plural   func(n int) string
+}
+
+var bundles = mustLoadBundles()
+
+// pluralRules segue as categorias do CLDR para inteiros: em pt, 0 e 1 são "one".
+var pluralRules = map[string]func(n int) string{
+	"pt-BR": func(n int) string {
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+	},
+	"en": func(n int) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	},
+	"es": func(n int) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	},
+}
+
+func mustLoadBundles() map[string]*bundle {
+	loaded := make(map[string]*bundle, len(pluralRules))
+	for locale, rule := range pluralRules {
+		raw, err := localesFS.ReadFile(path.Join("locales", locale+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing bundle %s: %v", locale, err))
+		}
+		messages, err := parseMessages(raw)
+		if err != nil {
+			panic(fmt.Sprintf("i18n: invalid bundle %s: %v", locale, err))
+		}
+		loaded[locale] = &bundle{locale: locale, messages: messages, plural: rule}
+	}
+	return loaded
+}
+
+func parseMessages(raw []byte) (map[string]message, error) {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, err
+	}
+	messages := make(map[string]message, len(entries))
+	for key, value := range entries {
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil {
+			messages[key] = message{One: text, Other: text}
+			continue
+		}
+		var forms struct {
+			One   string `json:"one"`
+			Other string `json:"other"`
+		}
+		if err := json.Unmarshal(value, &forms); err != nil || forms.Other == "" {
+			return nil, fmt.Errorf("key %q must be a string or {\"one\",\"other\"}", key)
+		}
+		if forms.One == "" {
+			forms.One = forms.Other
+		}
+		messages[key] = message{One: forms.One, Other: forms.Other}
+	}
+	return messages, nil
+}
+
+// Locales returns the supported locales, sorted.
+func Locales() []string {
+	out := make([]string, 0, len(bundles))
+	for locale := range bundles {
+		out = append(out, locale)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Normalize maps a user locale ("pt_br", "en-US", "es-MX") to a supported one, or "" when none matches.
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(locale, "_", "-")))
+	lang, _, _ := strings.Cut(locale, "-")
+	switch lang {
+	case "pt":
+		return "pt-BR"
+	case "en":
+		return "en"
+	case "es":
+		return "es"
+	default:
+		return ""
+	}
+}
+
+// Localizer renders catalogue messages for one locale. The zero value uses DefaultLocale.
+type Localizer struct {
+	b *bundle
+}
+
+// For returns the localizer for locale, falling back to DefaultLocale.
+func For(locale string) Localizer {
+	if b, ok := bundles[Normalize(locale)]; ok {
+		return Localizer{b: b}
+	}
+	return Localizer{b: bundles[DefaultLocale]}
+}
+
+// ForAcceptLanguage picks the first supported language of an Accept-Language header.
+func ForAcceptLanguage(header string) Localizer {
+	for _, part := range strings.Split(header, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		if locale := Normalize(tag); locale != "" {
+			return For(locale)
+		}
+	}
+	return For(DefaultLocale)
+}
+
+func (l Localizer) bundle() *bundle {
+	if l.b == nil {
+		return bundles[DefaultLocale]
+	}
+	return l.b
+}
+
+// Locale is the canonical locale of the localizer (pt-BR, en, es).
+func (l Localizer) Locale() string {
+	return l.bundle().locale
+}
+
+// IsDefault reports whether texts come from the default (pt-BR) bundle.
+func (l Localizer) IsDefault() bool {
+	return l.Locale() == DefaultLocale
+}
+
+// Has reports whether key exists in this locale or in the default bundle.
+func (l Localizer) Has(key string) bool {
+	_, ok := l.lookup(key)
+	return ok
+}
+
+// T renders key, replacing {name} placeholders with vars. Unknown keys render as the key itself.
+func (l Localizer) T(key string, vars ...Vars) string {
+	msg, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	return interpolate(msg.Other, vars)
+}
+
+// N renders the plural form of key for n; {count} is set to n.
+func (l Localizer) N(key string, n int, vars ...Vars) string {
+	msg, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	text := msg.Other
+	if l.bundle().plural(n) == "one" {
+		text = msg.One
+	}
+	return interpolate(text, append([]Vars{{"count": n}}, vars...))
+}
+
+// Weekday returns the full weekday name ("Segunda", "Monday").
+func (l Localizer) Weekday(d time.Weekday) string {
+	return l.T("weekday." + strconv.Itoa(int(d)))
+}
+
+// WeekdayShort returns the abbreviated weekday name ("Seg", "Mon").
+func (l Localizer) WeekdayShort(d time.Weekday) string {
+	return l.T("weekday_short." + strconv.Itoa(int(d)))
+}
+
+// Date formats t as a numeric date in the locale order (02/01/2006, 01/02/2006).
+func (l Localizer) Date(t time.Time) string {
+	return t.Format(l.T("format.date"))
+}
+
+// DayMonth formats t without the year (02/01, 01/02).
+func (l Localizer) DayMonth(t time.Time) string {
+	return t.Format(l.T("format.day_month"))
+}
+
+func (l Localizer) lookup(key string) (message, bool) {
+	if msg, ok := l.bundle().messages[key]; ok {
+		return msg, true
+	}
+	msg, ok := bundles[DefaultLocale].messages[key]
+	return msg, ok
+}
+
+func interpolate(text string, vars []Vars) string {
+	if len(vars) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	merged := make(map[string]string)
+	for _, set := range vars {
+		for name, value := range set {
+			merged["{"+name+"}"] = fmt.Sprint(value)
+		}
+	}
+	pairs := make([]string, 0, len(merged)*2)
+	for placeholder, value := range merged {
+		pairs = append(pairs, placeholder, value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
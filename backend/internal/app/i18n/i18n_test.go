@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{[a-z_]+\}`)
+
+func placeholders(msg message) []string {
+	found := placeholderPattern.FindAllString(msg.One+" "+msg.Other, -1)
+	set := make(map[string]struct{}, len(found))
+	out := make([]string, 0, len(found))
+	for _, p := range found {
+		if _, ok := set[p]; ok {
+			continue
+		}
+		set[p] = struct{}{}
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestBundlesHaveSameKeysAndPlaceholders(t *testing.T) {
+	base := bundles[DefaultLocale]
+	for _, locale := range Locales() {
+		b := bundles[locale]
+		for key, msg := range base.messages {
+			other, ok := b.messages[key]
+			if !ok {
+				t.Errorf("%s: missing key %q", locale, key)
+				continue
+			}
+			if got, want := placeholders(other), placeholders(msg); !equalStrings(got, want) {
+				t.Errorf("%s: key %q placeholders = %v, want %v", locale, key, got, want)
+			}
+		}
+		for key := range b.messages {
+			if _, ok := base.messages[key]; !ok {
+				t.Errorf("%s: key %q not in %s", locale, key, DefaultLocale)
+			}
+		}
+	}
+}
+
+func TestPluralRules(t *testing.T) {
+	pt := For("pt-BR")
+	if got := pt.N("weekly.age.days", 1); got != "há 1 dia" {
+		t.Fatalf("pt one = %q", got)
+	}
+	if got := pt.N("weekly.age.days", 3); got != "há 3 dias" {
+		t.Fatalf("pt other = %q", got)
+	}
+	if got := pt.N("weekly.load.items", 0); got != "0 item" {
+		t.Fatalf("pt zero should use the singular form, got %q", got)
+	}
+
+	en := For("en-US")
+	if got := en.N("weekly.load.items", 0); got != "0 items" {
+		t.Fatalf("en zero = %q", got)
+	}
+	if got := en.N("notification.bundle.title", 7, Vars{"window": 10}); got != "7 items in the next 10 min" {
+		t.Fatalf("en plural with vars = %q", got)
+	}
+}
+
+func TestNormalizeAndFallback(t *testing.T) {
+	cases := map[string]string{
+		"pt_br": "pt-BR",
+		"pt":    "pt-BR",
+		"en-GB": "en",
+		"es-MX": "es",
+		"fr":    "",
+		"":      "",
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if got := For("fr").Locale(); got != DefaultLocale {
+		t.Fatalf("unknown locale should fall back to %s, got %s", DefaultLocale, got)
+	}
+	var zero Localizer
+	if !zero.IsDefault() || zero.T("digest.title") != "Seu dia no Inbota" {
+		t.Fatal("zero Localizer should use the default bundle")
+	}
+	if got := For("en").T("missing.key"); got != "missing.key" {
+		t.Fatalf("unknown key should render as itself, got %q", got)
+	}
+	if got := ForAcceptLanguage("fr-FR,es;q=0.8,en;q=0.5").Locale(); got != "es" {
+		t.Fatalf("accept-language = %s, want es", got)
+	}
+}
+
+func TestDateFormatting(t *testing.T) {
+	day := time.Date(2026, time.March, 8, 9, 0, 0, 0, time.UTC)
+	if got := For("pt-BR").Date(day); got != "08/03/2026" {
+		t.Fatalf("pt date = %q", got)
+	}
+	if got := For("en").Date(day); got != "03/08/2026" {
+		t.Fatalf("en date = %q", got)
+	}
+	if got := For("es").Weekday(day.Weekday()) + " " + For("es").DayMonth(day); got != "Domingo 08/03" {
+		t.Fatalf("es weekday/day = %q", got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
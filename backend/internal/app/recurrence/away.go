@@ -0,0 +1,59 @@
+package recurrence
+
+import (
+	"time"
+
+	"inbota/backend/internal/app/domain"
+)
+
+// IsAway reports whether one of the periods pauses an item of flagID on date (YYYY-MM-DD).
+// Periods without a flag pause every item.
+func IsAway(periods []domain.AwayPeriod, flagID *string, date string) bool {
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	for _, p := range periods {
+		if awayApplies(p, flagID) && date >= p.StartsOn && date <= p.EndsOn {
+			return true
+		}
+	}
+	return false
+}
+
+// AwayExceptions turns the periods that pause the routine into "away" exceptions between from
+// and to (inclusive). Append them after the stored exceptions so they win on the same day.
+func AwayExceptions(r domain.Routine, periods []domain.AwayPeriod, from, to time.Time) []domain.RoutineException {
+	from, to = dateOf(from), dateOf(to)
+	var out []domain.RoutineException
+	for _, p := range periods {
+		if !awayApplies(p, r.FlagID) {
+			continue
+		}
+		start, okStart := parseDate(&p.StartsOn)
+		end, okEnd := parseDate(&p.EndsOn)
+		if !okStart || !okEnd {
+			continue
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			out = append(out, domain.RoutineException{
+				RoutineID:     r.ID,
+				ExceptionDate: day.Format("2006-01-02"),
+				Action:        ActionAway,
+			})
+		}
+	}
+	return out
+}
+
+func awayApplies(p domain.AwayPeriod, flagID *string) bool {
+	if p.FlagID == nil {
+		return true
+	}
+	return flagID != nil && *flagID == *p.FlagID
+}
@@ -0,0 +1,26 @@
+package recurrence
+
+import (
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/holiday"
+)
+
+// HolidayExceptions turns the holidays between from and to (inclusive) into "holiday"
+// exceptions when the routine skips holidays. Prepend them to the stored exceptions so an
+// explicit reschedule on a holiday still wins.
+func HolidayExceptions(r domain.Routine, cal holiday.Calendar, from, to time.Time) []domain.RoutineException {
+	if !r.SkipHolidays || cal == nil {
+		return nil
+	}
+	var out []domain.RoutineException
+	for _, h := range holiday.Between(cal, dateOf(from), dateOf(to)) {
+		out = append(out, domain.RoutineException{
+			RoutineID:     r.ID,
+			ExceptionDate: h.Date,
+			Action:        ActionHoliday,
+		})
+	}
+	return out
+}
@@ -0,0 +1,311 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/holiday"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func dates(occurrences []Occurrence) []string {
+	out := make([]string, 0, len(occurrences))
+	for _, o := range occurrences {
+		out = append(out, o.Date.Format("2006-01-02"))
+	}
+	return out
+}
+
+func mustParse(t *testing.T, value string) Rule {
+	t.Helper()
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", value, err)
+	}
+	return rule
+}
+
+func TestParseRoundTripAndErrors(t *testing.T) {
+	rule := mustParse(t, "RRULE:freq=monthly;byday=-1fr;until=20261231T235959Z")
+	if got, want := rule.String(), "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231"; got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=3;UNTIL=20260101",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=3",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYSETPOS=1",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;WKST=SU",
+	}
+	for _, value := range invalid {
+		if _, err := Parse(value); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidRule", value, err)
+		}
+	}
+}
+
+func TestSeriesExpansion(t *testing.T) {
+	cases := []struct {
+		name  string
+		rule  string
+		start time.Time
+		from  time.Time
+		to    time.Time
+		want  []string
+	}{
+		{
+			name:  "daily interval",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: day(2026, 3, 1),
+			from:  day(2026, 3, 1),
+			to:    day(2026, 3, 10),
+			want:  []string{"2026-03-01", "2026-03-04", "2026-03-07", "2026-03-10"},
+		},
+		{
+			name:  "monthly by day number skips short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: day(2026, 1, 1),
+			from:  day(2026, 1, 1),
+			to:    day(2026, 5, 31),
+			want:  []string{"2026-01-31", "2026-03-31", "2026-05-31"},
+		},
+		{
+			name:  "last friday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: day(2026, 1, 1),
+			from:  day(2026, 1, 1),
+			to:    day(2026, 3, 31),
+			want:  []string{"2026-01-30", "2026-02-27", "2026-03-27"},
+		},
+		{
+			name:  "yearly defaults to the start date",
+			rule:  "FREQ=YEARLY",
+			start: day(2026, 3, 8),
+			from:  day(2026, 1, 1),
+			to:    day(2028, 12, 31),
+			want:  []string{"2026-03-08", "2027-03-08", "2028-03-08"},
+		},
+		{
+			name:  "count includes occurrences before the window",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3",
+			start: day(2026, 3, 2),
+			from:  day(2026, 3, 4),
+			to:    day(2026, 3, 31),
+			want:  []string{"2026-03-04", "2026-03-09"},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  "FREQ=DAILY;UNTIL=20260303",
+			start: day(2026, 3, 1),
+			from:  day(2026, 2, 20),
+			to:    day(2026, 3, 10),
+			want:  []string{"2026-03-01", "2026-03-02", "2026-03-03"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			series := NewSeries(mustParse(t, tc.rule), tc.start, "08:00", "09:00")
+			got := dates(series.Between(tc.from, tc.to))
+			if len(got) != len(tc.want) {
+				t.Fatalf("Between = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("Between = %v, want %v", got, tc.want)
+				}
+				if !series.Matches(mustDate(t, got[i])) {
+					t.Fatalf("Matches(%s) = false, but Between returned it", got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLegacyRoutineTypes(t *testing.T) {
+	week := 2
+	routines := map[string]domain.Routine{
+		"weekly":       {RecurrenceType: "weekly", Weekdays: []int{2}, StartsOn: "2026-03-03"},
+		"biweekly":     {RecurrenceType: "biweekly", Weekdays: []int{2}, StartsOn: "2026-03-03"},
+		"triweekly":    {RecurrenceType: "triweekly", Weekdays: []int{2}, StartsOn: "2026-03-03"},
+		"monthly_week": {RecurrenceType: "monthly_week", Weekdays: []int{2}, WeekOfMonth: &week, StartsOn: "2026-03-03"},
+	}
+	want := map[string][]string{
+		"weekly":       {"2026-03-03", "2026-03-10", "2026-03-17", "2026-03-24", "2026-03-31", "2026-04-07", "2026-04-14"},
+		"biweekly":     {"2026-03-03", "2026-03-17", "2026-03-31", "2026-04-14"},
+		"triweekly":    {"2026-03-03", "2026-03-24", "2026-04-14"},
+		"monthly_week": {"2026-03-10", "2026-04-14"},
+	}
+	for name, routine := range routines {
+		got := dates(ForRoutine(routine).Between(day(2026, 3, 1), day(2026, 4, 15)))
+		if len(got) != len(want[name]) {
+			t.Fatalf("%s: %v, want %v", name, got, want[name])
+		}
+		for i := range got {
+			if got[i] != want[name][i] {
+				t.Fatalf("%s: %v, want %v", name, got, want[name])
+			}
+		}
+	}
+
+	endsOn := "2026-03-10"
+	ended := domain.Routine{RecurrenceType: "weekly", Weekdays: []int{2}, StartsOn: "2026-03-03", EndsOn: &endsOn}
+	if ForRoutine(ended).Matches(day(2026, 3, 17)) {
+		t.Fatal("routine should not occur after EndsOn")
+	}
+}
+
+func TestRoutineExceptions(t *testing.T) {
+	rrule := "FREQ=DAILY"
+	routine := domain.Routine{RecurrenceType: TypeRRule, RRule: &rrule, StartsOn: "2026-03-01", StartTime: "07:00", EndTime: "08:00"}
+	newStart, newEnd := "09:30", "10:00"
+	series := ForRoutine(routine,
+		domain.RoutineException{ExceptionDate: "2026-03-02T00:00:00Z", Action: ActionSkip},
+		domain.RoutineException{ExceptionDate: "2026-03-03", Action: ActionReschedule, NewStartTime: &newStart, NewEndTime: &newEnd},
+	)
+
+	if _, ok := series.On(day(2026, 3, 2)); ok {
+		t.Fatal("skipped date should have no occurrence")
+	}
+	if !series.Matches(day(2026, 3, 2)) {
+		t.Fatal("Matches ignores exceptions")
+	}
+
+	occ, ok := series.On(day(2026, 3, 3))
+	if !ok || !occ.Rescheduled || occ.StartTime != "09:30" || occ.EndTime != "10:00" {
+		t.Fatalf("rescheduled occurrence = %+v, %v", occ, ok)
+	}
+
+	got := dates(series.Between(day(2026, 3, 1), day(2026, 3, 4)))
+	if len(got) != 3 || got[1] != "2026-03-03" {
+		t.Fatalf("Between with exceptions = %v", got)
+	}
+}
+
+func TestRuleWeekdays(t *testing.T) {
+	if got := mustParse(t, "FREQ=MONTHLY;BYDAY=1MO,-1FR,3MO").Weekdays(time.Time{}); len(got) != 2 || got[0] != 1 || got[1] != 5 {
+		t.Fatalf("BYDAY weekdays = %v", got)
+	}
+	if got := mustParse(t, "FREQ=WEEKLY").Weekdays(day(2026, 3, 4)); len(got) != 1 || got[0] != 3 {
+		t.Fatalf("weekly default weekdays = %v", got)
+	}
+	if got := mustParse(t, "FREQ=MONTHLY;BYMONTHDAY=15").Weekdays(day(2026, 3, 4)); len(got) != 7 {
+		t.Fatalf("month day weekdays = %v", got)
+	}
+}
+
+func TestAwayExceptions_PauseMatchingRoutines(t *testing.T) {
+	work := "flag-work"
+	periods := []domain.AwayPeriod{
+		{StartsOn: "2026-03-02", EndsOn: "2026-03-04"},
+		{StartsOn: "2026-03-10", EndsOn: "2026-03-12", FlagID: &work},
+	}
+	routine := domain.Routine{ID: "r1", RecurrenceType: TypeWeekly, Weekdays: []int{1, 2, 3, 4, 5}, StartsOn: "2026-03-02"}
+
+	away := AwayExceptions(routine, periods, day(2026, 3, 3), day(2026, 3, 31))
+	if len(away) != 2 || away[0].ExceptionDate != "2026-03-03" || away[1].Action != ActionAway {
+		t.Fatalf("away exceptions = %+v", away)
+	}
+	got := dates(ForRoutine(routine, away...).Between(day(2026, 3, 2), day(2026, 3, 6)))
+	if len(got) != 3 || got[0] != "2026-03-02" || got[1] != "2026-03-05" {
+		t.Fatalf("occurrences while away = %v", got)
+	}
+
+	if IsAway(periods, nil, "2026-03-11") || !IsAway(periods, &work, "2026-03-11T09:00:00Z") {
+		t.Fatal("flag scoped period should only pause items of that flag")
+	}
+	routine.FlagID = &work
+	if away := AwayExceptions(routine, periods, day(2026, 3, 1), day(2026, 3, 31)); len(away) != 6 {
+		t.Fatalf("flagged routine away days = %d, want 6", len(away))
+	}
+}
+
+func TestHolidayExceptions_SkipWeekdayRoutineOnHolidays(t *testing.T) {
+	routine := domain.Routine{ID: "r1", RecurrenceType: TypeWeekly, Weekdays: []int{1, 2, 3, 4, 5}, StartsOn: "2026-03-30"}
+	cal := holiday.For("BR")
+
+	if got := HolidayExceptions(routine, cal, day(2026, 3, 30), day(2026, 4, 24)); len(got) != 0 {
+		t.Fatalf("routine without skipHolidays got %+v", got)
+	}
+
+	routine.SkipHolidays = true
+	exceptions := HolidayExceptions(routine, cal, day(2026, 3, 30), day(2026, 4, 24))
+	if len(exceptions) != 2 || exceptions[0].ExceptionDate != "2026-04-03" || exceptions[1].Action != ActionHoliday {
+		t.Fatalf("holiday exceptions = %+v", exceptions)
+	}
+	got := dates(ForRoutine(routine, exceptions...).Between(day(2026, 3, 30), day(2026, 4, 3)))
+	if len(got) != 4 || got[3] != "2026-04-02" {
+		t.Fatalf("occurrences around Sexta-feira Santa = %v", got)
+	}
+
+	moved := "10:00"
+	reschedule := domain.RoutineException{RoutineID: "r1", ExceptionDate: "2026-04-03", Action: ActionReschedule, NewStartTime: &moved}
+	occ, ok := ForRoutine(routine, append(exceptions, reschedule)...).On(day(2026, 4, 3))
+	if !ok || occ.StartTime != moved {
+		t.Fatalf("reschedule on a holiday should keep the occurrence, got %+v (ok=%v)", occ, ok)
+	}
+}
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestSeriesNext(t *testing.T) {
+	monthly := NewSeries(mustParse(t, "FREQ=MONTHLY;BYMONTHDAY=5"), day(2026, 1, 5), "", "")
+	if next, ok := monthly.Next(day(2026, 1, 5)); !ok || !next.Equal(day(2026, 2, 5)) {
+		t.Fatalf("Next after Jan 5 = %v, %v; want 2026-02-05", next, ok)
+	}
+	if next, ok := monthly.Next(day(2026, 2, 7)); !ok || !next.Equal(day(2026, 3, 5)) {
+		t.Fatalf("Next after Feb 7 = %v, %v; want 2026-03-05", next, ok)
+	}
+
+	quarterly := NewSeries(mustParse(t, "FREQ=MONTHLY;INTERVAL=3"), day(2026, 1, 20), "", "")
+	if next, ok := quarterly.Next(day(2026, 1, 20)); !ok || !next.Equal(day(2026, 4, 20)) {
+		t.Fatalf("Next quarterly = %v, %v; want 2026-04-20", next, ok)
+	}
+
+	ending := NewSeries(mustParse(t, "FREQ=WEEKLY;UNTIL=20260115"), day(2026, 1, 5), "", "")
+	if next, ok := ending.Next(day(2026, 1, 12)); ok {
+		t.Fatalf("expected series to end after UNTIL, got %v", next)
+	}
+}
+
+func TestSeriesCountAcrossCalls(t *testing.T) {
+	counted := NewSeries(mustParse(t, "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3"), day(2026, 3, 2), "", "")
+
+	var got []string
+	cursor := day(2026, 3, 1)
+	for {
+		next, ok := counted.Next(cursor)
+		if !ok {
+			break
+		}
+		got = append(got, next.Format("2006-01-02"))
+		cursor = next
+	}
+	if len(got) != 3 || got[0] != "2026-03-02" || got[2] != "2026-03-09" {
+		t.Fatalf("Next with COUNT = %v, want [2026-03-02 2026-03-04 2026-03-09]", got)
+	}
+
+	// dates before the walked point still answer the same, in copies of the series too
+	copied := counted.WithExceptions()
+	if !copied.Matches(day(2026, 3, 4)) || copied.Matches(day(2026, 3, 11)) {
+		t.Fatal("Matches with COUNT changed after the series was walked")
+	}
+}
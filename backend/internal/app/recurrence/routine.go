@@ -0,0 +1,124 @@
+package recurrence
+
+import (
+	"strings"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+)
+
+// Routine recurrence_type values. The legacy ones map onto equivalent rules; "rrule" keeps
+// the full rule in Routine.RRule.
+const (
+	TypeWeekly      = "weekly"
+	TypeBiweekly    = "biweekly"
+	TypeTriweekly   = "triweekly"
+	TypeMonthlyWeek = "monthly_week"
+	TypeRRule       = "rrule"
+)
+
+func IsRoutineType(recurrenceType string) bool {
+	switch recurrenceType {
+	case TypeWeekly, TypeBiweekly, TypeTriweekly, TypeMonthlyWeek, TypeRRule:
+		return true
+	default:
+		return false
+	}
+}
+
+// RoutineRule returns the rule behind a routine. EndsOn becomes UNTIL when it is earlier than
+// the rule's own end.
+//
+//	weekly       → FREQ=WEEKLY;BYDAY=<weekdays>
+//	biweekly     → FREQ=WEEKLY;INTERVAL=2;BYDAY=<weekdays>
+//	triweekly    → FREQ=WEEKLY;INTERVAL=3;BYDAY=<weekdays>
+//	monthly_week → FREQ=MONTHLY;BYDAY=<weekOfMonth><weekdays>
+func RoutineRule(r domain.Routine) (Rule, error) {
+	var rule Rule
+	switch r.RecurrenceType {
+	case TypeRRule:
+		if r.RRule == nil {
+			return Rule{}, invalid("routine without rrule")
+		}
+		parsed, err := Parse(*r.RRule)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule = parsed
+	case "", TypeWeekly:
+		rule = Rule{Freq: Weekly, ByDay: byWeekdays(r.Weekdays, 0)}
+	case TypeBiweekly:
+		rule = Rule{Freq: Weekly, Interval: 2, ByDay: byWeekdays(r.Weekdays, 0)}
+	case TypeTriweekly:
+		rule = Rule{Freq: Weekly, Interval: 3, ByDay: byWeekdays(r.Weekdays, 0)}
+	case TypeMonthlyWeek:
+		if r.WeekOfMonth == nil {
+			// Legacy rows without a week behaved as weekly.
+			rule = Rule{Freq: Weekly, ByDay: byWeekdays(r.Weekdays, 0)}
+			break
+		}
+		rule = Rule{Freq: Monthly, ByDay: byWeekdays(r.Weekdays, *r.WeekOfMonth)}
+	default:
+		return Rule{}, invalid("unknown recurrence type %q", r.RecurrenceType)
+	}
+
+	if endsOn, ok := parseDate(r.EndsOn); ok && (rule.Until == nil || endsOn.Before(*rule.Until)) {
+		rule.Until = &endsOn
+	}
+	return rule, nil
+}
+
+// ForRoutine builds the routine series with its exceptions applied. Routines whose rule cannot
+// be read fall back to their weekdays, so a bad row still shows up instead of vanishing.
+func ForRoutine(r domain.Routine, exceptions ...domain.RoutineException) Series {
+	rule, err := RoutineRule(r)
+	if err != nil {
+		rule = Rule{Freq: Weekly, ByDay: byWeekdays(r.Weekdays, 0)}
+	}
+
+	var start time.Time
+	if parsed, ok := parseDate(&r.StartsOn); ok {
+		start = parsed
+	}
+
+	series := NewSeries(rule, start, r.StartTime, r.EndTime)
+	if len(exceptions) == 0 {
+		return series
+	}
+	converted := make([]Exception, 0, len(exceptions))
+	for _, e := range exceptions {
+		converted = append(converted, Exception{
+			Date:      e.ExceptionDate,
+			Action:    e.Action,
+			StartTime: e.NewStartTime,
+			EndTime:   e.NewEndTime,
+		})
+	}
+	return series.WithExceptions(converted...)
+}
+
+func byWeekdays(weekdays []int, n int) []WeekdayNum {
+	out := make([]WeekdayNum, 0, len(weekdays))
+	for _, w := range weekdays {
+		if w < 0 || w > 6 {
+			continue
+		}
+		out = append(out, WeekdayNum{Weekday: time.Weekday(w), N: n})
+	}
+	return out
+}
+
+func parseDate(value *string) (time.Time, bool) {
+	if value == nil {
+		return time.Time{}, false
+	}
+	v := strings.TrimSpace(*value)
+	if len(v) > 10 {
+		v = v[:10]
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
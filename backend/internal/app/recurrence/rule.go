@@ -0,0 +1,411 @@
+// Package recurrence expands recurring schedules (RFC 5545 RRULE subset) into dates.
+//
+// Rules are date-granular: the time of day belongs to the caller (routines keep their own
+// start/end times). Weeks start on Monday (WKST=MO), which is also how the legacy
+// biweekly/triweekly routines anchor their cadence.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid_rrule")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry. N selects the nth weekday inside the month (or the year,
+// for YEARLY rules without BYMONTH); negative values count from the end, 0 means every one.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	Count      int
+	Until      *time.Time
+}
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse reads an RRULE value such as "FREQ=MONTHLY;BYDAY=-1FR". The "RRULE:" prefix is optional.
+// Parts that cannot be honoured on a date-only schedule (BYHOUR, BYSETPOS, ...) are rejected
+// instead of being silently ignored.
+func Parse(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+		value = value[6:]
+	}
+	if value == "" {
+		return Rule{}, invalid("empty rule")
+	}
+
+	var rule Rule
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if !ok || val == "" {
+			return Rule{}, invalid("malformed part %q", part)
+		}
+		if seen[key] {
+			return Rule{}, invalid("duplicated %s", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(val)
+			default:
+				err = invalid("unsupported FREQ %s", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(key, val)
+		case "COUNT":
+			rule.Count, err = parsePositive(key, val)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(val)
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(key, val, 1, 31, true)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(key, val, 1, 12, false)
+			for _, m := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(m))
+			}
+		case "WKST":
+			if val != "MO" {
+				err = invalid("only WKST=MO is supported")
+			}
+		default:
+			err = invalid("unsupported part %s", key)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// Validate checks the combinations RFC 5545 forbids or this package cannot expand.
+func (r Rule) Validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return invalid("FREQ is required")
+	default:
+		return invalid("unsupported FREQ %s", r.Freq)
+	}
+	if r.Interval < 0 || r.Count < 0 {
+		return invalid("INTERVAL and COUNT must be positive")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return invalid("COUNT and UNTIL are mutually exclusive")
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return invalid("BYMONTHDAY is not allowed with FREQ=WEEKLY")
+	}
+	for _, d := range r.ByDay {
+		if d.N == 0 {
+			continue
+		}
+		if r.Freq != Monthly && r.Freq != Yearly {
+			return invalid("ordinal BYDAY needs FREQ=MONTHLY or FREQ=YEARLY")
+		}
+		limit := 53
+		if r.Freq == Monthly || len(r.ByMonth) > 0 {
+			limit = 5
+		}
+		if d.N > limit || d.N < -limit {
+			return invalid("BYDAY ordinal %d out of range", d.N)
+		}
+	}
+	return nil
+}
+
+// String renders the rule in canonical form, which is how it is stored.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, 0, len(r.ByMonth))
+		for _, m := range r.ByMonth {
+			months = append(months, strconv.Itoa(int(m)))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, d := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(d))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			code := weekdayCodes[d.Weekday]
+			if d.N != 0 {
+				code = strconv.Itoa(d.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Weekdays lists the weekdays (0=sun..6=sat) on which the rule can fire. Routines store it in
+// the weekdays column so the per-weekday SQL filters keep working for every rule.
+func (r Rule) Weekdays(start time.Time) []int {
+	if len(r.ByDay) > 0 {
+		set := make(map[int]bool, len(r.ByDay))
+		out := make([]int, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			if !set[int(d.Weekday)] {
+				set[int(d.Weekday)] = true
+				out = append(out, int(d.Weekday))
+			}
+		}
+		sort.Ints(out)
+		return out
+	}
+	if r.Freq == Weekly && !start.IsZero() {
+		return []int{int(start.Weekday())}
+	}
+	return []int{0, 1, 2, 3, 4, 5, 6}
+}
+
+// matches reports whether date fits the rule pattern for a series starting on start.
+// COUNT and UNTIL are handled by Series.
+func (r Rule) matches(start, date time.Time) bool {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Freq {
+	case Daily:
+		if daysBetween(start, date)%interval != 0 {
+			return false
+		}
+	case Weekly:
+		if daysBetween(mondayOf(start), mondayOf(date))/7%interval != 0 {
+			return false
+		}
+	case Monthly:
+		months := (date.Year()-start.Year())*12 + int(date.Month()-start.Month())
+		if months%interval != 0 {
+			return false
+		}
+	case Yearly:
+		if (date.Year()-start.Year())%interval != 0 {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, date.Month()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(date) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !r.matchesByDay(date) {
+		return false
+	}
+
+	// Parts left out are taken from the start date, as RFC 5545 does with DTSTART.
+	switch r.Freq {
+	case Weekly:
+		return len(r.ByDay) > 0 || date.Weekday() == start.Weekday()
+	case Monthly:
+		return len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 || date.Day() == start.Day()
+	case Yearly:
+		if len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 {
+			return true
+		}
+		if len(r.ByMonth) == 0 && date.Month() != start.Month() {
+			return false
+		}
+		return date.Day() == start.Day()
+	}
+	return true
+}
+
+func (r Rule) matchesMonthDay(date time.Time) bool {
+	last := daysInMonth(date)
+	for _, d := range r.ByMonthDay {
+		if d > 0 && date.Day() == d {
+			return true
+		}
+		if d < 0 && date.Day() == last+d+1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matchesByDay(date time.Time) bool {
+	for _, d := range r.ByDay {
+		if d.Weekday != date.Weekday() {
+			continue
+		}
+		if d.N == 0 {
+			return true
+		}
+
+		var pos, fromEnd int
+		if r.Freq == Yearly && len(r.ByMonth) == 0 {
+			yearDays := time.Date(date.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+			pos = (date.YearDay()-1)/7 + 1
+			fromEnd = (yearDays-date.YearDay())/7 + 1
+		} else {
+			pos = (date.Day()-1)/7 + 1
+			fromEnd = (daysInMonth(date)-date.Day())/7 + 1
+		}
+		if d.N == pos || d.N == -fromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePositive(key, val string) (int, error) {
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		return 0, invalid("%s must be a positive integer", key)
+	}
+	return n, nil
+}
+
+func parseUntil(val string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, val); err == nil {
+			return dateOf(t), nil
+		}
+	}
+	return time.Time{}, invalid("UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ")
+}
+
+func parseByDay(val string) ([]WeekdayNum, error) {
+	out := make([]WeekdayNum, 0)
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return nil, invalid("malformed BYDAY %q", item)
+		}
+		code, ordinal := item[len(item)-2:], item[:len(item)-2]
+		weekday := -1
+		for i, c := range weekdayCodes {
+			if c == code {
+				weekday = i
+				break
+			}
+		}
+		if weekday < 0 {
+			return nil, invalid("unknown weekday %q", code)
+		}
+		n := 0
+		if ordinal != "" {
+			parsed, err := strconv.Atoi(ordinal)
+			if err != nil || parsed == 0 {
+				return nil, invalid("malformed BYDAY %q", item)
+			}
+			n = parsed
+		}
+		out = append(out, WeekdayNum{Weekday: time.Weekday(weekday), N: n})
+	}
+	return out, nil
+}
+
+func parseIntList(key, val string, min, max int, allowNegative bool) ([]int, error) {
+	out := make([]int, 0)
+	for _, item := range strings.Split(val, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		abs := n
+		if abs < 0 && allowNegative {
+			abs = -abs
+		}
+		if err != nil || abs < min || abs > max {
+			return nil, invalid("%s value %q out of range", key, item)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRule, fmt.Sprintf(format, args...))
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+// dateOf drops the clock and location, keeping the calendar date as seen by the caller.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func mondayOf(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return dateOf(t).AddDate(0, 0, -(weekday - 1))
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(dateOf(to).Sub(dateOf(from)).Hours() / 24)
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,220 @@
+package recurrence
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	ActionSkip       = "skip"
+	ActionReschedule = "reschedule"
+	ActionRest       = "rest"
+	ActionAway       = "away"
+	ActionHoliday    = "holiday"
+)
+
+// Exception overrides a single occurrence: skip removes it, reschedule moves its time window.
+// rest removes it like skip, but streaks treat it as a planned day off instead of a miss.
+// away is a rest day that comes from a vacation period instead of a stored exception, and
+// holiday one that comes from a public holiday on a routine that skips holidays.
+type Exception struct {
+	Date      string
+	Action    string
+	StartTime *string
+	EndTime   *string
+}
+
+type Occurrence struct {
+	Date        time.Time
+	StartTime   string
+	EndTime     string
+	Rescheduled bool
+}
+
+// Series is a rule anchored on a start date, with the default time window of its occurrences.
+// Unlike DTSTART in RFC 5545, Start is only a lower bound: it is not an occurrence by itself
+// unless it matches the rule.
+type Series struct {
+	Rule       Rule
+	Start      time.Time
+	StartTime  string
+	EndTime    string
+	exceptions map[string]Exception
+	// count remembers how far COUNT was walked, shared by the copies of the series.
+	count *countCursor
+}
+
+// countCursor walks a COUNT rule forward once: Matches and Next ask for increasing dates, so
+// restarting from Start on every call would make expanding n days cost O(n²).
+type countCursor struct {
+	mu     sync.Mutex
+	cursor time.Time // next date to look at
+	n      int       // occurrences before cursor
+	last   time.Time // date of occurrence number COUNT, once reached
+}
+
+func NewSeries(rule Rule, start time.Time, startTime, endTime string) Series {
+	if !start.IsZero() {
+		start = dateOf(start)
+	}
+	s := Series{Rule: rule, Start: start, StartTime: startTime, EndTime: endTime}
+	if rule.Count > 0 && !start.IsZero() {
+		s.count = &countCursor{cursor: start}
+	}
+	return s
+}
+
+// WithExceptions returns a copy of the series that applies the given exceptions.
+func (s Series) WithExceptions(exceptions ...Exception) Series {
+	merged := make(map[string]Exception, len(s.exceptions)+len(exceptions))
+	for date, e := range s.exceptions {
+		merged[date] = e
+	}
+	for _, e := range exceptions {
+		if len(e.Date) < 10 {
+			continue
+		}
+		merged[e.Date[:10]] = e
+	}
+	s.exceptions = merged
+	return s
+}
+
+// Matches reports whether the rule produces an occurrence on date, ignoring exceptions.
+func (s Series) Matches(date time.Time) bool {
+	date = dateOf(date)
+	if !s.Start.IsZero() && date.Before(s.Start) {
+		return false
+	}
+	if s.Rule.Until != nil && date.After(*s.Rule.Until) {
+		return false
+	}
+	if !s.Rule.matches(s.anchor(date), date) {
+		return false
+	}
+	if s.Rule.Count > 0 && !s.Start.IsZero() {
+		return s.withinCount(date)
+	}
+	return true
+}
+
+// On returns the occurrence on date with exceptions applied; skipped dates report false.
+func (s Series) On(date time.Time) (Occurrence, bool) {
+	if !s.Matches(date) {
+		return Occurrence{}, false
+	}
+	return s.apply(dateOf(date))
+}
+
+// Between expands the occurrences from from to to, both inclusive, with exceptions applied.
+func (s Series) Between(from, to time.Time) []Occurrence {
+	from, to = dateOf(from), dateOf(to)
+	if !s.Start.IsZero() && from.Before(s.Start) {
+		from = s.Start
+	}
+	if s.Rule.Until != nil && to.After(*s.Rule.Until) {
+		to = *s.Rule.Until
+	}
+
+	out := make([]Occurrence, 0)
+	counted := 0
+	cursor := from
+	if s.Rule.Count > 0 && !s.Start.IsZero() {
+		// COUNT includes occurrences before the window, so walk from the start.
+		cursor = s.Start
+	}
+	for ; !cursor.After(to); cursor = cursor.AddDate(0, 0, 1) {
+		if !s.Rule.matches(s.anchor(cursor), cursor) {
+			continue
+		}
+		if s.Rule.Count > 0 && !s.Start.IsZero() {
+			counted++
+			if counted > s.Rule.Count {
+				break
+			}
+		}
+		if cursor.Before(from) {
+			continue
+		}
+		if occ, ok := s.apply(cursor); ok {
+			out = append(out, occ)
+		}
+	}
+	return out
+}
+
+// maxNextSearchDays bounds Next, so rules that stopped producing dates (UNTIL, BYMONTHDAY=31
+// with BYMONTH=2, ...) end instead of looping.
+const maxNextSearchDays = 10 * 366
+
+// Next returns the first date strictly after after on which the rule fires, ignoring exceptions.
+// It reports false when the series has ended.
+func (s Series) Next(after time.Time) (time.Time, bool) {
+	cursor := dateOf(after).AddDate(0, 0, 1)
+	if !s.Start.IsZero() && cursor.Before(s.Start) {
+		cursor = s.Start
+	}
+	for i := 0; i < maxNextSearchDays; i++ {
+		if s.Rule.Until != nil && cursor.After(*s.Rule.Until) {
+			return time.Time{}, false
+		}
+		if s.Matches(cursor) {
+			return cursor, true
+		}
+		cursor = cursor.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+func (s Series) apply(date time.Time) (Occurrence, bool) {
+	occ := Occurrence{Date: date, StartTime: s.StartTime, EndTime: s.EndTime}
+	e, ok := s.exceptions[date.Format("2006-01-02")]
+	if !ok {
+		return occ, true
+	}
+	switch e.Action {
+	case ActionSkip, ActionRest, ActionAway, ActionHoliday:
+		return Occurrence{}, false
+	case ActionReschedule:
+		if e.StartTime != nil && *e.StartTime != "" {
+			occ.StartTime = *e.StartTime
+			occ.Rescheduled = true
+		}
+		if e.EndTime != nil && *e.EndTime != "" {
+			occ.EndTime = *e.EndTime
+			occ.Rescheduled = true
+		}
+	}
+	return occ, true
+}
+
+// anchor is the date intervals are counted from. Series without a start fall back to the
+// date itself, so every interval matches.
+func (s Series) anchor(date time.Time) time.Time {
+	if s.Start.IsZero() {
+		return date
+	}
+	return s.Start
+}
+
+// withinCount reports whether the occurrence on date is one of the first COUNT, extending the
+// cached walk only up to date.
+func (s Series) withinCount(date time.Time) bool {
+	c := s.count
+	if c == nil {
+		c = &countCursor{cursor: s.Start}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.last.IsZero() && !c.cursor.After(date) {
+		if s.Rule.matches(s.Start, c.cursor) {
+			c.n++
+			if c.n == s.Rule.Count {
+				c.last = c.cursor
+			}
+		}
+		c.cursor = c.cursor.AddDate(0, 0, 1)
+	}
+	return c.last.IsZero() || !date.After(c.last)
+}
@@ -28,6 +28,9 @@ type AgendaItem struct {
 	FlagColor      *string `db:"flag_color"`
 	SubflagName    *string `db:"subflag_name"`
 	SubflagColor   *string `db:"subflag_color"`
+	ProjectID      *string `db:"project_id"`
+	ProjectName    *string `db:"project_name"`
+	Blocked        bool    `db:"blocked"`
 
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type AwayPeriodRepository interface {
+	Create(ctx context.Context, period domain.AwayPeriod) (domain.AwayPeriod, error)
+	Delete(ctx context.Context, userID, id string) error
+	// ListByUser returns every period of the user, the latest first.
+	ListByUser(ctx context.Context, userID string) ([]domain.AwayPeriod, error)
+	// ListOverlapping returns the periods of the given users that overlap from..to (inclusive dates).
+	ListOverlapping(ctx context.Context, userIDs []string, from, to string) ([]domain.AwayPeriod, error)
+}
@@ -7,4 +7,7 @@ type ListOptions struct {
 	Cursor  string
 	StartAt *time.Time
 	EndAt   *time.Time
+
+	// ProjectID restricts task listings (tasks and agenda) to a project.
+	ProjectID *string
 }
@@ -10,5 +10,6 @@ type DeviceTokenRepository interface {
 	Upsert(ctx context.Context, dt domain.DeviceToken) error
 	Delete(ctx context.Context, deviceID, userID string) error
 	ListByUserID(ctx context.Context, userID string) ([]domain.DeviceToken, error)
+	ListByUserIDs(ctx context.Context, userIDs []string) ([]domain.DeviceToken, error)
 	Deactivate(ctx context.Context, topic string) error
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type DigestSettingsRepository interface {
+	// GetByUserID returns nil when the user never customized the digest.
+	GetByUserID(ctx context.Context, userID string) (*domain.DigestSettings, error)
+	Upsert(ctx context.Context, settings domain.DigestSettings) (domain.DigestSettings, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"inbota/backend/internal/app/domain"
 )
@@ -9,4 +10,8 @@ import (
 type EmailDigestRepository interface {
 	Create(ctx context.Context, digest *domain.EmailDigest) (created bool, err error)
 	Update(ctx context.Context, digest *domain.EmailDigest) error
+	// ListRetryable returns failed digests whose next attempt is due.
+	ListRetryable(ctx context.Context, now time.Time, limit int) ([]domain.EmailDigest, error)
+	// UpdateProviderStatus records a webhook delivery event for the digest sent with providerID.
+	UpdateProviderStatus(ctx context.Context, providerID, status string, at time.Time) error
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type EmailSuppressionRepository interface {
+	// Suppress records the address; suppressing it again keeps the first reason.
+	Suppress(ctx context.Context, suppression domain.EmailSuppression) error
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
@@ -13,5 +13,7 @@ type EventRepository interface {
 	Delete(ctx context.Context, userID, id string) error
 	Get(ctx context.Context, userID, id string) (domain.Event, error)
 	List(ctx context.Context, userID string, opts ListOptions) ([]domain.Event, *string, error)
+	// ListUpcoming returns the items due in [start, end], plus later ones whose own lead times
+	// (notify_lead_mins) already fall before end.
 	ListUpcoming(ctx context.Context, start, end time.Time) ([]domain.Event, error)
 }
@@ -12,5 +12,7 @@ type FlagRepository interface {
 	Delete(ctx context.Context, userID, id string) error
 	Get(ctx context.Context, userID, id string) (domain.Flag, error)
 	GetByIDs(ctx context.Context, userID string, ids []string) ([]domain.Flag, error)
+	// GetByIDsForUsers loads the flags of several users at once, keyed by user id in idsByUser.
+	GetByIDsForUsers(ctx context.Context, idsByUser map[string][]string) ([]domain.Flag, error)
 	List(ctx context.Context, userID string, opts ListOptions) ([]domain.Flag, *string, error)
 }
@@ -30,6 +30,9 @@ type HomeFocusTask struct {
 	Description *string
 	Status      string
 	DueAt       *time.Time
+	Priority    *string
+	EffortMins  *int
+	Blocked     bool
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
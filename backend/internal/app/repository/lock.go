@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// LeaderLock elects a single instance to run background jobs.
+type LeaderLock interface {
+	// TryAcquire returns true while the caller holds the lock. It is safe to call on every tick.
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
@@ -10,10 +10,27 @@ import (
 type NotificationLogRepository interface {
 	Create(ctx context.Context, log domain.NotificationLog) (domain.NotificationLog, error)
 	ListPending(ctx context.Context, scheduledBefore time.Time) ([]domain.NotificationLog, error)
+	// ClaimPending leases up to limit due rows for owner; rows whose lease expired can be claimed again.
+	ClaimPending(ctx context.Context, scheduledBefore time.Time, limit int, lease time.Duration, owner string) ([]domain.NotificationLog, error)
+	// ClaimPendingForUser leases the user's pending push rows due up to scheduledBefore (used for bundling).
+	ClaimPendingForUser(ctx context.Context, userID string, scheduledBefore time.Time, lease time.Duration, owner string) ([]domain.NotificationLog, error)
+	// MarkBundleSent marks the rows as sent and links them to a new bundle id.
+	MarkBundleSent(ctx context.Context, ids []string) (string, error)
+	PendingBacklog(ctx context.Context, scheduledBefore time.Time) (int, *time.Time, error)
 	UpdateStatus(ctx context.Context, id string, status domain.NotificationStatus, errorMsg *string) error
 	ListByUserID(ctx context.Context, userID string, limit, offset int) ([]domain.NotificationLog, error)
 	MarkAsRead(ctx context.Context, id, userID string) error
 	MarkAllAsRead(ctx context.Context, userID string) error
 	Exists(ctx context.Context, referenceID string, leadMins *int) (bool, error)
+	// ListAcknowledged reports which reference ids have at least one read notification.
+	ListAcknowledged(ctx context.Context, referenceIDs []string) (map[string]bool, error)
 	UpdateScheduledFor(ctx context.Context, id string, scheduledFor time.Time) error
+	// CancelPending cancels the reference's pending rows scheduled in [from, to) and returns how many changed.
+	CancelPending(ctx context.Context, referenceID string, from, to time.Time) (int, error)
+	// Release frees the (referenceID, leadMins) dedup key: pending rows are cancelled and sent ones
+	// marked as read, so the reference can be notified again.
+	Release(ctx context.Context, referenceID string, leadMins int) (int, error)
+	// CancelPendingByType cancels the user's pending rows of the given types scheduled in [from, to).
+	// A non-nil flagID limits it to routines and tasks of that flag.
+	CancelPendingByType(ctx context.Context, userID string, types []domain.NotificationType, flagID *string, from, to time.Time) (int, error)
 }
@@ -10,6 +10,9 @@ type NotificationPreferencesRepository interface {
 	GetByUserID(ctx context.Context, userID string) (domain.NotificationPreferences, error)
 	Upsert(ctx context.Context, prefs domain.NotificationPreferences) error
 	ListEnabled(ctx context.Context) ([]domain.NotificationPreferences, error)
+	ListByUserIDs(ctx context.Context, userIDs []string) ([]domain.NotificationPreferences, error)
+	ListWithBriefings(ctx context.Context) ([]domain.NotificationPreferences, error)
+	ListWeeklyDigestEnabled(ctx context.Context) ([]domain.NotificationPreferences, error)
 
 	// Public daily-summary token helpers
 	GetDailySummaryTokenByUserID(ctx context.Context, userID string) (string, error)
@@ -7,5 +7,11 @@ import (
 )
 
 type NotificationTemplateRepository interface {
+	// GetAll returns only active templates (scheduler cache).
 	GetAll(ctx context.Context) ([]domain.NotificationTemplate, error)
+	List(ctx context.Context) ([]domain.NotificationTemplate, error)
+	Get(ctx context.Context, id string) (domain.NotificationTemplate, error)
+	Create(ctx context.Context, tpl domain.NotificationTemplate) (domain.NotificationTemplate, error)
+	Update(ctx context.Context, tpl domain.NotificationTemplate) (domain.NotificationTemplate, error)
+	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type ProjectRepository interface {
+	Create(ctx context.Context, project domain.Project) (domain.Project, error)
+	Update(ctx context.Context, project domain.Project) (domain.Project, error)
+	Delete(ctx context.Context, userID, id string) error
+	Get(ctx context.Context, userID, id string) (domain.Project, error)
+	GetByIDs(ctx context.Context, userID string, ids []string) ([]domain.Project, error)
+	// FindByName matches the name case-insensitively among the projects that are not archived.
+	FindByName(ctx context.Context, userID, name string) (domain.Project, error)
+	List(ctx context.Context, userID string, status *domain.ProjectStatus, opts ListOptions) ([]domain.Project, *string, error)
+	// Progress counts the tasks of each project; projects without tasks are left out.
+	Progress(ctx context.Context, userID string, projectIDs []string) (map[string]domain.ProjectProgress, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type PushTopicRepository interface {
+	// GetOrCreate returns the user's topic, storing candidate when the user has none yet.
+	GetOrCreate(ctx context.Context, candidate domain.PushTopic) (domain.PushTopic, error)
+	// Rotate replaces the user's topic and key and moves the user's devices to it.
+	Rotate(ctx context.Context, topic domain.PushTopic) (domain.PushTopic, error)
+}
@@ -13,5 +13,9 @@ type ReminderRepository interface {
 	Delete(ctx context.Context, userID, id string) error
 	Get(ctx context.Context, userID, id string) (domain.Reminder, error)
 	List(ctx context.Context, userID string, opts ListOptions) ([]domain.Reminder, *string, error)
+	// ListUpcoming returns the items due in [start, end], plus later ones whose own lead times
+	// (notify_lead_mins) already fall before end.
 	ListUpcoming(ctx context.Context, start, end time.Time) ([]domain.Reminder, error)
+	// ListEscalating returns OPEN reminders in nag mode whose repeats are still due at now.
+	ListEscalating(ctx context.Context, now time.Time) ([]domain.Reminder, error)
 }
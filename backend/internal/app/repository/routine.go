@@ -10,6 +10,7 @@ type RoutineDailyStatus struct {
 	domain.Routine
 	CompletedAt     *string `db:"completed_at"`
 	IsCompleted     bool    `db:"is_completed"`
+	Progress        float64 `db:"progress"`
 	ExceptionAction *string `db:"exception_action"`
 }
 
@@ -31,12 +32,18 @@ type RoutineExceptionRepository interface {
 	Delete(ctx context.Context, userID, routineID, exceptionDate string) error
 	GetByRoutine(ctx context.Context, userID, routineID string) ([]domain.RoutineException, error)
 	GetForDate(ctx context.Context, userID, routineID, date string) (*domain.RoutineException, error)
+	ListByDate(ctx context.Context, userID, date string) ([]domain.RoutineException, error)
+	// ListForRoutines returns the exceptions of the routines between from and to (inclusive dates), for any user.
+	ListForRoutines(ctx context.Context, routineIDs []string, from, to string) ([]domain.RoutineException, error)
 }
 
 type RoutineCompletionRepository interface {
 	Create(ctx context.Context, userID string, completion domain.RoutineCompletion) (domain.RoutineCompletion, error)
+	// AddProgress adds completion.Value to the day's progress and returns the accumulated value.
+	AddProgress(ctx context.Context, userID string, completion domain.RoutineCompletion) (domain.RoutineCompletion, error)
 	Delete(ctx context.Context, userID, routineID, completedOn string) error
 	GetByRoutine(ctx context.Context, userID, routineID string) ([]domain.RoutineCompletion, error)
 	GetByDate(ctx context.Context, userID, date string) ([]domain.RoutineCompletion, error)
-	GetStreak(ctx context.Context, userID, routineID string) (int, int, error)
+	// ListBetween returns the completions of all the user's routines between from and to (inclusive dates).
+	ListBetween(ctx context.Context, userID, from, to string) ([]domain.RoutineCompletion, error)
 }
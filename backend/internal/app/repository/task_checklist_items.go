@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type TaskChecklistItemRepository interface {
+	Create(ctx context.Context, item domain.TaskChecklistItem) (domain.TaskChecklistItem, error)
+	Update(ctx context.Context, item domain.TaskChecklistItem) (domain.TaskChecklistItem, error)
+	Delete(ctx context.Context, userID, id string) error
+	Get(ctx context.Context, userID, id string) (domain.TaskChecklistItem, error)
+	ListByTask(ctx context.Context, userID, taskID string) ([]domain.TaskChecklistItem, error)
+	// ListByTasks returns the checklist items of several tasks, ordered by task and sort order.
+	ListByTasks(ctx context.Context, userID string, taskIDs []string) ([]domain.TaskChecklistItem, error)
+}
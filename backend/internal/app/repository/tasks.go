@@ -7,11 +7,31 @@ import (
 	"inbota/backend/internal/app/domain"
 )
 
+// TaskDependencyNode is a task of the user's dependency graph.
+type TaskDependencyNode struct {
+	ID        string
+	Status    domain.TaskStatus
+	BlockedBy []string
+}
+
 type TaskRepository interface {
 	Create(ctx context.Context, task domain.Task) (domain.Task, error)
 	Update(ctx context.Context, task domain.Task) (domain.Task, error)
+	// UpdateIfOpen saves task only while the stored row is not DONE yet; it reports false
+	// when another request completed it first.
+	UpdateIfOpen(ctx context.Context, task domain.Task) (domain.Task, bool, error)
 	Delete(ctx context.Context, userID, id string) error
 	Get(ctx context.Context, userID, id string) (domain.Task, error)
 	List(ctx context.Context, userID string, opts ListOptions) ([]domain.Task, *string, error)
+	// ListOpen returns the user's open tasks, oldest due date first.
+	ListOpen(ctx context.Context, userID string, limit int) ([]domain.Task, error)
+	// ListSeries returns every task of a recurring series (seriesID is the first task), newest first.
+	ListSeries(ctx context.Context, userID, seriesID string) ([]domain.Task, error)
+	// ListUpcoming returns the items due in [start, end], plus later ones whose own lead times
+	// (notify_lead_mins) already fall before end.
 	ListUpcoming(ctx context.Context, start, end time.Time) ([]domain.Task, error)
+	// ListDependencyGraph returns the tasks in ids plus every task of the user that waits on another one, by id.
+	ListDependencyGraph(ctx context.Context, userID string, ids []string) (map[string]TaskDependencyNode, error)
+	// ListUnblocked returns open tasks whose blockers are all done, the last one completed since since.
+	ListUnblocked(ctx context.Context, since time.Time) ([]domain.Task, error)
 }
@@ -7,6 +7,7 @@ type TxRepositories struct {
 	Inbox         InboxRepository
 	Suggestions   AiSuggestionRepository
 	Tasks         TaskRepository
+	TaskChecklist TaskChecklistItemRepository
 	Reminders     ReminderRepository
 	Events        EventRepository
 	ShoppingLists ShoppingListRepository
@@ -10,4 +10,6 @@ type UserRepository interface {
 	Create(ctx context.Context, user domain.User) (domain.User, error)
 	Get(ctx context.Context, id string) (domain.User, error)
 	FindByEmail(ctx context.Context, email string) (domain.User, error)
+	ListByIDs(ctx context.Context, ids []string) ([]domain.User, error)
+	UpdateHolidayCalendar(ctx context.Context, id, code string) error
 }
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/recurrence"
 )
 
 var ErrAISchemaInvalid = errors.New("ai_schema_invalid")
@@ -26,18 +29,44 @@ type AIContext struct {
 	SubflagID *string `json:"subflagId,omitempty"`
 }
 
+// NotificationPayload overrides the user's default lead times for one item.
+// LeadMins nil keeps the defaults; 0 means "at time".
+type NotificationPayload struct {
+	LeadMins []int `json:"leadMins,omitempty"`
+	Disabled bool  `json:"disabled,omitempty"`
+}
+
 type TaskPayload struct {
-	DueAt *time.Time
+	DueAt        *time.Time
+	Notification *NotificationPayload
+	// Checklist lists the ordered steps of the task ("passaporte", "mala", ...).
+	Checklist []string
+	// Project is the name of an existing project the task belongs to.
+	Project *string
+	// Priority is P1..P4 (Eisenhower quadrant); EffortMins is the estimated work in minutes.
+	Priority   *string
+	EffortMins *int
+	// Recurrence repeats the task when it is completed.
+	Recurrence *TaskRecurrencePayload
+}
+
+// TaskRecurrencePayload is either a fixed RRULE schedule ("pagar aluguel todo dia 5") or a
+// number of days after the completion ("trocar filtro a cada 90 dias"), never both.
+type TaskRecurrencePayload struct {
+	Rule      *string `json:"rule,omitempty"`
+	AfterDays *int    `json:"afterDays,omitempty"`
 }
 
 type ReminderPayload struct {
-	At time.Time
+	At           time.Time
+	Notification *NotificationPayload
 }
 
 type EventPayload struct {
-	Start  time.Time
-	End    *time.Time
-	AllDay bool
+	Start        time.Time
+	End          *time.Time
+	AllDay       bool
+	Notification *NotificationPayload
 }
 
 type ShoppingItemPayload struct {
@@ -61,6 +90,9 @@ type RoutinePayload struct {
 	WeekOfMonth    *int    `json:"weekOfMonth,omitempty"`
 	StartsOn       *string `json:"startsOn,omitempty"`
 	EndsOn         *string `json:"endsOn,omitempty"`
+	SkipHolidays   bool    `json:"skipHolidays,omitempty"`
+
+	Notification *NotificationPayload `json:"notification,omitempty"`
 }
 
 type ValidatedOutput struct {
@@ -199,9 +231,17 @@ func normalizeOutputAliases(raw []byte) []byte {
 	typ, _ := generic["type"].(string)
 	payloadMap, hasPayload := generic["payload"].(map[string]any)
 	if hasPayload {
+		if notificationMap, ok := payloadMap["notification"].(map[string]any); ok {
+			renameKey(notificationMap, "lead_mins", "leadMins")
+		}
 		switch strings.ToLower(strings.TrimSpace(typ)) {
 		case "task":
 			renameKey(payloadMap, "due_at", "dueAt")
+			renameKey(payloadMap, "effort_mins", "effortMins")
+			if recurrenceMap, ok := payloadMap["recurrence"].(map[string]any); ok {
+				renameKey(recurrenceMap, "after_days", "afterDays")
+				renameKey(recurrenceMap, "rrule", "rule")
+			}
 		case "reminder":
 			renameKey(payloadMap, "remindAt", "at")
 			renameKey(payloadMap, "reminderAt", "at")
@@ -408,7 +448,13 @@ func (v *AiSchemaValidator) validatePayload(typ string, payload json.RawMessage)
 
 func parseTaskPayload(payload json.RawMessage) (TaskPayload, error) {
 	var raw struct {
-		DueAt *string `json:"dueAt"`
+		DueAt        *string                `json:"dueAt"`
+		Notification *NotificationPayload   `json:"notification"`
+		Checklist    []string               `json:"checklist"`
+		Project      *string                `json:"project"`
+		Priority     *string                `json:"priority"`
+		EffortMins   *int                   `json:"effortMins"`
+		Recurrence   *TaskRecurrencePayload `json:"recurrence"`
 	}
 	if err := decodeStrict(payload, &raw); err != nil {
 		return TaskPayload{}, err
@@ -422,12 +468,81 @@ func parseTaskPayload(payload json.RawMessage) (TaskPayload, error) {
 		}
 		dueAt = &parsed
 	}
-	return TaskPayload{DueAt: dueAt}, nil
+	notification, err := validateNotificationPayload(raw.Notification, domain.MaxNotificationLeadMins)
+	if err != nil {
+		return TaskPayload{}, err
+	}
+	var checklist []string
+	for _, step := range raw.Checklist {
+		step = strings.TrimSpace(step)
+		if step == "" {
+			return TaskPayload{}, fmt.Errorf("%w: task_checklist_title_required", ErrAISchemaInvalid)
+		}
+		checklist = append(checklist, step)
+	}
+	var project *string
+	if raw.Project != nil && strings.TrimSpace(*raw.Project) != "" {
+		name := strings.TrimSpace(*raw.Project)
+		project = &name
+	}
+	var priority *string
+	if raw.Priority != nil && strings.TrimSpace(*raw.Priority) != "" {
+		value := strings.ToUpper(strings.TrimSpace(*raw.Priority))
+		switch value {
+		case "P1", "P2", "P3", "P4":
+		default:
+			return TaskPayload{}, fmt.Errorf("%w: task_priority_invalid", ErrAISchemaInvalid)
+		}
+		priority = &value
+	}
+	var effortMins *int
+	if raw.EffortMins != nil && *raw.EffortMins != 0 {
+		if *raw.EffortMins < 0 || *raw.EffortMins > domain.MaxTaskEffortMins {
+			return TaskPayload{}, fmt.Errorf("%w: task_effort_invalid", ErrAISchemaInvalid)
+		}
+		effortMins = raw.EffortMins
+	}
+	taskRecurrence, err := validateTaskRecurrencePayload(raw.Recurrence)
+	if err != nil {
+		return TaskPayload{}, err
+	}
+	return TaskPayload{DueAt: dueAt, Notification: notification, Checklist: checklist, Project: project, Priority: priority, EffortMins: effortMins, Recurrence: taskRecurrence}, nil
+}
+
+// validateTaskRecurrencePayload accepts a rule or afterDays; an empty object means no recurrence.
+func validateTaskRecurrencePayload(payload *TaskRecurrencePayload) (*TaskRecurrencePayload, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	var rule *string
+	if payload.Rule != nil && strings.TrimSpace(*payload.Rule) != "" {
+		parsed, err := recurrence.Parse(*payload.Rule)
+		if err != nil || parsed.Count > 0 {
+			return nil, fmt.Errorf("%w: task_recurrence_rule_invalid", ErrAISchemaInvalid)
+		}
+		canonical := parsed.String()
+		rule = &canonical
+	}
+	afterDays := payload.AfterDays
+	if afterDays != nil && *afterDays == 0 {
+		afterDays = nil
+	}
+	if afterDays != nil && (*afterDays < 0 || *afterDays > domain.MaxTaskRecurrenceAfterDays) {
+		return nil, fmt.Errorf("%w: task_recurrence_after_days_invalid", ErrAISchemaInvalid)
+	}
+	if rule != nil && afterDays != nil {
+		return nil, fmt.Errorf("%w: task_recurrence_ambiguous", ErrAISchemaInvalid)
+	}
+	if rule == nil && afterDays == nil {
+		return nil, nil
+	}
+	return &TaskRecurrencePayload{Rule: rule, AfterDays: afterDays}, nil
 }
 
 func parseReminderPayload(payload json.RawMessage) (ReminderPayload, error) {
 	var raw struct {
-		At *string `json:"at"`
+		At           *string              `json:"at"`
+		Notification *NotificationPayload `json:"notification"`
 	}
 	if err := decodeStrict(payload, &raw); err != nil {
 		return ReminderPayload{}, err
@@ -439,14 +554,19 @@ func parseReminderPayload(payload json.RawMessage) (ReminderPayload, error) {
 	if err != nil {
 		return ReminderPayload{}, err
 	}
-	return ReminderPayload{At: parsed}, nil
+	notification, err := validateNotificationPayload(raw.Notification, domain.MaxNotificationLeadMins)
+	if err != nil {
+		return ReminderPayload{}, err
+	}
+	return ReminderPayload{At: parsed, Notification: notification}, nil
 }
 
 func parseEventPayload(payload json.RawMessage) (EventPayload, error) {
 	var raw struct {
-		Start  *string `json:"start"`
-		End    *string `json:"end"`
-		AllDay *bool   `json:"allDay"`
+		Start        *string              `json:"start"`
+		End          *string              `json:"end"`
+		AllDay       *bool                `json:"allDay"`
+		Notification *NotificationPayload `json:"notification"`
 	}
 	if err := decodeStrict(payload, &raw); err != nil {
 		return EventPayload{}, err
@@ -474,7 +594,11 @@ func parseEventPayload(payload json.RawMessage) (EventPayload, error) {
 	if raw.AllDay != nil {
 		allDay = *raw.AllDay
 	}
-	return EventPayload{Start: start, End: endPtr, AllDay: allDay}, nil
+	notification, err := validateNotificationPayload(raw.Notification, domain.MaxNotificationLeadMins)
+	if err != nil {
+		return EventPayload{}, err
+	}
+	return EventPayload{Start: start, End: endPtr, AllDay: allDay, Notification: notification}, nil
 }
 
 func parseShoppingPayload(payload json.RawMessage) (ShoppingPayload, error) {
@@ -524,6 +648,9 @@ func parseRoutinePayload(payload json.RawMessage) (RoutinePayload, error) {
 		WeekOfMonth    *int    `json:"weekOfMonth"`
 		StartsOn       *string `json:"startsOn"`
 		EndsOn         *string `json:"endsOn"`
+		SkipHolidays   bool    `json:"skipHolidays"`
+
+		Notification *NotificationPayload `json:"notification"`
 	}
 	if err := decodeStrict(payload, &raw); err != nil {
 		return RoutinePayload{}, err
@@ -552,6 +679,11 @@ func parseRoutinePayload(payload json.RawMessage) (RoutinePayload, error) {
 		return RoutinePayload{}, fmt.Errorf("%w: routine_invalid_recurrence_type", ErrAISchemaInvalid)
 	}
 
+	notification, err := validateNotificationPayload(raw.Notification, domain.MaxRoutineLeadMins)
+	if err != nil {
+		return RoutinePayload{}, err
+	}
+
 	return RoutinePayload{
 		Weekdays:       raw.Weekdays,
 		StartTime:      raw.StartTime,
@@ -560,9 +692,23 @@ func parseRoutinePayload(payload json.RawMessage) (RoutinePayload, error) {
 		WeekOfMonth:    raw.WeekOfMonth,
 		StartsOn:       raw.StartsOn,
 		EndsOn:         raw.EndsOn,
+		SkipHolidays:   raw.SkipHolidays,
+		Notification:   notification,
 	}, nil
 }
 
+func validateNotificationPayload(notification *NotificationPayload, maxLead int) (*NotificationPayload, error) {
+	if notification == nil {
+		return nil, nil
+	}
+	for _, lead := range notification.LeadMins {
+		if lead < 0 || lead > maxLead {
+			return nil, fmt.Errorf("%w: notification_lead_mins_out_of_range", ErrAISchemaInvalid)
+		}
+	}
+	return notification, nil
+}
+
 func decodeStrict(payload json.RawMessage, target any) error {
 	dec := json.NewDecoder(bytes.NewReader(payload))
 	dec.DisallowUnknownFields()
@@ -44,3 +44,165 @@ func TestAiSchemaValidatorValidateManyArrayInsideMarkdown(t *testing.T) {
 		t.Fatalf("unexpected titles: %#v", []string{outs[0].Output.Title, outs[1].Output.Title})
 	}
 }
+
+func TestAiSchemaValidatorReminderNotificationOverride(t *testing.T) {
+	v := NewAiSchemaValidator()
+
+	raw := []byte(`{"type":"reminder","title":"Ligar pro dentista","needs_review":false,"payload":{"at":"2026-03-10T15:00:00-03:00","notification":{"lead_mins":[60]}}}`)
+
+	out, err := v.Validate(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, ok := out.Payload.(ReminderPayload)
+	if !ok {
+		t.Fatalf("expected ReminderPayload, got %T", out.Payload)
+	}
+	if payload.Notification == nil || len(payload.Notification.LeadMins) != 1 || payload.Notification.LeadMins[0] != 60 {
+		t.Fatalf("expected notification leadMins [60], got %+v", payload.Notification)
+	}
+}
+
+func TestAiSchemaValidatorRejectsNegativeNotificationLead(t *testing.T) {
+	v := NewAiSchemaValidator()
+
+	raw := []byte(`{"type":"task","title":"Pagar boleto","needs_review":false,"payload":{"dueAt":null,"notification":{"leadMins":[-5]}}}`)
+
+	if _, err := v.Validate(raw); err == nil {
+		t.Fatalf("expected error for negative lead time")
+	}
+}
+
+func TestAiSchemaValidatorRoutineSkipHolidays(t *testing.T) {
+	v := NewAiSchemaValidator()
+
+	raw := []byte(`{"type":"routine","title":"Academia","needs_review":false,"payload":{"weekdays":[1,2,3,4,5],"startTime":"07:00","endTime":"08:00","recurrenceType":"weekly","skipHolidays":true}}`)
+
+	out, err := v.Validate(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, ok := out.Payload.(RoutinePayload)
+	if !ok {
+		t.Fatalf("expected RoutinePayload, got %T", out.Payload)
+	}
+	if !payload.SkipHolidays {
+		t.Fatalf("expected skipHolidays to be kept")
+	}
+}
+
+func TestAiSchemaValidatorTaskChecklist(t *testing.T) {
+	v := NewAiSchemaValidator()
+
+	raw := []byte(`{"type":"task","title":"Preparar viagem","needs_review":false,"payload":{"dueAt":null,"checklist":["passaporte"," mala ","reservar hotel"]}}`)
+
+	out, err := v.Validate(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, ok := out.Payload.(TaskPayload)
+	if !ok {
+		t.Fatalf("expected TaskPayload, got %T", out.Payload)
+	}
+	if len(payload.Checklist) != 3 || payload.Checklist[1] != "mala" {
+		t.Fatalf("expected 3 trimmed checklist steps, got %+v", payload.Checklist)
+	}
+
+	blank := []byte(`{"type":"task","title":"Preparar viagem","needs_review":false,"payload":{"checklist":["passaporte",""]}}`)
+	if _, err := v.Validate(blank); err == nil {
+		t.Fatalf("expected error for blank checklist step")
+	}
+}
+
+func TestAiSchemaValidatorTaskProject(t *testing.T) {
+	v := NewAiSchemaValidator()
+
+	raw := []byte(`{"type":"task","title":"Comprar tinta","needs_review":false,"payload":{"dueAt":null,"project":" Reforma "}}`)
+	out, err := v.Validate(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, ok := out.Payload.(TaskPayload)
+	if !ok {
+		t.Fatalf("expected TaskPayload, got %T", out.Payload)
+	}
+	if payload.Project == nil || *payload.Project != "Reforma" {
+		t.Fatalf("expected trimmed project name, got %v", payload.Project)
+	}
+
+	blank := []byte(`{"type":"task","title":"Comprar tinta","needs_review":false,"payload":{"project":"  "}}`)
+	out, err = v.Validate(blank)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload := out.Payload.(TaskPayload); payload.Project != nil {
+		t.Fatalf("expected blank project to be dropped, got %q", *payload.Project)
+	}
+}
+
+func TestAiSchemaValidatorTaskPriorityAndEffort(t *testing.T) {
+	v := NewAiSchemaValidator()
+
+	raw := []byte(`{"type":"task","title":"Entregar relatorio","needs_review":false,"payload":{"dueAt":null,"priority":"p1","effort_mins":45}}`)
+	out, err := v.Validate(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, ok := out.Payload.(TaskPayload)
+	if !ok {
+		t.Fatalf("expected TaskPayload, got %T", out.Payload)
+	}
+	if payload.Priority == nil || *payload.Priority != "P1" {
+		t.Fatalf("expected priority P1, got %v", payload.Priority)
+	}
+	if payload.EffortMins == nil || *payload.EffortMins != 45 {
+		t.Fatalf("expected effortMins 45, got %v", payload.EffortMins)
+	}
+
+	invalid := []byte(`{"type":"task","title":"Entregar relatorio","needs_review":false,"payload":{"priority":"alta"}}`)
+	if _, err := v.Validate(invalid); err == nil {
+		t.Fatalf("expected error for invalid priority")
+	}
+	negative := []byte(`{"type":"task","title":"Entregar relatorio","needs_review":false,"payload":{"effortMins":-10}}`)
+	if _, err := v.Validate(negative); err == nil {
+		t.Fatalf("expected error for negative effort")
+	}
+}
+
+func TestAiSchemaValidatorTaskRecurrence(t *testing.T) {
+	v := NewAiSchemaValidator()
+
+	raw := []byte(`{"type":"task","title":"Pagar aluguel","needs_review":false,"payload":{"dueAt":null,"recurrence":{"rrule":"freq=monthly;bymonthday=5"}}}`)
+	out, err := v.Validate(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, ok := out.Payload.(TaskPayload)
+	if !ok {
+		t.Fatalf("expected TaskPayload, got %T", out.Payload)
+	}
+	if payload.Recurrence == nil || payload.Recurrence.Rule == nil || *payload.Recurrence.Rule != "FREQ=MONTHLY;BYMONTHDAY=5" {
+		t.Fatalf("expected canonical rule, got %+v", payload.Recurrence)
+	}
+
+	afterDays := []byte(`{"type":"task","title":"Trocar filtro","needs_review":false,"payload":{"recurrence":{"after_days":90}}}`)
+	out, err = v.Validate(afterDays)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload = out.Payload.(TaskPayload)
+	if payload.Recurrence == nil || payload.Recurrence.AfterDays == nil || *payload.Recurrence.AfterDays != 90 {
+		t.Fatalf("expected afterDays 90, got %+v", payload.Recurrence)
+	}
+
+	invalid := [][]byte{
+		[]byte(`{"type":"task","title":"Pagar aluguel","needs_review":false,"payload":{"recurrence":{"rule":"FREQ=MONTHLY","afterDays":30}}}`),
+		[]byte(`{"type":"task","title":"Pagar aluguel","needs_review":false,"payload":{"recurrence":{"rule":"FREQ=MONTHLY;COUNT=3"}}}`),
+		[]byte(`{"type":"task","title":"Trocar filtro","needs_review":false,"payload":{"recurrence":{"afterDays":-7}}}`),
+	}
+	for _, raw := range invalid {
+		if _, err := v.Validate(raw); err == nil {
+			t.Fatalf("expected error for %s", raw)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/i18n"
+)
+
+// DefaultNotificationLocale is used when a user locale has no matching template.
+const DefaultNotificationLocale = "pt-BR"
+
+// ErrNotificationTemplateInvalid is returned when a template does not parse or
+// references an unknown variable.
+var ErrNotificationTemplateInvalid = errors.New("notification_template_invalid")
+
+// NotificationTemplateVars are the variables available to notification templates,
+// e.g. "{{.Title}} começa em {{.LeadLabel}}".
+type NotificationTemplateVars struct {
+	Title     string
+	LeadMins  int
+	LeadLabel string
+	Location  string
+	FlagName  string
+	Time      string
+}
+
+var sampleNotificationTemplateVars = NotificationTemplateVars{
+	Title:     "Reunião",
+	LeadMins:  30,
+	LeadLabel: "30 minutos",
+	Location:  "Escritório",
+	FlagName:  "Trabalho",
+	Time:      "09:00",
+}
+
+// ParseNotificationTemplate parses text as a text/template and executes it against
+// sample values so unknown variables are rejected up front.
+func ParseNotificationTemplate(name, text string) (*template.Template, error) {
+	tpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotificationTemplateInvalid, err)
+	}
+	if err := tpl.Execute(io.Discard, sampleNotificationTemplateVars); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotificationTemplateInvalid, err)
+	}
+	return tpl, nil
+}
+
+// NormalizeNotificationLocale canonicalizes locales like "pt_br" to "pt-BR".
+func NormalizeNotificationLocale(locale string) string {
+	locale = strings.TrimSpace(strings.ReplaceAll(locale, "_", "-"))
+	if locale == "" {
+		return ""
+	}
+	parts := strings.SplitN(locale, "-", 2)
+	lang := strings.ToLower(parts[0])
+	if len(parts) == 1 || parts[1] == "" {
+		return lang
+	}
+	return lang + "-" + strings.ToUpper(parts[1])
+}
+
+type compiledNotificationTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+// NotificationRenderer keeps the active templates compiled in memory and renders
+// notification messages per locale, falling back to built-in strings.
+type NotificationRenderer struct {
+	mu        sync.RWMutex
+	templates map[string]compiledNotificationTemplate
+}
+
+func NewNotificationRenderer() *NotificationRenderer {
+	return &NotificationRenderer{templates: map[string]compiledNotificationTemplate{}}
+}
+
+// Load replaces the cached templates. Invalid templates are skipped and returned as errors.
+func (r *NotificationRenderer) Load(templates []domain.NotificationTemplate) []error {
+	compiled := make(map[string]compiledNotificationTemplate, len(templates))
+	var errs []error
+	for _, t := range templates {
+		if !t.IsActive {
+			continue
+		}
+		title, err := ParseNotificationTemplate("title", t.TitleTemplate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", t.ID, err))
+			continue
+		}
+		body, err := ParseNotificationTemplate("body", t.BodyTemplate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", t.ID, err))
+			continue
+		}
+		key := notificationTemplateKey(t.Type, t.TriggerKey, NormalizeNotificationLocale(t.Locale))
+		compiled[key] = compiledNotificationTemplate{title: title, body: body}
+	}
+
+	r.mu.Lock()
+	r.templates = compiled
+	r.mu.Unlock()
+	return errs
+}
+
+// Render returns (title, body) for the given type, trigger and locale.
+// Lookup order: templates in the exact locale or its base language, the built-in catalogue
+// when it speaks the user's language, DefaultNotificationLocale templates, built-in defaults.
+// The catalogue comes before pt-BR templates so that a language without seeded templates
+// (e.g. es) is not answered in Portuguese.
+func (r *NotificationRenderer) Render(nType domain.NotificationType, triggerKey, locale string, vars NotificationTemplateVars) (string, string) {
+	locale = NormalizeNotificationLocale(locale)
+	if vars.LeadLabel == "" {
+		vars.LeadLabel = HumanLeadLabel(locale, vars.LeadMins)
+	}
+
+	if title, body, ok := r.renderTemplate(nType, triggerKey, notificationLocaleCandidates(locale), vars); ok {
+		return title, body
+	}
+	if hasDefaultNotificationMessage(nType, triggerKey, locale) {
+		return DefaultNotificationMessage(nType, triggerKey, locale, vars)
+	}
+	if title, body, ok := r.renderTemplate(nType, triggerKey, []string{DefaultNotificationLocale}, vars); ok {
+		return title, body
+	}
+	return DefaultNotificationMessage(nType, triggerKey, locale, vars)
+}
+
+func (r *NotificationRenderer) renderTemplate(nType domain.NotificationType, triggerKey string, locales []string, vars NotificationTemplateVars) (string, string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, candidate := range locales {
+		tpl, ok := r.templates[notificationTemplateKey(nType, triggerKey, candidate)]
+		if !ok {
+			continue
+		}
+		title, err := executeNotificationTemplate(tpl.title, vars)
+		if err != nil {
+			continue
+		}
+		body, err := executeNotificationTemplate(tpl.body, vars)
+		if err != nil {
+			continue
+		}
+		return title, body, true
+	}
+	return "", "", false
+}
+
+// RenderTemplates renders ad-hoc template texts, used by the preview endpoint.
+func RenderTemplates(titleTemplate, bodyTemplate, locale string, vars NotificationTemplateVars) (string, string, error) {
+	if vars.LeadLabel == "" {
+		vars.LeadLabel = HumanLeadLabel(NormalizeNotificationLocale(locale), vars.LeadMins)
+	}
+	titleTpl, err := ParseNotificationTemplate("title", titleTemplate)
+	if err != nil {
+		return "", "", err
+	}
+	bodyTpl, err := ParseNotificationTemplate("body", bodyTemplate)
+	if err != nil {
+		return "", "", err
+	}
+	title, err := executeNotificationTemplate(titleTpl, vars)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrNotificationTemplateInvalid, err)
+	}
+	body, err := executeNotificationTemplate(bodyTpl, vars)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrNotificationTemplateInvalid, err)
+	}
+	return title, body, nil
+}
+
+func executeNotificationTemplate(tpl *template.Template, vars NotificationTemplateVars) (string, error) {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func notificationTemplateKey(nType domain.NotificationType, triggerKey, locale string) string {
+	return string(nType) + "_" + triggerKey + "_" + locale
+}
+
+// notificationLocaleCandidates lists the template locales in the user's own language:
+// the exact locale and its base language.
+func notificationLocaleCandidates(locale string) []string {
+	candidates := make([]string, 0, 2)
+	if locale != "" {
+		candidates = append(candidates, locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			candidates = append(candidates, base)
+		}
+	}
+	return candidates
+}
+
+// hasDefaultNotificationMessage reports whether the built-in catalogue has the message in the
+// user's own language (unsupported locales would only get the pt-BR fallback).
+func hasDefaultNotificationMessage(nType domain.NotificationType, triggerKey, locale string) bool {
+	if i18n.Normalize(locale) == "" {
+		return false
+	}
+	return i18n.For(locale).Has("notification." + string(nType) + "." + triggerKey + ".title")
+}
+
+// HumanLeadLabel formats a lead time in minutes, e.g. "30 minutos" / "30 minutes".
+func HumanLeadLabel(locale string, mins int) string {
+	l := i18n.For(locale)
+	if mins <= 0 {
+		return l.T("notification.lead.soon")
+	}
+	return l.N("notification.lead.minutes", mins)
+}
+
+// DefaultNotificationMessage is the built-in fallback when no template matches.
+func DefaultNotificationMessage(nType domain.NotificationType, triggerKey, locale string, vars NotificationTemplateVars) (string, string) {
+	leadLabel := vars.LeadLabel
+	if leadLabel == "" {
+		leadLabel = HumanLeadLabel(locale, vars.LeadMins)
+	}
+	l := i18n.For(locale)
+	msgVars := i18n.Vars{"title": vars.Title, "lead": leadLabel}
+
+	key := "notification." + string(nType) + "." + triggerKey
+	if l.Has(key + ".title") {
+		return l.T(key+".title", msgVars), l.T(key+".body", msgVars)
+	}
+
+	// fallback final
+	if key := "notification.fallback." + triggerKey; l.Has(key) {
+		return vars.Title, l.T(key, msgVars)
+	}
+	return vars.Title, ""
+}
+
+// maxBundleLines limits how many items are listed in a bundled push body.
+const maxBundleLines = 5
+
+// BundleNotificationMessage builds the summary push for bundled notifications,
+// e.g. "3 itens nos próximos 15 min".
+func BundleNotificationMessage(locale string, windowMins int, lines []string) (string, string) {
+	count := len(lines)
+	shown := lines
+	if len(shown) > maxBundleLines {
+		shown = shown[:maxBundleLines]
+	}
+	body := strings.Join(shown, "\n")
+
+	l := i18n.For(locale)
+	if extra := count - len(shown); extra > 0 {
+		body += "\n" + l.T("notification.bundle.more", i18n.Vars{"count": extra})
+	}
+	return l.N("notification.bundle.title", count, i18n.Vars{"window": windowMins}), body
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"inbota/backend/internal/app/domain"
+)
+
+func TestNotificationRendererLocaleFallback(t *testing.T) {
+	r := NewNotificationRenderer()
+	errs := r.Load([]domain.NotificationTemplate{
+		{ID: "1", Type: domain.NotificationTypeEvent, TriggerKey: "lead_time", Locale: "pt-BR", TitleTemplate: "Evento em {{.LeadLabel}}", BodyTemplate: "{{.Title}} em {{.Location}}", IsActive: true},
+		{ID: "2", Type: domain.NotificationTypeEvent, TriggerKey: "lead_time", Locale: "en", TitleTemplate: "Event in {{.LeadLabel}}", BodyTemplate: "{{.Title}} at {{.Location}}", IsActive: true},
+	})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected load errors: %v", errs)
+	}
+
+	vars := NotificationTemplateVars{Title: "Standup", LeadMins: 15, Location: "Sala 2"}
+
+	title, body := r.Render(domain.NotificationTypeEvent, "lead_time", "en-US", vars)
+	if title != "Event in 15 minutes" || body != "Standup at Sala 2" {
+		t.Fatalf("expected english base-language template, got %q / %q", title, body)
+	}
+
+	title, _ = r.Render(domain.NotificationTypeEvent, "lead_time", "fr-FR", vars)
+	if title != "Evento em 15 minutos" {
+		t.Fatalf("expected default locale template for an unsupported locale, got %q", title)
+	}
+
+	title, body = r.Render(domain.NotificationTypeTask, "at_time", "pt-BR", vars)
+	if title != "Prazo agora" || body != "Standup vence agora." {
+		t.Fatalf("expected built-in fallback, got %q / %q", title, body)
+	}
+}
+
+func TestNotificationRendererSpanishUsesCatalogue(t *testing.T) {
+	r := NewNotificationRenderer()
+	errs := r.Load([]domain.NotificationTemplate{
+		{ID: "1", Type: domain.NotificationTypeReminder, TriggerKey: "at_time", Locale: "pt-BR", TitleTemplate: "Lembrete agora", BodyTemplate: "{{.Title}}", IsActive: true},
+		{ID: "2", Type: domain.NotificationTypeReminder, TriggerKey: "escalation", Locale: "pt-BR", TitleTemplate: "Lembrete pendente há {{.LeadLabel}}", BodyTemplate: "{{.Title}}", IsActive: true},
+		{ID: "3", Type: domain.NotificationTypeTask, TriggerKey: "unblocked", Locale: "pt-BR", TitleTemplate: "Tarefa liberada", BodyTemplate: "{{.Title}} já pode começar.", IsActive: true},
+	})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected load errors: %v", errs)
+	}
+
+	cases := []struct {
+		nType   domain.NotificationType
+		trigger string
+		title   string
+		body    string
+	}{
+		{domain.NotificationTypeReminder, "at_time", "Recordatorio ahora", "Pagar alquiler"},
+		{domain.NotificationTypeReminder, "escalation", "Recordatorio pendiente hace 30 minutos", "Pagar alquiler"},
+		{domain.NotificationTypeTask, "unblocked", "Tarea desbloqueada", "Pagar alquiler ya puede empezar."},
+	}
+	vars := NotificationTemplateVars{Title: "Pagar alquiler", LeadMins: 30}
+	for _, tc := range cases {
+		title, body := r.Render(tc.nType, tc.trigger, "es-MX", vars)
+		if title != tc.title || body != tc.body {
+			t.Fatalf("%s/%s: expected %q / %q, got %q / %q", tc.nType, tc.trigger, tc.title, tc.body, title, body)
+		}
+	}
+}
+
+func TestParseNotificationTemplateRejectsUnknownVariable(t *testing.T) {
+	if _, err := ParseNotificationTemplate("title", "Oi {{.Unknown}}"); !errors.Is(err, ErrNotificationTemplateInvalid) {
+		t.Fatalf("expected ErrNotificationTemplateInvalid, got %v", err)
+	}
+	if _, err := ParseNotificationTemplate("title", "Oi {{.Title"); !errors.Is(err, ErrNotificationTemplateInvalid) {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+	if _, err := ParseNotificationTemplate("title", "{{.Title}} às {{.Time}}"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBundleNotificationMessage(t *testing.T) {
+	lines := []string{"a", "b", "c", "d", "e", "f", "g"}
+
+	title, body := BundleNotificationMessage("pt-BR", 15, lines[:3])
+	if title != "3 itens nos próximos 15 min" || body != "a\nb\nc" {
+		t.Fatalf("unexpected bundle message: %q / %q", title, body)
+	}
+
+	title, body = BundleNotificationMessage("en", 10, lines)
+	if title != "7 items in the next 10 min" || body != "a\nb\nc\nd\ne\n+2 more" {
+		t.Fatalf("unexpected bundle message: %q / %q", title, body)
+	}
+}
@@ -25,6 +25,12 @@ type ContextHint struct {
 	Reason    string
 }
 
+// HolidayItem is an upcoming public holiday of the user's calendar (Date = YYYY-MM-DD).
+type HolidayItem struct {
+	Date string
+	Name string
+}
+
 type PromptInput struct {
 	RawText  string
 	Locale   string
@@ -33,6 +39,9 @@ type PromptInput struct {
 	Contexts []ContextItem
 	Rules    []RuleItem
 	Hint     *ContextHint
+	Holidays []HolidayItem
+	// Projects are the names of the user's open projects.
+	Projects []string
 }
 
 type PromptBuilder struct{}
@@ -58,6 +67,12 @@ func (b *PromptBuilder) Build(input PromptInput) string {
 		}
 		writeLine(&sb, fmt.Sprintf("Now (local): %s", now.Format(time.RFC3339)))
 	}
+	if len(input.Holidays) > 0 {
+		writeLine(&sb, "Upcoming public holidays (not business days):")
+		for _, h := range input.Holidays {
+			writeLine(&sb, fmt.Sprintf("- %s %s", h.Date, h.Name))
+		}
+	}
 	writeLine(&sb, "Raw text:")
 	writeLine(&sb, quoteBlock(input.RawText))
 
@@ -72,6 +87,13 @@ func (b *PromptBuilder) Build(input PromptInput) string {
 		}
 	}
 
+	if len(input.Projects) > 0 {
+		writeLine(&sb, "Open projects:")
+		for _, name := range input.Projects {
+			writeLine(&sb, fmt.Sprintf("- %s", name))
+		}
+	}
+
 	if len(input.Rules) > 0 {
 		writeLine(&sb, "Context rules (keyword -> context):")
 		for _, rule := range input.Rules {
@@ -97,28 +119,39 @@ func (b *PromptBuilder) Build(input PromptInput) string {
 	writeLine(&sb, "Output JSON schema:")
 	writeLine(&sb, `{"type":"task|reminder|event|shopping|note|routine","title":"string","confidence":0.0,"context":{"flagId":"string","subflagId":"string|null"},"needs_review":true,"payload":{...}}`)
 	writeLine(&sb, "Payload by type:")
-	writeLine(&sb, "- task: {\"dueAt\": \"RFC3339|null\"}")
+	writeLine(&sb, "- task: {\"dueAt\": \"RFC3339|null\", \"checklist\": [\"string\"], \"project\": \"string|null\", \"priority\": \"P1|P2|P3|P4|null\", \"effortMins\": \"int|null\", \"recurrence\": {\"rule\": \"RRULE|null\", \"afterDays\": \"int|null\"}}")
 	writeLine(&sb, "- reminder: {\"at\": \"RFC3339\"}")
 	writeLine(&sb, "- event: {\"start\": \"RFC3339\", \"end\": \"RFC3339\", \"allDay\": true}")
 	writeLine(&sb, "- shopping: {\"items\": [{\"title\": \"string\", \"quantity\": \"string|null\"}]}")
 	writeLine(&sb, "- note: {\"content\": \"string\"}")
-	writeLine(&sb, "- routine: {\"weekdays\": [0-6], \"startTime\": \"HH:MM\", \"endTime\": \"HH:MM|null\", \"recurrenceType\": \"weekly|biweekly|triweekly|monthly_week\", \"weekOfMonth\": 1-5|null, \"startsOn\": \"YYYY-MM-DD|null\", \"endsOn\": \"YYYY-MM-DD|null\"}")
+	writeLine(&sb, "- routine: {\"weekdays\": [0-6], \"startTime\": \"HH:MM\", \"endTime\": \"HH:MM|null\", \"recurrenceType\": \"weekly|biweekly|triweekly|monthly_week\", \"weekOfMonth\": 1-5|null, \"startsOn\": \"YYYY-MM-DD|null\", \"endsOn\": \"YYYY-MM-DD|null\", \"skipHolidays\": bool}")
+	writeLine(&sb, "- task, reminder, event and routine may also include an optional \"notification\": {\"leadMins\": [int], \"disabled\": bool}")
 	writeLine(&sb, "Rules:")
 	writeLine(&sb, "- You may return a single item object OR an array of item objects at the root level.")
 	writeLine(&sb, "- Prefer returning an array when the text clearly contains multiple actionable items.")
 	writeLine(&sb, "- If the text has multiple actions, return one item per action in an array, preserving the original order.")
 	writeLine(&sb, "- Do not set needs_review=true only because there are multiple actions; set it only when an item is actually ambiguous.")
+	writeLine(&sb, "- CHECKLIST: When the text is one goal followed by its steps (e.g., \"preparar viagem: passaporte, mala, reservar hotel\"), return ONE task with the goal as title and the steps in payload.checklist, in the original order. Do not split those steps into separate tasks. Omit checklist when there are no steps.")
 	writeLine(&sb, "- Use needs_review=true when unsure.")
 	writeLine(&sb, "- Interpret relative dates (today, tomorrow, next week) using the provided Timezone and Now (local). Never use UTC for relative dates.")
 	writeLine(&sb, "- For weekday phrases (e.g., \"next Tuesday\", \"terca que vem\", \"proxima terca\"), resolve to the next occurrence of that weekday after Now (same week if upcoming, otherwise next week). Do not shift to the day after the weekday.")
 	writeLine(&sb, "- If the user says \"next week <weekday>\" or \"<weekday> da semana que vem\", use that weekday in the next calendar week (not the immediate upcoming weekday in the current week).")
+	writeLine(&sb, "- Business days (dias uteis) are Monday to Friday, excluding the Upcoming public holidays. For \"em N dias uteis\" / \"in N business days\", count N business days after today; \"proximo dia util\" is the first business day after today.")
 	writeLine(&sb, "- Preserve explicit dates and times exactly as stated. Do not shift hours or dates; only format to RFC3339 with the correct timezone offset.")
 	writeLine(&sb, "- If a reminder time is not explicit, choose 09:00 in the user's local timezone and set needs_review=true.")
 	writeLine(&sb, "- Choose context from Available contexts and Context rules. Use Hinted context when relevant.")
 	writeLine(&sb, "- Do not invent flagId or subflagId. If no subflag applies, use null and set needs_review=true if uncertain.")
+	writeLine(&sb, "- PROJECT: If a task clearly belongs to one of the Open projects (mentioned by name or obvious subject), set payload.project to that exact project name. Never invent a project; otherwise omit project.")
+	writeLine(&sb, "- PRIORITY: Only set payload.priority when the user signals it: P1 = urgent and important (\"urgente\", \"prioridade maxima\"), P2 = important but not urgent, P3 = urgent but not important, P4 = low priority (\"quando der\"). Set payload.effortMins only when a duration is stated (\"leva uns 30 minutos\" → 30). Otherwise omit both.")
+	writeLine(&sb, "- RECURRING TASK: A task with a due date that repeats (\"pagar aluguel todo dia 5\", \"trocar filtro a cada 3 meses\") stays type=task with payload.recurrence. Fixed calendar → recurrence.rule as an RRULE without COUNT (\"todo dia 5\" → \"FREQ=MONTHLY;BYMONTHDAY=5\"; \"a cada 3 meses\" → \"FREQ=MONTHLY;INTERVAL=3\"). Counted from when it is done (\"3 meses depois de trocar\", \"a cada 90 dias depois de feito\") → recurrence.afterDays. Never set both. Set dueAt to the first due date.")
 	writeLine(&sb, "- If type=event then end must be >= start.")
 	writeLine(&sb, "- If type=shopping then items must be non-empty.")
 	writeLine(&sb, "- If type=reminder then payload.at must exist.")
+	writeLine(&sb, "- NOTIFICATION: Only set payload.notification when the user explicitly asks for a specific warning time or for no warning.")
+	writeLine(&sb, "  - \"me avisa 1 hora antes\" → notification: {\"leadMins\": [60]}")
+	writeLine(&sb, "  - \"me lembra 1 dia e 30 minutos antes\" → notification: {\"leadMins\": [1440, 30]}")
+	writeLine(&sb, "  - \"me avisa na hora\" → notification: {\"leadMins\": [0]}")
+	writeLine(&sb, "  - \"sem notificação\" / \"não precisa avisar\" → notification: {\"disabled\": true}")
 	writeLine(&sb, "- ROUTINE DETECTION: Detect recurring patterns using keywords: \"toda\", \"todo\", \"sempre\", \"every\", \"a cada\", \"semanalmente\", \"quinzenalmente\", \"de segunda a sexta\"")
 	writeLine(&sb, "- ROUTINE RULES:")
 	writeLine(&sb, "  - \"Toda semana\" / \"sempre\" → recurrenceType: \"weekly\"")
@@ -126,6 +159,7 @@ func (b *PromptBuilder) Build(input PromptInput) string {
 	writeLine(&sb, "  - \"A cada três semanas\" → recurrenceType: \"triweekly\"")
 	writeLine(&sb, "  - \"Todo primeiro/segundo/terceiro/último [weekday] do mês\" → recurrenceType: \"monthly_week\"")
 	writeLine(&sb, "  - \"De segunda a sexta\" / \"dias úteis\" → weekdays: [1,2,3,4,5]")
+	writeLine(&sb, "  - \"Dias úteis\" / \"exceto feriados\" / \"menos nos feriados\" → skipHolidays: true; otherwise omit skipHolidays")
 	writeLine(&sb, "  - \"Final de semana\" → weekdays: [0,6]")
 	writeLine(&sb, "  - \"Todo dia\" → weekdays: [0,1,2,3,4,5,6]")
 	writeLine(&sb, "  - Weekday mapping: 0=domingo, 1=segunda, 2=terca, 3=quarta, 4=quinta, 5=sexta, 6=sabado")
@@ -135,6 +169,9 @@ func (b *PromptBuilder) Build(input PromptInput) string {
 	writeLine(&sb, "    - \"Reunião toda segunda às 14h\" → routine")
 	writeLine(&sb, "    - \"Reunião segunda que vem às 14h\" → event (single occurrence)")
 	writeLine(&sb, "    - Key indicators for routine: \"toda/todo\", \"sempre\", \"a cada\", \"semanalmente\"")
+	writeLine(&sb, "  - DIFFERENTIATE routine vs recurring task:")
+	writeLine(&sb, "    - \"Pagar aluguel todo dia 5\" → task with payload.recurrence (something to get done by a date)")
+	writeLine(&sb, "    - \"Academia toda segunda às 7h\" → routine (a time block that repeats)")
 
 	return sb.String()
 }
@@ -2,12 +2,20 @@ package usecase
 
 import (
 	"context"
+	"time"
 
+	"inbota/backend/internal/app/holiday"
 	"inbota/backend/internal/app/repository"
 )
 
+// agendaHolidayDays é quanto a agenda marca de feriados à frente quando nenhum item vai além.
+const agendaHolidayDays = 30
+
 type AgendaUsecase struct {
 	Repo repository.AgendaRepository
+
+	// Users resolve o calendário de feriados e o timezone do usuário (nil = calendário padrão).
+	Users repository.UserRepository
 }
 
 func NewAgendaUsecase(repo repository.AgendaRepository) *AgendaUsecase {
@@ -17,3 +25,20 @@ func NewAgendaUsecase(repo repository.AgendaRepository) *AgendaUsecase {
 func (uc *AgendaUsecase) List(ctx context.Context, userID string, opts repository.ListOptions) ([]repository.AgendaItem, error) {
 	return uc.Repo.List(ctx, userID, opts)
 }
+
+// Holidays returns the holidays of the user's calendar from today until the last agenda item,
+// at least agendaHolidayDays ahead and at most a year.
+func (uc *AgendaUsecase) Holidays(ctx context.Context, userID string, items []repository.AgendaItem) []holiday.Holiday {
+	now := userNow(ctx, uc.Users, userID)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 0, agendaHolidayDays)
+	for _, item := range items {
+		if at := item.ScheduledAt.In(now.Location()); at.After(to) {
+			to = at
+		}
+	}
+	if limit := from.AddDate(0, 0, maxHolidayRangeDays-1); to.After(limit) {
+		to = limit
+	}
+	return holiday.Between(userHolidayCalendar(ctx, uc.Users, userID), from, to)
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/postgres"
+)
+
+// maxAwayPeriodDays limita um período de férias a um ano.
+const maxAwayPeriodDays = 366
+
+// AwayUsecase manages vacation mode: while a period is running the routines count as skipped
+// without breaking streaks, routine and task pushes are held back and the digests pause.
+type AwayUsecase struct {
+	Away  repository.AwayPeriodRepository
+	Users repository.UserRepository
+	Flags repository.FlagRepository
+
+	// NotificationLogs cancela pushes de rotinas e tarefas já agendados no período (nil = não cancela).
+	NotificationLogs repository.NotificationLogRepository
+}
+
+type AwayPeriodInput struct {
+	StartsOn string
+	EndsOn   string
+	FlagID   *string
+	Reason   *string
+}
+
+func (uc *AwayUsecase) Create(ctx context.Context, userID string, input AwayPeriodInput) (domain.AwayPeriod, error) {
+	if userID == "" {
+		return domain.AwayPeriod{}, ErrMissingRequiredFields
+	}
+	if uc.Away == nil {
+		return domain.AwayPeriod{}, ErrDependencyMissing
+	}
+
+	if strings.TrimSpace(input.StartsOn) == "" || strings.TrimSpace(input.EndsOn) == "" {
+		return domain.AwayPeriod{}, ErrMissingRequiredFields
+	}
+	startsOn, okStart := parseRoutineDate(strings.TrimSpace(input.StartsOn))
+	endsOn, okEnd := parseRoutineDate(strings.TrimSpace(input.EndsOn))
+	if !okStart || !okEnd {
+		return domain.AwayPeriod{}, ErrInvalidPayload
+	}
+	if endsOn.Before(startsOn) {
+		return domain.AwayPeriod{}, ErrInvalidTimeRange
+	}
+	if int(endsOn.Sub(startsOn).Hours()/24)+1 > maxAwayPeriodDays {
+		return domain.AwayPeriod{}, ErrInvalidTimeRange
+	}
+
+	// Períodos já encerrados reescreveriam o histórico das sequências.
+	now := userNow(ctx, uc.Users, userID)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if endsOn.Before(today) {
+		return domain.AwayPeriod{}, ErrInvalidTimeRange
+	}
+
+	flagID := normalizeOptionalString(input.FlagID)
+	if flagID != nil {
+		if uc.Flags == nil {
+			return domain.AwayPeriod{}, ErrDependencyMissing
+		}
+		if _, err := uc.Flags.Get(ctx, userID, *flagID); err != nil {
+			if errors.Is(err, postgres.ErrNotFound) {
+				return domain.AwayPeriod{}, ErrInvalidPayload
+			}
+			return domain.AwayPeriod{}, err
+		}
+	}
+
+	created, err := uc.Away.Create(ctx, domain.AwayPeriod{
+		UserID:   userID,
+		StartsOn: startsOn.Format("2006-01-02"),
+		EndsOn:   endsOn.Format("2006-01-02"),
+		FlagID:   flagID,
+		Reason:   normalizeOptionalString(input.Reason),
+	})
+	if err != nil {
+		return domain.AwayPeriod{}, err
+	}
+
+	uc.cancelQueuedNotifications(ctx, created, now)
+	return created, nil
+}
+
+// cancelQueuedNotifications drops the routine and task pushes already queued inside the
+// period; the scheduler does not queue new ones while it lasts.
+func (uc *AwayUsecase) cancelQueuedNotifications(ctx context.Context, period domain.AwayPeriod, now time.Time) {
+	if uc.NotificationLogs == nil {
+		return
+	}
+	startsOn, okStart := parseRoutineDate(period.StartsOn)
+	endsOn, okEnd := parseRoutineDate(period.EndsOn)
+	if !okStart || !okEnd {
+		return
+	}
+	loc := now.Location()
+	from := time.Date(startsOn.Year(), startsOn.Month(), startsOn.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(endsOn.Year(), endsOn.Month(), endsOn.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	types := []domain.NotificationType{domain.NotificationTypeRoutine, domain.NotificationTypeTask}
+	_, _ = uc.NotificationLogs.CancelPendingByType(ctx, period.UserID, types, period.FlagID, from, to)
+}
+
+func (uc *AwayUsecase) List(ctx context.Context, userID string) ([]domain.AwayPeriod, error) {
+	if userID == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	if uc.Away == nil {
+		return nil, ErrDependencyMissing
+	}
+	return uc.Away.ListByUser(ctx, userID)
+}
+
+// Current returns the period running today, preferring one that pauses the whole account.
+func (uc *AwayUsecase) Current(ctx context.Context, userID string) (*domain.AwayPeriod, error) {
+	if userID == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	if uc.Away == nil {
+		return nil, ErrDependencyMissing
+	}
+	today := userNow(ctx, uc.Users, userID).Format("2006-01-02")
+	periods, err := uc.Away.ListOverlapping(ctx, []string{userID}, today, today)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *domain.AwayPeriod
+	for i := range periods {
+		if current == nil || (current.FlagID != nil && periods[i].FlagID == nil) {
+			current = &periods[i]
+		}
+	}
+	return current, nil
+}
+
+func (uc *AwayUsecase) Delete(ctx context.Context, userID, id string) error {
+	if userID == "" || id == "" {
+		return ErrMissingRequiredFields
+	}
+	if uc.Away == nil {
+		return ErrDependencyMissing
+	}
+	return uc.Away.Delete(ctx, userID, id)
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/postgres"
+)
+
+type awayRepoStub struct {
+	repository.AwayPeriodRepository
+	created []domain.AwayPeriod
+}
+
+func (s *awayRepoStub) Create(_ context.Context, period domain.AwayPeriod) (domain.AwayPeriod, error) {
+	period.ID = "away-1"
+	s.created = append(s.created, period)
+	return period, nil
+}
+
+type cancelByTypeStub struct {
+	repository.NotificationLogRepository
+	calls    int
+	userID   string
+	types    []domain.NotificationType
+	flagID   *string
+	from, to time.Time
+}
+
+func (s *cancelByTypeStub) CancelPendingByType(_ context.Context, userID string, types []domain.NotificationType, flagID *string, from, to time.Time) (int, error) {
+	s.calls++
+	s.userID, s.types, s.flagID, s.from, s.to = userID, types, flagID, from, to
+	return 2, nil
+}
+
+func ownFlagsStub(userID string) flagRepoStub {
+	return flagRepoStub{getFn: func(_ context.Context, owner, id string) (domain.Flag, error) {
+		if owner != userID || id != "f1" {
+			return domain.Flag{}, postgres.ErrNotFound
+		}
+		return domain.Flag{ID: id, UserID: owner}, nil
+	}}
+}
+
+func TestAwayCreateValidatesRange(t *testing.T) {
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	today := time.Now().In(loc)
+	day := func(offset int) string { return today.AddDate(0, 0, offset).Format("2006-01-02") }
+	otherFlag := "f2"
+
+	cases := []struct {
+		name  string
+		input AwayPeriodInput
+		want  error
+	}{
+		{name: "missing end", input: AwayPeriodInput{StartsOn: day(0)}, want: ErrMissingRequiredFields},
+		{name: "invalid date", input: AwayPeriodInput{StartsOn: day(0), EndsOn: "2026-02-30"}, want: ErrInvalidPayload},
+		{name: "end before start", input: AwayPeriodInput{StartsOn: day(3), EndsOn: day(1)}, want: ErrInvalidTimeRange},
+		{name: "longer than a year", input: AwayPeriodInput{StartsOn: day(0), EndsOn: day(maxAwayPeriodDays)}, want: ErrInvalidTimeRange},
+		{name: "already ended", input: AwayPeriodInput{StartsOn: day(-5), EndsOn: day(-1)}, want: ErrInvalidTimeRange},
+		{name: "flag of another user", input: AwayPeriodInput{StartsOn: day(0), EndsOn: day(2), FlagID: &otherFlag}, want: ErrInvalidPayload},
+		{name: "ends today", input: AwayPeriodInput{StartsOn: day(-5), EndsOn: day(0)}},
+		{name: "exactly a year", input: AwayPeriodInput{StartsOn: day(0), EndsOn: day(maxAwayPeriodDays - 1)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			away := &awayRepoStub{}
+			uc := &AwayUsecase{Away: away, Users: timezoneUserStub{}, Flags: ownFlagsStub("u1")}
+			_, err := uc.Create(context.Background(), "u1", tc.input)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("Create error = %v, want %v", err, tc.want)
+			}
+			if tc.want != nil && len(away.created) != 0 {
+				t.Fatalf("expected nothing stored, got %+v", away.created)
+			}
+		})
+	}
+}
+
+func TestAwayCreateCancelsQueuedNotifications(t *testing.T) {
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	start := time.Now().In(loc).AddDate(0, 0, 1)
+	end := start.AddDate(0, 0, 2)
+	away := &awayRepoStub{}
+	logs := &cancelByTypeStub{}
+	uc := &AwayUsecase{Away: away, Users: timezoneUserStub{}, Flags: ownFlagsStub("u1"), NotificationLogs: logs}
+
+	flagID, reason := " f1 ", " Viagem "
+	period, err := uc.Create(context.Background(), "u1", AwayPeriodInput{
+		StartsOn: start.Format("2006-01-02"),
+		EndsOn:   end.Format("2006-01-02"),
+		FlagID:   &flagID,
+		Reason:   &reason,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if period.FlagID == nil || *period.FlagID != "f1" || period.Reason == nil || *period.Reason != "Viagem" {
+		t.Fatalf("expected trimmed flag and reason, got %+v", period)
+	}
+
+	if logs.calls != 1 || logs.userID != "u1" {
+		t.Fatalf("expected one cancellation for u1, got %d for %q", logs.calls, logs.userID)
+	}
+	if len(logs.types) != 2 || logs.types[0] != domain.NotificationTypeRoutine || logs.types[1] != domain.NotificationTypeTask {
+		t.Fatalf("cancelled types = %v", logs.types)
+	}
+	if logs.flagID == nil || *logs.flagID != "f1" {
+		t.Fatalf("expected cancellation scoped to f1, got %v", logs.flagID)
+	}
+	wantFrom := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	wantTo := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	if !logs.from.Equal(wantFrom) || !logs.to.Equal(wantTo) {
+		t.Fatalf("window = %s..%s, want %s..%s", logs.from, logs.to, wantFrom, wantTo)
+	}
+}
@@ -32,6 +32,9 @@ func (s flagRepoStub) Get(ctx context.Context, userID, id string) (domain.Flag,
 func (s flagRepoStub) GetByIDs(context.Context, string, []string) ([]domain.Flag, error) {
 	panic("unexpected GetByIDs")
 }
+func (s flagRepoStub) GetByIDsForUsers(context.Context, map[string][]string) ([]domain.Flag, error) {
+	panic("unexpected GetByIDsForUsers")
+}
 func (s flagRepoStub) List(context.Context, string, repository.ListOptions) ([]domain.Flag, *string, error) {
 	panic("unexpected List")
 }
@@ -2,27 +2,37 @@ package usecase
 
 import (
 	"context"
-	"crypto/sha256"
-	"fmt"
-	"regexp"
 
 	"inbota/backend/internal/app/domain"
 	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/push"
 )
 
 type DeviceTokenUsecase struct {
 	DeviceTokens repository.DeviceTokenRepository
+	PushTopics   repository.PushTopicRepository
+	Ntfy         *push.NtfyClient
 }
 
-func (uc *DeviceTokenUsecase) RegisterToken(ctx context.Context, userID, deviceID, platform, deviceName, appVersion string) (string, error) {
-	// Regra de geração do tópico: Prefixo + Hash estável do DeviceID
-	// Isso garante que a regra de "como o tópico é gerado" fique só no backend.
-	topic := uc.generateTopic(deviceID)
+// DeviceRegistration is what the app needs to subscribe to the user's topic.
+type DeviceRegistration struct {
+	Topic         string
+	Server        string
+	EncryptionKey *string
+}
+
+func (uc *DeviceTokenUsecase) RegisterToken(ctx context.Context, userID, deviceID, platform, deviceName, appVersion string) (DeviceRegistration, error) {
+	// O tópico é gerado aleatoriamente pelo backend, um por usuário, compartilhado
+	// pelos dispositivos dele (não é derivável do deviceID).
+	topic, err := uc.userTopic(ctx, userID)
+	if err != nil {
+		return DeviceRegistration{}, err
+	}
 
 	dt := domain.DeviceToken{
 		UserID:     userID,
 		DeviceID:   deviceID,
-		Topic:      topic,
+		Topic:      topic.Topic,
 		Platform:   domain.DevicePlatform(platform),
 		DeviceName: &deviceName,
 		AppVersion: &appVersion,
@@ -36,22 +46,66 @@ func (uc *DeviceTokenUsecase) RegisterToken(ctx context.Context, userID, deviceI
 	}
 
 	if err := uc.DeviceTokens.Upsert(ctx, dt); err != nil {
-		return "", err
+		return DeviceRegistration{}, err
 	}
 
-	return topic, nil
+	return uc.registration(topic), nil
+}
+
+// RotateTopic troca o tópico e a chave do usuário (ex.: tópico vazado). Os dispositivos
+// passam a receber no novo tópico e precisam se reinscrever.
+func (uc *DeviceTokenUsecase) RotateTopic(ctx context.Context, userID string) (DeviceRegistration, error) {
+	if userID == "" {
+		return DeviceRegistration{}, ErrMissingRequiredFields
+	}
+	if uc.PushTopics == nil {
+		return DeviceRegistration{}, ErrDependencyMissing
+	}
+	candidate, err := newPushTopic(userID)
+	if err != nil {
+		return DeviceRegistration{}, err
+	}
+	topic, err := uc.PushTopics.Rotate(ctx, candidate)
+	if err != nil {
+		return DeviceRegistration{}, err
+	}
+	return uc.registration(topic), nil
 }
 
 func (uc *DeviceTokenUsecase) UnregisterToken(ctx context.Context, deviceID, userID string) error {
 	return uc.DeviceTokens.Delete(ctx, deviceID, userID)
 }
 
-func (uc *DeviceTokenUsecase) generateTopic(deviceID string) string {
-	// Remove caracteres não alfanuméricos para segurança
-	reg := regexp.MustCompile("[^a-zA-Z0-9]+")
-	cleanID := reg.ReplaceAllString(deviceID, "")
+func (uc *DeviceTokenUsecase) userTopic(ctx context.Context, userID string) (domain.PushTopic, error) {
+	if userID == "" {
+		return domain.PushTopic{}, ErrMissingRequiredFields
+	}
+	if uc.PushTopics == nil {
+		return domain.PushTopic{}, ErrDependencyMissing
+	}
+	candidate, err := newPushTopic(userID)
+	if err != nil {
+		return domain.PushTopic{}, err
+	}
+	return uc.PushTopics.GetOrCreate(ctx, candidate)
+}
 
-	// Hash para evitar tópicos previsíveis caso o deviceID seja algo simples
-	hash := sha256.Sum256([]byte(cleanID + "inbota_salt_2024"))
-	return fmt.Sprintf("inbota_%x", hash[:12]) // 24 caracteres hex
+func (uc *DeviceTokenUsecase) registration(topic domain.PushTopic) DeviceRegistration {
+	reg := DeviceRegistration{Topic: topic.Topic, Server: push.DefaultNtfyBaseURL}
+	if uc.Ntfy != nil {
+		reg.Server = uc.Ntfy.BaseURL
+		if uc.Ntfy.EncryptionEnabled() {
+			key := topic.EncryptionKey
+			reg.EncryptionKey = &key
+		}
+	}
+	return reg
+}
+
+func newPushTopic(userID string) (domain.PushTopic, error) {
+	topic, key, err := push.NewTopicCredentials()
+	if err != nil {
+		return domain.PushTopic{}, err
+	}
+	return domain.PushTopic{UserID: userID, Topic: topic, EncryptionKey: key}, nil
 }
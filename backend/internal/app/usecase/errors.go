@@ -14,4 +14,8 @@ var (
 	ErrInvalidPassword       = errors.New("invalid_password")
 	ErrInvalidDisplayName    = errors.New("invalid_display_name")
 	ErrRoutineOverlap        = errors.New("routine_overlap")
+	ErrInvalidRecurrence     = errors.New("invalid_recurrence")
+	ErrTemplateConflict      = errors.New("template_conflict")
+	ErrTaskDependencyCycle   = errors.New("task_dependency_cycle")
+	ErrTaskDependencyTooDeep = errors.New("task_dependency_too_deep")
 )
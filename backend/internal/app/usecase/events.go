@@ -24,9 +24,11 @@ type EventUpdateInput struct {
 	Location  *string
 	FlagID    *string
 	SubflagID *string
+
+	Notification *domain.NotificationOverride
 }
 
-func (uc *EventUsecase) Create(ctx context.Context, userID, title string, startAt, endAt *time.Time, allDay *bool, location *string, flagID *string, subflagID *string, sourceInboxItemID *string) (domain.Event, error) {
+func (uc *EventUsecase) Create(ctx context.Context, userID, title string, startAt, endAt *time.Time, allDay *bool, location *string, flagID *string, subflagID *string, sourceInboxItemID *string, notification *domain.NotificationOverride) (domain.Event, error) {
 	title = normalizeString(title)
 	if userID == "" || title == "" {
 		return domain.Event{}, ErrMissingRequiredFields
@@ -39,6 +41,10 @@ func (uc *EventUsecase) Create(ctx context.Context, userID, title string, startA
 	if err != nil {
 		return domain.Event{}, err
 	}
+	override, err := normalizeNotificationOverride(notification, domain.MaxNotificationLeadMins)
+	if err != nil {
+		return domain.Event{}, err
+	}
 
 	event := domain.Event{
 		UserID:            userID,
@@ -50,6 +56,7 @@ func (uc *EventUsecase) Create(ctx context.Context, userID, title string, startA
 		FlagID:            resolvedFlagID,
 		SubflagID:         resolvedSubflagID,
 		SourceInboxItemID: normalizeOptionalString(sourceInboxItemID),
+		Notification:      override,
 	}
 	if allDay != nil {
 		event.AllDay = *allDay
@@ -105,6 +112,13 @@ func (uc *EventUsecase) Update(ctx context.Context, userID, id string, input Eve
 		event.FlagID = resolvedFlagID
 		event.SubflagID = resolvedSubflagID
 	}
+	if input.Notification != nil {
+		override, err := normalizeNotificationOverride(input.Notification, domain.MaxNotificationLeadMins)
+		if err != nil {
+			return domain.Event{}, err
+		}
+		event.Notification = override
+	}
 
 	return uc.Events.Update(ctx, event)
 }
@@ -2,12 +2,64 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/holiday"
+	"inbota/backend/internal/app/i18n"
 	"inbota/backend/internal/app/repository"
 )
 
-const defaultListAllLimit = 200
+const (
+	defaultListAllLimit = 200
+	defaultUserTimezone = "America/Sao_Paulo"
+)
+
+// userNow returns the current time in the user's timezone (defaultUserTimezone when unknown).
+func userNow(ctx context.Context, users repository.UserRepository, userID string) time.Time {
+	now := time.Now()
+	fallbackLoc, err := time.LoadLocation(defaultUserTimezone)
+	if err != nil {
+		fallbackLoc = now.Location()
+	}
+
+	if users == nil || userID == "" {
+		return now.In(fallbackLoc)
+	}
+	user, err := users.Get(ctx, userID)
+	if err != nil || user.Timezone == "" {
+		return now.In(fallbackLoc)
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return now.In(fallbackLoc)
+	}
+	return now.In(loc)
+}
+
+// userLocalizer returns the message catalogue for the user's locale (pt-BR when unknown).
+func userLocalizer(ctx context.Context, users repository.UserRepository, userID string) i18n.Localizer {
+	if users == nil || userID == "" {
+		return i18n.For(i18n.DefaultLocale)
+	}
+	user, err := users.Get(ctx, userID)
+	if err != nil {
+		return i18n.For(i18n.DefaultLocale)
+	}
+	return i18n.For(user.Locale)
+}
+
+// userHolidayCalendar returns the user's public-holiday calendar (holiday.DefaultCode when unknown).
+func userHolidayCalendar(ctx context.Context, users repository.UserRepository, userID string) holiday.Calendar {
+	if users == nil || userID == "" {
+		return holiday.For(holiday.DefaultCode)
+	}
+	user, err := users.Get(ctx, userID)
+	if err != nil {
+		return holiday.For(holiday.DefaultCode)
+	}
+	return holiday.For(user.HolidayCalendar)
+}
 
 func listAllFlags(ctx context.Context, repo repository.FlagRepository, userID string) ([]domain.Flag, error) {
 	opts := repository.ListOptions{Limit: defaultListAllLimit}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"inbota/backend/internal/app/holiday"
+	"inbota/backend/internal/app/repository"
+)
+
+// maxHolidayRangeDays limita a consulta de feriados a um ano.
+const maxHolidayRangeDays = 366
+
+// HolidayUsecase exposes the embedded holiday calendars and the one picked by each user.
+type HolidayUsecase struct {
+	Users repository.UserRepository
+}
+
+func (uc *HolidayUsecase) Calendars() []holiday.Calendar {
+	return holiday.Calendars()
+}
+
+func (uc *HolidayUsecase) Calendar(ctx context.Context, userID string) (holiday.Calendar, error) {
+	if userID == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	if uc.Users == nil {
+		return nil, ErrDependencyMissing
+	}
+	user, err := uc.Users.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return holiday.For(user.HolidayCalendar), nil
+}
+
+func (uc *HolidayUsecase) SetCalendar(ctx context.Context, userID, code string) (holiday.Calendar, error) {
+	if userID == "" || strings.TrimSpace(code) == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	if uc.Users == nil {
+		return nil, ErrDependencyMissing
+	}
+	cal, ok := holiday.Lookup(code)
+	if !ok {
+		return nil, ErrInvalidPayload
+	}
+	if err := uc.Users.UpdateHolidayCalendar(ctx, userID, cal.Code()); err != nil {
+		return nil, err
+	}
+	return cal, nil
+}
+
+// List returns the holidays of the user's calendar between from and to (YYYY-MM-DD, inclusive).
+// Empty bounds default to today and the next 30 days.
+func (uc *HolidayUsecase) List(ctx context.Context, userID, from, to string) (holiday.Calendar, []holiday.Holiday, error) {
+	cal, err := uc.Calendar(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := userNow(ctx, uc.Users, userID)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	fromDate, toDate := today, today.AddDate(0, 0, 30)
+	if strings.TrimSpace(from) != "" {
+		parsed, ok := parseRoutineDate(strings.TrimSpace(from))
+		if !ok {
+			return nil, nil, ErrInvalidPayload
+		}
+		fromDate = parsed
+		toDate = parsed.AddDate(0, 0, 30)
+	}
+	if strings.TrimSpace(to) != "" {
+		parsed, ok := parseRoutineDate(strings.TrimSpace(to))
+		if !ok {
+			return nil, nil, ErrInvalidPayload
+		}
+		toDate = parsed
+	}
+	if toDate.Before(fromDate) || int(toDate.Sub(fromDate).Hours()/24)+1 > maxHolidayRangeDays {
+		return nil, nil, ErrInvalidTimeRange
+	}
+
+	return cal, holiday.Between(cal, fromDate, toDate), nil
+}
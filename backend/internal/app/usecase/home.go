@@ -2,13 +2,15 @@ package usecase
 
 import (
 	"context"
-	"fmt"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/holiday"
+	"inbota/backend/internal/app/i18n"
+	"inbota/backend/internal/app/recurrence"
 	"inbota/backend/internal/app/repository"
 )
 
@@ -33,6 +35,7 @@ type HomeDashboard struct {
 type HomeDayProgress struct {
 	RoutinesDone    int
 	RoutinesTotal   int
+	RoutinesPartial int
 	TasksDone       int
 	TasksTotal      int
 	ProgressPercent float64
@@ -70,6 +73,7 @@ func (uc *HomeUsecase) GetDashboard(ctx context.Context, userID string) (HomeDas
 	}
 
 	now, loc := uc.nowInUserTimezone(ctx, userID)
+	l := userLocalizer(ctx, uc.Users, userID)
 	todayStartLocal := startOfDay(now)
 	todayEndLocal := todayStartLocal.Add(24 * time.Hour)
 	todayStartUTC := todayStartLocal.UTC()
@@ -91,10 +95,11 @@ func (uc *HomeUsecase) GetDashboard(ctx context.Context, userID string) (HomeDas
 		return HomeDashboard{}, err
 	}
 
-	routinesTotal, routinesDone, err := uc.Routines.GetTodaySummary(ctx, userID)
+	routinesProgress, err := uc.Routines.GetTodayProgress(ctx, userID)
 	if err != nil {
 		return HomeDashboard{}, err
 	}
+	routinesTotal := routinesProgress.Total
 
 	focusTaskRows, err := uc.Home.ListFocusTasks(ctx, userID, 200)
 	if err != nil {
@@ -120,7 +125,7 @@ func (uc *HomeUsecase) GetDashboard(ctx context.Context, userID string) (HomeDas
 
 	timeline, eventsTodayCount, remindersTodayCount, tasksTodayTotal, tasksTodayDone :=
 		buildAgendaTimeline(agendaToday, now, loc)
-	timeline = append(timeline, buildRoutineTimeline(routinesToday, now, loc)...)
+	timeline = append(timeline, buildRoutineTimeline(l, routinesToday, now, loc)...)
 	sortTimeline(timeline)
 
 	slots := make([]timeRange, 0, len(timeline))
@@ -139,17 +144,24 @@ func (uc *HomeUsecase) GetDashboard(ctx context.Context, userID string) (HomeDas
 		untimedCount = 0
 	}
 
-	insight := buildHomeInsight(templates, slots, commitmentsCount, untimedCount, now)
+	insight := buildHomeInsight(l, templates, slots, commitmentsCount, untimedCount, now)
+
+	// O feriado entra na timeline só como marcador do dia: fica fora dos horários ocupados.
+	if h, ok := holiday.On(userHolidayCalendar(ctx, uc.Users, userID), now); ok {
+		timeline = append([]HomeTimelineItem{buildHolidayTimelineItem(l, h, todayStartLocal)}, timeline...)
+	}
 
 	dayProgress := HomeDayProgress{
-		RoutinesDone:    routinesDone,
+		RoutinesDone:    routinesProgress.Completed,
 		RoutinesTotal:   routinesTotal,
+		RoutinesPartial: routinesProgress.Partial,
 		TasksDone:       tasksTodayDone,
 		TasksTotal:      tasksTodayTotal,
 		ProgressPercent: 0,
 	}
 	if total := routinesTotal + tasksTodayTotal; total > 0 {
-		dayProgress.ProgressPercent = clamp01(float64(routinesDone+tasksTodayDone) / float64(total))
+		// Rotinas com meta parcialmente cumprida contam pela fração feita.
+		dayProgress.ProgressPercent = clamp01((routinesProgress.Progress + float64(tasksTodayDone)) / float64(total))
 	}
 
 	return HomeDashboard{
@@ -167,15 +179,23 @@ func (uc *HomeUsecase) GetDashboard(ctx context.Context, userID string) (HomeDas
 func homeFocusRowsToDomainTasks(rows []repository.HomeFocusTask) []domain.Task {
 	items := make([]domain.Task, 0, len(rows))
 	for _, row := range rows {
-		items = append(items, domain.Task{
+		task := domain.Task{
 			ID:          row.ID,
 			Title:       row.Title,
 			Description: row.Description,
 			Status:      domain.TaskStatus(row.Status),
 			DueAt:       row.DueAt,
+			EffortMins:  row.EffortMins,
+			Blocked:     row.Blocked,
 			CreatedAt:   row.CreatedAt,
 			UpdatedAt:   row.UpdatedAt,
-		})
+		}
+		if row.Priority != nil {
+			if priority, ok := parseTaskPriority(*row.Priority); ok {
+				task.Priority = &priority
+			}
+		}
+		items = append(items, task)
 	}
 	return items
 }
@@ -184,10 +204,14 @@ func selectFocusTasks(tasks []domain.Task, now time.Time) []domain.Task {
 	dayStart := startOfDay(now)
 	dayEnd := dayStart.Add(24 * time.Hour)
 
+	// Tarefas P1 entram no foco mesmo com prazo futuro; bloqueadas ficam fora até as bloqueadoras terminarem.
 	filtered := make([]domain.Task, 0, len(tasks))
 	for _, task := range tasks {
+		if task.Blocked {
+			continue
+		}
 		dueLocal := toLocalPtr(task.DueAt, now.Location())
-		if dueLocal == nil || dueLocal.Before(dayEnd) {
+		if dueLocal == nil || dueLocal.Before(dayEnd) || taskPriorityRank(task.Priority) == 0 {
 			filtered = append(filtered, task)
 		}
 	}
@@ -204,6 +228,12 @@ func selectFocusTasks(tasks []domain.Task, now time.Time) []domain.Task {
 			return aPriority < bPriority
 		}
 
+		aRank := taskPriorityRank(a.Priority)
+		bRank := taskPriorityRank(b.Priority)
+		if aRank != bRank {
+			return aRank < bRank
+		}
+
 		if aPriority == 0 || aPriority == 1 {
 			if aDue != nil && bDue != nil {
 				if !aDue.Equal(*bDue) {
@@ -212,6 +242,11 @@ func selectFocusTasks(tasks []domain.Task, now time.Time) []domain.Task {
 			}
 		}
 
+		// Com a mesma urgência, tarefas rápidas vêm antes; sem estimativa vai por último.
+		if aEffort, bEffort := focusEffort(a.EffortMins), focusEffort(b.EffortMins); aEffort != bEffort {
+			return aEffort < bEffort
+		}
+
 		if aPriority == 2 {
 			aCreated := a.CreatedAt
 			bCreated := b.CreatedAt
@@ -242,6 +277,13 @@ func focusPriority(dueAt *time.Time, dayStart, dayEnd time.Time) int {
 	return 3
 }
 
+func focusEffort(effortMins *int) int {
+	if effortMins == nil {
+		return domain.MaxTaskEffortMins + 1
+	}
+	return *effortMins
+}
+
 func buildAgendaTimeline(items []repository.AgendaItem, now time.Time, loc *time.Location) ([]HomeTimelineItem, int, int, int, int) {
 	timeline := make([]HomeTimelineItem, 0)
 	eventsTodayCount := 0
@@ -314,7 +356,7 @@ func buildAgendaTimeline(items []repository.AgendaItem, now time.Time, loc *time
 	return timeline, eventsTodayCount, remindersTodayCount, tasksTodayTotal, tasksTodayDone
 }
 
-func buildRoutineTimeline(routines []domain.Routine, now time.Time, loc *time.Location) []HomeTimelineItem {
+func buildRoutineTimeline(l i18n.Localizer, routines []domain.Routine, now time.Time, loc *time.Location) []HomeTimelineItem {
 	baseDay := startOfDay(now)
 	items := make([]HomeTimelineItem, 0, len(routines))
 
@@ -355,7 +397,7 @@ func buildRoutineTimeline(routines []domain.Routine, now time.Time, loc *time.Lo
 			endLocalPtr = &end
 		}
 
-		subtitleText := normalizeText(weekdaysLabel(routine.Weekdays))
+		subtitleText := normalizeText(routineSubtitle(l, routine))
 		var subtitle *string
 		if subtitleText != "" {
 			subtitle = &subtitleText
@@ -376,6 +418,17 @@ func buildRoutineTimeline(routines []domain.Routine, now time.Time, loc *time.Lo
 	return items
 }
 
+func buildHolidayTimelineItem(l i18n.Localizer, h holiday.Holiday, dayStart time.Time) HomeTimelineItem {
+	subtitle := l.T("home.holiday." + h.Scope)
+	return HomeTimelineItem{
+		ID:            "holiday:" + h.Date,
+		ItemType:      "holiday",
+		Title:         h.Name,
+		Subtitle:      &subtitle,
+		ScheduledTime: dayStart,
+	}
+}
+
 func sortTimeline(items []HomeTimelineItem) {
 	sort.Slice(items, func(i, j int) bool {
 		if !items[i].ScheduledTime.Equal(items[j].ScheduledTime) {
@@ -386,12 +439,18 @@ func sortTimeline(items []HomeTimelineItem) {
 }
 
 func buildHomeInsight(
+	l i18n.Localizer,
 	templates []repository.HomeInsightTemplate,
 	slots []timeRange,
 	commitmentsCount int,
 	untimedCount int,
 	now time.Time,
 ) *HomeInsight {
+	// Os templates do banco são escritos em pt-BR; outros idiomas usam o catálogo.
+	if !l.IsDefault() {
+		templates = nil
+	}
+
 	base := now
 	dayStart := time.Date(base.Year(), base.Month(), base.Day(), 8, 0, 0, 0, base.Location())
 	dayEnd := time.Date(base.Year(), base.Month(), base.Day(), 22, 0, 0, 0, base.Location())
@@ -406,14 +465,14 @@ func buildHomeInsight(
 	}
 
 	if !from.Before(dayEnd) {
-		return renderInsightTemplate(templates, "END_OF_DAY", 0, map[string]string{}, defaultInsight("END_OF_DAY"))
+		return renderInsightTemplate(templates, "END_OF_DAY", 0, map[string]string{}, defaultInsight(l, "END_OF_DAY"))
 	}
 
 	if untimedCount > 0 && len(slots) == 0 {
 		vars := map[string]string{
 			"untimed_count": strconv.Itoa(untimedCount),
 		}
-		return renderInsightTemplate(templates, "PENDING_TIMES", 0, vars, defaultInsight("PENDING_TIMES"))
+		return renderInsightTemplate(templates, "PENDING_TIMES", 0, vars, defaultInsight(l, "PENDING_TIMES"))
 	}
 
 	busyRanges := buildBusyRanges(slots, from, dayEnd)
@@ -429,30 +488,30 @@ func buildHomeInsight(
 		"end":            formatHM(bestGap.end),
 		"duration":       strconv.Itoa(bestGapMinutes),
 		"untimed_count":  strconv.Itoa(untimedCount),
-		"footer_dynamic": "Aproveitar tempo com menos interrupções.",
+		"footer_dynamic": l.T("home.footer.default"),
 	}
 
 	if untimedCount > 0 {
-		vars["footer_dynamic"] = fmt.Sprintf("Aproveite e veja %d tarefa(s) sem horário.", untimedCount)
+		vars["footer_dynamic"] = l.N("home.footer.untimed", untimedCount)
 	}
 
 	if untimedCount > 0 && (!hasTimedSlots || untimedDominant) {
-		return renderInsightTemplate(templates, "MISSING_TIMES", bestGapMinutes, vars, defaultInsight("MISSING_TIMES"))
+		return renderInsightTemplate(templates, "MISSING_TIMES", bestGapMinutes, vars, defaultInsight(l, "MISSING_TIMES"))
 	}
 
 	if !hasAgenda {
-		return renderInsightTemplate(templates, "FREE_TIME", bestGapMinutes, vars, defaultInsight("FREE_TIME"))
+		return renderInsightTemplate(templates, "FREE_TIME", bestGapMinutes, vars, defaultInsight(l, "FREE_TIME"))
 	}
 
 	if bestGapMinutes >= 120 {
-		return renderInsightTemplate(templates, "MELHOR_MOMENTO", bestGapMinutes, vars, defaultInsight("MELHOR_MOMENTO"))
+		return renderInsightTemplate(templates, "MELHOR_MOMENTO", bestGapMinutes, vars, defaultInsight(l, "MELHOR_MOMENTO"))
 	}
 
 	if bestGapMinutes >= 45 {
-		return renderInsightTemplate(templates, "GOOD_FREE_TIME", bestGapMinutes, vars, defaultInsight("GOOD_FREE_TIME"))
+		return renderInsightTemplate(templates, "GOOD_FREE_TIME", bestGapMinutes, vars, defaultInsight(l, "GOOD_FREE_TIME"))
 	}
 
-	return renderInsightTemplate(templates, "BUSY", bestGapMinutes, vars, defaultInsight("BUSY"))
+	return renderInsightTemplate(templates, "BUSY", bestGapMinutes, vars, defaultInsight(l, "BUSY"))
 }
 
 func renderInsightTemplate(
@@ -514,57 +573,25 @@ func selectTemplate(templates []repository.HomeInsightTemplate, category string,
 	return selected
 }
 
-func defaultInsight(category string) HomeInsight {
-	switch strings.ToUpper(category) {
-	case "END_OF_DAY":
-		return HomeInsight{
-			Title:   "Dia encerrando",
-			Summary: "Hoje ja nao ha muito tempo livre.",
-			Footer:  "Planeje o comeco de amanha.",
-			IsFocus: false,
-		}
-	case "PENDING_TIMES":
-		return HomeInsight{
-			Title:   "Horarios pendentes",
-			Summary: "{{untimed_count}} ainda sem horario.",
-			Footer:  "Defina os horarios para se organizar melhor",
-			IsFocus: false,
-		}
-	case "MISSING_TIMES":
-		return HomeInsight{
-			Title:   "Faltam horarios",
-			Summary: "{{untimed_count}} compromisso(s) ainda sem horario.",
-			Footer:  "Defina os horarios para organizar melhor.",
-			IsFocus: false,
-		}
-	case "MELHOR_MOMENTO":
-		return HomeInsight{
-			Title:   "Melhor momento",
-			Summary: "{{start}} - {{end}} para fazer algo em paz.",
-			Footer:  "{{footer_dynamic}}",
-			IsFocus: true,
-		}
-	case "GOOD_FREE_TIME":
-		return HomeInsight{
-			Title:   "Bom tempo livre",
-			Summary: "{{start}} - {{end}} esta disponivel.",
-			Footer:  "Da para resolver algo importante.",
-			IsFocus: true,
-		}
-	case "FREE_TIME":
-		return HomeInsight{
-			Title:   "Tempo livre",
-			Summary: "{{start}} - {{end}} ({{duration}} min livres).",
-			Footer:  "Que tal adiantar algo as {{start}}?",
-			IsFocus: true,
-		}
-	default:
-		return HomeInsight{
-			Title:   "Dia mais corrido",
-			Summary: "Maior tempo livre hoje e {{start}} - {{end}}.",
-			Footer:  "Tente aproveitar pequenas pausas.",
-			IsFocus: false,
-		}
+// insightFocus marks the categories that suggest a focus block.
+var insightFocus = map[string]bool{
+	"MELHOR_MOMENTO": true,
+	"GOOD_FREE_TIME": true,
+	"FREE_TIME":      true,
+}
+
+func defaultInsight(l i18n.Localizer, category string) HomeInsight {
+	category = strings.ToUpper(category)
+	key := "home.insight." + strings.ToLower(category)
+	if !l.Has(key + ".title") {
+		category = "BUSY"
+		key = "home.insight.busy"
+	}
+	return HomeInsight{
+		Title:   l.T(key + ".title"),
+		Summary: l.T(key + ".summary"),
+		Footer:  l.T(key + ".footer"),
+		IsFocus: insightFocus[category],
 	}
 }
 
@@ -750,12 +777,51 @@ func parseRoutineTimeForDay(raw string, base time.Time) (time.Time, bool) {
 	return time.Date(base.Year(), base.Month(), base.Day(), hour, minute, 0, 0, base.Location()), true
 }
 
-func weekdaysLabel(weekdays []int) string {
+// routineSubtitle lists the weekdays of weekly-shaped routines. Other RRULE routines store
+// every weekday they may fall on, which would read as "every day", so they show the cadence.
+func routineSubtitle(l i18n.Localizer, routine domain.Routine) string {
+	if routine.TargetValue != nil {
+		return routineProgressLabel(l, routine)
+	}
+	if routine.RecurrenceType != recurrence.TypeRRule {
+		return weekdaysLabel(l, routine.Weekdays)
+	}
+	rule, err := recurrence.RoutineRule(routine)
+	if err != nil {
+		return l.T("home.recurrence.custom")
+	}
+	if rule.Interval <= 1 && len(rule.ByMonth) == 0 && len(rule.ByMonthDay) == 0 {
+		switch rule.Freq {
+		case recurrence.Daily, recurrence.Weekly:
+			return weekdaysLabel(l, routine.Weekdays)
+		case recurrence.Monthly:
+			return l.T("home.recurrence.monthly")
+		case recurrence.Yearly:
+			return l.T("home.recurrence.yearly")
+		}
+	}
+	return l.T("home.recurrence.custom")
+}
+
+// routineProgressLabel shows today's progress towards the target, e.g. "3/8 copos".
+func routineProgressLabel(l i18n.Localizer, routine domain.Routine) string {
+	unit := ""
+	if routine.TargetUnit != nil {
+		unit = *routine.TargetUnit
+	}
+	return l.T("home.routine.progress", i18n.Vars{
+		"done":   formatTargetValue(routine.ProgressToday),
+		"target": formatTargetValue(*routine.TargetValue),
+		"unit":   unit,
+	})
+}
+
+func weekdaysLabel(l i18n.Localizer, weekdays []int) string {
 	if len(weekdays) == 0 {
 		return ""
 	}
 	if len(weekdays) == 7 {
-		return "Todo dia"
+		return l.T("home.weekdays.every_day")
 	}
 
 	has := make(map[int]bool, len(weekdays))
@@ -764,20 +830,10 @@ func weekdaysLabel(weekdays []int) string {
 	}
 
 	if len(weekdays) == 5 && has[1] && has[2] && has[3] && has[4] && has[5] {
-		return "Seg-Sex"
+		return l.T("home.weekdays.weekdays")
 	}
 	if len(weekdays) == 2 && has[0] && has[6] {
-		return "Final de semana"
-	}
-
-	dayNames := map[int]string{
-		0: "Dom",
-		1: "Seg",
-		2: "Ter",
-		3: "Qua",
-		4: "Qui",
-		5: "Sex",
-		6: "Sab",
+		return l.T("home.weekdays.weekend")
 	}
 
 	sorted := make([]int, 0, len(weekdays))
@@ -788,8 +844,8 @@ func weekdaysLabel(weekdays []int) string {
 
 	labels := make([]string, 0, len(sorted))
 	for _, d := range sorted {
-		if name, ok := dayNames[d]; ok {
-			labels = append(labels, name)
+		if d >= 0 && d <= 6 {
+			labels = append(labels, l.WeekdayShort(time.Weekday(d)))
 		}
 	}
 	return strings.Join(labels, "-")
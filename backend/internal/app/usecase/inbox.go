@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/holiday"
 	"inbota/backend/internal/app/repository"
 	"inbota/backend/internal/app/service"
 )
@@ -25,6 +27,8 @@ type InboxUsecase struct {
 	Events        repository.EventRepository
 	ShoppingLists repository.ShoppingListRepository
 	ShoppingItems repository.ShoppingItemRepository
+	TaskChecklist repository.TaskChecklistItemRepository
+	Projects      repository.ProjectRepository
 
 	// Usecases (preferred): used to unify business rules. When running inside a tx,
 	// we inject the tx-bound repositories into a copied usecase instance.
@@ -64,6 +68,7 @@ type ConfirmInboxInput struct {
 type ConfirmResult struct {
 	Type          domain.AiSuggestionType
 	Task          *domain.Task
+	TaskChecklist []domain.TaskChecklistItem
 	Reminder      *domain.Reminder
 	Event         *domain.Event
 	ShoppingList  *domain.ShoppingList
@@ -328,6 +333,17 @@ func (uc *InboxUsecase) ReprocessInboxItem(ctx context.Context, userID, id strin
 		}
 	}
 
+	projects, err := listPromptProjects(ctx, uc.Projects, userID)
+	if err != nil {
+		return InboxItemResult{}, err
+	}
+
+	// Feriados dos próximos 60 dias, para o modelo contar dias úteis.
+	holidays := make([]service.HolidayItem, 0)
+	for _, h := range holiday.Between(holiday.For(user.HolidayCalendar), now, now.AddDate(0, 0, 60)) {
+		holidays = append(holidays, service.HolidayItem{Date: h.Date, Name: h.Name})
+	}
+
 	promptInput := service.PromptInput{
 		RawText:  item.RawText,
 		Locale:   strings.TrimSpace(user.Locale),
@@ -336,6 +352,8 @@ func (uc *InboxUsecase) ReprocessInboxItem(ctx context.Context, userID, id strin
 		Contexts: contexts,
 		Rules:    ruleItems,
 		Hint:     hint,
+		Holidays: holidays,
+		Projects: projects,
 	}
 	prompt := uc.PromptBuilder.Build(promptInput)
 
@@ -632,9 +650,11 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 	if err != nil {
 		fallbackLoc = now.Location()
 	}
+	cal := holiday.For(holiday.DefaultCode)
 	if uc.Users != nil {
 		user, err := uc.Users.Get(ctx, userID)
 		if err == nil {
+			cal = holiday.For(user.HolidayCalendar)
 			if tz := strings.TrimSpace(user.Timezone); tz != "" {
 				if loc, err := time.LoadLocation(tz); err == nil {
 					now = now.In(loc)
@@ -669,13 +689,36 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 					return ErrInvalidPayload
 				}
 
+				fixBusinessDayOffset(taskPayload.DueAt, nil, item.RawText, now, cal)
+
+				projectID, err := resolveProjectByName(ctx, uc.Projects, userID, taskPayload.Project)
+				if err != nil {
+					return err
+				}
+
 				taskUC := *uc.TasksUsecase
 				taskUC.Tasks = tx.Tasks
-				created, err := taskUC.Create(ctx, userID, title, nil, nil, taskPayload.DueAt, flagID, subflagID, &item.ID)
+				created, err := taskUC.Create(ctx, userID, TaskCreateInput{
+					Title:             title,
+					DueAt:             taskPayload.DueAt,
+					FlagID:            flagID,
+					SubflagID:         subflagID,
+					SourceInboxItemID: &item.ID,
+					ProjectID:         projectID,
+					Priority:          taskPayload.Priority,
+					EffortMins:        taskPayload.EffortMins,
+					Recurrence:        taskRecurrenceFromPayload(taskPayload.Recurrence),
+					Notification:      notificationOverrideFromPayload(taskPayload.Notification),
+				})
 				if err != nil {
 					return err
 				}
 				result.Task = &created
+				checklist, err := createTaskChecklist(ctx, tx.TaskChecklist, userID, created.ID, taskPayload.Checklist)
+				if err != nil {
+					return err
+				}
+				result.TaskChecklist = checklist
 			case domain.AiSuggestionTypeReminder:
 				if tx.Reminders == nil {
 					return ErrDependencyMissing
@@ -689,10 +732,11 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 				}
 
 				fixWeekdayMismatch(&reminderPayload.At, nil, item.RawText, now)
+				fixBusinessDayOffset(&reminderPayload.At, nil, item.RawText, now, cal)
 
 				remUC := *uc.RemindersUsecase
 				remUC.Reminders = tx.Reminders
-				created, err := remUC.Create(ctx, userID, title, nil, &reminderPayload.At, flagID, subflagID, &item.ID)
+				created, err := remUC.Create(ctx, userID, title, nil, &reminderPayload.At, flagID, subflagID, &item.ID, notificationOverrideFromPayload(reminderPayload.Notification), nil)
 				if err != nil {
 					return err
 				}
@@ -712,10 +756,11 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 				// Guardrail: if the user explicitly mentioned a weekday (e.g. "sexta") and the
 				// model returned a different weekday (e.g. sábado), fix it deterministically.
 				fixWeekdayMismatch(&eventPayload.Start, eventPayload.End, item.RawText, now)
+				fixBusinessDayOffset(&eventPayload.Start, eventPayload.End, item.RawText, now, cal)
 
 				eventUC := *uc.EventsUsecase
 				eventUC.Events = tx.Events
-				created, err := eventUC.Create(ctx, userID, title, &eventPayload.Start, eventPayload.End, &eventPayload.AllDay, nil, flagID, subflagID, &item.ID)
+				created, err := eventUC.Create(ctx, userID, title, &eventPayload.Start, eventPayload.End, &eventPayload.AllDay, nil, flagID, subflagID, &item.ID, notificationOverrideFromPayload(eventPayload.Notification))
 				if err != nil {
 					return err
 				}
@@ -784,6 +829,8 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 					FlagID:            flagID,
 					SubflagID:         subflagID,
 					SourceInboxItemID: &item.ID,
+					Notification:      notificationOverrideFromPayload(routinePayload.Notification),
+					SkipHolidays:      routinePayload.SkipHolidays,
 				})
 				if err != nil {
 					return err
@@ -812,11 +859,31 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 			if !ok {
 				return ConfirmResult{}, ErrInvalidPayload
 			}
-			created, err := uc.TasksUsecase.Create(ctx, userID, title, nil, nil, taskPayload.DueAt, flagID, subflagID, &item.ID)
+			projectID, err := resolveProjectByName(ctx, uc.Projects, userID, taskPayload.Project)
+			if err != nil {
+				return ConfirmResult{}, err
+			}
+			created, err := uc.TasksUsecase.Create(ctx, userID, TaskCreateInput{
+				Title:             title,
+				DueAt:             taskPayload.DueAt,
+				FlagID:            flagID,
+				SubflagID:         subflagID,
+				SourceInboxItemID: &item.ID,
+				ProjectID:         projectID,
+				Priority:          taskPayload.Priority,
+				EffortMins:        taskPayload.EffortMins,
+				Recurrence:        taskRecurrenceFromPayload(taskPayload.Recurrence),
+				Notification:      notificationOverrideFromPayload(taskPayload.Notification),
+			})
 			if err != nil {
 				return ConfirmResult{}, err
 			}
 			result.Task = &created
+			checklist, err := createTaskChecklist(ctx, uc.TaskChecklist, userID, created.ID, taskPayload.Checklist)
+			if err != nil {
+				return ConfirmResult{}, err
+			}
+			result.TaskChecklist = checklist
 		case domain.AiSuggestionTypeReminder:
 			if uc.RemindersUsecase == nil {
 				return ConfirmResult{}, ErrDependencyMissing
@@ -825,7 +892,7 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 			if !ok {
 				return ConfirmResult{}, ErrInvalidPayload
 			}
-			created, err := uc.RemindersUsecase.Create(ctx, userID, title, nil, &reminderPayload.At, flagID, subflagID, &item.ID)
+			created, err := uc.RemindersUsecase.Create(ctx, userID, title, nil, &reminderPayload.At, flagID, subflagID, &item.ID, notificationOverrideFromPayload(reminderPayload.Notification), nil)
 			if err != nil {
 				return ConfirmResult{}, err
 			}
@@ -838,7 +905,7 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 			if !ok {
 				return ConfirmResult{}, ErrInvalidPayload
 			}
-			created, err := uc.EventsUsecase.Create(ctx, userID, title, &eventPayload.Start, eventPayload.End, &eventPayload.AllDay, nil, flagID, subflagID, &item.ID)
+			created, err := uc.EventsUsecase.Create(ctx, userID, title, &eventPayload.Start, eventPayload.End, &eventPayload.AllDay, nil, flagID, subflagID, &item.ID, notificationOverrideFromPayload(eventPayload.Notification))
 			if err != nil {
 				return ConfirmResult{}, err
 			}
@@ -899,6 +966,8 @@ func (uc *InboxUsecase) ConfirmInboxItem(ctx context.Context, userID, id string,
 				FlagID:            flagID,
 				SubflagID:         subflagID,
 				SourceInboxItemID: &item.ID,
+				Notification:      notificationOverrideFromPayload(routinePayload.Notification),
+				SkipHolidays:      routinePayload.SkipHolidays,
 			})
 			if err != nil {
 				return ConfirmResult{}, err
@@ -1163,6 +1232,64 @@ func fixWeekdayMismatch(start *time.Time, end *time.Time, rawText string, now ti
 	*start = fixedStart
 }
 
+var (
+	businessDaysOffsetRe = regexp.MustCompile(`\b(?:em|daqui a|daqui|in|within)\s+(\d{1,2}|[a-zêé]+)\s+(?:dias?\s+[uú]teis|business\s+days?|working\s+days?)`)
+	nextBusinessDayRe    = regexp.MustCompile(`\b(?:pr[oó]ximo\s+dia\s+[uú]til|next\s+business\s+day)\b`)
+)
+
+var businessDaysWords = map[string]int{
+	"um": 1, "uma": 1, "dois": 2, "duas": 2, "tres": 3, "três": 3, "quatro": 4, "cinco": 5,
+	"seis": 6, "sete": 7, "oito": 8, "nove": 9, "dez": 10,
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// detectBusinessDaysOffset finds "em N dias úteis" / "próximo dia útil" in the text.
+func detectBusinessDaysOffset(text string) (int, bool) {
+	lower := strings.ToLower(text)
+	if nextBusinessDayRe.FindStringIndex(lower) != nil {
+		return 1, true
+	}
+	match := businessDaysOffsetRe.FindStringSubmatch(lower)
+	if match == nil {
+		return 0, false
+	}
+	if n, err := strconv.Atoi(match[1]); err == nil && n > 0 {
+		return n, true
+	}
+	n, ok := businessDaysWords[match[1]]
+	return n, ok
+}
+
+// fixBusinessDayOffset resolves "em N dias úteis" against the user's holiday calendar, keeping
+// the time of day chosen by the model. Explicit dates win over the offset.
+func fixBusinessDayOffset(start *time.Time, end *time.Time, rawText string, now time.Time, cal holiday.Calendar) {
+	if start == nil || hasExplicitDate(rawText) {
+		return
+	}
+	n, ok := detectBusinessDaysOffset(rawText)
+	if !ok {
+		return
+	}
+
+	loc := now.Location()
+	startLocal := start.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	target := holiday.AddBusinessDays(cal, today, n)
+	if startLocal.Year() == target.Year() && startLocal.YearDay() == target.YearDay() {
+		return
+	}
+
+	fixedStart := time.Date(
+		target.Year(), target.Month(), target.Day(),
+		startLocal.Hour(), startLocal.Minute(), startLocal.Second(), startLocal.Nanosecond(),
+		loc,
+	)
+	if end != nil {
+		*end = fixedStart.Add(end.In(loc).Sub(startLocal))
+	}
+	*start = fixedStart
+}
+
 func hasExplicitDate(text string) bool {
 	lower := strings.ToLower(text)
 	// very lightweight checks
@@ -1215,3 +1342,17 @@ func nextOccurrenceOfWeekday(now time.Time, target time.Weekday) time.Time {
 	}
 	return start.AddDate(0, 0, delta)
 }
+
+func notificationOverrideFromPayload(payload *service.NotificationPayload) *domain.NotificationOverride {
+	if payload == nil {
+		return nil
+	}
+	return &domain.NotificationOverride{LeadMins: payload.LeadMins, Disabled: payload.Disabled}
+}
+
+func taskRecurrenceFromPayload(payload *service.TaskRecurrencePayload) *domain.TaskRecurrence {
+	if payload == nil {
+		return nil
+	}
+	return &domain.TaskRecurrence{Rule: payload.Rule, AfterDays: payload.AfterDays}
+}
@@ -30,11 +30,30 @@ func (uc *InboxUsecase) applyValidatedSuggestionTx(ctx context.Context, tx repos
 			fID = normalizeOptionalString(vout.Output.Context.FlagID)
 			sfID = normalizeOptionalString(vout.Output.Context.SubflagID)
 		}
-		task, err := taskUC.Create(ctx, userID, vout.Output.Title, nil, nil, p.DueAt, fID, sfID, &item.ID)
+		projectID, err := resolveProjectByName(ctx, uc.Projects, userID, p.Project)
 		if err != nil {
 			return ConfirmResult{}, err
 		}
-		return ConfirmResult{Type: typ, Task: &task}, nil
+		task, err := taskUC.Create(ctx, userID, TaskCreateInput{
+			Title:             vout.Output.Title,
+			DueAt:             p.DueAt,
+			FlagID:            fID,
+			SubflagID:         sfID,
+			SourceInboxItemID: &item.ID,
+			ProjectID:         projectID,
+			Priority:          p.Priority,
+			EffortMins:        p.EffortMins,
+			Recurrence:        taskRecurrenceFromPayload(p.Recurrence),
+			Notification:      notificationOverrideFromPayload(p.Notification),
+		})
+		if err != nil {
+			return ConfirmResult{}, err
+		}
+		checklist, err := createTaskChecklist(ctx, tx.TaskChecklist, userID, task.ID, p.Checklist)
+		if err != nil {
+			return ConfirmResult{}, err
+		}
+		return ConfirmResult{Type: typ, Task: &task, TaskChecklist: checklist}, nil
 
 	case domain.AiSuggestionTypeReminder:
 		if tx.Reminders == nil || uc.RemindersUsecase == nil {
@@ -51,7 +70,7 @@ func (uc *InboxUsecase) applyValidatedSuggestionTx(ctx context.Context, tx repos
 			fID = normalizeOptionalString(vout.Output.Context.FlagID)
 			sfID = normalizeOptionalString(vout.Output.Context.SubflagID)
 		}
-		reminder, err := remUC.Create(ctx, userID, vout.Output.Title, nil, &p.At, fID, sfID, &item.ID)
+		reminder, err := remUC.Create(ctx, userID, vout.Output.Title, nil, &p.At, fID, sfID, &item.ID, notificationOverrideFromPayload(p.Notification), nil)
 		if err != nil {
 			return ConfirmResult{}, err
 		}
@@ -72,7 +91,7 @@ func (uc *InboxUsecase) applyValidatedSuggestionTx(ctx context.Context, tx repos
 			fID = normalizeOptionalString(vout.Output.Context.FlagID)
 			sfID = normalizeOptionalString(vout.Output.Context.SubflagID)
 		}
-		event, err := eventUC.Create(ctx, userID, vout.Output.Title, &p.Start, p.End, &p.AllDay, nil, fID, sfID, &item.ID)
+		event, err := eventUC.Create(ctx, userID, vout.Output.Title, &p.Start, p.End, &p.AllDay, nil, fID, sfID, &item.ID, notificationOverrideFromPayload(p.Notification))
 		if err != nil {
 			return ConfirmResult{}, err
 		}
@@ -105,6 +124,8 @@ func (uc *InboxUsecase) applyValidatedSuggestionTx(ctx context.Context, tx repos
 			FlagID:            fID,
 			SubflagID:         sfID,
 			SourceInboxItemID: &item.ID,
+			Notification:      notificationOverrideFromPayload(p.Notification),
+			SkipHolidays:      p.SkipHolidays,
 		})
 		if err != nil {
 			return ConfirmResult{}, err
@@ -161,11 +182,30 @@ func (uc *InboxUsecase) applyValidatedSuggestionNoTx(ctx context.Context, userID
 			fID = normalizeOptionalString(vout.Output.Context.FlagID)
 			sfID = normalizeOptionalString(vout.Output.Context.SubflagID)
 		}
-		task, err := uc.TasksUsecase.Create(ctx, userID, vout.Output.Title, nil, nil, p.DueAt, fID, sfID, &item.ID)
+		projectID, err := resolveProjectByName(ctx, uc.Projects, userID, p.Project)
+		if err != nil {
+			return ConfirmResult{}, err
+		}
+		task, err := uc.TasksUsecase.Create(ctx, userID, TaskCreateInput{
+			Title:             vout.Output.Title,
+			DueAt:             p.DueAt,
+			FlagID:            fID,
+			SubflagID:         sfID,
+			SourceInboxItemID: &item.ID,
+			ProjectID:         projectID,
+			Priority:          p.Priority,
+			EffortMins:        p.EffortMins,
+			Recurrence:        taskRecurrenceFromPayload(p.Recurrence),
+			Notification:      notificationOverrideFromPayload(p.Notification),
+		})
+		if err != nil {
+			return ConfirmResult{}, err
+		}
+		checklist, err := createTaskChecklist(ctx, uc.TaskChecklist, userID, task.ID, p.Checklist)
 		if err != nil {
 			return ConfirmResult{}, err
 		}
-		return ConfirmResult{Type: typ, Task: &task}, nil
+		return ConfirmResult{Type: typ, Task: &task, TaskChecklist: checklist}, nil
 	case domain.AiSuggestionTypeReminder:
 		if uc.RemindersUsecase == nil {
 			return ConfirmResult{}, ErrDependencyMissing
@@ -179,7 +219,7 @@ func (uc *InboxUsecase) applyValidatedSuggestionNoTx(ctx context.Context, userID
 			fID = normalizeOptionalString(vout.Output.Context.FlagID)
 			sfID = normalizeOptionalString(vout.Output.Context.SubflagID)
 		}
-		reminder, err := uc.RemindersUsecase.Create(ctx, userID, vout.Output.Title, nil, &p.At, fID, sfID, &item.ID)
+		reminder, err := uc.RemindersUsecase.Create(ctx, userID, vout.Output.Title, nil, &p.At, fID, sfID, &item.ID, notificationOverrideFromPayload(p.Notification), nil)
 		if err != nil {
 			return ConfirmResult{}, err
 		}
@@ -197,7 +237,7 @@ func (uc *InboxUsecase) applyValidatedSuggestionNoTx(ctx context.Context, userID
 			fID = normalizeOptionalString(vout.Output.Context.FlagID)
 			sfID = normalizeOptionalString(vout.Output.Context.SubflagID)
 		}
-		event, err := uc.EventsUsecase.Create(ctx, userID, vout.Output.Title, &p.Start, p.End, &p.AllDay, nil, fID, sfID, &item.ID)
+		event, err := uc.EventsUsecase.Create(ctx, userID, vout.Output.Title, &p.Start, p.End, &p.AllDay, nil, fID, sfID, &item.ID, notificationOverrideFromPayload(p.Notification))
 		if err != nil {
 			return ConfirmResult{}, err
 		}
@@ -227,6 +267,8 @@ func (uc *InboxUsecase) applyValidatedSuggestionNoTx(ctx context.Context, userID
 			FlagID:            fID,
 			SubflagID:         sfID,
 			SourceInboxItemID: &item.ID,
+			Notification:      notificationOverrideFromPayload(p.Notification),
+			SkipHolidays:      p.SkipHolidays,
 		})
 		if err != nil {
 			return ConfirmResult{}, err
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/holiday"
+)
+
+func TestFixBusinessDayOffsetSkipsWeekendAndHolidays(t *testing.T) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+	// Quinta 2026-04-02: sexta é Sexta-feira Santa.
+	now := time.Date(2026, 4, 2, 10, 0, 0, 0, loc)
+	cal := holiday.For("BR")
+
+	start := time.Date(2026, 4, 5, 9, 0, 0, 0, loc)
+	end := start.Add(time.Hour)
+	fixBusinessDayOffset(&start, &end, "Reunião com o contador em 2 dias úteis às 9h", now, cal)
+	if want := time.Date(2026, 4, 7, 9, 0, 0, 0, loc); !start.Equal(want) || !end.Equal(want.Add(time.Hour)) {
+		t.Fatalf("expected %s-%s, got %s-%s", want, want.Add(time.Hour), start, end)
+	}
+
+	due := time.Date(2026, 4, 3, 18, 0, 0, 0, loc)
+	fixBusinessDayOffset(&due, nil, "entregar relatório no próximo dia útil", now, cal)
+	if want := time.Date(2026, 4, 6, 18, 0, 0, 0, loc); !due.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, due)
+	}
+
+	explicit := time.Date(2026, 4, 10, 9, 0, 0, 0, loc)
+	fixBusinessDayOffset(&explicit, nil, "em 3 dias úteis, dia 10/04", now, cal)
+	if want := time.Date(2026, 4, 10, 9, 0, 0, 0, loc); !explicit.Equal(want) {
+		t.Fatalf("explicit dates should win, got %s", explicit)
+	}
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/app/service"
+)
+
+// NotificationTemplateUsecase manages notification templates (admin only).
+// Every write reloads the shared renderer so the scheduler picks up changes without a restart;
+// a failed reload is only logged, since the write itself already committed.
+type NotificationTemplateUsecase struct {
+	Templates repository.NotificationTemplateRepository
+	Renderer  *service.NotificationRenderer
+}
+
+type NotificationTemplateInput struct {
+	Type          string
+	TriggerKey    string
+	Locale        string
+	TitleTemplate string
+	BodyTemplate  string
+	IsActive      *bool
+}
+
+type NotificationTemplateUpdateInput struct {
+	TitleTemplate *string
+	BodyTemplate  *string
+	IsActive      *bool
+}
+
+type NotificationTemplatePreviewInput struct {
+	Type          string
+	TriggerKey    string
+	Locale        string
+	TitleTemplate *string
+	BodyTemplate  *string
+	Vars          service.NotificationTemplateVars
+}
+
+var notificationTriggerKeys = map[string]struct{}{
+	"at_time":       {},
+	"lead_time":     {},
+	"lead_time_day": {},
+	"escalation":    {},
+	"unblocked":     {},
+}
+
+func (uc *NotificationTemplateUsecase) List(ctx context.Context) ([]domain.NotificationTemplate, error) {
+	return uc.Templates.List(ctx)
+}
+
+func (uc *NotificationTemplateUsecase) Get(ctx context.Context, id string) (domain.NotificationTemplate, error) {
+	if id == "" {
+		return domain.NotificationTemplate{}, ErrMissingRequiredFields
+	}
+	return uc.Templates.Get(ctx, id)
+}
+
+func (uc *NotificationTemplateUsecase) Create(ctx context.Context, input NotificationTemplateInput) (domain.NotificationTemplate, error) {
+	nType, triggerKey, locale, err := normalizeTemplateKey(input.Type, input.TriggerKey, input.Locale)
+	if err != nil {
+		return domain.NotificationTemplate{}, err
+	}
+	if normalizeString(input.TitleTemplate) == "" || normalizeString(input.BodyTemplate) == "" {
+		return domain.NotificationTemplate{}, ErrMissingRequiredFields
+	}
+	if err := validateTemplateTexts(input.TitleTemplate, input.BodyTemplate); err != nil {
+		return domain.NotificationTemplate{}, err
+	}
+
+	existing, err := uc.Templates.List(ctx)
+	if err != nil {
+		return domain.NotificationTemplate{}, err
+	}
+	for _, t := range existing {
+		if t.Type == nType && t.TriggerKey == triggerKey && service.NormalizeNotificationLocale(t.Locale) == locale {
+			return domain.NotificationTemplate{}, ErrTemplateConflict
+		}
+	}
+
+	active := true
+	if input.IsActive != nil {
+		active = *input.IsActive
+	}
+	tpl, err := uc.Templates.Create(ctx, domain.NotificationTemplate{
+		Type:          nType,
+		TriggerKey:    triggerKey,
+		Locale:        locale,
+		TitleTemplate: input.TitleTemplate,
+		BodyTemplate:  input.BodyTemplate,
+		IsActive:      active,
+	})
+	if err != nil {
+		return domain.NotificationTemplate{}, err
+	}
+	uc.reloadAfterWrite(ctx)
+	return tpl, nil
+}
+
+func (uc *NotificationTemplateUsecase) Update(ctx context.Context, id string, input NotificationTemplateUpdateInput) (domain.NotificationTemplate, error) {
+	if id == "" {
+		return domain.NotificationTemplate{}, ErrMissingRequiredFields
+	}
+	tpl, err := uc.Templates.Get(ctx, id)
+	if err != nil {
+		return domain.NotificationTemplate{}, err
+	}
+
+	if input.TitleTemplate != nil {
+		if normalizeString(*input.TitleTemplate) == "" {
+			return domain.NotificationTemplate{}, ErrMissingRequiredFields
+		}
+		tpl.TitleTemplate = *input.TitleTemplate
+	}
+	if input.BodyTemplate != nil {
+		if normalizeString(*input.BodyTemplate) == "" {
+			return domain.NotificationTemplate{}, ErrMissingRequiredFields
+		}
+		tpl.BodyTemplate = *input.BodyTemplate
+	}
+	if input.IsActive != nil {
+		tpl.IsActive = *input.IsActive
+	}
+	if err := validateTemplateTexts(tpl.TitleTemplate, tpl.BodyTemplate); err != nil {
+		return domain.NotificationTemplate{}, err
+	}
+
+	tpl, err = uc.Templates.Update(ctx, tpl)
+	if err != nil {
+		return domain.NotificationTemplate{}, err
+	}
+	uc.reloadAfterWrite(ctx)
+	return tpl, nil
+}
+
+func (uc *NotificationTemplateUsecase) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrMissingRequiredFields
+	}
+	if err := uc.Templates.Delete(ctx, id); err != nil {
+		return err
+	}
+	uc.reloadAfterWrite(ctx)
+	return nil
+}
+
+// Reload recompiles the active templates into the shared renderer.
+func (uc *NotificationTemplateUsecase) Reload(ctx context.Context) error {
+	if uc.Renderer == nil {
+		return nil
+	}
+	tmpls, err := uc.Templates.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	uc.Renderer.Load(tmpls)
+	return nil
+}
+
+// reloadAfterWrite refreshes the renderer after a committed write. On failure the cache keeps
+// the previous templates until the next write or POST /reload.
+func (uc *NotificationTemplateUsecase) reloadAfterWrite(ctx context.Context) {
+	if err := uc.Reload(ctx); err != nil {
+		slog.Error("notification_template_reload_error", slog.String("error", err.Error()))
+	}
+}
+
+// Preview renders the given template texts, or the stored template (with fallbacks)
+// when none is provided.
+func (uc *NotificationTemplateUsecase) Preview(ctx context.Context, input NotificationTemplatePreviewInput) (string, string, error) {
+	nType, triggerKey, locale, err := normalizeTemplateKey(input.Type, input.TriggerKey, input.Locale)
+	if err != nil {
+		return "", "", err
+	}
+
+	if input.TitleTemplate != nil || input.BodyTemplate != nil {
+		if input.TitleTemplate == nil || input.BodyTemplate == nil {
+			return "", "", ErrMissingRequiredFields
+		}
+		return service.RenderTemplates(*input.TitleTemplate, *input.BodyTemplate, locale, input.Vars)
+	}
+
+	if uc.Renderer == nil {
+		return "", "", ErrDependencyMissing
+	}
+	title, body := uc.Renderer.Render(nType, triggerKey, locale, input.Vars)
+	return title, body, nil
+}
+
+func normalizeTemplateKey(rawType, rawTrigger, rawLocale string) (domain.NotificationType, string, string, error) {
+	typ := normalizeString(rawType)
+	triggerKey := normalizeString(rawTrigger)
+	if typ == "" || triggerKey == "" {
+		return "", "", "", ErrMissingRequiredFields
+	}
+
+	// Briefings e revisões montam o texto a partir do digest e não passam pelo renderer.
+	nType := domain.NotificationType(typ)
+	switch nType {
+	case domain.NotificationTypeReminder, domain.NotificationTypeEvent, domain.NotificationTypeTask, domain.NotificationTypeRoutine:
+	default:
+		return "", "", "", ErrInvalidType
+	}
+	if _, ok := notificationTriggerKeys[triggerKey]; !ok {
+		return "", "", "", ErrInvalidPayload
+	}
+
+	locale := service.NormalizeNotificationLocale(rawLocale)
+	if locale == "" {
+		locale = service.DefaultNotificationLocale
+	}
+	return nType, triggerKey, locale, nil
+}
+
+func validateTemplateTexts(title, body string) error {
+	if _, err := service.ParseNotificationTemplate("title", title); err != nil {
+		return err
+	}
+	_, err := service.ParseNotificationTemplate("body", body)
+	return err
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/app/service"
+)
+
+type templateRepoStub struct {
+	repository.NotificationTemplateRepository
+	getAllErr error
+	deleted   []string
+}
+
+func (s *templateRepoStub) GetAll(context.Context) ([]domain.NotificationTemplate, error) {
+	return nil, s.getAllErr
+}
+
+func (s *templateRepoStub) List(context.Context) ([]domain.NotificationTemplate, error) {
+	return nil, nil
+}
+
+func (s *templateRepoStub) Get(_ context.Context, id string) (domain.NotificationTemplate, error) {
+	return domain.NotificationTemplate{ID: id, Type: domain.NotificationTypeTask, TriggerKey: "at_time", TitleTemplate: "{{.Title}}", BodyTemplate: "Agora"}, nil
+}
+
+func (s *templateRepoStub) Create(_ context.Context, tpl domain.NotificationTemplate) (domain.NotificationTemplate, error) {
+	tpl.ID = "tpl-1"
+	return tpl, nil
+}
+
+func (s *templateRepoStub) Update(_ context.Context, tpl domain.NotificationTemplate) (domain.NotificationTemplate, error) {
+	return tpl, nil
+}
+
+func (s *templateRepoStub) Delete(_ context.Context, id string) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func TestNotificationTemplateWritesSucceedWhenReloadFails(t *testing.T) {
+	repo := &templateRepoStub{getAllErr: errors.New("connection reset")}
+	uc := &NotificationTemplateUsecase{Templates: repo, Renderer: service.NewNotificationRenderer()}
+	ctx := context.Background()
+
+	created, err := uc.Create(ctx, NotificationTemplateInput{Type: "task", TriggerKey: "at_time", TitleTemplate: "{{.Title}}", BodyTemplate: "Agora"})
+	if err != nil || created.ID != "tpl-1" {
+		t.Fatalf("Create = %+v, %v; want the stored template", created, err)
+	}
+	title := "Hoje: {{.Title}}"
+	if _, err := uc.Update(ctx, "tpl-1", NotificationTemplateUpdateInput{TitleTemplate: &title}); err != nil {
+		t.Fatalf("Update error = %v", err)
+	}
+	if err := uc.Delete(ctx, "tpl-1"); err != nil || len(repo.deleted) != 1 {
+		t.Fatalf("Delete error = %v, deleted %v", err, repo.deleted)
+	}
+
+	if err := uc.Reload(ctx); err == nil {
+		t.Fatalf("expected explicit Reload to report the failure")
+	}
+}
+
+func TestNormalizeTemplateKeyTypes(t *testing.T) {
+	for _, typ := range []string{"reminder", "event", "task", "routine"} {
+		if _, _, _, err := normalizeTemplateKey(typ, "at_time", ""); err != nil {
+			t.Fatalf("%s: unexpected error %v", typ, err)
+		}
+	}
+	for _, typ := range []string{"briefing", "review", "bundle"} {
+		if _, _, _, err := normalizeTemplateKey(typ, "at_time", ""); !errors.Is(err, ErrInvalidType) {
+			t.Fatalf("%s: expected ErrInvalidType, got %v", typ, err)
+		}
+	}
+}
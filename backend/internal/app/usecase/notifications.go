@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"inbota/backend/internal/app/domain"
 	"inbota/backend/internal/app/repository"
@@ -16,6 +17,7 @@ type NotificationUsecase struct {
 	Tokens  repository.DeviceTokenRepository
 	Config  repository.AppConfigRepository
 	Ntfy    *push.NtfyClient
+	Users   repository.UserRepository
 }
 
 func (uc *NotificationUsecase) GetDailySummaryToken(ctx context.Context, userID string) (string, error) {
@@ -36,6 +38,20 @@ func (uc *NotificationUsecase) UpdatePreferences(ctx context.Context, userID str
 		return err
 	}
 	updateFn(&prefs)
+	if prefs.BundleWindowMins < 0 || prefs.BundleWindowMins > maxBundleWindowMins {
+		return ErrInvalidPayload
+	}
+	if prefs.WeeklyDigestWeekday < 0 || prefs.WeeklyDigestWeekday > 6 || prefs.WeeklyDigestHour < 0 || prefs.WeeklyDigestHour > 23 {
+		return ErrInvalidPayload
+	}
+	for _, clock := range []string{prefs.MorningBriefingTime, prefs.EveningReviewTime} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return ErrInvalidPayload
+		}
+	}
 	return uc.Prefs.Upsert(ctx, prefs)
 }
 
@@ -65,13 +81,20 @@ func (uc *NotificationUsecase) SendTestNotification(ctx context.Context, userID
 		return fmt.Errorf("no_active_devices")
 	}
 
-	title := "Teste de Notificação"
-	body := "Isso é um teste do Inbota via ntfy.sh! 🎉"
+	l := userLocalizer(ctx, uc.Users, userID)
+	title := l.T("notification.test.title")
+	body := l.T("notification.test.body")
 	
 	data := map[string]string{"type": "test"}
 	var lastErr error
+	sent := make(map[string]struct{}, len(tokens))
 	for _, t := range tokens {
-		if err := uc.Ntfy.Send(ctx, t.Topic, title, body, data); err != nil {
+		// dispositivos do mesmo usuário compartilham o tópico
+		if _, ok := sent[t.Topic]; ok {
+			continue
+		}
+		sent[t.Topic] = struct{}{}
+		if err := uc.Ntfy.Send(ctx, push.NewDestination(t.Topic, t.EncryptionKey), title, body, data); err != nil {
 			slog.Error("ntfy_test_send_error",
 				slog.String("error", err.Error()),
 				slog.String("topic", t.Topic),
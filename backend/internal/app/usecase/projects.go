@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/postgres"
+)
+
+// maxPromptProjects limita quantos projetos abertos vão para o prompt da IA.
+const maxPromptProjects = 50
+
+type ProjectUsecase struct {
+	Projects repository.ProjectRepository
+	Flags    repository.FlagRepository
+	Subflags repository.SubflagRepository
+}
+
+type ProjectUpdateInput struct {
+	Name        *string
+	Description *string
+	Status      *string
+	TargetDate  *string
+	FlagID      *string
+	SubflagID   *string
+}
+
+func (uc *ProjectUsecase) Create(ctx context.Context, userID, name string, description, status, targetDate, flagID, subflagID *string) (domain.Project, error) {
+	name = normalizeString(name)
+	if userID == "" || name == "" {
+		return domain.Project{}, ErrMissingRequiredFields
+	}
+
+	resolvedFlagID, resolvedSubflagID, err := uc.resolveFlagAndSubflag(ctx, userID, flagID, subflagID)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	date, err := normalizeProjectTargetDate(targetDate)
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	project := domain.Project{
+		UserID:      userID,
+		Name:        name,
+		Description: normalizeOptionalString(description),
+		TargetDate:  date,
+		FlagID:      resolvedFlagID,
+		SubflagID:   resolvedSubflagID,
+	}
+	if status != nil {
+		parsed, ok := parseProjectStatus(*status)
+		if !ok {
+			return domain.Project{}, ErrInvalidStatus
+		}
+		project.Status = parsed
+	}
+
+	return uc.Projects.Create(ctx, project)
+}
+
+func (uc *ProjectUsecase) Update(ctx context.Context, userID, id string, input ProjectUpdateInput) (domain.Project, error) {
+	if userID == "" || id == "" {
+		return domain.Project{}, ErrMissingRequiredFields
+	}
+	project, err := uc.Projects.Get(ctx, userID, id)
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	if input.Name != nil {
+		trimmed := normalizeString(*input.Name)
+		if trimmed == "" {
+			return domain.Project{}, ErrMissingRequiredFields
+		}
+		project.Name = trimmed
+	}
+	if input.Description != nil {
+		project.Description = normalizeOptionalString(input.Description)
+	}
+	if input.Status != nil {
+		parsed, ok := parseProjectStatus(*input.Status)
+		if !ok {
+			return domain.Project{}, ErrInvalidStatus
+		}
+		project.Status = parsed
+	}
+	if input.TargetDate != nil {
+		date, err := normalizeProjectTargetDate(input.TargetDate)
+		if err != nil {
+			return domain.Project{}, err
+		}
+		project.TargetDate = date
+	}
+	if input.FlagID != nil || input.SubflagID != nil {
+		nextFlagID := project.FlagID
+		nextSubflagID := project.SubflagID
+		if input.FlagID != nil {
+			nextFlagID = normalizeOptionalString(input.FlagID)
+		}
+		if input.SubflagID != nil {
+			nextSubflagID = normalizeOptionalString(input.SubflagID)
+		}
+		resolvedFlagID, resolvedSubflagID, err := uc.resolveFlagAndSubflag(ctx, userID, nextFlagID, nextSubflagID)
+		if err != nil {
+			return domain.Project{}, err
+		}
+		project.FlagID = resolvedFlagID
+		project.SubflagID = resolvedSubflagID
+	}
+
+	return uc.Projects.Update(ctx, project)
+}
+
+func (uc *ProjectUsecase) Delete(ctx context.Context, userID, id string) error {
+	if userID == "" || id == "" {
+		return ErrMissingRequiredFields
+	}
+	return uc.Projects.Delete(ctx, userID, id)
+}
+
+func (uc *ProjectUsecase) Get(ctx context.Context, userID, id string) (domain.Project, error) {
+	if userID == "" || id == "" {
+		return domain.Project{}, ErrMissingRequiredFields
+	}
+	return uc.Projects.Get(ctx, userID, id)
+}
+
+func (uc *ProjectUsecase) GetByIDs(ctx context.Context, userID string, ids []string) (map[string]domain.Project, error) {
+	if userID == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	projects, err := uc.Projects.GetByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]domain.Project, len(projects))
+	for _, project := range projects {
+		byID[project.ID] = project
+	}
+	return byID, nil
+}
+
+func (uc *ProjectUsecase) List(ctx context.Context, userID string, status *string, opts repository.ListOptions) ([]domain.Project, *string, error) {
+	if userID == "" {
+		return nil, nil, ErrMissingRequiredFields
+	}
+	var statusFilter *domain.ProjectStatus
+	if status != nil && strings.TrimSpace(*status) != "" {
+		parsed, ok := parseProjectStatus(*status)
+		if !ok {
+			return nil, nil, ErrInvalidStatus
+		}
+		statusFilter = &parsed
+	}
+	return uc.Projects.List(ctx, userID, statusFilter, opts)
+}
+
+// Progress returns done/total task counts for each project; projects without tasks get 0/0.
+func (uc *ProjectUsecase) Progress(ctx context.Context, userID string, projectIDs []string) (map[string]domain.ProjectProgress, error) {
+	if userID == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	return uc.Projects.Progress(ctx, userID, projectIDs)
+}
+
+func (uc *ProjectUsecase) resolveFlagAndSubflag(ctx context.Context, userID string, flagID *string, subflagID *string) (*string, *string, error) {
+	resolvedFlagID := normalizeOptionalString(flagID)
+	resolvedSubflagID := normalizeOptionalString(subflagID)
+
+	if resolvedFlagID != nil {
+		if uc.Flags == nil {
+			return nil, nil, ErrDependencyMissing
+		}
+		if _, err := uc.Flags.Get(ctx, userID, *resolvedFlagID); err != nil {
+			if errors.Is(err, postgres.ErrNotFound) {
+				return nil, nil, ErrInvalidPayload
+			}
+			return nil, nil, err
+		}
+	}
+
+	if resolvedSubflagID != nil {
+		if uc.Subflags == nil {
+			return nil, nil, ErrDependencyMissing
+		}
+		subflag, err := uc.Subflags.Get(ctx, userID, *resolvedSubflagID)
+		if err != nil {
+			if errors.Is(err, postgres.ErrNotFound) {
+				return nil, nil, ErrInvalidPayload
+			}
+			return nil, nil, err
+		}
+		if resolvedFlagID != nil && subflag.FlagID != *resolvedFlagID {
+			return nil, nil, ErrInvalidPayload
+		}
+		if resolvedFlagID == nil {
+			flag := subflag.FlagID
+			resolvedFlagID = &flag
+		}
+	}
+
+	return resolvedFlagID, resolvedSubflagID, nil
+}
+
+// normalizeProjectTargetDate accepts YYYY-MM-DD; an empty value clears the date.
+func normalizeProjectTargetDate(value *string) (*string, error) {
+	trimmed := normalizeOptionalString(value)
+	if trimmed == nil {
+		return nil, nil
+	}
+	parsed, ok := parseRoutineDate(*trimmed)
+	if !ok {
+		return nil, ErrInvalidPayload
+	}
+	date := parsed.Format("2006-01-02")
+	return &date, nil
+}
+
+// listPromptProjects returns the names of the open projects sent to the AI prompt.
+func listPromptProjects(ctx context.Context, repo repository.ProjectRepository, userID string) ([]string, error) {
+	if repo == nil {
+		return nil, nil
+	}
+	status := domain.ProjectStatusOpen
+	projects, _, err := repo.List(ctx, userID, &status, repository.ListOptions{Limit: maxPromptProjects})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(projects))
+	for _, project := range projects {
+		names = append(names, project.Name)
+	}
+	return names, nil
+}
+
+// resolveProjectByName attaches an AI suggestion to an existing project. Unknown names are ignored.
+func resolveProjectByName(ctx context.Context, repo repository.ProjectRepository, userID string, name *string) (*string, error) {
+	trimmed := normalizeOptionalString(name)
+	if repo == nil || trimmed == nil {
+		return nil, nil
+	}
+	project, err := repo.FindByName(ctx, userID, *trimmed)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project.ID, nil
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/postgres"
+)
+
+type projectRepoStub struct {
+	repository.ProjectRepository
+	projects []domain.Project
+}
+
+func (s *projectRepoStub) Get(_ context.Context, userID, id string) (domain.Project, error) {
+	for _, project := range s.projects {
+		if project.ID == id && project.UserID == userID {
+			return project, nil
+		}
+	}
+	return domain.Project{}, postgres.ErrNotFound
+}
+
+func (s *projectRepoStub) FindByName(_ context.Context, userID, name string) (domain.Project, error) {
+	for _, project := range s.projects {
+		if project.UserID == userID && project.Name == name {
+			return project, nil
+		}
+	}
+	return domain.Project{}, postgres.ErrNotFound
+}
+
+type projectTaskRepoStub struct {
+	repository.TaskRepository
+}
+
+func (projectTaskRepoStub) Create(_ context.Context, task domain.Task) (domain.Task, error) {
+	return task, nil
+}
+
+type projectFlagRepoStub struct {
+	repository.FlagRepository
+}
+
+func (projectFlagRepoStub) Get(_ context.Context, userID, id string) (domain.Flag, error) {
+	return domain.Flag{ID: id, UserID: userID}, nil
+}
+
+type projectSubflagRepoStub struct {
+	repository.SubflagRepository
+}
+
+func (projectSubflagRepoStub) Get(_ context.Context, userID, id string) (domain.Subflag, error) {
+	return domain.Subflag{ID: id, UserID: userID, FlagID: "f1"}, nil
+}
+
+func TestTaskCreateInheritsProjectContext(t *testing.T) {
+	flagID, subflagID := "f1", "s1"
+	projects := &projectRepoStub{projects: []domain.Project{{ID: "p1", UserID: "u1", Name: "Reforma", FlagID: &flagID, SubflagID: &subflagID}}}
+	uc := &TaskUsecase{Tasks: projectTaskRepoStub{}, Flags: projectFlagRepoStub{}, Subflags: projectSubflagRepoStub{}, Projects: projects}
+
+	projectID := "p1"
+	task, err := uc.Create(context.Background(), "u1", TaskCreateInput{Title: "Comprar tinta", ProjectID: &projectID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.ProjectID == nil || *task.ProjectID != "p1" {
+		t.Fatalf("expected task in project p1, got %v", task.ProjectID)
+	}
+	if task.FlagID == nil || *task.FlagID != "f1" || task.SubflagID == nil || *task.SubflagID != "s1" {
+		t.Fatalf("expected inherited flag/subflag, got %v/%v", task.FlagID, task.SubflagID)
+	}
+
+	unknown := "p2"
+	if _, err := uc.Create(context.Background(), "u1", TaskCreateInput{Title: "Comprar tinta", ProjectID: &unknown}); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload for unknown project, got %v", err)
+	}
+}
+
+func TestResolveProjectByNameIgnoresUnknown(t *testing.T) {
+	projects := &projectRepoStub{projects: []domain.Project{{ID: "p1", UserID: "u1", Name: "Reforma"}}}
+
+	name := " Reforma "
+	id, err := resolveProjectByName(context.Background(), projects, "u1", &name)
+	if err != nil || id == nil || *id != "p1" {
+		t.Fatalf("expected project p1, got %v, %v", id, err)
+	}
+
+	missing := "Mudanca"
+	if id, err := resolveProjectByName(context.Background(), projects, "u1", &missing); err != nil || id != nil {
+		t.Fatalf("expected unknown project to be ignored, got %v, %v", id, err)
+	}
+}
+
+func TestNormalizeProjectTargetDate(t *testing.T) {
+	value := "2026-11-30"
+	date, err := normalizeProjectTargetDate(&value)
+	if err != nil || date == nil || *date != "2026-11-30" {
+		t.Fatalf("unexpected date: %v, %v", date, err)
+	}
+
+	empty := ""
+	if date, err := normalizeProjectTargetDate(&empty); err != nil || date != nil {
+		t.Fatalf("expected empty date to clear, got %v, %v", date, err)
+	}
+
+	invalid := "30/11/2026"
+	if _, err := normalizeProjectTargetDate(&invalid); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload, got %v", err)
+	}
+}
@@ -22,9 +22,12 @@ type ReminderUpdateInput struct {
 	RemindAt  *time.Time
 	FlagID    *string
 	SubflagID *string
+
+	Notification *domain.NotificationOverride
+	Escalation   *domain.ReminderEscalation
 }
 
-func (uc *ReminderUsecase) Create(ctx context.Context, userID, title string, status *string, remindAt *time.Time, flagID *string, subflagID *string, sourceInboxItemID *string) (domain.Reminder, error) {
+func (uc *ReminderUsecase) Create(ctx context.Context, userID, title string, status *string, remindAt *time.Time, flagID *string, subflagID *string, sourceInboxItemID *string, notification *domain.NotificationOverride, escalation *domain.ReminderEscalation) (domain.Reminder, error) {
 	title = normalizeString(title)
 	if userID == "" || title == "" {
 		return domain.Reminder{}, ErrMissingRequiredFields
@@ -34,6 +37,14 @@ func (uc *ReminderUsecase) Create(ctx context.Context, userID, title string, sta
 	if err != nil {
 		return domain.Reminder{}, err
 	}
+	override, err := normalizeNotificationOverride(notification, domain.MaxNotificationLeadMins)
+	if err != nil {
+		return domain.Reminder{}, err
+	}
+	nag, err := normalizeReminderEscalation(escalation)
+	if err != nil {
+		return domain.Reminder{}, err
+	}
 
 	reminder := domain.Reminder{
 		UserID:            userID,
@@ -42,6 +53,8 @@ func (uc *ReminderUsecase) Create(ctx context.Context, userID, title string, sta
 		FlagID:            resolvedFlagID,
 		SubflagID:         resolvedSubflagID,
 		SourceInboxItemID: normalizeOptionalString(sourceInboxItemID),
+		Notification:      override,
+		Escalation:        nag,
 	}
 
 	if status != nil {
@@ -97,6 +110,20 @@ func (uc *ReminderUsecase) Update(ctx context.Context, userID, id string, input
 		reminder.FlagID = resolvedFlagID
 		reminder.SubflagID = resolvedSubflagID
 	}
+	if input.Notification != nil {
+		override, err := normalizeNotificationOverride(input.Notification, domain.MaxNotificationLeadMins)
+		if err != nil {
+			return domain.Reminder{}, err
+		}
+		reminder.Notification = override
+	}
+	if input.Escalation != nil {
+		nag, err := normalizeReminderEscalation(input.Escalation)
+		if err != nil {
+			return domain.Reminder{}, err
+		}
+		reminder.Escalation = nag
+	}
 
 	return uc.Reminders.Update(ctx, reminder)
 }
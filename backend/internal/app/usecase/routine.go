@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/holiday"
+	"inbota/backend/internal/app/recurrence"
 	"inbota/backend/internal/app/repository"
 	"inbota/backend/internal/infra/postgres"
 )
@@ -19,6 +22,12 @@ type RoutineUsecase struct {
 	Users       repository.UserRepository
 	Flags       repository.FlagRepository
 	Subflags    repository.SubflagRepository
+
+	// NotificationLogs cancela pushes já agendados quando uma exceção muda o dia (nil = não cancela).
+	NotificationLogs repository.NotificationLogRepository
+
+	// Away aplica os períodos de férias do usuário como dias pulados (nil = ignora).
+	Away repository.AwayPeriodRepository
 }
 
 type RoutineInput struct {
@@ -29,12 +38,19 @@ type RoutineInput struct {
 	StartTime         string
 	EndTime           string
 	WeekOfMonth       *int
+	RRule             *string
+	TargetKind        *string
+	TargetValue       *float64
+	TargetUnit        *string
 	StartsOn          *string
 	EndsOn            *string
 	Color             *string
 	FlagID            *string
 	SubflagID         *string
 	SourceInboxItemID *string
+	Notification      *domain.NotificationOverride
+	Streak            *domain.RoutineStreakProtection
+	SkipHolidays      bool
 }
 
 type RoutineUpdateInput struct {
@@ -45,11 +61,18 @@ type RoutineUpdateInput struct {
 	StartTime      *string
 	EndTime        *string
 	WeekOfMonth    *int
+	RRule          *string
+	TargetKind     *string
+	TargetValue    *float64
+	TargetUnit     *string
 	StartsOn       *string
 	EndsOn         *string
 	Color          *string
 	FlagID         *string
 	SubflagID      *string
+	Notification   *domain.NotificationOverride
+	Streak         *domain.RoutineStreakProtection
+	SkipHolidays   *bool
 }
 
 func (uc *RoutineUsecase) Create(ctx context.Context, userID string, input RoutineInput) (domain.Routine, error) {
@@ -60,33 +83,64 @@ func (uc *RoutineUsecase) Create(ctx context.Context, userID string, input Routi
 		return domain.Routine{}, ErrMissingRequiredFields
 	}
 
-	if len(input.Weekdays) == 0 {
-		return domain.Routine{}, ErrMissingRequiredFields
-	}
-
 	if input.StartTime == "" || input.EndTime == "" {
 		return domain.Routine{}, ErrMissingRequiredFields
 	}
 
-	validRecurrenceTypes := map[string]bool{
-		"weekly":       true,
-		"biweekly":     true,
-		"triweekly":    true,
-		"monthly_week": true,
+	if normalizeOptionalString(input.RRule) != nil {
+		input.RecurrenceType = recurrence.TypeRRule
 	}
 	if input.RecurrenceType == "" {
-		input.RecurrenceType = "weekly"
+		input.RecurrenceType = recurrence.TypeWeekly
 	}
-	if !validRecurrenceTypes[input.RecurrenceType] {
+	if !recurrence.IsRoutineType(input.RecurrenceType) {
 		return domain.Routine{}, ErrInvalidPayload
 	}
 
+	now := uc.nowInUserTimezone(ctx, userID)
+
+	var rrule *string
+	if input.RecurrenceType == recurrence.TypeRRule {
+		rule, err := parseRoutineRule(input.RRule)
+		if err != nil {
+			return domain.Routine{}, err
+		}
+		canonical := rule.String()
+		rrule = &canonical
+		anchor := now
+		if input.StartsOn != nil {
+			if parsed, ok := parseRoutineDate(*input.StartsOn); ok {
+				anchor = parsed
+			}
+		}
+		input.Weekdays = rule.Weekdays(anchor)
+	}
+
+	if len(input.Weekdays) == 0 {
+		return domain.Routine{}, ErrMissingRequiredFields
+	}
+
+	targetKind, targetValue, targetUnit, err := normalizeRoutineTarget(input.TargetKind, input.TargetValue, input.TargetUnit)
+	if err != nil {
+		return domain.Routine{}, err
+	}
+
 	resolvedFlagID, resolvedSubflagID, err := uc.ResolveFlagAndSubflag(ctx, userID, input.FlagID, input.SubflagID)
 	if err != nil {
 		return domain.Routine{}, err
 	}
+	override, err := normalizeNotificationOverride(input.Notification, domain.MaxRoutineLeadMins)
+	if err != nil {
+		return domain.Routine{}, err
+	}
+	var streakProtection domain.RoutineStreakProtection
+	if input.Streak != nil {
+		if err := validateStreakProtection(*input.Streak); err != nil {
+			return domain.Routine{}, err
+		}
+		streakProtection = *input.Streak
+	}
 
-	now := uc.nowInUserTimezone(ctx, userID)
 	startsOn := computeStartsOn(now, input.Weekdays, input.StartsOn)
 
 	routine := domain.Routine{
@@ -98,6 +152,10 @@ func (uc *RoutineUsecase) Create(ctx context.Context, userID string, input Routi
 		StartTime:      input.StartTime,
 		EndTime:        input.EndTime,
 		WeekOfMonth:    input.WeekOfMonth,
+		RRule:          rrule,
+		TargetKind:     targetKind,
+		TargetValue:    targetValue,
+		TargetUnit:     targetUnit,
 		StartsOn:       startsOn,
 		EndsOn:         input.EndsOn,
 		Color:          input.Color,
@@ -105,6 +163,9 @@ func (uc *RoutineUsecase) Create(ctx context.Context, userID string, input Routi
 		FlagID:            resolvedFlagID,
 		SubflagID:         resolvedSubflagID,
 		SourceInboxItemID: input.SourceInboxItemID,
+		Notification:      override,
+		StreakProtection:  streakProtection,
+		SkipHolidays:      input.SkipHolidays,
 	}
 
 	if err := uc.Validate(ctx, routine); err != nil {
@@ -137,18 +198,21 @@ func (uc *RoutineUsecase) Update(ctx context.Context, userID, id string, input R
 	}
 
 	if input.RecurrenceType != nil {
-		validRecurrenceTypes := map[string]bool{
-			"weekly":       true,
-			"biweekly":     true,
-			"triweekly":    true,
-			"monthly_week": true,
-		}
-		if !validRecurrenceTypes[*input.RecurrenceType] {
+		if !recurrence.IsRoutineType(*input.RecurrenceType) {
 			return domain.Routine{}, ErrInvalidPayload
 		}
 		routine.RecurrenceType = *input.RecurrenceType
 	}
 
+	if input.RRule != nil {
+		if rrule := normalizeOptionalString(input.RRule); rrule != nil {
+			routine.RecurrenceType = recurrence.TypeRRule
+			routine.RRule = rrule
+		} else if routine.RecurrenceType == recurrence.TypeRRule {
+			routine.RecurrenceType = recurrence.TypeWeekly
+		}
+	}
+
 	if input.Weekdays != nil {
 		if len(*input.Weekdays) == 0 {
 			return domain.Routine{}, ErrMissingRequiredFields
@@ -174,6 +238,28 @@ func (uc *RoutineUsecase) Update(ctx context.Context, userID, id string, input R
 		routine.WeekOfMonth = input.WeekOfMonth
 	}
 
+	if input.TargetKind != nil || input.TargetValue != nil || input.TargetUnit != nil {
+		nextKind, nextValue, nextUnit := routine.TargetKind, routine.TargetValue, routine.TargetUnit
+		if input.TargetKind != nil {
+			nextKind = input.TargetKind
+		}
+		if input.TargetValue != nil {
+			nextValue = input.TargetValue
+		}
+		if input.TargetUnit != nil {
+			nextUnit = input.TargetUnit
+		}
+		if input.TargetKind != nil && normalizeOptionalString(input.TargetKind) == nil {
+			// targetKind vazio remove a meta e a rotina volta a ser binária.
+			nextValue, nextUnit = nil, nil
+		}
+		kind, value, unit, err := normalizeRoutineTarget(nextKind, nextValue, nextUnit)
+		if err != nil {
+			return domain.Routine{}, err
+		}
+		routine.TargetKind, routine.TargetValue, routine.TargetUnit = kind, value, unit
+	}
+
 	if input.StartsOn != nil {
 		routine.StartsOn = *input.StartsOn
 	}
@@ -186,6 +272,22 @@ func (uc *RoutineUsecase) Update(ctx context.Context, userID, id string, input R
 		routine.Color = input.Color
 	}
 
+	if routine.RecurrenceType == recurrence.TypeRRule {
+		rule, err := parseRoutineRule(routine.RRule)
+		if err != nil {
+			return domain.Routine{}, err
+		}
+		canonical := rule.String()
+		routine.RRule = &canonical
+		anchor, _ := parseRoutineDate(routine.StartsOn)
+		routine.Weekdays = rule.Weekdays(anchor)
+	} else {
+		routine.RRule = nil
+		if len(routine.Weekdays) == 0 {
+			return domain.Routine{}, ErrMissingRequiredFields
+		}
+	}
+
 	if input.FlagID != nil || input.SubflagID != nil {
 		nextFlagID := routine.FlagID
 		nextSubflagID := routine.SubflagID
@@ -203,6 +305,25 @@ func (uc *RoutineUsecase) Update(ctx context.Context, userID, id string, input R
 		routine.SubflagID = resolvedSubflagID
 	}
 
+	if input.Notification != nil {
+		override, err := normalizeNotificationOverride(input.Notification, domain.MaxRoutineLeadMins)
+		if err != nil {
+			return domain.Routine{}, err
+		}
+		routine.Notification = override
+	}
+
+	if input.Streak != nil {
+		if err := validateStreakProtection(*input.Streak); err != nil {
+			return domain.Routine{}, err
+		}
+		routine.StreakProtection = *input.Streak
+	}
+
+	if input.SkipHolidays != nil {
+		routine.SkipHolidays = *input.SkipHolidays
+	}
+
 	if err := uc.checkOverlap(ctx, userID, id, routine.Weekdays, routine.StartTime, routine.EndTime); err != nil {
 		return domain.Routine{}, err
 	}
@@ -223,23 +344,22 @@ func (uc *RoutineUsecase) Validate(ctx context.Context, routine domain.Routine)
 		return ErrMissingRequiredFields
 	}
 
-	validRecurrenceTypes := map[string]bool{
-		"weekly":       true,
-		"biweekly":     true,
-		"triweekly":    true,
-		"monthly_week": true,
-	}
 	recurrenceType := routine.RecurrenceType
 	if recurrenceType == "" {
-		recurrenceType = "weekly"
+		recurrenceType = recurrence.TypeWeekly
 	}
-	if !validRecurrenceTypes[recurrenceType] {
+	if !recurrence.IsRoutineType(recurrenceType) {
 		return ErrInvalidPayload
 	}
 
-	if recurrenceType == "monthly_week" && routine.WeekOfMonth == nil {
+	if recurrenceType == recurrence.TypeMonthlyWeek && routine.WeekOfMonth == nil {
 		return ErrInvalidPayload
 	}
+	if recurrenceType == recurrence.TypeRRule {
+		if _, err := parseRoutineRule(routine.RRule); err != nil {
+			return err
+		}
+	}
 
 	return uc.checkOverlap(ctx, routine.UserID, routine.ID, routine.Weekdays, routine.StartTime, routine.EndTime)
 }
@@ -313,104 +433,149 @@ func (uc *RoutineUsecase) ListByWeekday(ctx context.Context, userID string, week
 			return nil, err
 		}
 
-		targetDate := now
+		exceptions := uc.exceptionsOn(ctx, userID, nowStr)
+		away := uc.awayPeriods(ctx, userID, nowStr, nowStr)
+		cal := userHolidayCalendar(ctx, uc.Users, userID)
 		filtered := make([]domain.Routine, 0, len(routines))
 		for _, r := range routines {
-			if shouldShowRoutineForDate(r.Routine, targetDate) {
-				r.Routine.IsCompletedToday = r.IsCompleted
-				filtered = append(filtered, r.Routine)
+			routineExceptions := withHolidays(r.Routine, exceptions[r.ID], cal, now, now)
+			routine, ok := routineOn(r.Routine, now, withAway(r.Routine, routineExceptions, away, now, now))
+			if ok {
+				routine.IsCompletedToday = r.IsCompleted
+				routine.ProgressToday = r.Progress
+				filtered = append(filtered, routine)
 			}
 		}
 		return filtered, nil
 	}
 
 	// Fallback para datas que não sejam hoje
+	targetDate := time.Now()
+	if t, err := time.Parse("2006-01-02", date); err == nil {
+		targetDate = t
+		weekday = int(t.Weekday())
+	}
+
 	routines, err := uc.Routines.ListByWeekday(ctx, userID, weekday)
 	if err != nil {
 		return nil, err
 	}
 
-	completions := make(map[string]bool)
+	progress := make(map[string]float64)
 	if date != "" {
 		comps, err := uc.Completions.GetByDate(ctx, userID, date)
 		if err == nil {
 			for _, c := range comps {
-				completions[c.RoutineID] = true
+				progress[c.RoutineID] = c.Value
 			}
 		}
 	}
 
-	targetDate := time.Now()
-	if date != "" {
-		if t, err := time.Parse("2006-01-02", date); err == nil {
-			targetDate = t
-		}
-	}
-
+	exceptions := uc.exceptionsOn(ctx, userID, date)
+	targetStr := targetDate.Format("2006-01-02")
+	away := uc.awayPeriods(ctx, userID, targetStr, targetStr)
+	cal := userHolidayCalendar(ctx, uc.Users, userID)
 	filtered := make([]domain.Routine, 0, len(routines))
 	for _, r := range routines {
-		if shouldShowRoutineForDate(r, targetDate) {
-			r.IsCompletedToday = completions[r.ID]
-			filtered = append(filtered, r)
+		routineExceptions := withHolidays(r, exceptions[r.ID], cal, targetDate, targetDate)
+		routine, ok := routineOn(r, targetDate, withAway(r, routineExceptions, away, targetDate, targetDate))
+		if ok {
+			routine.IsCompletedToday = routineTargetReached(r, progress[r.ID])
+			routine.ProgressToday = progress[r.ID]
+			filtered = append(filtered, routine)
 		}
 	}
 	return filtered, nil
 }
 
-func shouldShowRoutineForDate(r domain.Routine, targetDate time.Time) bool {
-	// Always respect StartsOn: never show routines on dates prior to their start.
-	startsOnStr := r.StartsOn
-	if len(startsOnStr) > 10 {
-		startsOnStr = startsOnStr[:10]
+// routineOn resolves the routine on date: skipped dates drop it and rescheduled ones carry
+// the new time window.
+func routineOn(r domain.Routine, date time.Time, exceptions []domain.RoutineException) (domain.Routine, bool) {
+	occurrence, ok := recurrence.ForRoutine(r, exceptions...).On(date)
+	if !ok {
+		return r, false
 	}
-	startsOn, err := time.Parse("2006-01-02", startsOnStr)
-	if err == nil {
-		startsOnDate := time.Date(startsOn.Year(), startsOn.Month(), startsOn.Day(), 0, 0, 0, 0, time.UTC)
-		targetDateOnly := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, time.UTC)
-		if targetDateOnly.Before(startsOnDate) {
-			return false
-		}
+	r.StartTime = occurrence.StartTime
+	r.EndTime = occurrence.EndTime
+	return r, true
+}
+
+func (uc *RoutineUsecase) exceptionsOn(ctx context.Context, userID, date string) map[string][]domain.RoutineException {
+	byRoutine := make(map[string][]domain.RoutineException)
+	if uc.Exceptions == nil || date == "" {
+		return byRoutine
 	}
+	exceptions, err := uc.Exceptions.ListByDate(ctx, userID, date)
+	if err != nil {
+		return byRoutine
+	}
+	for _, e := range exceptions {
+		byRoutine[e.RoutineID] = append(byRoutine[e.RoutineID], e)
+	}
+	return byRoutine
+}
 
-	if r.RecurrenceType == "weekly" || r.RecurrenceType == "" {
-		return true
+// awayPeriods loads the user's vacation periods overlapping from..to. Failures only leave the
+// routines unpaused.
+func (uc *RoutineUsecase) awayPeriods(ctx context.Context, userID, from, to string) []domain.AwayPeriod {
+	if uc.Away == nil {
+		return nil
+	}
+	periods, err := uc.Away.ListOverlapping(ctx, []string{userID}, from, to)
+	if err != nil {
+		return nil
 	}
+	return periods
+}
 
-	// For multi-week recurrences we anchor the cadence to the start week.
-	startsOnMonday := mondayOf(startsOn)
-	targetMonday := mondayOf(targetDate)
+// withAway appends the routine's away days between from and to after its stored exceptions.
+func withAway(r domain.Routine, exceptions []domain.RoutineException, away []domain.AwayPeriod, from, to time.Time) []domain.RoutineException {
+	if len(away) == 0 {
+		return exceptions
+	}
+	merged := make([]domain.RoutineException, 0, len(exceptions))
+	merged = append(merged, exceptions...)
+	return append(merged, recurrence.AwayExceptions(r, away, from, to)...)
+}
 
-	if targetMonday.Before(startsOnMonday) {
-		return false
+// withHolidays puts the holidays between from and to before the stored exceptions of a routine
+// that skips holidays, so a reschedule on a holiday still wins.
+func withHolidays(r domain.Routine, exceptions []domain.RoutineException, cal holiday.Calendar, from, to time.Time) []domain.RoutineException {
+	holidays := recurrence.HolidayExceptions(r, cal, from, to)
+	if len(holidays) == 0 {
+		return exceptions
 	}
+	return append(holidays, exceptions...)
+}
 
-	weeksDiff := int(targetMonday.Sub(startsOnMonday).Hours()) / (24 * 7)
+func shouldShowRoutineForDate(r domain.Routine, targetDate time.Time) bool {
+	return recurrence.ForRoutine(r).Matches(targetDate)
+}
 
-	switch r.RecurrenceType {
-	case "biweekly":
-		return weeksDiff%2 == 0
-	case "triweekly":
-		return weeksDiff%3 == 0
-	case "monthly_week":
-		if r.WeekOfMonth == nil {
-			return true
-		}
-		// Week of month: 1st, 2nd, 3rd, 4th, 5th
-		// Logic: (day-1)/7 + 1
-		targetWeek := (targetDate.Day()-1)/7 + 1
-		return targetWeek == *r.WeekOfMonth
-	default:
-		return true
+// parseRoutineRule reads the RRULE of an "rrule" routine.
+func parseRoutineRule(value *string) (recurrence.Rule, error) {
+	rrule := normalizeOptionalString(value)
+	if rrule == nil {
+		return recurrence.Rule{}, ErrInvalidRecurrence
+	}
+	rule, err := recurrence.Parse(*rrule)
+	if err != nil {
+		return recurrence.Rule{}, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
 	}
+	return rule, nil
 }
 
-func mondayOf(t time.Time) time.Time {
-	weekday := int(t.Weekday())
-	if weekday == 0 {
-		weekday = 7
+// parseRoutineDate reads a YYYY-MM-DD date, ignoring any time suffix.
+func parseRoutineDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) > 10 {
+		value = value[:10]
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, false
 	}
-	monday := t.AddDate(0, 0, -(weekday - 1))
-	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, time.UTC)
+	return parsed, true
 }
 
 func routineHasWeekday(weekdays []int, weekday int) bool {
@@ -481,12 +646,14 @@ func (uc *RoutineUsecase) Toggle(ctx context.Context, userID, id string, isActiv
 	return uc.Routines.Toggle(ctx, userID, id, isActive)
 }
 
-func (uc *RoutineUsecase) Complete(ctx context.Context, userID, routineID, date string) (domain.RoutineCompletion, error) {
+// Complete registra um check-in. Rotinas com meta somam value (padrão 1) ao progresso do dia
+// e contam como feitas quando a meta é atingida; rotinas binárias ignoram value.
+func (uc *RoutineUsecase) Complete(ctx context.Context, userID, routineID, date string, value *float64) (domain.RoutineCompletion, error) {
 	if userID == "" || routineID == "" {
 		return domain.RoutineCompletion{}, ErrMissingRequiredFields
 	}
 
-	_, err := uc.Routines.Get(ctx, userID, routineID)
+	routine, err := uc.Routines.Get(ctx, userID, routineID)
 	if err != nil {
 		return domain.RoutineCompletion{}, err
 	}
@@ -498,9 +665,20 @@ func (uc *RoutineUsecase) Complete(ctx context.Context, userID, routineID, date
 	completion := domain.RoutineCompletion{
 		RoutineID:   routineID,
 		CompletedOn: date,
+		Value:       1,
 	}
 
-	return uc.Completions.Create(ctx, userID, completion)
+	if routine.TargetValue == nil {
+		return uc.Completions.Create(ctx, userID, completion)
+	}
+
+	if value != nil {
+		if *value <= 0 {
+			return domain.RoutineCompletion{}, ErrInvalidPayload
+		}
+		completion.Value = *value
+	}
+	return uc.Completions.AddProgress(ctx, userID, completion)
 }
 
 func (uc *RoutineUsecase) Uncomplete(ctx context.Context, userID, routineID, date string) error {
@@ -522,14 +700,26 @@ func (uc *RoutineUsecase) GetCompletions(ctx context.Context, userID, routineID
 	return uc.Completions.GetByRoutine(ctx, userID, routineID)
 }
 
-func (uc *RoutineUsecase) GetStreak(ctx context.Context, userID, routineID string) (int, int, string, []domain.RoutineActivityDay, error) {
+// RoutineStreak is the streak summary of a routine. Missed days covered by a grace miss or a
+// freeze keep the chain and show up as IsFrozen in Activity.
+type RoutineStreak struct {
+	Current          int
+	Best             int
+	TotalCompletions int
+	Text             string
+	FreezesAvailable int
+	GraceLeft        int
+	Activity         []domain.RoutineActivityDay
+}
+
+func (uc *RoutineUsecase) GetStreak(ctx context.Context, userID, routineID string) (RoutineStreak, error) {
 	if userID == "" || routineID == "" {
-		return 0, 0, "", nil, ErrMissingRequiredFields
+		return RoutineStreak{}, ErrMissingRequiredFields
 	}
 
 	routine, err := uc.Routines.Get(ctx, userID, routineID)
 	if err != nil {
-		return 0, 0, "", nil, err
+		return RoutineStreak{}, err
 	}
 
 	completions, err := uc.Completions.GetByRoutine(ctx, userID, routineID)
@@ -542,41 +732,38 @@ func (uc *RoutineUsecase) GetStreak(ctx context.Context, userID, routineID strin
 		exceptions = []domain.RoutineException{}
 	}
 
+	progressMap := make(map[string]float64)
 	completionMap := make(map[string]bool)
+	totalCompletions := 0
 	for _, c := range completions {
-		completionMap[c.CompletedOn] = true
+		progressMap[c.CompletedOn] = c.Value
+		if routineTargetReached(routine, c.Value) {
+			completionMap[c.CompletedOn] = true
+			totalCompletions++
+		}
 	}
 
+	now := uc.nowInUserTimezone(ctx, userID)
+	todayStr := now.Format("2006-01-02")
+	lookback := now.AddDate(0, 0, -streakLookbackDays)
+	away := uc.awayPeriods(ctx, userID, lookback.Format("2006-01-02"), todayStr)
+	cal := userHolidayCalendar(ctx, uc.Users, userID)
+	exceptions = withAway(routine, withHolidays(routine, exceptions, cal, lookback, now), away, lookback, now)
+
 	exceptionMap := make(map[string]string)
 	for _, e := range exceptions {
-		exceptionMap[e.ExceptionDate] = e.Action
+		if len(e.ExceptionDate) >= 10 {
+			exceptionMap[e.ExceptionDate[:10]] = e.Action
+		}
 	}
 
-	now := uc.nowInUserTimezone(ctx, userID)
-	todayStr := now.Format("2006-01-02")
-	
-	currentStreak := 0
-	checkDate := now
-	if !completionMap[todayStr] && exceptionMap[todayStr] != "skip" {
-		checkDate = now.AddDate(0, 0, -1)
-	}
-
-	for i := 0; i < 730; i++ {
-		dateStr := checkDate.Format("2006-01-02")
-		if dateStr < routine.StartsOn {
-			break
-		}
-		if uc.isScheduledOn(routine, checkDate) {
-			if completionMap[dateStr] {
-				currentStreak++
-			//} else if exceptionMap[dateStr] == "skip" { -> não pula, quebra o streak
-			} else {
-				break
-			}
-		}
-		checkDate = checkDate.AddDate(0, 0, -1)
+	series := recurrence.ForRoutine(routine)
+	isScheduledOn := func(date time.Time) bool {
+		return routine.IsActive && series.Matches(date)
 	}
 
+	streak := computeRoutineStreak(routine, completionMap, exceptionMap, now)
+
 	startsOn, err := time.Parse("2006-01-02", routine.StartsOn)
 	if err != nil {
 		startsOn = now.AddDate(0, 0, -6) // Fallback de 7 dias
@@ -584,7 +771,7 @@ func (uc *RoutineUsecase) GetStreak(ctx context.Context, userID, routineID strin
 
 	sDay := time.Date(startsOn.Year(), startsOn.Month(), startsOn.Day(), 0, 0, 0, 0, now.Location())
 	nDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	
+
 	daysSinceStart := int(nDay.Sub(sDay).Hours() / 24)
 	if daysSinceStart < 6 {
 		daysSinceStart = 6
@@ -593,8 +780,8 @@ func (uc *RoutineUsecase) GetStreak(ctx context.Context, userID, routineID strin
 		daysSinceStart = 365 // Safety limit of 1 year
 	}
 
+	l := userLocalizer(ctx, uc.Users, userID)
 	activity := make([]domain.RoutineActivityDay, 0, daysSinceStart+1)
-	weekdayNames := []string{"D", "S", "T", "Q", "Q", "S", "S"}
 
 	for i := daysSinceStart; i >= 0; i-- {
 		d := now.AddDate(0, 0, -i)
@@ -602,88 +789,47 @@ func (uc *RoutineUsecase) GetStreak(ctx context.Context, userID, routineID strin
 		activity = append(activity, domain.RoutineActivityDay{
 			Date:         dStr,
 			IsCompleted:  completionMap[dStr],
-			IsScheduled:  uc.isScheduledOn(routine, d),
+			IsPartial:    !completionMap[dStr] && progressMap[dStr] > 0,
+			Progress:     progressMap[dStr],
+			IsScheduled:  isScheduledOn(d),
 			IsToday:      dStr == todayStr,
-			IsSkipped:    exceptionMap[dStr] == "skip",
-			WeekdayLabel: weekdayNames[int(d.Weekday())],
+			IsSkipped:    exceptionMap[dStr] == recurrence.ActionSkip,
+			IsRest:       exceptionMap[dStr] == recurrence.ActionRest,
+			IsFrozen:     streak.frozen[dStr],
+			IsAway:       exceptionMap[dStr] == recurrence.ActionAway,
+			IsHoliday:    exceptionMap[dStr] == recurrence.ActionHoliday,
+			WeekdayLabel: l.T("weekday_initial." + strconv.Itoa(int(d.Weekday()))),
 		})
 	}
 
-	totalCompletions := len(completions)
-	unit := "semana"
+	unitKey := "routine.streak.weeks"
 	if routine.RecurrenceType == "weekly" && len(routine.Weekdays) >= 3 {
-		unit = "dia"
-	}
-	
-	streakText := ""
-	if currentStreak == 1 {
-		if unit == "dia" {
-			streakText = "1 dia consecutivo"
-		} else {
-			streakText = "1 semana consecutiva"
-		}
-	} else if currentStreak > 1 {
-		if unit == "dia" {
-			streakText = fmt.Sprintf("%d dias consecutivos", currentStreak)
-		} else {
-			streakText = fmt.Sprintf("%d semanas consecutivas", currentStreak)
-		}
-	} else {
-		streakText = "Inicie sua sequência!"
+		unitKey = "routine.streak.days"
 	}
 
-	return currentStreak, totalCompletions, streakText, activity, nil
-}
-
-func (uc *RoutineUsecase) isScheduledOn(r domain.Routine, date time.Time) bool {
-	if !r.IsActive {
-		return false
+	streakText := l.T("routine.streak.start")
+	if streak.current > 0 {
+		streakText = l.N(unitKey, streak.current)
 	}
 
-	weekday := int(date.Weekday())
-	found := false
-	for _, wd := range r.Weekdays {
-		if wd == weekday {
-			found = true
-			break
-		}
-	}
-	if !found {
-		return false
-	}
+	return RoutineStreak{
+		Current:          streak.current,
+		Best:             streak.best,
+		TotalCompletions: totalCompletions,
+		Text:             streakText,
+		FreezesAvailable: streak.freezes,
+		GraceLeft:        streak.graceLeft,
+		Activity:         activity,
+	}, nil
+}
 
-	startsOnStr := r.StartsOn
-	if len(startsOnStr) > 10 {
-		startsOnStr = startsOnStr[:10]
-	}
-	startsOn, err := time.Parse("2006-01-02", startsOnStr)
+// CurrentStreak returns only the current streak, for the weekly digest.
+func (uc *RoutineUsecase) CurrentStreak(ctx context.Context, userID, routineID string) (int, error) {
+	streak, err := uc.GetStreak(ctx, userID, routineID)
 	if err != nil {
-		return true
-	}
-
-	startsOnMonday := mondayOf(startsOn)
-	targetMonday := mondayOf(date)
-
-	if targetMonday.Before(startsOnMonday) {
-		return false
-	}
-
-	weeksDiff := int(targetMonday.Sub(startsOnMonday).Hours()) / (24 * 7)
-
-	switch r.RecurrenceType {
-	case "biweekly":
-		return weeksDiff%2 == 0
-	case "triweekly":
-		return weeksDiff%3 == 0
-	case "monthly_week":
-		if r.WeekOfMonth == nil {
-			return true
-		}
-		targetWeek := (date.Day()-1)/7 + 1
-		return targetWeek == *r.WeekOfMonth
-	default:
-		return true
+		return 0, err
 	}
+	return streak.Current, nil
 }
 
 func (uc *RoutineUsecase) CreateException(ctx context.Context, userID, routineID, date, action string, newStartTime, newEndTime, reason *string) (domain.RoutineException, error) {
@@ -697,7 +843,13 @@ func (uc *RoutineUsecase) CreateException(ctx context.Context, userID, routineID
 	}
 
 	if action == "" {
-		action = "skip"
+		action = recurrence.ActionSkip
+	}
+	if action != recurrence.ActionSkip && action != recurrence.ActionRest && action != recurrence.ActionReschedule {
+		return domain.RoutineException{}, ErrInvalidPayload
+	}
+	if action == recurrence.ActionReschedule && normalizeOptionalString(newStartTime) == nil && normalizeOptionalString(newEndTime) == nil {
+		return domain.RoutineException{}, ErrMissingRequiredFields
 	}
 
 	exception := domain.RoutineException{
@@ -709,7 +861,28 @@ func (uc *RoutineUsecase) CreateException(ctx context.Context, userID, routineID
 		Reason:        reason,
 	}
 
-	return uc.Exceptions.Create(ctx, userID, exception)
+	created, err := uc.Exceptions.Create(ctx, userID, exception)
+	if err != nil {
+		return domain.RoutineException{}, err
+	}
+
+	uc.cancelQueuedNotifications(ctx, userID, routineID, date)
+	return created, nil
+}
+
+// cancelQueuedNotifications drops the routine pushes already queued for date; the scheduler
+// queues them again at the rescheduled time, if any. Failures only leave a stale push behind.
+func (uc *RoutineUsecase) cancelQueuedNotifications(ctx context.Context, userID, routineID, date string) {
+	if uc.NotificationLogs == nil {
+		return
+	}
+	day, ok := parseRoutineDate(date)
+	if !ok {
+		return
+	}
+	loc := uc.nowInUserTimezone(ctx, userID).Location()
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	_, _ = uc.NotificationLogs.CancelPending(ctx, routineID, from, from.AddDate(0, 0, 1))
 }
 
 func (uc *RoutineUsecase) DeleteException(ctx context.Context, userID, routineID, date string) error {
@@ -752,8 +925,25 @@ func (uc *RoutineUsecase) GetExceptions(ctx context.Context, userID, routineID s
 }
 
 func (uc *RoutineUsecase) GetTodaySummary(ctx context.Context, userID string) (int, int, error) {
+	progress, err := uc.GetTodayProgress(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return progress.Total, progress.Completed, nil
+}
+
+// RoutineDayProgress resume as rotinas do dia. Progress soma a fração feita de cada rotina,
+// então dias parciais de rotinas com meta contam proporcionalmente.
+type RoutineDayProgress struct {
+	Total     int
+	Completed int
+	Partial   int
+	Progress  float64
+}
+
+func (uc *RoutineUsecase) GetTodayProgress(ctx context.Context, userID string) (RoutineDayProgress, error) {
 	if userID == "" {
-		return 0, 0, ErrMissingRequiredFields
+		return RoutineDayProgress{}, ErrMissingRequiredFields
 	}
 
 	now := uc.nowInUserTimezone(ctx, userID)
@@ -761,25 +951,37 @@ func (uc *RoutineUsecase) GetTodaySummary(ctx context.Context, userID string) (i
 	date := now.Format("2006-01-02")
 	routines, err := uc.Routines.ListDailyStatus(ctx, userID, weekday, date)
 	if err != nil {
-		return 0, 0, err
+		return RoutineDayProgress{}, err
 	}
 
-	total := 0
-	completed := 0
+	away := uc.awayPeriods(ctx, userID, date, date)
+	_, isHoliday := holiday.On(userHolidayCalendar(ctx, uc.Users, userID), now)
+	var summary RoutineDayProgress
 	for _, r := range routines {
 		if !shouldShowRoutineForDate(r.Routine, now) {
 			continue
 		}
-		if r.ExceptionAction != nil && *r.ExceptionAction == "skip" {
+		if r.ExceptionAction != nil && (*r.ExceptionAction == recurrence.ActionSkip || *r.ExceptionAction == recurrence.ActionRest) {
+			continue
+		}
+		if isHoliday && r.SkipHolidays && (r.ExceptionAction == nil || *r.ExceptionAction != recurrence.ActionReschedule) {
+			continue
+		}
+		if recurrence.IsAway(away, r.FlagID, date) {
 			continue
 		}
-		total++
-		if r.IsCompleted {
-			completed++
+		summary.Total++
+		switch {
+		case r.IsCompleted:
+			summary.Completed++
+			summary.Progress++
+		case r.Progress > 0:
+			summary.Partial++
+			summary.Progress += routineDayFraction(r.Routine, r.Progress)
 		}
 	}
 
-	return total, completed, nil
+	return summary, nil
 }
 
 func (uc *RoutineUsecase) ResolveFlagAndSubflag(ctx context.Context, userID string, flagID *string, subflagID *string) (*string, *string, error) {
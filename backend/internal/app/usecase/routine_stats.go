@@ -0,0 +1,407 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/recurrence"
+	"inbota/backend/internal/app/repository"
+)
+
+const (
+	routineStatsWindowDays = 365
+	routineStatsWeeks      = 12
+	routineStatsMonths     = 12
+)
+
+// Faixas de horário usadas em BestTimeSlot, pela hora local em que a rotina foi concluída.
+const (
+	TimeSlotNight     = "night"     // 00h-05h
+	TimeSlotMorning   = "morning"   // 05h-12h
+	TimeSlotAfternoon = "afternoon" // 12h-18h
+	TimeSlotEvening   = "evening"   // 18h-24h
+)
+
+// RoutineStats reports how a routine (or all of them) followed its real schedule: only days
+// produced by the recurrence count, skip exceptions are reported apart and today only counts
+// once it is done.
+type RoutineStats struct {
+	From           string
+	To             string
+	Scheduled      int
+	Completed      int
+	Skipped        int
+	CompletionRate float64
+	BestStreak     int
+	BestWeekday    *int
+	BestTimeSlot   *string
+	Weekly         []RoutinePeriodStats
+	Monthly        []RoutinePeriodStats
+	WeekTrend      RoutineTrend
+	MonthTrend     RoutineTrend
+	Heatmap        []RoutineHeatmapDay
+	Routines       []RoutineStatsSummary
+}
+
+type RoutinePeriodStats struct {
+	Start          string
+	End            string
+	Scheduled      int
+	Completed      int
+	CompletionRate float64
+}
+
+// RoutineTrend compares the completion rate of the last 7 (or 30) days with the period before.
+type RoutineTrend struct {
+	Current  float64
+	Previous float64
+	Delta    float64
+}
+
+type RoutineHeatmapDay struct {
+	Date      string
+	Scheduled int
+	Completed int
+	Skipped   int
+}
+
+type RoutineStatsSummary struct {
+	RoutineID      string
+	Title          string
+	Scheduled      int
+	Completed      int
+	CompletionRate float64
+}
+
+func (uc *RoutineUsecase) GetStats(ctx context.Context, userID, routineID string) (RoutineStats, error) {
+	if userID == "" || routineID == "" {
+		return RoutineStats{}, ErrMissingRequiredFields
+	}
+
+	routine, err := uc.Routines.Get(ctx, userID, routineID)
+	if err != nil {
+		return RoutineStats{}, err
+	}
+	completions, err := uc.Completions.GetByRoutine(ctx, userID, routineID)
+	if err != nil {
+		return RoutineStats{}, err
+	}
+	exceptions, err := uc.Exceptions.GetByRoutine(ctx, userID, routineID)
+	if err != nil {
+		return RoutineStats{}, err
+	}
+
+	now := uc.nowInUserTimezone(ctx, userID)
+	stats := newRoutineStatsBuilder(now, []domain.Routine{routine})
+	away := uc.awayPeriods(ctx, userID, stats.from.Format("2006-01-02"), stats.today.Format("2006-01-02"))
+	cal := userHolidayCalendar(ctx, uc.Users, userID)
+	exceptions = withHolidays(routine, exceptions, cal, stats.from, stats.today)
+	stats.add(routine, completions, withAway(routine, exceptions, away, stats.from, stats.today))
+	return stats.build(true), nil
+}
+
+// GetAllStats aggregates the active routines of the user, with a per-routine breakdown.
+func (uc *RoutineUsecase) GetAllStats(ctx context.Context, userID string) (RoutineStats, error) {
+	if userID == "" {
+		return RoutineStats{}, ErrMissingRequiredFields
+	}
+
+	routines := make([]domain.Routine, 0)
+	opts := repository.ListOptions{Limit: 200}
+	for {
+		page, next, err := uc.Routines.List(ctx, userID, opts)
+		if err != nil {
+			return RoutineStats{}, err
+		}
+		routines = append(routines, page...)
+		if next == nil {
+			break
+		}
+		opts.Cursor = *next
+	}
+
+	now := uc.nowInUserTimezone(ctx, userID)
+	stats := newRoutineStatsBuilder(now, nil)
+	from, to := stats.from.Format("2006-01-02"), stats.today.Format("2006-01-02")
+
+	completions, err := uc.Completions.ListBetween(ctx, userID, from, to)
+	if err != nil {
+		return RoutineStats{}, err
+	}
+	completionsByRoutine := make(map[string][]domain.RoutineCompletion)
+	for _, c := range completions {
+		completionsByRoutine[c.RoutineID] = append(completionsByRoutine[c.RoutineID], c)
+	}
+
+	exceptionsByRoutine := make(map[string][]domain.RoutineException)
+	if len(routines) > 0 {
+		ids := make([]string, 0, len(routines))
+		for _, r := range routines {
+			ids = append(ids, r.ID)
+		}
+		exceptions, err := uc.Exceptions.ListForRoutines(ctx, ids, from, to)
+		if err != nil {
+			return RoutineStats{}, err
+		}
+		for _, e := range exceptions {
+			exceptionsByRoutine[e.RoutineID] = append(exceptionsByRoutine[e.RoutineID], e)
+		}
+	}
+
+	away := uc.awayPeriods(ctx, userID, from, to)
+	cal := userHolidayCalendar(ctx, uc.Users, userID)
+	for _, r := range routines {
+		routineExceptions := withHolidays(r, exceptionsByRoutine[r.ID], cal, stats.from, stats.today)
+		stats.add(r, completionsByRoutine[r.ID], withAway(r, routineExceptions, away, stats.from, stats.today))
+	}
+	return stats.build(false), nil
+}
+
+type routineStatsDay struct {
+	scheduled int
+	completed int
+	skipped   int
+	pending   int
+}
+
+type routineStatsBuilder struct {
+	loc      *time.Location
+	from     time.Time
+	today    time.Time
+	days     []routineStatsDay
+	weekdays [7]routineStatsDay
+	slots    map[string]int
+	routines []RoutineStatsSummary
+}
+
+// newRoutineStatsBuilder covers the last year up to today (in the user's timezone). When
+// routines are given, the window starts at the earliest StartsOn among them.
+func newRoutineStatsBuilder(now time.Time, routines []domain.Routine) *routineStatsBuilder {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, -(routineStatsWindowDays - 1))
+	if len(routines) > 0 {
+		earliest := today
+		for _, r := range routines {
+			if startsOn, ok := parseRoutineDate(r.StartsOn); ok && startsOn.Before(earliest) {
+				earliest = startsOn
+			}
+		}
+		if earliest.After(from) {
+			from = earliest
+		}
+	}
+
+	return &routineStatsBuilder{
+		loc:   now.Location(),
+		from:  from,
+		today: today,
+		days:  make([]routineStatsDay, int(today.Sub(from).Hours()/24)+1),
+		slots: make(map[string]int),
+	}
+}
+
+func (b *routineStatsBuilder) add(routine domain.Routine, completions []domain.RoutineCompletion, exceptions []domain.RoutineException) {
+	done := make(map[string]domain.RoutineCompletion, len(completions))
+	for _, c := range completions {
+		if routineTargetReached(routine, c.Value) {
+			done[c.CompletedOn] = c
+		}
+	}
+
+	summary := RoutineStatsSummary{RoutineID: routine.ID, Title: routine.Title}
+	series := recurrence.ForRoutine(routine, exceptions...)
+	for i := range b.days {
+		date := b.from.AddDate(0, 0, i)
+		if !series.Matches(date) {
+			continue
+		}
+		day := &b.days[i]
+		if _, ok := series.On(date); !ok {
+			day.skipped++
+			continue
+		}
+
+		completion, completed := done[date.Format("2006-01-02")]
+		if !completed && date.Equal(b.today) {
+			day.pending++
+			continue
+		}
+
+		weekday := &b.weekdays[date.Weekday()]
+		day.scheduled++
+		weekday.scheduled++
+		summary.Scheduled++
+		if completed {
+			day.completed++
+			weekday.completed++
+			summary.Completed++
+			b.slots[timeSlotOf(completion.CompletedAt.In(b.loc))]++
+		}
+	}
+
+	summary.CompletionRate = completionRate(summary.Completed, summary.Scheduled)
+	b.routines = append(b.routines, summary)
+}
+
+func (b *routineStatsBuilder) build(single bool) RoutineStats {
+	stats := RoutineStats{
+		From:       b.from.Format("2006-01-02"),
+		To:         b.today.Format("2006-01-02"),
+		Heatmap:    make([]RoutineHeatmapDay, 0, len(b.days)),
+		WeekTrend:  b.trend(7),
+		MonthTrend: b.trend(30),
+	}
+
+	streak := 0
+	for i, day := range b.days {
+		stats.Scheduled += day.scheduled
+		stats.Completed += day.completed
+		stats.Skipped += day.skipped
+		stats.Heatmap = append(stats.Heatmap, RoutineHeatmapDay{
+			Date:      b.from.AddDate(0, 0, i).Format("2006-01-02"),
+			Scheduled: day.scheduled + day.pending,
+			Completed: day.completed,
+			Skipped:   day.skipped,
+		})
+
+		// Dias sem agenda ou pulados não quebram a sequência.
+		if day.scheduled == 0 {
+			continue
+		}
+		if day.completed == day.scheduled {
+			streak++
+			if streak > stats.BestStreak {
+				stats.BestStreak = streak
+			}
+		} else {
+			streak = 0
+		}
+	}
+	stats.CompletionRate = completionRate(stats.Completed, stats.Scheduled)
+	stats.BestWeekday = b.bestWeekday()
+	stats.BestTimeSlot = b.bestTimeSlot()
+	stats.Weekly = b.weeklyPeriods()
+	stats.Monthly = b.monthlyPeriods()
+
+	if !single {
+		stats.BestStreak = 0
+		stats.Routines = b.routines
+	}
+	return stats
+}
+
+// period sums the days between from and to (inclusive), clipped to the window.
+func (b *routineStatsBuilder) period(from, to time.Time) RoutinePeriodStats {
+	period := RoutinePeriodStats{Start: from.Format("2006-01-02"), End: to.Format("2006-01-02")}
+	if from.Before(b.from) {
+		from = b.from
+	}
+	if to.After(b.today) {
+		to = b.today
+	}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := b.days[int(date.Sub(b.from).Hours()/24)]
+		period.Scheduled += day.scheduled
+		period.Completed += day.completed
+	}
+	period.CompletionRate = completionRate(period.Completed, period.Scheduled)
+	return period
+}
+
+func (b *routineStatsBuilder) weeklyPeriods() []RoutinePeriodStats {
+	weekday := int(b.today.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	monday := b.today.AddDate(0, 0, -(weekday - 1))
+
+	periods := make([]RoutinePeriodStats, 0, routineStatsWeeks)
+	for i := routineStatsWeeks - 1; i >= 0; i-- {
+		start := monday.AddDate(0, 0, -7*i)
+		end := start.AddDate(0, 0, 6)
+		if end.Before(b.from) {
+			continue
+		}
+		periods = append(periods, b.period(start, end))
+	}
+	return periods
+}
+
+func (b *routineStatsBuilder) monthlyPeriods() []RoutinePeriodStats {
+	first := time.Date(b.today.Year(), b.today.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	periods := make([]RoutinePeriodStats, 0, routineStatsMonths)
+	for i := routineStatsMonths - 1; i >= 0; i-- {
+		start := first.AddDate(0, -i, 0)
+		end := start.AddDate(0, 1, -1)
+		if end.Before(b.from) {
+			continue
+		}
+		periods = append(periods, b.period(start, end))
+	}
+	return periods
+}
+
+func (b *routineStatsBuilder) trend(days int) RoutineTrend {
+	current := b.period(b.today.AddDate(0, 0, -(days-1)), b.today)
+	previous := b.period(b.today.AddDate(0, 0, -(2*days-1)), b.today.AddDate(0, 0, -days))
+	return RoutineTrend{
+		Current:  current.CompletionRate,
+		Previous: previous.CompletionRate,
+		Delta:    current.CompletionRate - previous.CompletionRate,
+	}
+}
+
+// bestWeekday is the weekday with the highest completion rate; ties go to the one with more
+// completions.
+func (b *routineStatsBuilder) bestWeekday() *int {
+	best := -1
+	bestRate := 0.0
+	for weekday, day := range b.weekdays {
+		if day.scheduled == 0 {
+			continue
+		}
+		rate := completionRate(day.completed, day.scheduled)
+		if best < 0 || rate > bestRate || (rate == bestRate && day.completed > b.weekdays[best].completed) {
+			best, bestRate = weekday, rate
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	return &best
+}
+
+func (b *routineStatsBuilder) bestTimeSlot() *string {
+	var best string
+	for _, slot := range []string{TimeSlotMorning, TimeSlotAfternoon, TimeSlotEvening, TimeSlotNight} {
+		if b.slots[slot] > 0 && (best == "" || b.slots[slot] > b.slots[best]) {
+			best = slot
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	return &best
+}
+
+func timeSlotOf(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour < 5:
+		return TimeSlotNight
+	case hour < 12:
+		return TimeSlotMorning
+	case hour < 18:
+		return TimeSlotAfternoon
+	default:
+		return TimeSlotEvening
+	}
+}
+
+func completionRate(completed, scheduled int) float64 {
+	if scheduled == 0 {
+		return 0
+	}
+	return float64(completed) / float64(scheduled)
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/recurrence"
+)
+
+const (
+	// maxStreakFreezes limita quantos congelamentos ficam guardados ao mesmo tempo.
+	maxStreakFreezes = 3
+	// streakLookbackDays limita a caminhada pela agenda, como o antigo limite de 2 anos.
+	streakLookbackDays = 730
+
+	maxGracePerMonth = 10
+	maxFreezeEvery   = 365
+)
+
+type routineStreakResult struct {
+	current   int
+	best      int
+	freezes   int
+	graceLeft int
+	frozen    map[string]bool
+}
+
+// computeRoutineStreak walks the routine schedule up to today. Completed days extend the chain,
+// rest and away days and unscheduled days are neutral and today only counts once done. A missed
+// day (skip included) first uses a grace miss of its month, then a freeze token; only when
+// both are gone does the chain reset. Tokens are earned every FreezeEvery completed days in a
+// row.
+func computeRoutineStreak(routine domain.Routine, done map[string]bool, exceptions map[string]string, now time.Time) routineStreakResult {
+	result := routineStreakResult{frozen: make(map[string]bool)}
+	protection := routine.StreakProtection
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, -streakLookbackDays)
+	if startsOn, ok := parseRoutineDate(routine.StartsOn); ok && startsOn.After(start) {
+		start = startsOn
+	}
+
+	graceUsed := make(map[string]int)
+	run := 0
+	series := recurrence.ForRoutine(routine)
+	for date := start; !date.After(today) && routine.IsActive; date = date.AddDate(0, 0, 1) {
+		if !series.Matches(date) {
+			continue
+		}
+		key := date.Format("2006-01-02")
+		switch {
+		case done[key]:
+			result.current++
+			run++
+			if result.current > result.best {
+				result.best = result.current
+			}
+			if protection.FreezeEvery > 0 && run%protection.FreezeEvery == 0 && result.freezes < maxStreakFreezes {
+				result.freezes++
+			}
+		case exceptions[key] == recurrence.ActionRest, exceptions[key] == recurrence.ActionAway, exceptions[key] == recurrence.ActionHoliday:
+		case date.Equal(today):
+		default:
+			run = 0
+			month := key[:7]
+			switch {
+			case graceUsed[month] < protection.GracePerMonth:
+				graceUsed[month]++
+				result.frozen[key] = true
+			case result.freezes > 0:
+				result.freezes--
+				result.frozen[key] = true
+			default:
+				result.current = 0
+			}
+		}
+	}
+
+	result.graceLeft = protection.GracePerMonth - graceUsed[today.Format("2006-01")]
+	if result.graceLeft < 0 {
+		result.graceLeft = 0
+	}
+	return result
+}
+
+func validateStreakProtection(protection domain.RoutineStreakProtection) error {
+	if protection.GracePerMonth < 0 || protection.GracePerMonth > maxGracePerMonth {
+		return ErrInvalidPayload
+	}
+	if protection.FreezeEvery < 0 || protection.FreezeEvery > maxFreezeEvery {
+		return ErrInvalidPayload
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"strconv"
+
+	"inbota/backend/internal/app/domain"
+)
+
+const defaultDurationUnit = "min"
+
+// normalizeRoutineTarget validates a quantitative target. A blank kind with no value means a
+// binary routine; a value without kind is a count. Amounts need a unit (ml, km, páginas...).
+func normalizeRoutineTarget(kind *string, value *float64, unit *string) (*string, *float64, *string, error) {
+	normalizedKind := normalizeOptionalString(kind)
+	if normalizedKind == nil && value == nil {
+		return nil, nil, nil, nil
+	}
+	if normalizedKind == nil {
+		count := domain.RoutineTargetCount
+		normalizedKind = &count
+	}
+
+	switch *normalizedKind {
+	case domain.RoutineTargetCount, domain.RoutineTargetDuration, domain.RoutineTargetAmount:
+	default:
+		return nil, nil, nil, ErrInvalidPayload
+	}
+
+	if value == nil {
+		return nil, nil, nil, ErrMissingRequiredFields
+	}
+	if *value <= 0 {
+		return nil, nil, nil, ErrInvalidPayload
+	}
+	target := *value
+
+	normalizedUnit := normalizeOptionalString(unit)
+	switch *normalizedKind {
+	case domain.RoutineTargetDuration:
+		if normalizedUnit == nil {
+			minutes := defaultDurationUnit
+			normalizedUnit = &minutes
+		}
+	case domain.RoutineTargetAmount:
+		if normalizedUnit == nil {
+			return nil, nil, nil, ErrMissingRequiredFields
+		}
+	}
+
+	return normalizedKind, &target, normalizedUnit, nil
+}
+
+// routineTargetReached reports whether the progress of a day completes the routine. Binary
+// routines only need a check-in.
+func routineTargetReached(r domain.Routine, value float64) bool {
+	if r.TargetValue == nil {
+		return value > 0
+	}
+	return value >= *r.TargetValue
+}
+
+// routineDayFraction is the share of the target done on a day, between 0 and 1.
+func routineDayFraction(r domain.Routine, value float64) float64 {
+	if routineTargetReached(r, value) {
+		return 1
+	}
+	if r.TargetValue == nil || value <= 0 {
+		return 0
+	}
+	return value / *r.TargetValue
+}
+
+func formatTargetValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -1,10 +1,13 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
 )
 
 func TestShouldShowRoutineForDate_RespectsStartsOnForWeekly(t *testing.T) {
@@ -49,3 +52,265 @@ func TestComputeStartsOn_SanitizesWhenStartsOnIsNotSelectedWeekday(t *testing.T)
 		t.Fatalf("expected startsOn to sanitize to next Tuesday 2026-03-17, got %s", startsOn)
 	}
 }
+
+type routineGetStub struct {
+	repository.RoutineRepository
+}
+
+func (routineGetStub) Get(_ context.Context, userID, id string) (domain.Routine, error) {
+	return domain.Routine{ID: id, UserID: userID}, nil
+}
+
+type exceptionCreateStub struct {
+	repository.RoutineExceptionRepository
+}
+
+func (exceptionCreateStub) Create(_ context.Context, _ string, e domain.RoutineException) (domain.RoutineException, error) {
+	e.ID = "exc-1"
+	return e, nil
+}
+
+type cancelPendingStub struct {
+	repository.NotificationLogRepository
+	referenceID string
+	from, to    time.Time
+}
+
+func (s *cancelPendingStub) CancelPending(_ context.Context, referenceID string, from, to time.Time) (int, error) {
+	s.referenceID, s.from, s.to = referenceID, from, to
+	return 1, nil
+}
+
+type timezoneUserStub struct {
+	repository.UserRepository
+}
+
+func (timezoneUserStub) Get(_ context.Context, id string) (domain.User, error) {
+	return domain.User{ID: id, Timezone: "America/Sao_Paulo"}, nil
+}
+
+func TestCreateException_CancelsQueuedNotificationsForThatDay(t *testing.T) {
+	logs := &cancelPendingStub{}
+	uc := &RoutineUsecase{
+		Routines:         routineGetStub{},
+		Exceptions:       exceptionCreateStub{},
+		Users:            timezoneUserStub{},
+		NotificationLogs: logs,
+	}
+
+	newStart := "10:00"
+	if _, err := uc.CreateException(context.Background(), "user-1", "routine-1", "2026-03-10", "reschedule", &newStart, nil, nil); err != nil {
+		t.Fatalf("CreateException: %v", err)
+	}
+
+	if logs.referenceID != "routine-1" {
+		t.Fatalf("cancelled reference = %q", logs.referenceID)
+	}
+	if got := logs.from.UTC().Format(time.RFC3339); got != "2026-03-10T03:00:00Z" {
+		t.Fatalf("window start = %s, want local midnight", got)
+	}
+	if logs.to.Sub(logs.from) != 24*time.Hour {
+		t.Fatalf("window = %s..%s", logs.from, logs.to)
+	}
+
+	if _, err := uc.CreateException(context.Background(), "user-1", "routine-1", "2026-03-10", "reschedule", nil, nil, nil); !errors.Is(err, ErrMissingRequiredFields) {
+		t.Fatalf("reschedule without times error = %v", err)
+	}
+	if _, err := uc.CreateException(context.Background(), "user-1", "routine-1", "2026-03-10", "postpone", nil, nil, nil); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("unknown action error = %v", err)
+	}
+}
+
+type targetRoutineStub struct {
+	repository.RoutineRepository
+	routine domain.Routine
+	daily   []repository.RoutineDailyStatus
+}
+
+func (s targetRoutineStub) Get(_ context.Context, _, _ string) (domain.Routine, error) {
+	return s.routine, nil
+}
+
+func (s targetRoutineStub) ListDailyStatus(_ context.Context, _ string, _ int, _ string) ([]repository.RoutineDailyStatus, error) {
+	return s.daily, nil
+}
+
+type progressCompletionStub struct {
+	repository.RoutineCompletionRepository
+	progress map[string]float64
+	created  int
+}
+
+func (s *progressCompletionStub) Create(_ context.Context, _ string, c domain.RoutineCompletion) (domain.RoutineCompletion, error) {
+	s.created++
+	return c, nil
+}
+
+func (s *progressCompletionStub) AddProgress(_ context.Context, _ string, c domain.RoutineCompletion) (domain.RoutineCompletion, error) {
+	s.progress[c.CompletedOn] += c.Value
+	c.Value = s.progress[c.CompletedOn]
+	return c, nil
+}
+
+func TestComplete_AccumulatesCheckInsTowardsTarget(t *testing.T) {
+	target := 8.0
+	completions := &progressCompletionStub{progress: map[string]float64{}}
+	uc := &RoutineUsecase{
+		Routines:    targetRoutineStub{routine: domain.Routine{ID: "routine-1", TargetValue: &target}},
+		Completions: completions,
+	}
+
+	three := 3.0
+	var last domain.RoutineCompletion
+	for _, value := range []*float64{&three, nil, &three} {
+		c, err := uc.Complete(context.Background(), "user-1", "routine-1", "2026-03-10", value)
+		if err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+		last = c
+	}
+	if last.Value != 7 {
+		t.Fatalf("accumulated value = %v, want 7", last.Value)
+	}
+	if routineTargetReached(uc.Routines.(targetRoutineStub).routine, last.Value) {
+		t.Fatal("7 of 8 should still be partial")
+	}
+
+	zero := 0.0
+	if _, err := uc.Complete(context.Background(), "user-1", "routine-1", "2026-03-10", &zero); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("zero check-in error = %v", err)
+	}
+
+	binary := &RoutineUsecase{Routines: targetRoutineStub{routine: domain.Routine{ID: "routine-2"}}, Completions: completions}
+	if _, err := binary.Complete(context.Background(), "user-1", "routine-2", "2026-03-10", &three); err != nil || completions.created != 1 {
+		t.Fatalf("binary routine should keep the single completion path: err=%v created=%d", err, completions.created)
+	}
+}
+
+func TestGetTodayProgress_CountsPartialDays(t *testing.T) {
+	target := 4.0
+	unit := "copos"
+	daily := []repository.RoutineDailyStatus{
+		{Routine: domain.Routine{ID: "a", RecurrenceType: "weekly", Weekdays: []int{0, 1, 2, 3, 4, 5, 6}}, IsCompleted: true, Progress: 1},
+		{Routine: domain.Routine{ID: "b", RecurrenceType: "weekly", Weekdays: []int{0, 1, 2, 3, 4, 5, 6}, TargetValue: &target, TargetUnit: &unit}, Progress: 1},
+		{Routine: domain.Routine{ID: "c", RecurrenceType: "weekly", Weekdays: []int{0, 1, 2, 3, 4, 5, 6}}},
+	}
+	uc := &RoutineUsecase{Routines: targetRoutineStub{daily: daily}, Users: timezoneUserStub{}}
+
+	got, err := uc.GetTodayProgress(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("GetTodayProgress: %v", err)
+	}
+	if got.Total != 3 || got.Completed != 1 || got.Partial != 1 || got.Progress != 1.25 {
+		t.Fatalf("progress = %+v", got)
+	}
+}
+
+func TestNormalizeRoutineTarget(t *testing.T) {
+	value := 30.0
+	duration := "duration"
+	kind, target, unit, err := normalizeRoutineTarget(&duration, &value, nil)
+	if err != nil || *kind != "duration" || *target != 30 || unit == nil || *unit != "min" {
+		t.Fatalf("duration target = %v %v %v %v", kind, target, unit, err)
+	}
+
+	if kind, _, _, err := normalizeRoutineTarget(nil, &value, nil); err != nil || *kind != "count" {
+		t.Fatalf("value without kind should default to count: %v %v", kind, err)
+	}
+	if kind, target, unit, err := normalizeRoutineTarget(nil, nil, nil); err != nil || kind != nil || target != nil || unit != nil {
+		t.Fatalf("no target should stay binary")
+	}
+
+	amount := "amount"
+	if _, _, _, err := normalizeRoutineTarget(&amount, &value, nil); !errors.Is(err, ErrMissingRequiredFields) {
+		t.Fatalf("amount without unit error = %v", err)
+	}
+	negative := -1.0
+	if _, _, _, err := normalizeRoutineTarget(&amount, &negative, nil); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("negative target error = %v", err)
+	}
+}
+
+func TestRoutineStats_FollowsTheRealSchedule(t *testing.T) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+	now := time.Date(2026, 3, 18, 20, 0, 0, 0, loc) // Wed
+	routine := domain.Routine{ID: "r1", RecurrenceType: "weekly", Weekdays: []int{1, 3}, StartsOn: "2026-03-02", IsActive: true}
+	morning := time.Date(2026, 3, 2, 7, 0, 0, 0, loc)
+	completions := []domain.RoutineCompletion{
+		{RoutineID: "r1", CompletedOn: "2026-03-02", Value: 1, CompletedAt: morning},
+		{RoutineID: "r1", CompletedOn: "2026-03-04", Value: 1, CompletedAt: morning.AddDate(0, 0, 2)},
+		{RoutineID: "r1", CompletedOn: "2026-03-09", Value: 1, CompletedAt: morning.AddDate(0, 0, 7)},
+		// Dia sem agenda: não entra na taxa.
+		{RoutineID: "r1", CompletedOn: "2026-03-10", Value: 1, CompletedAt: morning.AddDate(0, 0, 8)},
+	}
+	exceptions := []domain.RoutineException{{RoutineID: "r1", ExceptionDate: "2026-03-11", Action: "skip"}}
+
+	builder := newRoutineStatsBuilder(now, []domain.Routine{routine})
+	builder.add(routine, completions, exceptions)
+	stats := builder.build(true)
+
+	if stats.From != "2026-03-02" || stats.To != "2026-03-18" || len(stats.Heatmap) != 17 {
+		t.Fatalf("window = %s..%s (%d days)", stats.From, stats.To, len(stats.Heatmap))
+	}
+	// 02, 04, 09 feitos; 16 perdido; 11 pulado; 18 (hoje) ainda pendente.
+	if stats.Scheduled != 4 || stats.Completed != 3 || stats.Skipped != 1 || stats.CompletionRate != 0.75 {
+		t.Fatalf("totals = %+v", stats)
+	}
+	if stats.BestStreak != 3 {
+		t.Fatalf("best streak = %d, want 3 (skip does not break it)", stats.BestStreak)
+	}
+	if stats.BestWeekday == nil || *stats.BestWeekday != int(time.Wednesday) {
+		t.Fatalf("best weekday = %v", stats.BestWeekday)
+	}
+	if stats.BestTimeSlot == nil || *stats.BestTimeSlot != TimeSlotMorning {
+		t.Fatalf("best time slot = %v", stats.BestTimeSlot)
+	}
+	if today := stats.Heatmap[len(stats.Heatmap)-1]; today.Scheduled != 1 || today.Completed != 0 {
+		t.Fatalf("today heatmap = %+v", today)
+	}
+	if stats.WeekTrend.Current != 0 || stats.WeekTrend.Previous != 1 || stats.WeekTrend.Delta != -1 {
+		t.Fatalf("week trend = %+v", stats.WeekTrend)
+	}
+}
+
+func TestComputeRoutineStreak_GraceFreezeAndRest(t *testing.T) {
+	now := time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)
+	routine := domain.Routine{
+		ID:               "r1",
+		RecurrenceType:   "weekly",
+		Weekdays:         []int{0, 1, 2, 3, 4, 5, 6},
+		StartsOn:         "2026-03-01",
+		IsActive:         true,
+		StreakProtection: domain.RoutineStreakProtection{GracePerMonth: 1, FreezeEvery: 3},
+	}
+	done := map[string]bool{
+		"2026-03-01": true, "2026-03-02": true, "2026-03-03": true, // ganha 1 congelamento
+		"2026-03-05": true, "2026-03-06": true, // 04 usa a tolerância do mês
+		"2026-03-08": true, // 07 é descanso
+		"2026-03-10": true, // 09 consome o congelamento
+		"2026-03-11": true,
+	}
+	exceptions := map[string]string{"2026-03-07": "rest"}
+
+	result := computeRoutineStreak(routine, done, exceptions, now)
+	if result.current != 8 || result.best != 8 {
+		t.Fatalf("streak = %d/%d, want 8/8", result.current, result.best)
+	}
+	if !result.frozen["2026-03-04"] || !result.frozen["2026-03-09"] || result.frozen["2026-03-07"] {
+		t.Fatalf("frozen = %v", result.frozen)
+	}
+	// 05, 06 e 08 seguidos rendem outro congelamento.
+	if result.freezes != 1 || result.graceLeft != 0 {
+		t.Fatalf("freezes = %d grace = %d", result.freezes, result.graceLeft)
+	}
+
+	// Sem proteção, a mesma agenda quebra no primeiro dia perdido.
+	routine.StreakProtection = domain.RoutineStreakProtection{}
+	result = computeRoutineStreak(routine, done, exceptions, now)
+	if result.current != 2 || result.best != 3 {
+		t.Fatalf("unprotected streak = %d/%d, want 2/3", result.current, result.best)
+	}
+}
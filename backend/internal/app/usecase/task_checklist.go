@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+)
+
+// maxTaskChecklistItems limita quantos passos uma tarefa pode ter.
+const maxTaskChecklistItems = 100
+
+type TaskChecklistUsecase struct {
+	Items repository.TaskChecklistItemRepository
+	Tasks repository.TaskRepository
+}
+
+type TaskChecklistItemUpdateInput struct {
+	Title     *string
+	Checked   *bool
+	SortOrder *int
+}
+
+func (uc *TaskChecklistUsecase) Create(ctx context.Context, userID, taskID, title string, checked *bool, sortOrder *int) (domain.TaskChecklistItem, error) {
+	title = normalizeString(title)
+	if userID == "" || taskID == "" || title == "" {
+		return domain.TaskChecklistItem{}, ErrMissingRequiredFields
+	}
+	if uc.Tasks == nil {
+		return domain.TaskChecklistItem{}, ErrDependencyMissing
+	}
+	if _, err := uc.Tasks.Get(ctx, userID, taskID); err != nil {
+		return domain.TaskChecklistItem{}, err
+	}
+
+	existing, err := uc.Items.ListByTask(ctx, userID, taskID)
+	if err != nil {
+		return domain.TaskChecklistItem{}, err
+	}
+	if len(existing) >= maxTaskChecklistItems {
+		return domain.TaskChecklistItem{}, ErrInvalidPayload
+	}
+
+	// Sem sort_order explícito o passo vai para o fim da lista.
+	order := 0
+	for _, item := range existing {
+		if item.SortOrder >= order {
+			order = item.SortOrder + 1
+		}
+	}
+	if sortOrder != nil {
+		if *sortOrder < 0 {
+			return domain.TaskChecklistItem{}, ErrInvalidPayload
+		}
+		order = *sortOrder
+	}
+	isChecked := false
+	if checked != nil {
+		isChecked = *checked
+	}
+
+	item := domain.TaskChecklistItem{
+		UserID:    userID,
+		TaskID:    taskID,
+		Title:     title,
+		Checked:   isChecked,
+		SortOrder: order,
+	}
+
+	return uc.Items.Create(ctx, item)
+}
+
+func (uc *TaskChecklistUsecase) Update(ctx context.Context, userID, id string, input TaskChecklistItemUpdateInput) (domain.TaskChecklistItem, error) {
+	if userID == "" || id == "" {
+		return domain.TaskChecklistItem{}, ErrMissingRequiredFields
+	}
+	item, err := uc.Items.Get(ctx, userID, id)
+	if err != nil {
+		return domain.TaskChecklistItem{}, err
+	}
+
+	if input.Title != nil {
+		trimmed := normalizeString(*input.Title)
+		if trimmed == "" {
+			return domain.TaskChecklistItem{}, ErrMissingRequiredFields
+		}
+		item.Title = trimmed
+	}
+	if input.Checked != nil {
+		item.Checked = *input.Checked
+	}
+	if input.SortOrder != nil {
+		if *input.SortOrder < 0 {
+			return domain.TaskChecklistItem{}, ErrInvalidPayload
+		}
+		item.SortOrder = *input.SortOrder
+	}
+
+	return uc.Items.Update(ctx, item)
+}
+
+func (uc *TaskChecklistUsecase) Delete(ctx context.Context, userID, id string) error {
+	if userID == "" || id == "" {
+		return ErrMissingRequiredFields
+	}
+	return uc.Items.Delete(ctx, userID, id)
+}
+
+func (uc *TaskChecklistUsecase) ListByTask(ctx context.Context, userID, taskID string) ([]domain.TaskChecklistItem, error) {
+	if userID == "" || taskID == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	if uc.Tasks == nil {
+		return nil, ErrDependencyMissing
+	}
+	if _, err := uc.Tasks.Get(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+	return uc.Items.ListByTask(ctx, userID, taskID)
+}
+
+// ListByTasks groups the checklist items of the given tasks by task ID.
+func (uc *TaskChecklistUsecase) ListByTasks(ctx context.Context, userID string, taskIDs []string) (map[string][]domain.TaskChecklistItem, error) {
+	if userID == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	items, err := uc.Items.ListByTasks(ctx, userID, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	byTask := make(map[string][]domain.TaskChecklistItem)
+	for _, item := range items {
+		byTask[item.TaskID] = append(byTask[item.TaskID], item)
+	}
+	return byTask, nil
+}
+
+// newTaskChecklistItems builds the checklist of a task from the step titles suggested by the AI,
+// dropping blanks and keeping the original order.
+func newTaskChecklistItems(userID, taskID string, titles []string) []domain.TaskChecklistItem {
+	items := make([]domain.TaskChecklistItem, 0, len(titles))
+	for _, title := range titles {
+		title = normalizeString(title)
+		if title == "" {
+			continue
+		}
+		if len(items) >= maxTaskChecklistItems {
+			break
+		}
+		items = append(items, domain.TaskChecklistItem{
+			UserID:    userID,
+			TaskID:    taskID,
+			Title:     title,
+			SortOrder: len(items),
+		})
+	}
+	return items
+}
+
+// createTaskChecklist persists the checklist steps of a freshly created task.
+func createTaskChecklist(ctx context.Context, repo repository.TaskChecklistItemRepository, userID, taskID string, titles []string) ([]domain.TaskChecklistItem, error) {
+	items := newTaskChecklistItems(userID, taskID, titles)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	if repo == nil {
+		return nil, ErrDependencyMissing
+	}
+	created := make([]domain.TaskChecklistItem, 0, len(items))
+	for _, item := range items {
+		saved, err := repo.Create(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, saved)
+	}
+	return created, nil
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+)
+
+type checklistRepoStub struct {
+	repository.TaskChecklistItemRepository
+	items []domain.TaskChecklistItem
+}
+
+func (s *checklistRepoStub) Create(_ context.Context, item domain.TaskChecklistItem) (domain.TaskChecklistItem, error) {
+	s.items = append(s.items, item)
+	return item, nil
+}
+
+func (s *checklistRepoStub) ListByTask(_ context.Context, _, taskID string) ([]domain.TaskChecklistItem, error) {
+	out := make([]domain.TaskChecklistItem, 0)
+	for _, item := range s.items {
+		if item.TaskID == taskID {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+type checklistTaskRepoStub struct {
+	repository.TaskRepository
+}
+
+func (checklistTaskRepoStub) Get(_ context.Context, userID, id string) (domain.Task, error) {
+	return domain.Task{ID: id, UserID: userID}, nil
+}
+
+func TestCreateTaskChecklistKeepsOrderAndDropsBlanks(t *testing.T) {
+	repo := &checklistRepoStub{}
+	created, err := createTaskChecklist(context.Background(), repo, "u1", "t1", []string{"passaporte", "  ", "mala", "reservar hotel"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(created))
+	}
+	for i, want := range []string{"passaporte", "mala", "reservar hotel"} {
+		if created[i].Title != want || created[i].SortOrder != i || created[i].TaskID != "t1" {
+			t.Fatalf("unexpected step %d: %+v", i, created[i])
+		}
+	}
+
+	if _, err := createTaskChecklist(context.Background(), nil, "u1", "t1", []string{"passaporte"}); err != ErrDependencyMissing {
+		t.Fatalf("expected ErrDependencyMissing without repository, got %v", err)
+	}
+	if created, err := createTaskChecklist(context.Background(), nil, "u1", "t1", nil); err != nil || created != nil {
+		t.Fatalf("expected no-op for an empty checklist, got %v, %v", created, err)
+	}
+}
+
+func TestTaskChecklistCreateAppendsToEnd(t *testing.T) {
+	repo := &checklistRepoStub{items: []domain.TaskChecklistItem{
+		{ID: "a", TaskID: "t1", Title: "passaporte", SortOrder: 0},
+		{ID: "b", TaskID: "t1", Title: "mala", SortOrder: 4},
+	}}
+	uc := &TaskChecklistUsecase{Items: repo, Tasks: checklistTaskRepoStub{}}
+
+	item, err := uc.Create(context.Background(), "u1", "t1", " reservar hotel ", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Title != "reservar hotel" || item.SortOrder != 5 || item.Checked {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+
+	negative := -1
+	if _, err := uc.Create(context.Background(), "u1", "t1", "seguro", nil, &negative); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload for negative sort order, got %v", err)
+	}
+}
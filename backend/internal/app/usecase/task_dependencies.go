@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+)
+
+const (
+	// maxTaskBlockers caps how many tasks a single task can wait on.
+	maxTaskBlockers = 50
+	// maxTaskDependencyChain caps how many tasks can be reached through the blockers of a task.
+	maxTaskDependencyChain = 500
+)
+
+// resolveTaskBlockers validates the blockers of taskID (empty while creating) and reports whether
+// any of them is still open. Unknown ids are rejected, and so is any chain that leads back to
+// taskID, since the tasks would wait on each other forever. The user's dependency graph is
+// loaded in one query and walked in memory.
+func (uc *TaskUsecase) resolveTaskBlockers(ctx context.Context, userID, taskID string, input []string) ([]string, bool, error) {
+	blockedBy := make([]string, 0, len(input))
+	seen := make(map[string]struct{}, len(input))
+	for _, id := range input {
+		id = normalizeString(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		blockedBy = append(blockedBy, id)
+	}
+	if len(blockedBy) == 0 {
+		return nil, false, nil
+	}
+	if len(blockedBy) > maxTaskBlockers {
+		return nil, false, ErrInvalidPayload
+	}
+	if _, ok := seen[taskID]; ok {
+		return nil, false, ErrTaskDependencyCycle
+	}
+
+	graph, err := uc.Tasks.ListDependencyGraph(ctx, userID, blockedBy)
+	if err != nil {
+		return nil, false, err
+	}
+	blocked := false
+	for _, id := range blockedBy {
+		blocker, ok := graph[id]
+		if !ok {
+			return nil, false, ErrInvalidPayload
+		}
+		if blocker.Status != domain.TaskStatusDone {
+			blocked = true
+		}
+	}
+
+	queue := append([]string(nil), blockedBy...)
+	visited := make(map[string]struct{}, len(blockedBy))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		// Sem id (criação) ninguém pode depender da tarefa ainda, então não há ciclo possível.
+		if taskID != "" && id == taskID {
+			return nil, false, ErrTaskDependencyCycle
+		}
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		if len(visited) >= maxTaskDependencyChain {
+			return nil, false, ErrTaskDependencyTooDeep
+		}
+		visited[id] = struct{}{}
+		// Bloqueadora excluída no meio da cadeia não está no grafo: o caminho termina ali.
+		queue = append(queue, graph[id].BlockedBy...)
+	}
+	return blockedBy, blocked, nil
+}
+
+// cancelBlockedNotifications drops the pending due-date pushes of a task that became blocked.
+// The scheduler schedules them again once the blockers are done. It also releases the earlier
+// "unblocked" notice, otherwise its dedup key would keep the next unblock from being announced.
+func (uc *TaskUsecase) cancelBlockedNotifications(ctx context.Context, task domain.Task) {
+	if uc.NotificationLogs == nil || !task.Blocked || task.Status != domain.TaskStatusOpen {
+		return
+	}
+	uc.releaseBlockedTask(ctx, task.ID)
+}
+
+// cancelDependentNotifications does the same for the open tasks waiting on a blocker that was
+// reopened, since they are blocked again.
+func (uc *TaskUsecase) cancelDependentNotifications(ctx context.Context, userID, blockerID string) {
+	if uc.NotificationLogs == nil {
+		return
+	}
+	graph, err := uc.Tasks.ListDependencyGraph(ctx, userID, nil)
+	if err != nil {
+		return
+	}
+	for _, node := range graph {
+		if node.Status != domain.TaskStatusOpen {
+			continue
+		}
+		for _, id := range node.BlockedBy {
+			if id == blockerID {
+				uc.releaseBlockedTask(ctx, node.ID)
+				break
+			}
+		}
+	}
+}
+
+func (uc *TaskUsecase) releaseBlockedTask(ctx context.Context, taskID string) {
+	now := time.Now()
+	_, _ = uc.NotificationLogs.CancelPending(ctx, taskID, now, now.AddDate(1, 0, 0))
+	_, _ = uc.NotificationLogs.Release(ctx, taskID, domain.TaskUnblockedLeadMins)
+}
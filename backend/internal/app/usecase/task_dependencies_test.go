@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/postgres"
+)
+
+type dependencyTaskRepoStub struct {
+	repository.TaskRepository
+	tasks      map[string]domain.Task
+	graphLoads int
+}
+
+func (s *dependencyTaskRepoStub) Get(_ context.Context, _, id string) (domain.Task, error) {
+	task, ok := s.tasks[id]
+	if !ok {
+		return domain.Task{}, postgres.ErrNotFound
+	}
+	return task, nil
+}
+
+func (s *dependencyTaskRepoStub) Update(_ context.Context, task domain.Task) (domain.Task, error) {
+	s.tasks[task.ID] = task
+	return task, nil
+}
+
+func (s *dependencyTaskRepoStub) ListDependencyGraph(_ context.Context, _ string, ids []string) (map[string]repository.TaskDependencyNode, error) {
+	s.graphLoads++
+	nodes := make(map[string]repository.TaskDependencyNode)
+	for _, task := range s.tasks {
+		nodes[task.ID] = repository.TaskDependencyNode{ID: task.ID, Status: task.Status, BlockedBy: task.BlockedBy}
+	}
+	return nodes, nil
+}
+
+type dependencyLogRepoStub struct {
+	repository.NotificationLogRepository
+	canceled []string
+	released []string
+}
+
+func (s *dependencyLogRepoStub) CancelPending(_ context.Context, referenceID string, _, _ time.Time) (int, error) {
+	s.canceled = append(s.canceled, referenceID)
+	return 1, nil
+}
+
+func (s *dependencyLogRepoStub) Release(_ context.Context, referenceID string, leadMins int) (int, error) {
+	if leadMins == domain.TaskUnblockedLeadMins {
+		s.released = append(s.released, referenceID)
+	}
+	return 1, nil
+}
+
+func TestTaskUpdateBlockedByRejectsCycles(t *testing.T) {
+	// a espera b, b espera c: c esperar a fecharia o ciclo.
+	repo := &dependencyTaskRepoStub{tasks: map[string]domain.Task{
+		"a": {ID: "a", UserID: "u1", Title: "Pintar", Status: domain.TaskStatusOpen, BlockedBy: []string{"b"}},
+		"b": {ID: "b", UserID: "u1", Title: "Lixar", Status: domain.TaskStatusOpen, BlockedBy: []string{"c"}},
+		"c": {ID: "c", UserID: "u1", Title: "Comprar tinta", Status: domain.TaskStatusOpen},
+	}}
+	uc := &TaskUsecase{Tasks: repo}
+
+	cases := map[string][]string{
+		"self":     {"c"},
+		"indirect": {"a"},
+	}
+	for name, blockedBy := range cases {
+		blockedBy := blockedBy
+		_, err := uc.Update(context.Background(), "u1", "c", TaskUpdateInput{BlockedBy: &blockedBy})
+		if !errors.Is(err, ErrTaskDependencyCycle) {
+			t.Fatalf("%s: expected ErrTaskDependencyCycle, got %v", name, err)
+		}
+	}
+
+	unknown := []string{"missing"}
+	if _, err := uc.Update(context.Background(), "u1", "c", TaskUpdateInput{BlockedBy: &unknown}); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("expected ErrInvalidPayload for unknown blocker, got %v", err)
+	}
+}
+
+func TestTaskUpdateBlockedByRejectsLongChains(t *testing.T) {
+	// t0 espera t1, que espera t2... além do limite que a validação percorre.
+	tasks := make(map[string]domain.Task)
+	for i := 0; i <= maxTaskDependencyChain; i++ {
+		id := fmt.Sprintf("t%d", i)
+		task := domain.Task{ID: id, UserID: "u1", Title: id, Status: domain.TaskStatusOpen}
+		if i < maxTaskDependencyChain {
+			task.BlockedBy = []string{fmt.Sprintf("t%d", i+1)}
+		}
+		tasks[id] = task
+	}
+	tasks["new"] = domain.Task{ID: "new", UserID: "u1", Title: "Nova", Status: domain.TaskStatusOpen}
+	repo := &dependencyTaskRepoStub{tasks: tasks}
+	uc := &TaskUsecase{Tasks: repo}
+
+	blockedBy := []string{"t0"}
+	_, err := uc.Update(context.Background(), "u1", "new", TaskUpdateInput{BlockedBy: &blockedBy})
+	if !errors.Is(err, ErrTaskDependencyTooDeep) {
+		t.Fatalf("expected ErrTaskDependencyTooDeep, got %v", err)
+	}
+	if repo.graphLoads != 1 {
+		t.Fatalf("expected the dependency graph to be loaded once, got %d", repo.graphLoads)
+	}
+
+	short := []string{"t1"}
+	if _, err := uc.Update(context.Background(), "u1", "new", TaskUpdateInput{BlockedBy: &short}); err != nil {
+		t.Fatalf("expected a chain within the limit to be accepted, got %v", err)
+	}
+}
+
+func TestTaskUpdateBlockedByMarksBlockedAndCancelsPushes(t *testing.T) {
+	repo := &dependencyTaskRepoStub{tasks: map[string]domain.Task{
+		"a": {ID: "a", UserID: "u1", Title: "Pintar", Status: domain.TaskStatusOpen},
+		"b": {ID: "b", UserID: "u1", Title: "Lixar", Status: domain.TaskStatusOpen},
+		"c": {ID: "c", UserID: "u1", Title: "Comprar tinta", Status: domain.TaskStatusDone},
+	}}
+	logs := &dependencyLogRepoStub{}
+	uc := &TaskUsecase{Tasks: repo, NotificationLogs: logs}
+
+	blockedBy := []string{" b ", "c", "b"}
+	updated, err := uc.Update(context.Background(), "u1", "a", TaskUpdateInput{BlockedBy: &blockedBy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated.BlockedBy) != 2 || updated.BlockedBy[0] != "b" || updated.BlockedBy[1] != "c" {
+		t.Fatalf("expected deduplicated blockers [b c], got %v", updated.BlockedBy)
+	}
+	if !updated.Blocked {
+		t.Fatalf("expected task to be blocked while b is open")
+	}
+	if len(logs.canceled) != 1 || logs.canceled[0] != "a" {
+		t.Fatalf("expected pending pushes of a to be canceled, got %v", logs.canceled)
+	}
+	if len(logs.released) != 1 || logs.released[0] != "a" {
+		t.Fatalf("expected the unblocked notice of a to be released, got %v", logs.released)
+	}
+
+	onlyDone := []string{"c"}
+	updated, err = uc.Update(context.Background(), "u1", "a", TaskUpdateInput{BlockedBy: &onlyDone})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Blocked || len(logs.canceled) != 1 {
+		t.Fatalf("expected task not blocked by a done blocker, got blocked=%v canceled=%v", updated.Blocked, logs.canceled)
+	}
+
+	none := []string{}
+	updated, err = uc.Update(context.Background(), "u1", "a", TaskUpdateInput{BlockedBy: &none})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.BlockedBy != nil || updated.Blocked {
+		t.Fatalf("expected blockers to be removed, got %+v", updated)
+	}
+}
+
+func TestTaskReopenReleasesDependentsUnblockedNotice(t *testing.T) {
+	repo := &dependencyTaskRepoStub{tasks: map[string]domain.Task{
+		"a": {ID: "a", UserID: "u1", Title: "Pintar", Status: domain.TaskStatusOpen, BlockedBy: []string{"b"}},
+		"b": {ID: "b", UserID: "u1", Title: "Lixar", Status: domain.TaskStatusDone},
+		"c": {ID: "c", UserID: "u1", Title: "Envernizar", Status: domain.TaskStatusDone, BlockedBy: []string{"b"}},
+	}}
+	logs := &dependencyLogRepoStub{}
+	uc := &TaskUsecase{Tasks: repo, NotificationLogs: logs}
+
+	open := string(domain.TaskStatusOpen)
+	if _, err := uc.Update(context.Background(), "u1", "b", TaskUpdateInput{Status: &open}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs.released) != 1 || logs.released[0] != "a" {
+		t.Fatalf("expected only the open dependent a to be released, got %v", logs.released)
+	}
+}
+
+func TestSelectFocusTasksSkipsBlocked(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	overdue := now.Add(-2 * time.Hour)
+
+	tasks := []domain.Task{
+		{ID: "blocked", Title: "Pintar", Status: domain.TaskStatusOpen, DueAt: &overdue, Priority: priorityPtr(domain.TaskPriorityP1), Blocked: true},
+		{ID: "free", Title: "Lixar", Status: domain.TaskStatusOpen, DueAt: &overdue},
+	}
+	focus := selectFocusTasks(tasks, now)
+	if len(focus) != 1 || focus[0].ID != "free" {
+		t.Fatalf("expected only the unblocked task in focus, got %+v", focus)
+	}
+}
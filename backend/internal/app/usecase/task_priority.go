@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"time"
+
+	"inbota/backend/internal/app/domain"
+)
+
+// maxMatrixTasks limits how many open tasks the Eisenhower view loads.
+const maxMatrixTasks = 500
+
+// taskUrgentWindow makes any task due within it urgent, whatever its priority.
+const taskUrgentWindow = 48 * time.Hour
+
+// TaskMatrix holds the open tasks split into the Eisenhower quadrants.
+type TaskMatrix struct {
+	UrgentImportant       []domain.Task
+	NotUrgentImportant    []domain.Task
+	UrgentNotImportant    []domain.Task
+	NotUrgentNotImportant []domain.Task
+}
+
+// normalizeTaskPriority accepts P1..P4 (case-insensitive); an empty value clears the priority.
+func normalizeTaskPriority(value *string) (*domain.TaskPriority, error) {
+	trimmed := normalizeOptionalString(value)
+	if trimmed == nil {
+		return nil, nil
+	}
+	parsed, ok := parseTaskPriority(*trimmed)
+	if !ok {
+		return nil, ErrInvalidPayload
+	}
+	return &parsed, nil
+}
+
+// normalizeTaskEffort accepts 1..domain.MaxTaskEffortMins minutes; zero clears the estimate.
+func normalizeTaskEffort(value *int) (*int, error) {
+	if value == nil || *value == 0 {
+		return nil, nil
+	}
+	if *value < 0 || *value > domain.MaxTaskEffortMins {
+		return nil, ErrInvalidPayload
+	}
+	effort := *value
+	return &effort, nil
+}
+
+// taskImportant is true for P1 and P2.
+func taskImportant(task domain.Task) bool {
+	if task.Priority == nil {
+		return false
+	}
+	return *task.Priority == domain.TaskPriorityP1 || *task.Priority == domain.TaskPriorityP2
+}
+
+// taskUrgent is true for P1 and P3, and for any task due (or overdue) within taskUrgentWindow.
+func taskUrgent(task domain.Task, now time.Time) bool {
+	if task.Priority != nil && (*task.Priority == domain.TaskPriorityP1 || *task.Priority == domain.TaskPriorityP3) {
+		return true
+	}
+	return task.DueAt != nil && task.DueAt.Before(now.Add(taskUrgentWindow))
+}
+
+// taskPriorityRank orders P1 first and tasks without priority last.
+func taskPriorityRank(priority *domain.TaskPriority) int {
+	if priority == nil {
+		return 4
+	}
+	switch *priority {
+	case domain.TaskPriorityP1:
+		return 0
+	case domain.TaskPriorityP2:
+		return 1
+	case domain.TaskPriorityP3:
+		return 2
+	case domain.TaskPriorityP4:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func buildTaskMatrix(tasks []domain.Task, now time.Time) TaskMatrix {
+	matrix := TaskMatrix{
+		UrgentImportant:       make([]domain.Task, 0),
+		NotUrgentImportant:    make([]domain.Task, 0),
+		UrgentNotImportant:    make([]domain.Task, 0),
+		NotUrgentNotImportant: make([]domain.Task, 0),
+	}
+	for _, task := range tasks {
+		if task.Status != domain.TaskStatusOpen {
+			continue
+		}
+		important := taskImportant(task)
+		urgent := taskUrgent(task, now)
+		switch {
+		case urgent && important:
+			matrix.UrgentImportant = append(matrix.UrgentImportant, task)
+		case important:
+			matrix.NotUrgentImportant = append(matrix.NotUrgentImportant, task)
+		case urgent:
+			matrix.UrgentNotImportant = append(matrix.UrgentNotImportant, task)
+		default:
+			matrix.NotUrgentNotImportant = append(matrix.NotUrgentNotImportant, task)
+		}
+	}
+	return matrix
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+)
+
+func priorityPtr(p domain.TaskPriority) *domain.TaskPriority {
+	return &p
+}
+
+func TestBuildTaskMatrixQuadrants(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	tomorrow := now.Add(24 * time.Hour)
+	nextWeek := now.AddDate(0, 0, 7)
+
+	tasks := []domain.Task{
+		{ID: "p1", Status: domain.TaskStatusOpen, Priority: priorityPtr(domain.TaskPriorityP1)},
+		{ID: "p2-far", Status: domain.TaskStatusOpen, Priority: priorityPtr(domain.TaskPriorityP2), DueAt: &nextWeek},
+		{ID: "p2-soon", Status: domain.TaskStatusOpen, Priority: priorityPtr(domain.TaskPriorityP2), DueAt: &tomorrow},
+		{ID: "p3", Status: domain.TaskStatusOpen, Priority: priorityPtr(domain.TaskPriorityP3)},
+		{ID: "none-soon", Status: domain.TaskStatusOpen, DueAt: &tomorrow},
+		{ID: "p4", Status: domain.TaskStatusOpen, Priority: priorityPtr(domain.TaskPriorityP4), DueAt: &nextWeek},
+		{ID: "done", Status: domain.TaskStatusDone, Priority: priorityPtr(domain.TaskPriorityP1)},
+	}
+
+	matrix := buildTaskMatrix(tasks, now)
+	ids := func(items []domain.Task) []string {
+		out := make([]string, 0, len(items))
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+
+	cases := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"urgentImportant", ids(matrix.UrgentImportant), []string{"p1", "p2-soon"}},
+		{"notUrgentImportant", ids(matrix.NotUrgentImportant), []string{"p2-far"}},
+		{"urgentNotImportant", ids(matrix.UrgentNotImportant), []string{"p3", "none-soon"}},
+		{"notUrgentNotImportant", ids(matrix.NotUrgentNotImportant), []string{"p4"}},
+	}
+	for _, tc := range cases {
+		if len(tc.got) != len(tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, tc.got)
+		}
+		for i := range tc.want {
+			if tc.got[i] != tc.want[i] {
+				t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, tc.got)
+			}
+		}
+	}
+}
+
+func TestSelectFocusTasksRanksByPriorityAndEffort(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	today := now.Add(3 * time.Hour)
+	nextWeek := now.AddDate(0, 0, 7)
+	quick, long := 15, 120
+
+	tasks := []domain.Task{
+		{ID: "today-plain", Title: "a", DueAt: &today},
+		{ID: "today-p2-long", Title: "b", DueAt: &today, Priority: priorityPtr(domain.TaskPriorityP2), EffortMins: &long},
+		{ID: "today-p2-quick", Title: "c", DueAt: &today, Priority: priorityPtr(domain.TaskPriorityP2), EffortMins: &quick},
+		{ID: "future-p1", Title: "d", DueAt: &nextWeek, Priority: priorityPtr(domain.TaskPriorityP1)},
+		{ID: "future-p2", Title: "e", DueAt: &nextWeek, Priority: priorityPtr(domain.TaskPriorityP2)},
+	}
+
+	focus := selectFocusTasks(tasks, now)
+	want := []string{"today-p2-quick", "today-p2-long", "today-plain", "future-p1"}
+	if len(focus) != len(want) {
+		t.Fatalf("expected %d focus tasks, got %d", len(want), len(focus))
+	}
+	for i, id := range want {
+		if focus[i].ID != id {
+			t.Fatalf("position %d: expected %s, got %s", i, id, focus[i].ID)
+		}
+	}
+}
+
+func TestNormalizeTaskPriorityAndEffort(t *testing.T) {
+	lower := " p2 "
+	priority, err := normalizeTaskPriority(&lower)
+	if err != nil || priority == nil || *priority != domain.TaskPriorityP2 {
+		t.Fatalf("unexpected priority: %v, %v", priority, err)
+	}
+	invalid := "P5"
+	if _, err := normalizeTaskPriority(&invalid); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload, got %v", err)
+	}
+
+	zero := 0
+	if effort, err := normalizeTaskEffort(&zero); err != nil || effort != nil {
+		t.Fatalf("expected zero effort to clear, got %v, %v", effort, err)
+	}
+	tooLong := domain.MaxTaskEffortMins + 1
+	if _, err := normalizeTaskEffort(&tooLong); err != ErrInvalidPayload {
+		t.Fatalf("expected ErrInvalidPayload, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/recurrence"
+	"inbota/backend/internal/app/repository"
+)
+
+// normalizeTaskRecurrence validates the rule or the day count and stores the rule in canonical
+// form. An empty recurrence (no rule, no days) removes it. COUNT is rejected: each instance only
+// knows its own due date, so the series cannot count occurrences; UNTIL covers the same need.
+func normalizeTaskRecurrence(input *domain.TaskRecurrence) (*domain.TaskRecurrence, error) {
+	if input == nil {
+		return nil, nil
+	}
+	rule := normalizeOptionalString(input.Rule)
+	afterDays := input.AfterDays
+	if afterDays != nil && *afterDays == 0 {
+		afterDays = nil
+	}
+	if rule == nil && afterDays == nil {
+		return nil, nil
+	}
+	if rule != nil && afterDays != nil {
+		return nil, fmt.Errorf("%w: rule and afterDays are mutually exclusive", ErrInvalidRecurrence)
+	}
+
+	if afterDays != nil {
+		if *afterDays < 0 || *afterDays > domain.MaxTaskRecurrenceAfterDays {
+			return nil, ErrInvalidRecurrence
+		}
+		days := *afterDays
+		return &domain.TaskRecurrence{AfterDays: &days}, nil
+	}
+
+	parsed, err := recurrence.Parse(*rule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	if parsed.Count > 0 {
+		return nil, fmt.Errorf("%w: COUNT is not supported on tasks, use UNTIL", ErrInvalidRecurrence)
+	}
+	canonical := parsed.String()
+	return &domain.TaskRecurrence{Rule: &canonical}, nil
+}
+
+// nextTaskDue returns the due date of the instance that follows task, completed at completedAt.
+// Fixed rules continue from the current due date, skipping dates that were already past when the
+// task was completed; AfterDays counts from the completion day. The time of day of the current
+// due date is kept (midnight = no time). It reports false when the series has ended.
+func nextTaskDue(task domain.Task, completedAt time.Time, loc *time.Location) (time.Time, bool) {
+	if task.Recurrence == nil {
+		return time.Time{}, false
+	}
+	completedLocal := completedAt.In(loc)
+	completedDay := time.Date(completedLocal.Year(), completedLocal.Month(), completedLocal.Day(), 0, 0, 0, 0, loc)
+
+	clock := completedDay
+	anchor := completedDay
+	if task.DueAt != nil {
+		clock = task.DueAt.In(loc)
+		anchor = time.Date(clock.Year(), clock.Month(), clock.Day(), 0, 0, 0, 0, loc)
+	}
+
+	var next time.Time
+	switch {
+	case task.Recurrence.AfterDays != nil:
+		next = completedDay.AddDate(0, 0, *task.Recurrence.AfterDays)
+	case task.Recurrence.Rule != nil:
+		rule, err := recurrence.Parse(*task.Recurrence.Rule)
+		if err != nil {
+			return time.Time{}, false
+		}
+		after := anchor
+		if completedDay.After(after) {
+			after = completedDay
+		}
+		date, ok := recurrence.NewSeries(rule, anchor, "", "").Next(after)
+		if !ok {
+			return time.Time{}, false
+		}
+		next = date
+	default:
+		return time.Time{}, false
+	}
+
+	return time.Date(next.Year(), next.Month(), next.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc), true
+}
+
+// nextTaskInstance copies a completed recurring task into its next open instance. The recurrence
+// moves to the new instance, so reopening and completing the old one does not repeat it twice.
+func nextTaskInstance(completed *domain.Task, completedAt time.Time, loc *time.Location) (domain.Task, bool) {
+	dueAt, ok := nextTaskDue(*completed, completedAt, loc)
+	recurrenceRule := completed.Recurrence
+	completed.Recurrence = nil
+	if !ok {
+		return domain.Task{}, false
+	}
+
+	seriesID := completed.ID
+	if completed.SeriesID != nil {
+		seriesID = *completed.SeriesID
+	}
+	completed.SeriesID = &seriesID
+
+	next := domain.Task{
+		UserID:       completed.UserID,
+		Title:        completed.Title,
+		Description:  completed.Description,
+		Status:       domain.TaskStatusOpen,
+		DueAt:        &dueAt,
+		FlagID:       completed.FlagID,
+		SubflagID:    completed.SubflagID,
+		ProjectID:    completed.ProjectID,
+		Priority:     completed.Priority,
+		EffortMins:   completed.EffortMins,
+		Recurrence:   recurrenceRule,
+		SeriesID:     &seriesID,
+		Notification: completed.Notification,
+	}
+	return next, true
+}
+
+// completeRecurringTask saves the completed instance, creates the next one and copies the
+// checklist in one transaction, so a failure keeps the rule on the task. The update only applies
+// while the task is still open: with concurrent completions only the first one spawns an instance.
+func (uc *TaskUsecase) completeRecurringTask(ctx context.Context, userID string, completed, next domain.Task) (domain.Task, error) {
+	if uc.TxRunner == nil {
+		return uc.saveRecurringCompletion(ctx, uc.Tasks, uc.Checklist, userID, completed, next)
+	}
+	var updated domain.Task
+	err := uc.TxRunner.WithTx(ctx, func(tx repository.TxRepositories) error {
+		if tx.Tasks == nil {
+			return ErrDependencyMissing
+		}
+		checklist := tx.TaskChecklist
+		if uc.Checklist == nil {
+			checklist = nil
+		}
+		var err error
+		updated, err = uc.saveRecurringCompletion(ctx, tx.Tasks, checklist, userID, completed, next)
+		return err
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return updated, nil
+}
+
+func (uc *TaskUsecase) saveRecurringCompletion(ctx context.Context, tasks repository.TaskRepository, checklist repository.TaskChecklistItemRepository, userID string, completed, next domain.Task) (domain.Task, error) {
+	updated, applied, err := tasks.UpdateIfOpen(ctx, completed)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if !applied {
+		// Outra requisição concluiu a tarefa primeiro e já criou a próxima instância.
+		return tasks.Get(ctx, userID, completed.ID)
+	}
+	created, err := tasks.Create(ctx, next)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if err := repeatTaskChecklist(ctx, checklist, userID, updated.ID, created.ID); err != nil {
+		return domain.Task{}, err
+	}
+	return updated, nil
+}
+
+// repeatTaskChecklist copies the checklist titles, unchecked, to the next instance (nil = not copied).
+func repeatTaskChecklist(ctx context.Context, checklist repository.TaskChecklistItemRepository, userID, fromTaskID, toTaskID string) error {
+	if checklist == nil {
+		return nil
+	}
+	items, err := checklist.ListByTask(ctx, userID, fromTaskID)
+	if err != nil {
+		return err
+	}
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	_, err = createTaskChecklist(ctx, checklist, userID, toTaskID, titles)
+	return err
+}
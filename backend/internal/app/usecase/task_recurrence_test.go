@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+)
+
+type recurringTaskRepoStub struct {
+	repository.TaskRepository
+	tasks     map[string]domain.Task
+	created   []domain.Task
+	createErr error
+}
+
+func (s *recurringTaskRepoStub) Get(_ context.Context, _, id string) (domain.Task, error) {
+	return s.tasks[id], nil
+}
+
+func (s *recurringTaskRepoStub) Update(_ context.Context, task domain.Task) (domain.Task, error) {
+	s.tasks[task.ID] = task
+	return task, nil
+}
+
+func (s *recurringTaskRepoStub) UpdateIfOpen(_ context.Context, task domain.Task) (domain.Task, bool, error) {
+	if s.tasks[task.ID].Status == domain.TaskStatusDone {
+		return domain.Task{}, false, nil
+	}
+	s.tasks[task.ID] = task
+	return task, true, nil
+}
+
+func (s *recurringTaskRepoStub) Create(_ context.Context, task domain.Task) (domain.Task, error) {
+	if s.createErr != nil {
+		return domain.Task{}, s.createErr
+	}
+	task.ID = "next"
+	s.created = append(s.created, task)
+	return task, nil
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func strPtr(v string) *string {
+	return &v
+}
+
+// recurringTxRunnerStub restores the stored tasks when fn fails, like a rolled back transaction.
+type recurringTxRunnerStub struct {
+	repo *recurringTaskRepoStub
+}
+
+func (s recurringTxRunnerStub) WithTx(_ context.Context, fn func(tx repository.TxRepositories) error) error {
+	snapshot := make(map[string]domain.Task, len(s.repo.tasks))
+	for id, task := range s.repo.tasks {
+		snapshot[id] = task
+	}
+	if err := fn(repository.TxRepositories{Tasks: s.repo}); err != nil {
+		s.repo.tasks = snapshot
+		return err
+	}
+	return nil
+}
+
+func TestNextTaskDue(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*60*60)
+	rent := "FREQ=MONTHLY;BYMONTHDAY=5"
+	due := time.Date(2026, 3, 5, 10, 0, 0, 0, loc)
+
+	cases := []struct {
+		name        string
+		recurrence  domain.TaskRecurrence
+		completedAt time.Time
+		want        time.Time
+	}{
+		{"on time keeps the schedule", domain.TaskRecurrence{Rule: &rent}, time.Date(2026, 3, 4, 18, 0, 0, 0, loc), time.Date(2026, 4, 5, 10, 0, 0, 0, loc)},
+		{"late completion skips past dates", domain.TaskRecurrence{Rule: &rent}, time.Date(2026, 4, 9, 8, 0, 0, 0, loc), time.Date(2026, 5, 5, 10, 0, 0, 0, loc)},
+		{"after days counts from completion", domain.TaskRecurrence{AfterDays: intPtr(90)}, time.Date(2026, 3, 10, 8, 0, 0, 0, loc), time.Date(2026, 6, 8, 10, 0, 0, 0, loc)},
+	}
+	for _, tc := range cases {
+		task := domain.Task{DueAt: &due, Recurrence: &tc.recurrence}
+		got, ok := nextTaskDue(task, tc.completedAt, loc)
+		if !ok || !got.Equal(tc.want) {
+			t.Fatalf("%s: got %v, %v; want %v", tc.name, got, ok, tc.want)
+		}
+	}
+
+	ended := "FREQ=MONTHLY;BYMONTHDAY=5;UNTIL=20260331"
+	task := domain.Task{DueAt: &due, Recurrence: &domain.TaskRecurrence{Rule: &ended}}
+	if got, ok := nextTaskDue(task, due, loc); ok {
+		t.Fatalf("expected series to end after UNTIL, got %v", got)
+	}
+}
+
+func TestNormalizeTaskRecurrence(t *testing.T) {
+	rule := "freq=monthly;bymonthday=5"
+	got, err := normalizeTaskRecurrence(&domain.TaskRecurrence{Rule: &rule})
+	if err != nil || got == nil || got.Rule == nil || *got.Rule != "FREQ=MONTHLY;BYMONTHDAY=5" {
+		t.Fatalf("unexpected recurrence: %+v, %v", got, err)
+	}
+
+	if got, err := normalizeTaskRecurrence(&domain.TaskRecurrence{}); err != nil || got != nil {
+		t.Fatalf("expected empty recurrence to clear, got %+v, %v", got, err)
+	}
+
+	invalid := []domain.TaskRecurrence{
+		{Rule: &rule, AfterDays: intPtr(30)},
+		{AfterDays: intPtr(-1)},
+		{Rule: strPtr("FREQ=MONTHLY;COUNT=3")},
+		{Rule: strPtr("FREQ=HOURLY")},
+	}
+	for _, input := range invalid {
+		input := input
+		if _, err := normalizeTaskRecurrence(&input); err == nil {
+			t.Fatalf("expected error for %+v", input)
+		}
+	}
+}
+
+func TestTaskUpdateCompletingRecurringTaskCreatesNextInstance(t *testing.T) {
+	due := time.Now().Add(-time.Hour)
+	flagID := "f1"
+	repo := &recurringTaskRepoStub{tasks: map[string]domain.Task{
+		"t1": {ID: "t1", UserID: "u1", Title: "Trocar filtro", Status: domain.TaskStatusOpen, DueAt: &due, FlagID: &flagID, Recurrence: &domain.TaskRecurrence{AfterDays: intPtr(90)}},
+	}}
+	uc := &TaskUsecase{Tasks: repo}
+
+	done := string(domain.TaskStatusDone)
+	completed, err := uc.Update(context.Background(), "u1", "t1", TaskUpdateInput{Status: &done})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if completed.Recurrence != nil || completed.SeriesID == nil || *completed.SeriesID != "t1" {
+		t.Fatalf("expected completed task to keep only the series, got %+v", completed)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected one next instance, got %d", len(repo.created))
+	}
+	next := repo.created[0]
+	if next.Status != domain.TaskStatusOpen || next.Recurrence == nil || next.SeriesID == nil || *next.SeriesID != "t1" {
+		t.Fatalf("unexpected next instance: %+v", next)
+	}
+	if next.FlagID == nil || *next.FlagID != "f1" || next.DueAt == nil || !next.DueAt.After(time.Now().AddDate(0, 0, 88)) {
+		t.Fatalf("expected next instance with flag and due in ~90 days, got %+v", next)
+	}
+
+	// Reabrir e concluir de novo não gera outra instância.
+	open := string(domain.TaskStatusOpen)
+	if _, err := uc.Update(context.Background(), "u1", "t1", TaskUpdateInput{Status: &open}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.Update(context.Background(), "u1", "t1", TaskUpdateInput{Status: &done}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected no extra instance, got %d", len(repo.created))
+	}
+}
+
+func TestTaskUpdateKeepsRecurrenceWhenNextInstanceFails(t *testing.T) {
+	due := time.Now().Add(-time.Hour)
+	repo := &recurringTaskRepoStub{
+		tasks: map[string]domain.Task{
+			"t1": {ID: "t1", UserID: "u1", Title: "Trocar filtro", Status: domain.TaskStatusOpen, DueAt: &due, Recurrence: &domain.TaskRecurrence{AfterDays: intPtr(90)}},
+		},
+		createErr: errors.New("insert failed"),
+	}
+	uc := &TaskUsecase{Tasks: repo, TxRunner: recurringTxRunnerStub{repo: repo}}
+
+	done := string(domain.TaskStatusDone)
+	if _, err := uc.Update(context.Background(), "u1", "t1", TaskUpdateInput{Status: &done}); err == nil {
+		t.Fatalf("expected error when the next instance cannot be created")
+	}
+	stored := repo.tasks["t1"]
+	if stored.Status != domain.TaskStatusOpen || stored.Recurrence == nil || stored.Recurrence.AfterDays == nil || *stored.Recurrence.AfterDays != 90 {
+		t.Fatalf("expected task to stay open with its rule, got %+v", stored)
+	}
+}
+
+func TestTaskUpdateConcurrentCompletionCreatesOneInstance(t *testing.T) {
+	due := time.Now().Add(-time.Hour)
+	repo := &recurringTaskRepoStub{tasks: map[string]domain.Task{
+		"t1": {ID: "t1", UserID: "u1", Title: "Trocar filtro", Status: domain.TaskStatusOpen, DueAt: &due, Recurrence: &domain.TaskRecurrence{AfterDays: intPtr(90)}},
+	}}
+	uc := &TaskUsecase{Tasks: repo, TxRunner: recurringTxRunnerStub{repo: repo}}
+
+	// Ambas as requisições leram a tarefa ainda aberta; a segunda chega depois da primeira salvar.
+	stale := repo.tasks["t1"]
+	done := string(domain.TaskStatusDone)
+	if _, err := uc.Update(context.Background(), "u1", "t1", TaskUpdateInput{Status: &done}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stale.Status = domain.TaskStatusDone
+	next, ok := nextTaskInstance(&stale, time.Now(), time.UTC)
+	if !ok {
+		t.Fatalf("expected a next instance")
+	}
+	got, err := uc.completeRecurringTask(context.Background(), "u1", stale, next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected one next instance, got %d", len(repo.created))
+	}
+	if got.ID != "t1" || got.Status != domain.TaskStatusDone {
+		t.Fatalf("expected the stored completed task, got %+v", got)
+	}
+}
@@ -14,6 +14,35 @@ type TaskUsecase struct {
 	Tasks    repository.TaskRepository
 	Flags    repository.FlagRepository
 	Subflags repository.SubflagRepository
+	Projects repository.ProjectRepository
+	// Users resolve the timezone used to schedule the next instance of recurring tasks.
+	Users repository.UserRepository
+	// Checklist is copied, unchecked, to the next instance of a recurring task (nil = not copied).
+	Checklist repository.TaskChecklistItemRepository
+	// NotificationLogs cancels the pending pushes of tasks that become blocked (nil = kept).
+	NotificationLogs repository.NotificationLogRepository
+	// TxRunner completes a recurring task and creates its next instance atomically.
+	// Prefer running with TxRunner in production; without it the steps are best effort.
+	TxRunner repository.TxRunner
+}
+
+type TaskCreateInput struct {
+	Title       string
+	Description *string
+	Status      *string
+	DueAt       *time.Time
+	FlagID      *string
+	SubflagID   *string
+	// SourceInboxItemID links the task to the inbox item it was confirmed from.
+	SourceInboxItemID *string
+	// ProjectID adds the task to a project; without its own flag the task inherits the project's.
+	ProjectID  *string
+	Priority   *string
+	EffortMins *int
+	Recurrence *domain.TaskRecurrence
+	BlockedBy  []string
+
+	Notification *domain.NotificationOverride
 }
 
 type TaskUpdateInput struct {
@@ -23,36 +52,94 @@ type TaskUpdateInput struct {
 	DueAt       *time.Time
 	FlagID      *string
 	SubflagID   *string
+	// ProjectID moves the task to another project; an empty string removes it from its project.
+	ProjectID *string
+	// Priority takes P1..P4; an empty string clears it. EffortMins = 0 clears the estimate.
+	Priority   *string
+	EffortMins *int
+	// Recurrence replaces the repetition; an empty one (no rule, no days) stops repeating.
+	Recurrence *domain.TaskRecurrence
+	// BlockedBy replaces the blockers; an empty list removes them.
+	BlockedBy *[]string
+
+	Notification *domain.NotificationOverride
 }
 
-func (uc *TaskUsecase) Create(ctx context.Context, userID, title string, description *string, status *string, dueAt *time.Time, flagID *string, subflagID *string, sourceInboxItemID *string) (domain.Task, error) {
-	title = normalizeString(title)
+func (uc *TaskUsecase) Create(ctx context.Context, userID string, input TaskCreateInput) (domain.Task, error) {
+	title := normalizeString(input.Title)
 	if userID == "" || title == "" {
 		return domain.Task{}, ErrMissingRequiredFields
 	}
 
+	project, err := uc.resolveProject(ctx, userID, input.ProjectID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	// Sem contexto próprio, a tarefa herda a flag/subflag do projeto.
+	flagID, subflagID := input.FlagID, input.SubflagID
+	if project != nil && normalizeOptionalString(flagID) == nil && normalizeOptionalString(subflagID) == nil {
+		flagID = project.FlagID
+		subflagID = project.SubflagID
+	}
+
 	resolvedFlagID, resolvedSubflagID, err := uc.resolveFlagAndSubflag(ctx, userID, flagID, subflagID)
 	if err != nil {
 		return domain.Task{}, err
 	}
+	override, err := normalizeNotificationOverride(input.Notification, domain.MaxNotificationLeadMins)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	resolvedPriority, err := normalizeTaskPriority(input.Priority)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	resolvedEffort, err := normalizeTaskEffort(input.EffortMins)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	resolvedRecurrence, err := normalizeTaskRecurrence(input.Recurrence)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	resolvedBlockedBy, blocked, err := uc.resolveTaskBlockers(ctx, userID, "", input.BlockedBy)
+	if err != nil {
+		return domain.Task{}, err
+	}
 
 	task := domain.Task{
 		UserID:            userID,
 		Title:             title,
-		Description:       description,
+		Description:       input.Description,
 		FlagID:            resolvedFlagID,
 		SubflagID:         resolvedSubflagID,
-		SourceInboxItemID: normalizeOptionalString(sourceInboxItemID),
+		SourceInboxItemID: normalizeOptionalString(input.SourceInboxItemID),
+		Priority:          resolvedPriority,
+		EffortMins:        resolvedEffort,
+		Recurrence:        resolvedRecurrence,
+		BlockedBy:         resolvedBlockedBy,
+		Blocked:           blocked,
+		Notification:      override,
+	}
+	if project != nil {
+		task.ProjectID = &project.ID
 	}
 
-	if status != nil {
-		parsed, ok := parseTaskStatus(*status)
+	if input.Status != nil {
+		parsed, ok := parseTaskStatus(*input.Status)
 		if !ok {
 			return domain.Task{}, ErrInvalidStatus
 		}
 		task.Status = parsed
 	}
-	task.DueAt = dueAt
+	task.DueAt = input.DueAt
+	// Agenda fixa sem prazo: a primeira instância cai na próxima data da regra (hoje inclusive).
+	if task.DueAt == nil && resolvedRecurrence != nil && resolvedRecurrence.Rule != nil {
+		now := userNow(ctx, uc.Users, userID)
+		if first, ok := nextTaskDue(task, now.AddDate(0, 0, -1), now.Location()); ok {
+			task.DueAt = &first
+		}
+	}
 
 	return uc.Tasks.Create(ctx, task)
 }
@@ -65,6 +152,7 @@ func (uc *TaskUsecase) Update(ctx context.Context, userID, id string, input Task
 	if err != nil {
 		return domain.Task{}, err
 	}
+	wasDone := task.Status == domain.TaskStatusDone
 
 	if input.Title != nil {
 		trimmed := normalizeString(*input.Title)
@@ -102,8 +190,78 @@ func (uc *TaskUsecase) Update(ctx context.Context, userID, id string, input Task
 		task.FlagID = resolvedFlagID
 		task.SubflagID = resolvedSubflagID
 	}
+	if input.ProjectID != nil {
+		project, err := uc.resolveProject(ctx, userID, input.ProjectID)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		task.ProjectID = nil
+		if project != nil {
+			task.ProjectID = &project.ID
+		}
+	}
+	if input.Priority != nil {
+		resolvedPriority, err := normalizeTaskPriority(input.Priority)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		task.Priority = resolvedPriority
+	}
+	if input.EffortMins != nil {
+		resolvedEffort, err := normalizeTaskEffort(input.EffortMins)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		task.EffortMins = resolvedEffort
+	}
+	if input.Recurrence != nil {
+		resolvedRecurrence, err := normalizeTaskRecurrence(input.Recurrence)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		task.Recurrence = resolvedRecurrence
+	}
+	if input.BlockedBy != nil {
+		resolvedBlockedBy, blocked, err := uc.resolveTaskBlockers(ctx, userID, task.ID, *input.BlockedBy)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		task.BlockedBy = resolvedBlockedBy
+		task.Blocked = blocked
+	}
+	if input.Notification != nil {
+		override, err := normalizeNotificationOverride(input.Notification, domain.MaxNotificationLeadMins)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		task.Notification = override
+	}
+
+	// Concluir uma tarefa recorrente gera a próxima instância; a concluída fica como histórico.
+	var next *domain.Task
+	if !wasDone && task.Status == domain.TaskStatusDone && task.Recurrence != nil {
+		now := userNow(ctx, uc.Users, userID)
+		if instance, ok := nextTaskInstance(&task, now, now.Location()); ok {
+			next = &instance
+		}
+	}
 
-	return uc.Tasks.Update(ctx, task)
+	var updated domain.Task
+	if next != nil {
+		updated, err = uc.completeRecurringTask(ctx, userID, task, *next)
+	} else {
+		updated, err = uc.Tasks.Update(ctx, task)
+	}
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if input.BlockedBy != nil {
+		uc.cancelBlockedNotifications(ctx, updated)
+	}
+	if wasDone && updated.Status != domain.TaskStatusDone {
+		uc.cancelDependentNotifications(ctx, userID, updated.ID)
+	}
+	return updated, nil
 }
 
 func (uc *TaskUsecase) Delete(ctx context.Context, userID, id string) error {
@@ -127,6 +285,51 @@ func (uc *TaskUsecase) List(ctx context.Context, userID string, opts repository.
 	return uc.Tasks.List(ctx, userID, opts)
 }
 
+// Matrix groups the open tasks into the Eisenhower quadrants.
+func (uc *TaskUsecase) Matrix(ctx context.Context, userID string) (TaskMatrix, error) {
+	if userID == "" {
+		return TaskMatrix{}, ErrMissingRequiredFields
+	}
+	tasks, err := uc.Tasks.ListOpen(ctx, userID, maxMatrixTasks)
+	if err != nil {
+		return TaskMatrix{}, err
+	}
+	return buildTaskMatrix(tasks, time.Now()), nil
+}
+
+// Series lists every instance of the recurring series the task belongs to, newest first.
+func (uc *TaskUsecase) Series(ctx context.Context, userID, id string) ([]domain.Task, error) {
+	if userID == "" || id == "" {
+		return nil, ErrMissingRequiredFields
+	}
+	task, err := uc.Tasks.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.SeriesID == nil {
+		return []domain.Task{task}, nil
+	}
+	return uc.Tasks.ListSeries(ctx, userID, *task.SeriesID)
+}
+
+func (uc *TaskUsecase) resolveProject(ctx context.Context, userID string, projectID *string) (*domain.Project, error) {
+	resolvedProjectID := normalizeOptionalString(projectID)
+	if resolvedProjectID == nil {
+		return nil, nil
+	}
+	if uc.Projects == nil {
+		return nil, ErrDependencyMissing
+	}
+	project, err := uc.Projects.Get(ctx, userID, *resolvedProjectID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, ErrInvalidPayload
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
 func (uc *TaskUsecase) resolveFlagAndSubflag(ctx context.Context, userID string, flagID *string, subflagID *string) (*string, *string, error) {
 	resolvedFlagID := normalizeOptionalString(flagID)
 	resolvedSubflagID := normalizeOptionalString(subflagID)
@@ -65,6 +65,21 @@ func parseTaskStatus(value string) (domain.TaskStatus, bool) {
 	}
 }
 
+func parseTaskPriority(value string) (domain.TaskPriority, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case string(domain.TaskPriorityP1):
+		return domain.TaskPriorityP1, true
+	case string(domain.TaskPriorityP2):
+		return domain.TaskPriorityP2, true
+	case string(domain.TaskPriorityP3):
+		return domain.TaskPriorityP3, true
+	case string(domain.TaskPriorityP4):
+		return domain.TaskPriorityP4, true
+	default:
+		return "", false
+	}
+}
+
 func parseReminderStatus(value string) (domain.ReminderStatus, bool) {
 	switch strings.ToUpper(strings.TrimSpace(value)) {
 	case string(domain.ReminderStatusOpen):
@@ -89,6 +104,19 @@ func parseShoppingListStatus(value string) (domain.ShoppingListStatus, bool) {
 	}
 }
 
+func parseProjectStatus(value string) (domain.ProjectStatus, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case string(domain.ProjectStatusOpen):
+		return domain.ProjectStatusOpen, true
+	case string(domain.ProjectStatusDone):
+		return domain.ProjectStatusDone, true
+	case string(domain.ProjectStatusArchived):
+		return domain.ProjectStatusArchived, true
+	default:
+		return "", false
+	}
+}
+
 func parseSuggestionType(value string) (domain.AiSuggestionType, bool) {
 	switch strings.ToLower(strings.TrimSpace(value)) {
 	case string(domain.AiSuggestionTypeTask):
@@ -137,3 +165,59 @@ func validateDisplayName(value string) bool {
 	length := len([]rune(trimmed))
 	return length >= minLen && length <= maxLen
 }
+
+// maxBundleWindowMins caps the notification bundling window at two hours.
+const maxBundleWindowMins = 120
+
+// normalizeNotificationOverride validates lead times up to maxLead and drops duplicates.
+// A nil override resets the item to the user's defaults.
+func normalizeNotificationOverride(override *domain.NotificationOverride, maxLead int) (domain.NotificationOverride, error) {
+	if override == nil {
+		return domain.NotificationOverride{}, nil
+	}
+	if override.Disabled {
+		return domain.NotificationOverride{Disabled: true}, nil
+	}
+	if override.LeadMins == nil {
+		return domain.NotificationOverride{}, nil
+	}
+
+	seen := make(map[int]bool, len(override.LeadMins))
+	leads := make([]int, 0, len(override.LeadMins))
+	for _, lead := range override.LeadMins {
+		if lead < 0 || lead > maxLead {
+			return domain.NotificationOverride{}, ErrInvalidPayload
+		}
+		if seen[lead] {
+			continue
+		}
+		seen[lead] = true
+		leads = append(leads, lead)
+	}
+	return domain.NotificationOverride{LeadMins: leads}, nil
+}
+
+// Nag mode limits: repeats every 5 minutes to 1 day, at most 24 times.
+const (
+	minEscalationIntervalMins = 5
+	maxEscalationIntervalMins = 24 * 60
+	maxEscalationRepeats      = 24
+)
+
+// normalizeReminderEscalation validates the nag settings. A nil value or a zero
+// interval disables escalation.
+func normalizeReminderEscalation(escalation *domain.ReminderEscalation) (domain.ReminderEscalation, error) {
+	if escalation == nil || escalation.IntervalMins == 0 {
+		return domain.ReminderEscalation{}, nil
+	}
+	if escalation.IntervalMins < minEscalationIntervalMins || escalation.IntervalMins > maxEscalationIntervalMins {
+		return domain.ReminderEscalation{}, ErrInvalidPayload
+	}
+	if escalation.MaxRepeats < 1 || escalation.MaxRepeats > maxEscalationRepeats {
+		return domain.ReminderEscalation{}, ErrInvalidPayload
+	}
+	if escalation.EmailFromRepeat < 0 || escalation.EmailFromRepeat > escalation.MaxRepeats {
+		return domain.ReminderEscalation{}, ErrInvalidPayload
+	}
+	return *escalation, nil
+}
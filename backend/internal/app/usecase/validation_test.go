@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"inbota/backend/internal/app/domain"
+)
+
+func TestNormalizeNotificationOverride(t *testing.T) {
+	cases := []struct {
+		name     string
+		override *domain.NotificationOverride
+		maxLead  int
+		want     domain.NotificationOverride
+		wantErr  bool
+	}{
+		{name: "nil resets to defaults", maxLead: domain.MaxNotificationLeadMins},
+		{name: "no leads resets to defaults", override: &domain.NotificationOverride{}, maxLead: domain.MaxNotificationLeadMins},
+		{
+			name:     "disabled drops leads",
+			override: &domain.NotificationOverride{Disabled: true, LeadMins: []int{15}},
+			maxLead:  domain.MaxNotificationLeadMins,
+			want:     domain.NotificationOverride{Disabled: true},
+		},
+		{
+			name:     "duplicates are dropped in order",
+			override: &domain.NotificationOverride{LeadMins: []int{60, 0, 60, 1440}},
+			maxLead:  domain.MaxNotificationLeadMins,
+			want:     domain.NotificationOverride{LeadMins: []int{60, 0, 1440}},
+		},
+		{
+			name:     "empty list keeps an explicit no-lead override",
+			override: &domain.NotificationOverride{LeadMins: []int{}},
+			maxLead:  domain.MaxNotificationLeadMins,
+			want:     domain.NotificationOverride{LeadMins: []int{}},
+		},
+		{
+			name:     "one week is accepted",
+			override: &domain.NotificationOverride{LeadMins: []int{domain.MaxNotificationLeadMins}},
+			maxLead:  domain.MaxNotificationLeadMins,
+			want:     domain.NotificationOverride{LeadMins: []int{domain.MaxNotificationLeadMins}},
+		},
+		{
+			name:     "beyond the limit is rejected",
+			override: &domain.NotificationOverride{LeadMins: []int{domain.MaxNotificationLeadMins + 1}},
+			maxLead:  domain.MaxNotificationLeadMins,
+			wantErr:  true,
+		},
+		{
+			name:     "routines stop at one day",
+			override: &domain.NotificationOverride{LeadMins: []int{2 * 24 * 60}},
+			maxLead:  domain.MaxRoutineLeadMins,
+			wantErr:  true,
+		},
+		{
+			name:     "negative is rejected",
+			override: &domain.NotificationOverride{LeadMins: []int{-5}},
+			maxLead:  domain.MaxNotificationLeadMins,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := normalizeNotificationOverride(tc.override, tc.maxLead)
+			if tc.wantErr {
+				if err != ErrInvalidPayload {
+					t.Fatalf("expected ErrInvalidPayload, got %+v, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("normalizeNotificationOverride = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
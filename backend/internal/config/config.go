@@ -17,6 +17,7 @@ type Config struct {
 	LogLevel                string
 	DatabaseURL             string
 	JWTSecret               string
+	AdminUserIDs            []string
 	AIProvider              string
 	AIAPIKey                string
 	AIBaseURL               string
@@ -26,9 +27,32 @@ type Config struct {
 	AITimeout               time.Duration
 	AIMaxRetries            int
 
+	// MailProvider selects the e-mail backend: "resend" (default) or "smtp".
+	MailProvider      string
 	ResendAPIKey      string
 	ResendFrom        string
+	SMTPHost          string
+	SMTPPort          int
+	SMTPUsername      string
+	SMTPPassword      string
+	SMTPFrom          string
+	SMTPStartTLS      bool
 	DigestJobInterval time.Duration
+	DigestMaxAttempts int
+
+	// PublicBaseURL is the public API origin used in e-mail links (unsubscribe).
+	PublicBaseURL       string
+	UnsubscribeSecret   string
+	ResendWebhookSecret string
+
+	NtfyBaseURL    string
+	NtfyToken      string
+	NtfyUsername   string
+	NtfyPassword   string
+	NtfyEncrypt    bool
+	NtfyPriorities map[string]string
+
+	MetricsEnabled bool
 
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
@@ -44,6 +68,7 @@ func Load() (Config, error) {
 		LogLevel:                strings.ToLower(getEnv("LOG_LEVEL", "info")),
 		DatabaseURL:             getEnv("DATABASE_URL", ""),
 		JWTSecret:               getEnv("JWT_SECRET", ""),
+		AdminUserIDs:            getEnvList("ADMIN_USER_IDS"),
 		AIProvider:              getEnv("AI_PROVIDER", ""),
 		AIAPIKey:                getEnv("AI_API_KEY", ""),
 		AIBaseURL:               getEnv("AI_BASE_URL", ""),
@@ -53,9 +78,30 @@ func Load() (Config, error) {
 		AITimeout:               getEnvDuration("AI_TIMEOUT", 15*time.Second),
 		AIMaxRetries:            getEnvInt("AI_MAX_RETRIES", 2),
 
+		MailProvider:      strings.ToLower(getEnv("MAIL_PROVIDER", "resend")),
 		ResendAPIKey:      getEnv("RESEND_API_KEY", ""),
 		ResendFrom:        getEnv("RESEND_FROM", "Inbota <noreply@resend.dev>"),
+		SMTPHost:          getEnv("SMTP_HOST", ""),
+		SMTPPort:          getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:      getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:      getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:          getEnv("SMTP_FROM", ""),
+		SMTPStartTLS:      getEnvBool("SMTP_STARTTLS", true),
 		DigestJobInterval: getEnvDuration("DIGEST_JOB_INTERVAL", 30*time.Minute),
+		DigestMaxAttempts: getEnvInt("DIGEST_MAX_ATTEMPTS", 3),
+
+		PublicBaseURL:       getEnv("PUBLIC_BASE_URL", ""),
+		UnsubscribeSecret:   getEnv("UNSUBSCRIBE_SECRET", ""),
+		ResendWebhookSecret: getEnv("RESEND_WEBHOOK_SECRET", ""),
+
+		NtfyBaseURL:    getEnv("NTFY_BASE_URL", "https://ntfy.sh"),
+		NtfyToken:      getEnv("NTFY_TOKEN", ""),
+		NtfyUsername:   getEnv("NTFY_USERNAME", ""),
+		NtfyPassword:   getEnv("NTFY_PASSWORD", ""),
+		NtfyEncrypt:    getEnvBool("NTFY_ENCRYPT", false),
+		NtfyPriorities: getEnvMap("NTFY_PRIORITIES"),
+
+		MetricsEnabled: getEnvBool("METRICS_ENABLED", false),
 
 		ReadTimeout:  getEnvDuration("READ_TIMEOUT", 5*time.Second),
 		WriteTimeout: getEnvDuration("WRITE_TIMEOUT", 10*time.Second),
@@ -68,6 +114,21 @@ func Load() (Config, error) {
 	if cfg.DigestJobInterval <= 0 {
 		return Config{}, errors.New("DIGEST_JOB_INTERVAL must be > 0")
 	}
+	if cfg.DigestMaxAttempts <= 0 {
+		return Config{}, errors.New("DIGEST_MAX_ATTEMPTS must be > 0")
+	}
+	if cfg.UnsubscribeSecret == "" {
+		cfg.UnsubscribeSecret = cfg.JWTSecret
+	}
+	switch cfg.MailProvider {
+	case "resend":
+	case "smtp":
+		if cfg.SMTPHost == "" || cfg.SMTPFrom == "" {
+			return Config{}, errors.New("SMTP_HOST and SMTP_FROM are required when MAIL_PROVIDER=smtp")
+		}
+	default:
+		return Config{}, fmt.Errorf("unsupported MAIL_PROVIDER %q", cfg.MailProvider)
+	}
 
 	return cfg, nil
 }
@@ -79,6 +140,31 @@ func getEnv(key, def string) string {
 	return def
 }
 
+func getEnvList(key string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if v := strings.TrimSpace(part); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// getEnvMap parses "key=value" pairs separated by commas.
+func getEnvMap(key string) map[string]string {
+	out := make(map[string]string)
+	for _, part := range getEnvList(key) {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		if k, v = strings.TrimSpace(k), strings.TrimSpace(v); k != "" && v != "" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
 func getEnvInt(key string, def int) int {
 	v := strings.TrimSpace(os.Getenv(key))
 	if v == "" {
@@ -29,11 +29,12 @@ type LoginRequest struct {
 type AuthResponse struct {
 	Token string `json:"token"`
 	User  struct {
-		ID          string `json:"id"`
-		Email       string `json:"email"`
-		DisplayName string `json:"displayName"`
-		Locale      string `json:"locale"`
-		Timezone    string `json:"timezone"`
+		ID              string `json:"id"`
+		Email           string `json:"email"`
+		DisplayName     string `json:"displayName"`
+		Locale          string `json:"locale"`
+		Timezone        string `json:"timezone"`
+		HolidayCalendar string `json:"holidayCalendar"`
 	} `json:"user"`
 }
 
@@ -192,16 +193,73 @@ type ConfirmInboxItemResponse struct {
 // Tasks
 
 type TaskResponse struct {
-	ID              string           `json:"id"`
-	Title           string           `json:"title"`
-	Description     *string          `json:"description,omitempty"`
-	Status          string           `json:"status"`
-	DueAt           *time.Time       `json:"dueAt,omitempty"`
-	Flag            *FlagObject      `json:"flag,omitempty"`
-	Subflag         *SubflagObject   `json:"subflag,omitempty"`
-	SourceInboxItem *InboxItemObject `json:"sourceInboxItem,omitempty"`
-	CreatedAt       time.Time        `json:"createdAt"`
-	UpdatedAt       time.Time        `json:"updatedAt"`
+	ID              string                      `json:"id"`
+	Title           string                      `json:"title"`
+	Description     *string                     `json:"description,omitempty"`
+	Status          string                      `json:"status"`
+	DueAt           *time.Time                  `json:"dueAt,omitempty"`
+	Flag            *FlagObject                 `json:"flag,omitempty"`
+	Subflag         *SubflagObject              `json:"subflag,omitempty"`
+	SourceInboxItem *InboxItemObject            `json:"sourceInboxItem,omitempty"`
+	Project         *ProjectObject              `json:"project,omitempty"`
+	Priority        *string                     `json:"priority,omitempty"`
+	EffortMins      *int                        `json:"effortMins,omitempty"`
+	Recurrence      *TaskRecurrenceObject       `json:"recurrence,omitempty"`
+	SeriesID        *string                     `json:"seriesId,omitempty"`
+	BlockedBy       []string                    `json:"blockedBy,omitempty"`
+	Blocked         bool                        `json:"blocked"`
+	Notification    NotificationOverrideObject  `json:"notification"`
+	Checklist       []TaskChecklistItemResponse `json:"checklist,omitempty"`
+	Progress        *TaskProgressObject         `json:"progress,omitempty"`
+	CreatedAt       time.Time                   `json:"createdAt"`
+	UpdatedAt       time.Time                   `json:"updatedAt"`
+}
+
+// TaskRecurrenceObject repeats a task on completion: a fixed RRULE or N days after completion.
+type TaskRecurrenceObject struct {
+	Rule      *string `json:"rule,omitempty"`
+	AfterDays *int    `json:"afterDays,omitempty"`
+}
+
+// TaskMatrixResponse groups the open tasks into the Eisenhower quadrants.
+type TaskMatrixResponse struct {
+	UrgentImportant       []TaskResponse `json:"urgentImportant"`
+	NotUrgentImportant    []TaskResponse `json:"notUrgentImportant"`
+	UrgentNotImportant    []TaskResponse `json:"urgentNotImportant"`
+	NotUrgentNotImportant []TaskResponse `json:"notUrgentNotImportant"`
+}
+
+// TaskProgressObject counts the checked checklist steps of a task.
+type TaskProgressObject struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type TaskChecklistItemResponse struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"taskId"`
+	Title     string    `json:"title"`
+	Checked   bool      `json:"checked"`
+	SortOrder int       `json:"sortOrder"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ListTaskChecklistItemsResponse struct {
+	Items    []TaskChecklistItemResponse `json:"items"`
+	Progress TaskProgressObject          `json:"progress"`
+}
+
+type CreateTaskChecklistItemRequest struct {
+	Title     string `json:"title"`
+	Checked   *bool  `json:"checked,omitempty"`
+	SortOrder *int   `json:"sortOrder,omitempty"`
+}
+
+type UpdateTaskChecklistItemRequest struct {
+	Title     *string `json:"title,omitempty"`
+	Checked   *bool   `json:"checked,omitempty"`
+	SortOrder *int    `json:"sortOrder,omitempty"`
 }
 
 type ListTasksResponse struct {
@@ -210,35 +268,123 @@ type ListTasksResponse struct {
 }
 
 type CreateTaskRequest struct {
-	Title       string     `json:"title"`
-	Description *string    `json:"description,omitempty"`
-	Status      *string    `json:"status,omitempty"`
-	DueAt       *time.Time `json:"dueAt,omitempty"`
-	FlagID      *string    `json:"flagId,omitempty"`
-	SubflagID   *string    `json:"subflagId,omitempty"`
+	Title        string                       `json:"title"`
+	Description  *string                      `json:"description,omitempty"`
+	Status       *string                      `json:"status,omitempty"`
+	DueAt        *time.Time                   `json:"dueAt,omitempty"`
+	FlagID       *string                      `json:"flagId,omitempty"`
+	SubflagID    *string                      `json:"subflagId,omitempty"`
+	ProjectID    *string                      `json:"projectId,omitempty"`
+	Priority     *string                      `json:"priority,omitempty"`
+	EffortMins   *int                         `json:"effortMins,omitempty"`
+	Recurrence   *TaskRecurrenceObject        `json:"recurrence,omitempty"`
+	BlockedBy    []string                     `json:"blockedBy,omitempty"`
+	Notification *NotificationOverrideRequest `json:"notification,omitempty"`
 }
 
 type UpdateTaskRequest struct {
-	Title       *string    `json:"title,omitempty"`
-	Description *string    `json:"description,omitempty"`
-	Status      *string    `json:"status,omitempty"`
-	DueAt       *time.Time `json:"dueAt,omitempty"`
-	FlagID      *string    `json:"flagId,omitempty"`
-	SubflagID   *string    `json:"subflagId,omitempty"`
+	Title        *string                      `json:"title,omitempty"`
+	Description  *string                      `json:"description,omitempty"`
+	Status       *string                      `json:"status,omitempty"`
+	DueAt        *time.Time                   `json:"dueAt,omitempty"`
+	FlagID       *string                      `json:"flagId,omitempty"`
+	SubflagID    *string                      `json:"subflagId,omitempty"`
+	ProjectID    *string                      `json:"projectId,omitempty"`
+	Priority     *string                      `json:"priority,omitempty"`
+	EffortMins   *int                         `json:"effortMins,omitempty"`
+	Recurrence   *TaskRecurrenceObject        `json:"recurrence,omitempty"`
+	BlockedBy    *[]string                    `json:"blockedBy,omitempty"`
+	Notification *NotificationOverrideRequest `json:"notification,omitempty"`
+}
+
+// NotificationOverrideObject is the per-item notification setting.
+// leadMins null means the user's notification preferences are used.
+type NotificationOverrideObject struct {
+	LeadMins []int `json:"leadMins"`
+	Disabled bool  `json:"disabled"`
+}
+
+// NotificationOverrideRequest replaces the per-item notification setting.
+// Send {"leadMins": null, "disabled": false} to go back to the defaults.
+type NotificationOverrideRequest struct {
+	LeadMins []int `json:"leadMins"`
+	Disabled bool  `json:"disabled"`
+}
+
+// Projects
+
+type ProjectObject struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+}
+
+// ProjectProgressObject counts the DONE tasks of a project; percent is 0 without tasks.
+type ProjectProgressObject struct {
+	Done    int `json:"done"`
+	Total   int `json:"total"`
+	Percent int `json:"percent"`
+}
+
+type ProjectResponse struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description *string               `json:"description,omitempty"`
+	Status      string                `json:"status"`
+	TargetDate  *string               `json:"targetDate,omitempty"`
+	Flag        *FlagObject           `json:"flag,omitempty"`
+	Subflag     *SubflagObject        `json:"subflag,omitempty"`
+	Progress    ProjectProgressObject `json:"progress"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt"`
+}
+
+type ListProjectsResponse struct {
+	Items      []ProjectResponse `json:"items"`
+	NextCursor *string           `json:"nextCursor,omitempty"`
+}
+
+type CreateProjectRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty"`
+	TargetDate  *string `json:"targetDate,omitempty"`
+	FlagID      *string `json:"flagId,omitempty"`
+	SubflagID   *string `json:"subflagId,omitempty"`
+}
+
+type UpdateProjectRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty"`
+	TargetDate  *string `json:"targetDate,omitempty"`
+	FlagID      *string `json:"flagId,omitempty"`
+	SubflagID   *string `json:"subflagId,omitempty"`
 }
 
 // Reminders
 
 type ReminderResponse struct {
-	ID              string           `json:"id"`
-	Title           string           `json:"title"`
-	Status          string           `json:"status"`
-	RemindAt        *time.Time       `json:"remindAt,omitempty"`
-	Flag            *FlagObject      `json:"flag,omitempty"`
-	Subflag         *SubflagObject   `json:"subflag,omitempty"`
-	SourceInboxItem *InboxItemObject `json:"sourceInboxItem,omitempty"`
-	CreatedAt       time.Time        `json:"createdAt"`
-	UpdatedAt       time.Time        `json:"updatedAt"`
+	ID              string                     `json:"id"`
+	Title           string                     `json:"title"`
+	Status          string                     `json:"status"`
+	RemindAt        *time.Time                 `json:"remindAt,omitempty"`
+	Flag            *FlagObject                `json:"flag,omitempty"`
+	Subflag         *SubflagObject             `json:"subflag,omitempty"`
+	SourceInboxItem *InboxItemObject           `json:"sourceInboxItem,omitempty"`
+	Notification    NotificationOverrideObject `json:"notification"`
+	Escalation      ReminderEscalationObject   `json:"escalation"`
+	CreatedAt       time.Time                  `json:"createdAt"`
+	UpdatedAt       time.Time                  `json:"updatedAt"`
+}
+
+// ReminderEscalationObject is the reminder "nag" mode: repeat every intervalMins, up to
+// maxRepeats times, until DONE or read. From repeat emailFromRepeat on (0 = never) it goes by e-mail.
+// Send intervalMins 0 to disable it.
+type ReminderEscalationObject struct {
+	IntervalMins    int `json:"intervalMins"`
+	MaxRepeats      int `json:"maxRepeats"`
+	EmailFromRepeat int `json:"emailFromRepeat"`
 }
 
 type ListRemindersResponse struct {
@@ -247,35 +393,40 @@ type ListRemindersResponse struct {
 }
 
 type CreateReminderRequest struct {
-	Title     string     `json:"title"`
-	Status    *string    `json:"status,omitempty"`
-	RemindAt  *time.Time `json:"remindAt,omitempty"`
-	FlagID    *string    `json:"flagId,omitempty"`
-	SubflagID *string    `json:"subflagId,omitempty"`
+	Title        string                       `json:"title"`
+	Status       *string                      `json:"status,omitempty"`
+	RemindAt     *time.Time                   `json:"remindAt,omitempty"`
+	FlagID       *string                      `json:"flagId,omitempty"`
+	SubflagID    *string                      `json:"subflagId,omitempty"`
+	Notification *NotificationOverrideRequest `json:"notification,omitempty"`
+	Escalation   *ReminderEscalationObject    `json:"escalation,omitempty"`
 }
 
 type UpdateReminderRequest struct {
-	Title     *string    `json:"title,omitempty"`
-	Status    *string    `json:"status,omitempty"`
-	RemindAt  *time.Time `json:"remindAt,omitempty"`
-	FlagID    *string    `json:"flagId,omitempty"`
-	SubflagID *string    `json:"subflagId,omitempty"`
+	Title        *string                      `json:"title,omitempty"`
+	Status       *string                      `json:"status,omitempty"`
+	RemindAt     *time.Time                   `json:"remindAt,omitempty"`
+	FlagID       *string                      `json:"flagId,omitempty"`
+	SubflagID    *string                      `json:"subflagId,omitempty"`
+	Notification *NotificationOverrideRequest `json:"notification,omitempty"`
+	Escalation   *ReminderEscalationObject    `json:"escalation,omitempty"`
 }
 
 // Events
 
 type EventResponse struct {
-	ID              string           `json:"id"`
-	Title           string           `json:"title"`
-	StartAt         *time.Time       `json:"startAt,omitempty"`
-	EndAt           *time.Time       `json:"endAt,omitempty"`
-	AllDay          bool             `json:"allDay"`
-	Location        *string          `json:"location,omitempty"`
-	Flag            *FlagObject      `json:"flag,omitempty"`
-	Subflag         *SubflagObject   `json:"subflag,omitempty"`
-	SourceInboxItem *InboxItemObject `json:"sourceInboxItem,omitempty"`
-	CreatedAt       time.Time        `json:"createdAt"`
-	UpdatedAt       time.Time        `json:"updatedAt"`
+	ID              string                     `json:"id"`
+	Title           string                     `json:"title"`
+	StartAt         *time.Time                 `json:"startAt,omitempty"`
+	EndAt           *time.Time                 `json:"endAt,omitempty"`
+	AllDay          bool                       `json:"allDay"`
+	Location        *string                    `json:"location,omitempty"`
+	Flag            *FlagObject                `json:"flag,omitempty"`
+	Subflag         *SubflagObject             `json:"subflag,omitempty"`
+	SourceInboxItem *InboxItemObject           `json:"sourceInboxItem,omitempty"`
+	Notification    NotificationOverrideObject `json:"notification"`
+	CreatedAt       time.Time                  `json:"createdAt"`
+	UpdatedAt       time.Time                  `json:"updatedAt"`
 }
 
 type ListEventsResponse struct {
@@ -287,6 +438,7 @@ type AgendaResponse struct {
 	Events    []EventResponse    `json:"events"`
 	Tasks     []TaskResponse     `json:"tasks"`
 	Reminders []ReminderResponse `json:"reminders"`
+	Holidays  []HolidayResponse  `json:"holidays"`
 }
 
 // Home
@@ -294,6 +446,7 @@ type AgendaResponse struct {
 type HomeDayProgressResponse struct {
 	RoutinesDone    int     `json:"routines_done"`
 	RoutinesTotal   int     `json:"routines_total"`
+	RoutinesPartial int     `json:"routines_partial"`
 	TasksDone       int     `json:"tasks_done"`
 	TasksTotal      int     `json:"tasks_total"`
 	ProgressPercent float64 `json:"progress_percent"`
@@ -337,23 +490,25 @@ type HomeDashboardResponse struct {
 }
 
 type CreateEventRequest struct {
-	Title     string     `json:"title"`
-	StartAt   *time.Time `json:"startAt,omitempty"`
-	EndAt     *time.Time `json:"endAt,omitempty"`
-	AllDay    *bool      `json:"allDay,omitempty"`
-	Location  *string    `json:"location,omitempty"`
-	FlagID    *string    `json:"flagId,omitempty"`
-	SubflagID *string    `json:"subflagId,omitempty"`
+	Title        string                       `json:"title"`
+	StartAt      *time.Time                   `json:"startAt,omitempty"`
+	EndAt        *time.Time                   `json:"endAt,omitempty"`
+	AllDay       *bool                        `json:"allDay,omitempty"`
+	Location     *string                      `json:"location,omitempty"`
+	FlagID       *string                      `json:"flagId,omitempty"`
+	SubflagID    *string                      `json:"subflagId,omitempty"`
+	Notification *NotificationOverrideRequest `json:"notification,omitempty"`
 }
 
 type UpdateEventRequest struct {
-	Title     *string    `json:"title,omitempty"`
-	StartAt   *time.Time `json:"startAt,omitempty"`
-	EndAt     *time.Time `json:"endAt,omitempty"`
-	AllDay    *bool      `json:"allDay,omitempty"`
-	Location  *string    `json:"location,omitempty"`
-	FlagID    *string    `json:"flagId,omitempty"`
-	SubflagID *string    `json:"subflagId,omitempty"`
+	Title        *string                      `json:"title,omitempty"`
+	StartAt      *time.Time                   `json:"startAt,omitempty"`
+	EndAt        *time.Time                   `json:"endAt,omitempty"`
+	AllDay       *bool                        `json:"allDay,omitempty"`
+	Location     *string                      `json:"location,omitempty"`
+	FlagID       *string                      `json:"flagId,omitempty"`
+	SubflagID    *string                      `json:"subflagId,omitempty"`
+	Notification *NotificationOverrideRequest `json:"notification,omitempty"`
 }
 
 // Shopping
@@ -432,23 +587,51 @@ type UpdateShoppingItemRequest struct {
 // Routines
 
 type RoutineResponse struct {
-	ID               string         `json:"id"`
-	Title            string         `json:"title"`
-	Description      *string        `json:"description,omitempty"`
-	RecurrenceType   string         `json:"recurrenceType"`
-	Weekdays         []int          `json:"weekdays"`
-	StartTime        string         `json:"startTime"`
-	EndTime          string         `json:"endTime"`
-	WeekOfMonth      *int           `json:"weekOfMonth,omitempty"`
-	StartsOn         string         `json:"startsOn"`
-	EndsOn           *string        `json:"endsOn,omitempty"`
-	Color            *string        `json:"color,omitempty"`
-	IsActive         bool           `json:"isActive"`
-	IsCompletedToday bool           `json:"isCompletedToday"`
-	Flag             *FlagObject    `json:"flag,omitempty"`
-	Subflag          *SubflagObject `json:"subflag,omitempty"`
-	CreatedAt        time.Time      `json:"createdAt"`
-	UpdatedAt        time.Time      `json:"updatedAt"`
+	ID               string                     `json:"id"`
+	Title            string                     `json:"title"`
+	Description      *string                    `json:"description,omitempty"`
+	RecurrenceType   string                     `json:"recurrenceType"`
+	Weekdays         []int                      `json:"weekdays"`
+	StartTime        string                     `json:"startTime"`
+	EndTime          string                     `json:"endTime"`
+	WeekOfMonth      *int                       `json:"weekOfMonth,omitempty"`
+	RRule            *string                    `json:"rrule,omitempty"`
+	Target           *RoutineTargetObject       `json:"target,omitempty"`
+	StartsOn         string                     `json:"startsOn"`
+	EndsOn           *string                    `json:"endsOn,omitempty"`
+	Color            *string                    `json:"color,omitempty"`
+	IsActive         bool                       `json:"isActive"`
+	IsCompletedToday bool                       `json:"isCompletedToday"`
+	ProgressToday    *float64                   `json:"progressToday,omitempty"`
+	Flag             *FlagObject                `json:"flag,omitempty"`
+	Subflag          *SubflagObject             `json:"subflag,omitempty"`
+	Notification     NotificationOverrideObject `json:"notification"`
+	StreakProtection StreakProtectionObject     `json:"streakProtection"`
+	SkipHolidays     bool                       `json:"skipHolidays"`
+	CreatedAt        time.Time                  `json:"createdAt"`
+	UpdatedAt        time.Time                  `json:"updatedAt"`
+}
+
+// StreakProtectionObject: gracePerMonth faltas perdoadas por mês e um congelamento a cada
+// freezeEvery dias seguidos feitos (0 = desligado). No PATCH, substitui a configuração inteira.
+type StreakProtectionObject struct {
+	GracePerMonth int `json:"gracePerMonth"`
+	FreezeEvery   int `json:"freezeEvery"`
+}
+
+// RoutineTargetObject is the quantitative goal of a routine (kind: count | duration | amount).
+type RoutineTargetObject struct {
+	Kind  string  `json:"kind"`
+	Value float64 `json:"value"`
+	Unit  *string `json:"unit,omitempty"`
+}
+
+// RoutineTargetRequest sets the routine goal. On update, omitted fields keep their value and
+// {"kind": ""} removes the goal.
+type RoutineTargetRequest struct {
+	Kind  *string  `json:"kind,omitempty"`
+	Value *float64 `json:"value,omitempty"`
+	Unit  *string  `json:"unit,omitempty"`
 }
 
 type ListRoutinesResponse struct {
@@ -457,33 +640,43 @@ type ListRoutinesResponse struct {
 }
 
 type CreateRoutineRequest struct {
-	Title          string  `json:"title"`
-	Description    *string `json:"description,omitempty"`
-	RecurrenceType *string `json:"recurrenceType,omitempty"`
-	Weekdays       []int   `json:"weekdays"`
-	StartTime      string  `json:"startTime"`
-	EndTime        string  `json:"endTime"`
-	WeekOfMonth    *int    `json:"weekOfMonth,omitempty"`
-	StartsOn       *string `json:"startsOn,omitempty"`
-	EndsOn         *string `json:"endsOn,omitempty"`
-	Color          *string `json:"color,omitempty"`
-	FlagID         *string `json:"flagId,omitempty"`
-	SubflagID      *string `json:"subflagId,omitempty"`
+	Title            string                       `json:"title"`
+	Description      *string                      `json:"description,omitempty"`
+	RecurrenceType   *string                      `json:"recurrenceType,omitempty"`
+	Weekdays         []int                        `json:"weekdays"`
+	StartTime        string                       `json:"startTime"`
+	EndTime          string                       `json:"endTime"`
+	WeekOfMonth      *int                         `json:"weekOfMonth,omitempty"`
+	RRule            *string                      `json:"rrule,omitempty"`
+	Target           *RoutineTargetRequest        `json:"target,omitempty"`
+	StartsOn         *string                      `json:"startsOn,omitempty"`
+	EndsOn           *string                      `json:"endsOn,omitempty"`
+	Color            *string                      `json:"color,omitempty"`
+	FlagID           *string                      `json:"flagId,omitempty"`
+	SubflagID        *string                      `json:"subflagId,omitempty"`
+	Notification     *NotificationOverrideRequest `json:"notification,omitempty"`
+	StreakProtection *StreakProtectionObject      `json:"streakProtection,omitempty"`
+	SkipHolidays     *bool                        `json:"skipHolidays,omitempty"`
 }
 
 type UpdateRoutineRequest struct {
-	Title          *string `json:"title,omitempty"`
-	Description    *string `json:"description,omitempty"`
-	RecurrenceType *string `json:"recurrenceType,omitempty"`
-	Weekdays       *[]int  `json:"weekdays,omitempty"`
-	StartTime      *string `json:"startTime,omitempty"`
-	EndTime        *string `json:"endTime,omitempty"`
-	WeekOfMonth    *int    `json:"weekOfMonth,omitempty"`
-	StartsOn       *string `json:"startsOn,omitempty"`
-	EndsOn         *string `json:"endsOn,omitempty"`
-	Color          *string `json:"color,omitempty"`
-	FlagID         *string `json:"flagId,omitempty"`
-	SubflagID      *string `json:"subflagId,omitempty"`
+	Title            *string                      `json:"title,omitempty"`
+	Description      *string                      `json:"description,omitempty"`
+	RecurrenceType   *string                      `json:"recurrenceType,omitempty"`
+	Weekdays         *[]int                       `json:"weekdays,omitempty"`
+	StartTime        *string                      `json:"startTime,omitempty"`
+	EndTime          *string                      `json:"endTime,omitempty"`
+	WeekOfMonth      *int                         `json:"weekOfMonth,omitempty"`
+	RRule            *string                      `json:"rrule,omitempty"`
+	Target           *RoutineTargetRequest        `json:"target,omitempty"`
+	StartsOn         *string                      `json:"startsOn,omitempty"`
+	EndsOn           *string                      `json:"endsOn,omitempty"`
+	Color            *string                      `json:"color,omitempty"`
+	FlagID           *string                      `json:"flagId,omitempty"`
+	SubflagID        *string                      `json:"subflagId,omitempty"`
+	Notification     *NotificationOverrideRequest `json:"notification,omitempty"`
+	StreakProtection *StreakProtectionObject      `json:"streakProtection,omitempty"`
+	SkipHolidays     *bool                        `json:"skipHolidays,omitempty"`
 }
 
 type RoutineExceptionResponse struct {
@@ -501,6 +694,7 @@ type RoutineCompletionResponse struct {
 	ID          string    `json:"id"`
 	RoutineID   string    `json:"routineId"`
 	CompletedOn string    `json:"completedOn"`
+	Value       float64   `json:"value"`
 	CompletedAt time.Time `json:"completedAt"`
 }
 
@@ -513,18 +707,27 @@ type CreateRoutineExceptionRequest struct {
 }
 
 type RoutineActivityDay struct {
-	Date         string `json:"date"`
-	IsCompleted  bool   `json:"isCompleted"`
-	IsScheduled  bool   `json:"isScheduled"`
-	IsToday      bool   `json:"isToday"`
-	IsSkipped    bool   `json:"isSkipped"`
-	WeekdayLabel string `json:"weekdayLabel"`
+	Date         string  `json:"date"`
+	IsCompleted  bool    `json:"isCompleted"`
+	IsPartial    bool    `json:"isPartial"`
+	Progress     float64 `json:"progress"`
+	IsScheduled  bool    `json:"isScheduled"`
+	IsToday      bool    `json:"isToday"`
+	IsSkipped    bool    `json:"isSkipped"`
+	IsRest       bool    `json:"isRest"`
+	IsFrozen     bool    `json:"isFrozen"`
+	IsAway       bool    `json:"isAway"`
+	IsHoliday    bool    `json:"isHoliday"`
+	WeekdayLabel string  `json:"weekdayLabel"`
 }
 
 type RoutineStreakResponse struct {
 	CurrentStreak    int                  `json:"currentStreak"`
+	BestStreak       int                  `json:"bestStreak"`
 	TotalCompletions int                  `json:"totalCompletions"`
 	StreakText       string               `json:"streakText"`
+	FreezesAvailable int                  `json:"freezesAvailable"`
+	GraceLeft        int                  `json:"graceLeft"` // faltas perdoadas restantes no mês
 	Activity         []RoutineActivityDay `json:"activity"`
 }
 
@@ -533,12 +736,116 @@ type RoutineTodaySummaryResponse struct {
 	Completed int `json:"completed"`
 }
 
+type RoutinePeriodStats struct {
+	Start          string  `json:"start"`
+	End            string  `json:"end"`
+	Scheduled      int     `json:"scheduled"`
+	Completed      int     `json:"completed"`
+	CompletionRate float64 `json:"completionRate"`
+}
+
+type RoutineTrend struct {
+	Current  float64 `json:"current"`
+	Previous float64 `json:"previous"`
+	Delta    float64 `json:"delta"`
+}
+
+type RoutineHeatmapDay struct {
+	Date      string `json:"date"`
+	Scheduled int    `json:"scheduled"`
+	Completed int    `json:"completed"`
+	Skipped   int    `json:"skipped"`
+}
+
+type RoutineStatsSummary struct {
+	RoutineID      string  `json:"routineId"`
+	Title          string  `json:"title"`
+	Scheduled      int     `json:"scheduled"`
+	Completed      int     `json:"completed"`
+	CompletionRate float64 `json:"completionRate"`
+}
+
+// RoutineStatsResponse is returned by /routines/{id}/stats and /routines/stats (the latter
+// without bestStreak and with the per-routine breakdown in routines).
+type RoutineStatsResponse struct {
+	From           string                `json:"from"`
+	To             string                `json:"to"`
+	Scheduled      int                   `json:"scheduled"`
+	Completed      int                   `json:"completed"`
+	Skipped        int                   `json:"skipped"`
+	CompletionRate float64               `json:"completionRate"`
+	BestStreak     *int                  `json:"bestStreak,omitempty"`
+	BestWeekday    *int                  `json:"bestWeekday,omitempty"`
+	BestTimeSlot   *string               `json:"bestTimeSlot,omitempty"`
+	Weekly         []RoutinePeriodStats  `json:"weekly"`
+	Monthly        []RoutinePeriodStats  `json:"monthly"`
+	WeekTrend      RoutineTrend          `json:"weekTrend"`
+	MonthTrend     RoutineTrend          `json:"monthTrend"`
+	Heatmap        []RoutineHeatmapDay   `json:"heatmap"`
+	Routines       []RoutineStatsSummary `json:"routines,omitempty"`
+}
+
 type ToggleRoutineRequest struct {
 	IsActive bool `json:"isActive"`
 }
 
 type CompleteRoutineRequest struct {
-	Date string `json:"date,omitempty"`
+	Date  string   `json:"date,omitempty"`
+	Value *float64 `json:"value,omitempty"` // progresso do check-in em rotinas com meta (padrão 1)
+}
+
+// Away (modo férias)
+
+// AwayPeriodResponse: startsOn/endsOn são datas inclusivas no timezone do usuário. Com flag,
+// só as rotinas e tarefas daquela flag ficam pausadas.
+type AwayPeriodResponse struct {
+	ID        string      `json:"id"`
+	StartsOn  string      `json:"startsOn"`
+	EndsOn    string      `json:"endsOn"`
+	Flag      *FlagObject `json:"flag,omitempty"`
+	Reason    *string     `json:"reason,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+// ListAwayPeriodsResponse traz em current o período que vale hoje, se houver.
+type ListAwayPeriodsResponse struct {
+	Items   []AwayPeriodResponse `json:"items"`
+	Current *AwayPeriodResponse  `json:"current,omitempty"`
+}
+
+type CreateAwayPeriodRequest struct {
+	StartsOn string  `json:"startsOn"`
+	EndsOn   string  `json:"endsOn"`
+	FlagID   *string `json:"flagId,omitempty"`
+	Reason   *string `json:"reason,omitempty"`
+}
+
+// Holidays (feriados)
+
+// HolidayResponse: scope é national | state | municipal.
+type HolidayResponse struct {
+	Date  string `json:"date"`
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+type HolidayCalendarResponse struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type ListHolidayCalendarsResponse struct {
+	Items []HolidayCalendarResponse `json:"items"`
+}
+
+type ListHolidaysResponse struct {
+	Calendar HolidayCalendarResponse `json:"calendar"`
+	Items    []HolidayResponse       `json:"items"`
+}
+
+type UpdateHolidayCalendarRequest struct {
+	Code string `json:"code"`
 }
 
 // Devices
@@ -550,8 +857,12 @@ type RegisterTokenRequest struct {
 	AppVersion *string `json:"appVersion,omitempty"`
 }
 
+// RegisterTokenResponse tells the app where to subscribe. encryptionKey (base64, AES-256-GCM)
+// is only returned when message encryption is enabled on the server.
 type RegisterTokenResponse struct {
-	Topic string `json:"topic"`
+	Topic         string  `json:"topic"`
+	Server        string  `json:"server"`
+	EncryptionKey *string `json:"encryptionKey,omitempty"`
 }
 
 type UnregisterTokenRequest struct {
@@ -561,24 +872,32 @@ type UnregisterTokenRequest struct {
 // Notifications
 
 type NotificationPreferencesResponse struct {
-	RemindersEnabled   bool      `json:"remindersEnabled"`
-	ReminderAtTime     bool      `json:"reminderAtTime"`
-	ReminderLeadMins   []int     `json:"reminderLeadMins"`
-	EventsEnabled      bool      `json:"eventsEnabled"`
-	EventAtTime        bool      `json:"eventAtTime"`
-	EventLeadMins      []int     `json:"eventLeadMins"`
-	TasksEnabled       bool      `json:"tasksEnabled"`
-	TaskAtTime         bool      `json:"taskAtTime"`
-	TaskLeadMins       []int     `json:"taskLeadMins"`
-	RoutinesEnabled    bool      `json:"routinesEnabled"`
-	RoutineAtTime      bool      `json:"routineAtTime"`
-	RoutineLeadMins    []int     `json:"routineLeadMins"`
-	QuietHoursEnabled  bool      `json:"quietHoursEnabled"`
-	QuietStart         *string   `json:"quietStart,omitempty"` // "HH:MM"
-	QuietEnd           *string   `json:"quietEnd,omitempty"`   // "HH:MM"
-	DailyDigestEnabled bool      `json:"dailyDigestEnabled"`
-	DailyDigestHour    int       `json:"dailyDigestHour"`
-	UpdatedAt          time.Time `json:"updatedAt"`
+	RemindersEnabled       bool      `json:"remindersEnabled"`
+	ReminderAtTime         bool      `json:"reminderAtTime"`
+	ReminderLeadMins       []int     `json:"reminderLeadMins"`
+	EventsEnabled          bool      `json:"eventsEnabled"`
+	EventAtTime            bool      `json:"eventAtTime"`
+	EventLeadMins          []int     `json:"eventLeadMins"`
+	TasksEnabled           bool      `json:"tasksEnabled"`
+	TaskAtTime             bool      `json:"taskAtTime"`
+	TaskLeadMins           []int     `json:"taskLeadMins"`
+	RoutinesEnabled        bool      `json:"routinesEnabled"`
+	RoutineAtTime          bool      `json:"routineAtTime"`
+	RoutineLeadMins        []int     `json:"routineLeadMins"`
+	QuietHoursEnabled      bool      `json:"quietHoursEnabled"`
+	QuietStart             *string   `json:"quietStart,omitempty"` // "HH:MM"
+	QuietEnd               *string   `json:"quietEnd,omitempty"`   // "HH:MM"
+	BundleWindowMins       int       `json:"bundleWindowMins"`     // 0 = sem agrupamento
+	MorningBriefingEnabled bool      `json:"morningBriefingEnabled"`
+	MorningBriefingTime    string    `json:"morningBriefingTime"` // "HH:MM"
+	EveningReviewEnabled   bool      `json:"eveningReviewEnabled"`
+	EveningReviewTime      string    `json:"eveningReviewTime"` // "HH:MM"
+	DailyDigestEnabled     bool      `json:"dailyDigestEnabled"`
+	DailyDigestHour        int       `json:"dailyDigestHour"`
+	WeeklyDigestEnabled    bool      `json:"weeklyDigestEnabled"`
+	WeeklyDigestWeekday    int       `json:"weeklyDigestWeekday"` // 0 = domingo
+	WeeklyDigestHour       int       `json:"weeklyDigestHour"`
+	UpdatedAt              time.Time `json:"updatedAt"`
 }
 
 type DailySummaryTokenResponse struct {
@@ -587,23 +906,31 @@ type DailySummaryTokenResponse struct {
 }
 
 type UpdateNotificationPreferencesRequest struct {
-	RemindersEnabled   *bool   `json:"remindersEnabled,omitempty"`
-	ReminderAtTime     *bool   `json:"reminderAtTime,omitempty"`
-	ReminderLeadMins   *[]int  `json:"reminderLeadMins,omitempty"`
-	EventsEnabled      *bool   `json:"eventsEnabled,omitempty"`
-	EventAtTime        *bool   `json:"eventAtTime,omitempty"`
-	EventLeadMins      *[]int  `json:"eventLeadMins,omitempty"`
-	TasksEnabled       *bool   `json:"tasksEnabled,omitempty"`
-	TaskAtTime         *bool   `json:"taskAtTime,omitempty"`
-	TaskLeadMins       *[]int  `json:"taskLeadMins,omitempty"`
-	RoutinesEnabled    *bool   `json:"routinesEnabled,omitempty"`
-	RoutineAtTime      *bool   `json:"routineAtTime,omitempty"`
-	RoutineLeadMins    *[]int  `json:"routineLeadMins,omitempty"`
-	QuietHoursEnabled  *bool   `json:"quietHoursEnabled,omitempty"`
-	QuietStart         *string `json:"quietStart,omitempty"`
-	QuietEnd           *string `json:"quietEnd,omitempty"`
-	DailyDigestEnabled *bool   `json:"dailyDigestEnabled,omitempty"`
-	DailyDigestHour    *int    `json:"dailyDigestHour,omitempty"`
+	RemindersEnabled       *bool   `json:"remindersEnabled,omitempty"`
+	ReminderAtTime         *bool   `json:"reminderAtTime,omitempty"`
+	ReminderLeadMins       *[]int  `json:"reminderLeadMins,omitempty"`
+	EventsEnabled          *bool   `json:"eventsEnabled,omitempty"`
+	EventAtTime            *bool   `json:"eventAtTime,omitempty"`
+	EventLeadMins          *[]int  `json:"eventLeadMins,omitempty"`
+	TasksEnabled           *bool   `json:"tasksEnabled,omitempty"`
+	TaskAtTime             *bool   `json:"taskAtTime,omitempty"`
+	TaskLeadMins           *[]int  `json:"taskLeadMins,omitempty"`
+	RoutinesEnabled        *bool   `json:"routinesEnabled,omitempty"`
+	RoutineAtTime          *bool   `json:"routineAtTime,omitempty"`
+	RoutineLeadMins        *[]int  `json:"routineLeadMins,omitempty"`
+	QuietHoursEnabled      *bool   `json:"quietHoursEnabled,omitempty"`
+	QuietStart             *string `json:"quietStart,omitempty"`
+	QuietEnd               *string `json:"quietEnd,omitempty"`
+	BundleWindowMins       *int    `json:"bundleWindowMins,omitempty"`
+	MorningBriefingEnabled *bool   `json:"morningBriefingEnabled,omitempty"`
+	MorningBriefingTime    *string `json:"morningBriefingTime,omitempty"`
+	EveningReviewEnabled   *bool   `json:"eveningReviewEnabled,omitempty"`
+	EveningReviewTime      *string `json:"eveningReviewTime,omitempty"`
+	DailyDigestEnabled     *bool   `json:"dailyDigestEnabled,omitempty"`
+	DailyDigestHour        *int    `json:"dailyDigestHour,omitempty"`
+	WeeklyDigestEnabled    *bool   `json:"weeklyDigestEnabled,omitempty"`
+	WeeklyDigestWeekday    *int    `json:"weeklyDigestWeekday,omitempty"`
+	WeeklyDigestHour       *int    `json:"weeklyDigestHour,omitempty"`
 }
 
 type NotificationLogResponse struct {
@@ -613,6 +940,7 @@ type NotificationLogResponse struct {
 	Title        string     `json:"title"`
 	Body         string     `json:"body"`
 	LeadMins     *int       `json:"leadMins,omitempty"`
+	BundleID     *string    `json:"bundleId,omitempty"`
 	Status       string     `json:"status"`
 	ScheduledFor time.Time  `json:"scheduledFor"`
 	SentAt       *time.Time `json:"sentAt,omitempty"`
@@ -624,3 +952,75 @@ type ListNotificationsResponse struct {
 	Items      []NotificationLogResponse `json:"items"`
 	NextCursor *string                   `json:"nextCursor,omitempty"`
 }
+
+// Notification templates (admin)
+
+type NotificationTemplateResponse struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	TriggerKey    string    `json:"triggerKey"`
+	Locale        string    `json:"locale"`
+	TitleTemplate string    `json:"titleTemplate"`
+	BodyTemplate  string    `json:"bodyTemplate"`
+	IsActive      bool      `json:"isActive"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type ListNotificationTemplatesResponse struct {
+	Items []NotificationTemplateResponse `json:"items"`
+}
+
+type CreateNotificationTemplateRequest struct {
+	Type          string `json:"type"`
+	TriggerKey    string `json:"triggerKey"`
+	Locale        string `json:"locale"`
+	TitleTemplate string `json:"titleTemplate"`
+	BodyTemplate  string `json:"bodyTemplate"`
+	IsActive      *bool  `json:"isActive,omitempty"`
+}
+
+type UpdateNotificationTemplateRequest struct {
+	TitleTemplate *string `json:"titleTemplate,omitempty"`
+	BodyTemplate  *string `json:"bodyTemplate,omitempty"`
+	IsActive      *bool   `json:"isActive,omitempty"`
+}
+
+type NotificationTemplateVarsRequest struct {
+	Title    string `json:"title"`
+	LeadMins int    `json:"leadMins"`
+	Location string `json:"location"`
+	FlagName string `json:"flagName"`
+	Time     string `json:"time"`
+}
+
+type PreviewNotificationTemplateRequest struct {
+	Type          string                          `json:"type"`
+	TriggerKey    string                          `json:"triggerKey"`
+	Locale        string                          `json:"locale"`
+	TitleTemplate *string                         `json:"titleTemplate,omitempty"`
+	BodyTemplate  *string                         `json:"bodyTemplate,omitempty"`
+	Vars          NotificationTemplateVarsRequest `json:"vars"`
+}
+
+type PreviewNotificationTemplateResponse struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type DigestSettingsResponse struct {
+	Sections       []string  `json:"sections" example:"agenda,tasks,schedule"`
+	MaxItems       int       `json:"maxItems" example:"5"` // 0 = sem limite
+	IncludeFlagIDs []string  `json:"includeFlagIds"`
+	ExcludeFlagIDs []string  `json:"excludeFlagIds"`
+	SkipEmpty      bool      `json:"skipEmpty"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type UpdateDigestSettingsRequest struct {
+	Sections       *[]string `json:"sections,omitempty"`
+	MaxItems       *int      `json:"maxItems,omitempty"`
+	IncludeFlagIDs *[]string `json:"includeFlagIds,omitempty"`
+	ExcludeFlagIDs *[]string `json:"excludeFlagIds,omitempty"`
+	SkipEmpty      *bool     `json:"skipEmpty,omitempty"`
+}
@@ -29,6 +29,7 @@ func NewAgendaHandler(
 // @Security BearerAuth
 // @Produce json
 // @Param limit query int false "Limite de itens. Max 200."
+// @Param projectId query string false "Filtrar tarefas por projeto"
 // @Success 200 {object} dto.AgendaResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -53,6 +54,9 @@ func (h *AgendaHandler) List(c *gin.Context) {
 		limit = parsed
 	}
 	opts := repository.ListOptions{Limit: limit}
+	if projectID := c.Query("projectId"); projectID != "" {
+		opts.ProjectID = &projectID
+	}
 
 	items, err := h.Agenda.List(c.Request.Context(), userID, opts)
 	if err != nil {
@@ -117,6 +121,10 @@ func (h *AgendaHandler) List(c *gin.Context) {
 				UpdatedAt: item.UpdatedAt,
 			})
 		case "task":
+			var project *dto.ProjectObject
+			if item.ProjectID != nil && item.ProjectName != nil {
+				project = &dto.ProjectObject{ID: *item.ProjectID, Name: *item.ProjectName}
+			}
 			taskItems = append(taskItems, dto.TaskResponse{
 				ID:          item.ID,
 				Title:       item.Title,
@@ -125,6 +133,8 @@ func (h *AgendaHandler) List(c *gin.Context) {
 				DueAt:       item.DueAt,
 				Flag:        flag,
 				Subflag:     subflag,
+				Project:     project,
+				Blocked:     item.Blocked,
 				CreatedAt:   item.CreatedAt,
 				UpdatedAt:   item.UpdatedAt,
 			})
@@ -142,9 +152,15 @@ func (h *AgendaHandler) List(c *gin.Context) {
 		}
 	}
 
+	holidayItems := make([]dto.HolidayResponse, 0)
+	for _, day := range h.Agenda.Holidays(c.Request.Context(), userID, items) {
+		holidayItems = append(holidayItems, toHolidayResponse(day))
+	}
+
 	c.JSON(http.StatusOK, dto.AgendaResponse{
 		Events:    eventItems,
 		Tasks:     taskItems,
 		Reminders: reminderItems,
+		Holidays:  holidayItems,
 	})
 }
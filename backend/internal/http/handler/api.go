@@ -10,12 +10,18 @@ type APIHandlers struct {
 	Agenda        *AgendaHandler
 	Home          *HomeHandler
 	Tasks         *TasksHandler
+	Projects      *ProjectsHandler
 	Reminders     *RemindersHandler
 	Events        *EventsHandler
 	ShoppingLists *ShoppingListsHandler
 	ShoppingItems *ShoppingItemsHandler
 	Routines      *RoutinesHandler
+	Away          *AwayHandler
+	Holidays      *HolidaysHandler
 	Devices       *DevicesHandler
 	Notifications *NotificationsHandler
 	Digest        *DigestHandler
+	Email         *EmailHandler
+
+	NotificationTemplates *NotificationTemplatesHandler
 }
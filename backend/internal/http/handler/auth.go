@@ -29,11 +29,12 @@ type LoginRequest struct {
 type AuthResponse struct {
 	Token string `json:"token"`
 	User  struct {
-		ID          string `json:"id"`
-		Email       string `json:"email"`
-		DisplayName string `json:"displayName"`
-		Locale      string `json:"locale"`
-		Timezone    string `json:"timezone"`
+		ID              string `json:"id"`
+		Email           string `json:"email"`
+		DisplayName     string `json:"displayName"`
+		Locale          string `json:"locale"`
+		Timezone        string `json:"timezone"`
+		HolidayCalendar string `json:"holidayCalendar"`
 	} `json:"user"`
 }
 
@@ -101,5 +102,6 @@ func toAuthResponse(user domain.User, token string) AuthResponse {
 	resp.User.DisplayName = user.DisplayName
 	resp.User.Locale = user.Locale
 	resp.User.Timezone = user.Timezone
+	resp.User.HolidayCalendar = user.HolidayCalendar
 	return resp
 }
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/usecase"
+	"inbota/backend/internal/http/dto"
+)
+
+type AwayHandler struct {
+	Usecase *usecase.AwayUsecase
+	Flags   *usecase.FlagUsecase
+}
+
+func NewAwayHandler(uc *usecase.AwayUsecase, flags *usecase.FlagUsecase) *AwayHandler {
+	return &AwayHandler{Usecase: uc, Flags: flags}
+}
+
+// List away periods.
+// @Summary Listar periodos de ferias
+// @Tags Away
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.ListAwayPeriodsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/away-periods [get]
+func (h *AwayHandler) List(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	periods, err := h.Usecase.List(c.Request.Context(), userID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+	current, err := h.Usecase.Current(c.Request.Context(), userID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	flagIDs := make([]string, 0)
+	for _, p := range periods {
+		if p.FlagID != nil {
+			flagIDs = append(flagIDs, *p.FlagID)
+		}
+	}
+	flagsByID := make(map[string]domain.Flag)
+	if h.Flags != nil && len(flagIDs) > 0 {
+		flags, err := h.Flags.GetByIDs(c.Request.Context(), userID, uniqueStrings(flagIDs))
+		if err != nil {
+			writeUsecaseError(c, err)
+			return
+		}
+		flagsByID = flags
+	}
+	flagOf := func(p domain.AwayPeriod) *domain.Flag {
+		if p.FlagID == nil {
+			return nil
+		}
+		if f, ok := flagsByID[*p.FlagID]; ok {
+			return &f
+		}
+		return nil
+	}
+
+	resp := dto.ListAwayPeriodsResponse{Items: make([]dto.AwayPeriodResponse, 0, len(periods))}
+	for _, p := range periods {
+		resp.Items = append(resp.Items, toAwayPeriodResponse(p, flagOf(p)))
+	}
+	if current != nil {
+		obj := toAwayPeriodResponse(*current, flagOf(*current))
+		resp.Current = &obj
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Create away period.
+// @Summary Criar periodo de ferias
+// @Tags Away
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateAwayPeriodRequest true "Periodo"
+// @Success 201 {object} dto.AwayPeriodResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/away-periods [post]
+func (h *AwayHandler) Create(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateAwayPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	period, err := h.Usecase.Create(c.Request.Context(), userID, usecase.AwayPeriodInput{
+		StartsOn: req.StartsOn,
+		EndsOn:   req.EndsOn,
+		FlagID:   req.FlagID,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	var flag *domain.Flag
+	if h.Flags != nil && period.FlagID != nil {
+		if f, err := h.Flags.Get(c.Request.Context(), userID, *period.FlagID); err == nil {
+			flag = &f
+		}
+	}
+
+	c.JSON(http.StatusCreated, toAwayPeriodResponse(period, flag))
+}
+
+// Delete away period.
+// @Summary Remover periodo de ferias
+// @Tags Away
+// @Security BearerAuth
+// @Param id path string true "Away period ID"
+// @Success 204 {object} nil
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/away-periods/{id} [delete]
+func (h *AwayHandler) Delete(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Usecase.Delete(c.Request.Context(), userID, c.Param("id")); err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
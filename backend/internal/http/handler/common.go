@@ -66,8 +66,18 @@ func writeUsecaseError(c *gin.Context, err error) {
 		writeError(c, http.StatusBadRequest, "invalid_password")
 	case errors.Is(err, usecase.ErrInvalidDisplayName):
 		writeError(c, http.StatusBadRequest, "invalid_display_name")
+	case errors.Is(err, usecase.ErrInvalidRecurrence):
+		writeError(c, http.StatusBadRequest, "invalid_recurrence")
 	case errors.Is(err, usecase.ErrRoutineOverlap):
 		writeError(c, http.StatusConflict, "routine_overlap")
+	case errors.Is(err, usecase.ErrTemplateConflict):
+		writeError(c, http.StatusConflict, "template_conflict")
+	case errors.Is(err, usecase.ErrTaskDependencyCycle):
+		writeError(c, http.StatusConflict, "task_dependency_cycle")
+	case errors.Is(err, usecase.ErrTaskDependencyTooDeep):
+		writeError(c, http.StatusBadRequest, "task_dependency_too_deep")
+	case errors.Is(err, service.ErrNotificationTemplateInvalid):
+		writeError(c, http.StatusBadRequest, "invalid_template")
 	case errors.Is(err, usecase.ErrInvalidCredentials):
 		writeError(c, http.StatusUnauthorized, "invalid_credentials")
 	case errors.Is(err, usecase.ErrDependencyMissing):
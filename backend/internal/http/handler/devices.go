@@ -17,7 +17,7 @@ func NewDevicesHandler(uc *usecase.DeviceTokenUsecase) *DevicesHandler {
 	return &DevicesHandler{Usecase: uc}
 }
 
-// RegisterToken registers or updates a device and returns its ntfy topic.
+// RegisterToken registers or updates a device and returns the user's ntfy topic.
 // @Summary Registrar dispositivo
 // @Tags Devices
 // @Security BearerAuth
@@ -49,13 +49,40 @@ func (h *DevicesHandler) RegisterToken(c *gin.Context) {
 		appVersion = *req.AppVersion
 	}
 
-	topic, err := h.Usecase.RegisterToken(c.Request.Context(), userID, req.DeviceID, req.Platform, deviceName, appVersion)
+	reg, err := h.Usecase.RegisterToken(c.Request.Context(), userID, req.DeviceID, req.Platform, deviceName, appVersion)
 	if err != nil {
 		writeUsecaseError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.RegisterTokenResponse{Topic: topic})
+	c.JSON(http.StatusOK, toRegisterTokenResponse(reg))
+}
+
+// RotateTopic generates a new ntfy topic (and key) for the user's devices.
+// @Summary Rotacionar tópico de push
+// @Tags Devices
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.RegisterTokenResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/devices/topic/rotate [post]
+func (h *DevicesHandler) RotateTopic(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	reg, err := h.Usecase.RotateTopic(c.Request.Context(), userID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRegisterTokenResponse(reg))
+}
+
+func toRegisterTokenResponse(reg usecase.DeviceRegistration) dto.RegisterTokenResponse {
+	return dto.RegisterTokenResponse{Topic: reg.Topic, Server: reg.Server, EncryptionKey: reg.EncryptionKey}
 }
 
 // UnregisterToken removes a device subscription.
@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"inbota/backend/internal/app/digest"
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/http/dto"
 	"inbota/backend/internal/infra/postgres"
 )
 
@@ -21,23 +26,34 @@ func NewDigestHandler(digestService *digest.DigestService) *DigestHandler {
 
 // GetDailySummary returns a consolidated daily summary for the user.
 // @Summary Resumo diário público
-// @Description Retorna o resumo consolidado do dia do usuário (rotinas, agenda, tarefas, compras).
+// @Description Retorna o resumo consolidado do dia do usuário (rotinas, agenda, tarefas, compras) em JSON, HTML, texto, Markdown ou ICS.
+// @Description O formato vem de `format` ou do header Accept. Suporta ETag/If-None-Match e Last-Modified/If-Modified-Since.
 // @Tags Digest
 // @Produce json
+// @Produce html
+// @Produce plain
 // @Param token query string true "Daily summary token"
+// @Param format query string false "json | html | text | markdown | ics"
+// @Param date query string false "today | tomorrow | yesterday | YYYY-MM-DD (até 31 dias)"
 // @Success 200 {object} digest.DigestData
+// @Success 304 "Não modificado"
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /v1/daily-summary [get]
 func (h *DigestHandler) GetDailySummary(c *gin.Context) {
-
 	token := c.Query("token")
 	if token == "" {
 		writeError(c, http.StatusBadRequest, "missing_token")
 		return
 	}
 
+	format, ok := negotiateSummaryFormat(c.Query("format"), c.GetHeader("Accept"))
+	if !ok {
+		writeError(c, http.StatusBadRequest, "invalid_format")
+		return
+	}
+
 	userID, err := h.digestService.ResolveUserIDByDailySummaryToken(c.Request.Context(), token)
 	if err != nil {
 		if errors.Is(err, postgres.ErrNotFound) {
@@ -48,16 +64,105 @@ func (h *DigestHandler) GetDailySummary(c *gin.Context) {
 		return
 	}
 
-	// We'll use a fixed time for "today" in the user's local day,
-	// but BuildDigestData handles time.Now() if we pass zero time or we can pass it explicitly.
-	// For simplicity and since it's a "daily summary", we use the current time.
-	data, err := h.digestService.BuildDigestData(c.Request.Context(), userID, time.Now())
+	date, err := h.digestService.ResolveSummaryDate(c.Request.Context(), userID, c.Query("date"))
+	if err != nil {
+		if errors.Is(err, digest.ErrInvalidSummaryDate) {
+			writeError(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		writeUsecaseError(c, err)
+		return
+	}
+
+	data, err := h.digestService.BuildDigestData(c.Request.Context(), userID, date)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	body, err := h.digestService.RenderSummary(data, format)
 	if err != nil {
 		writeUsecaseError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("Vary", "Accept")
+	if !data.LastModified.IsZero() {
+		c.Header("Last-Modified", data.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, data.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	contentType, _ := digest.SummaryContentType(format)
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// negotiateSummaryFormat: format= tem prioridade; sem ele, o primeiro tipo do Accept que
+// conhecemos decide. Sem Accept (ou */*) o padrão é JSON.
+func negotiateSummaryFormat(formatParam, accept string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(formatParam)) {
+	case "":
+	case "json":
+		return digest.SummaryFormatJSON, true
+	case "html":
+		return digest.SummaryFormatHTML, true
+	case "text", "txt":
+		return digest.SummaryFormatText, true
+	case "markdown", "md":
+		return digest.SummaryFormatMarkdown, true
+	case "ics", "ical":
+		return digest.SummaryFormatICS, true
+	default:
+		return "", false
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "application/json":
+			return digest.SummaryFormatJSON, true
+		case "text/html":
+			return digest.SummaryFormatHTML, true
+		case "text/plain":
+			return digest.SummaryFormatText, true
+		case "text/markdown":
+			return digest.SummaryFormatMarkdown, true
+		case "text/calendar":
+			return digest.SummaryFormatICS, true
+		}
+	}
+	return digest.SummaryFormatJSON, true
+}
+
+// notModified aplica If-None-Match e, só na ausência dele, If-Modified-Since (RFC 9110 §13.2.2).
+// Remover um item do dia não avança o Last-Modified; clientes que mandam os dois ficam com o ETag.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
 }
 
 func (h *DigestHandler) SendTestEmail(c *gin.Context) {
@@ -74,3 +179,170 @@ func (h *DigestHandler) SendTestEmail(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
+
+func (h *DigestHandler) SendTestWeeklyEmail(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.digestService.SendTestWeeklyDigestForUserID(c.Request.Context(), userID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// GetSettings returns the user's digest settings.
+// @Summary Configurações do digest diário
+// @Tags Digest
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.DigestSettingsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/digest/settings [get]
+func (h *DigestHandler) GetSettings(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.digestService.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDigestSettingsResponse(settings))
+}
+
+// UpdateSettings updates which sections the digest includes, their order, limits and flag filters.
+// @Summary Atualizar configurações do digest diário
+// @Tags Digest
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.UpdateDigestSettingsRequest true "Update digest settings request"
+// @Success 200 {object} dto.DigestSettingsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/digest/settings [put]
+func (h *DigestHandler) UpdateSettings(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateDigestSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	settings, err := h.digestService.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+	if req.Sections != nil {
+		settings.Sections = *req.Sections
+	}
+	if req.MaxItems != nil {
+		settings.MaxItems = *req.MaxItems
+	}
+	if req.IncludeFlagIDs != nil {
+		settings.IncludeFlagIDs = *req.IncludeFlagIDs
+	}
+	if req.ExcludeFlagIDs != nil {
+		settings.ExcludeFlagIDs = *req.ExcludeFlagIDs
+	}
+	if req.SkipEmpty != nil {
+		settings.SkipEmpty = *req.SkipEmpty
+	}
+
+	updated, err := h.digestService.UpdateSettings(c.Request.Context(), settings)
+	if err != nil {
+		if errors.Is(err, digest.ErrInvalidDigestSettings) {
+			writeError(c, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDigestSettingsResponse(updated))
+}
+
+// Preview renders the user's daily digest with the saved settings.
+// @Summary Pré-visualizar digest diário
+// @Tags Digest
+// @Security BearerAuth
+// @Produce json
+// @Produce html
+// @Produce plain
+// @Param format query string false "json | html | text | markdown | ics"
+// @Param date query string false "today | tomorrow | yesterday | YYYY-MM-DD"
+// @Success 200 {object} digest.DigestData
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/digest/preview [get]
+func (h *DigestHandler) Preview(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	format, ok := negotiateSummaryFormat(c.Query("format"), c.GetHeader("Accept"))
+	if !ok {
+		writeError(c, http.StatusBadRequest, "invalid_format")
+		return
+	}
+
+	date, err := h.digestService.ResolveSummaryDate(c.Request.Context(), userID, c.Query("date"))
+	if err != nil {
+		if errors.Is(err, digest.ErrInvalidSummaryDate) {
+			writeError(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		writeUsecaseError(c, err)
+		return
+	}
+
+	data, err := h.digestService.BuildDigestData(c.Request.Context(), userID, date)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	body, err := h.digestService.RenderSummary(data, format)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	contentType, _ := digest.SummaryContentType(format)
+	c.Data(http.StatusOK, contentType, body)
+}
+
+func toDigestSettingsResponse(s domain.DigestSettings) dto.DigestSettingsResponse {
+	resp := dto.DigestSettingsResponse{
+		Sections:       s.Sections,
+		MaxItems:       s.MaxItems,
+		IncludeFlagIDs: s.IncludeFlagIDs,
+		ExcludeFlagIDs: s.ExcludeFlagIDs,
+		SkipEmpty:      s.SkipEmpty,
+		UpdatedAt:      s.UpdatedAt,
+	}
+	if resp.Sections == nil {
+		resp.Sections = []string{}
+	}
+	if resp.IncludeFlagIDs == nil {
+		resp.IncludeFlagIDs = []string{}
+	}
+	if resp.ExcludeFlagIDs == nil {
+		resp.ExcludeFlagIDs = []string{}
+	}
+	return resp
+}
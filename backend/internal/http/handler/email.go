@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inbota/backend/internal/app/digest"
+	"inbota/backend/internal/app/i18n"
+	"inbota/backend/internal/infra/mailer"
+)
+
+// maxWebhookBodyBytes limita o corpo aceito no webhook do provedor de e-mail.
+const maxWebhookBodyBytes = 1 << 20
+
+type EmailHandler struct {
+	digestService       *digest.DigestService
+	resendWebhookSecret string
+	log                 *slog.Logger
+}
+
+func NewEmailHandler(digestService *digest.DigestService, resendWebhookSecret string, log *slog.Logger) *EmailHandler {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &EmailHandler{digestService: digestService, resendWebhookSecret: resendWebhookSecret, log: log}
+}
+
+// UnsubscribePage shows a confirmation page; link scanners that GET the URL do not unsubscribe.
+// @Summary Página de descadastro de e-mail
+// @Tags Email
+// @Produce html
+// @Param token query string true "Unsubscribe token"
+// @Success 200 {string} string "HTML"
+// @Router /v1/email/unsubscribe [get]
+func (h *EmailHandler) UnsubscribePage(c *gin.Context) {
+	if c.Query("token") == "" {
+		writeUnsubscribePage(c, http.StatusBadRequest, "email.unsubscribe_page.invalid", false)
+		return
+	}
+	writeUnsubscribePage(c, http.StatusOK, "email.unsubscribe_page.confirm", true)
+}
+
+// Unsubscribe handles the RFC 8058 one-click POST (and the confirmation form).
+// @Summary Descadastro de e-mail em um clique
+// @Description Aceita o POST "List-Unsubscribe=One-Click" enviado pelos provedores de e-mail (RFC 8058).
+// @Tags Email
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param token query string true "Unsubscribe token"
+// @Success 200 {string} string "HTML"
+// @Failure 400 {string} string "HTML"
+// @Router /v1/email/unsubscribe [post]
+func (h *EmailHandler) Unsubscribe(c *gin.Context) {
+	err := h.digestService.Unsubscribe(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		if errors.Is(err, digest.ErrInvalidUnsubscribeToken) {
+			writeUnsubscribePage(c, http.StatusBadRequest, "email.unsubscribe_page.invalid", false)
+			return
+		}
+		h.log.Error("email_unsubscribe_error", slog.String("error", err.Error()))
+		writeUnsubscribePage(c, http.StatusInternalServerError, "email.unsubscribe_page.error", false)
+		return
+	}
+	writeUnsubscribePage(c, http.StatusOK, "email.unsubscribe_page.done", false)
+}
+
+// ResendWebhook ingests delivery events (bounces, complaints...) signed by Resend.
+// @Summary Webhook de eventos do Resend
+// @Tags Email
+// @Accept json
+// @Produce json
+// @Success 204 "Processado"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /v1/webhooks/resend [post]
+func (h *EmailHandler) ResendWebhook(c *gin.Context) {
+	if h.resendWebhookSecret == "" {
+		writeError(c, http.StatusServiceUnavailable, "webhook_not_configured")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodyBytes))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	if err := mailer.VerifyResendWebhook(h.resendWebhookSecret, c.Request.Header, body, time.Now()); err != nil {
+		if !errors.Is(err, mailer.ErrInvalidWebhookSignature) {
+			h.log.Error("resend_webhook_verify_error", slog.String("error", err.Error()))
+		}
+		writeError(c, http.StatusUnauthorized, "invalid_signature")
+		return
+	}
+
+	event, err := mailer.ParseResendEvent(body)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	if err := h.digestService.HandleEmailEvent(c.Request.Context(), event); err != nil {
+		// 5xx faz o provedor reenviar o evento.
+		h.log.Error("resend_webhook_handle_error", slog.String("type", event.Type), slog.String("error", err.Error()))
+		writeError(c, http.StatusInternalServerError, "internal_error")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeUnsubscribePage renders messageKey in the browser language (the link carries no locale).
+func writeUnsubscribePage(c *gin.Context, status int, messageKey string, confirm bool) {
+	l := i18n.ForAcceptLanguage(c.GetHeader("Accept-Language"))
+	form := ""
+	if confirm {
+		// Sem action: o POST vai para a mesma URL, com o token na query.
+		form = `<form method="post"><input type="hidden" name="List-Unsubscribe" value="One-Click"><button type="submit">` +
+			html.EscapeString(l.T("email.unsubscribe")) + `</button></form>`
+	}
+	page := `<!DOCTYPE html><html lang="` + l.Locale() + `"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Inbota</title></head>` +
+		`<body style="font-family:sans-serif;max-width:480px;margin:48px auto;padding:0 16px;color:#1f2937"><h1 style="font-size:20px">Inbota</h1><p>` +
+		html.EscapeString(l.T(messageKey)) + `</p>` + form + `</body></html>`
+	c.Data(status, "text/html; charset=utf-8", []byte(page))
+}
@@ -165,7 +165,7 @@ func (h *EventsHandler) Create(c *gin.Context) {
 		return
 	}
 
-	event, err := h.Usecase.Create(c.Request.Context(), userID, req.Title, req.StartAt, req.EndAt, req.AllDay, req.Location, req.FlagID, req.SubflagID, nil)
+	event, err := h.Usecase.Create(c.Request.Context(), userID, req.Title, req.StartAt, req.EndAt, req.AllDay, req.Location, req.FlagID, req.SubflagID, nil, toNotificationOverride(req.Notification))
 	if err != nil {
 		writeUsecaseError(c, err)
 		return
@@ -235,6 +235,8 @@ func (h *EventsHandler) Update(c *gin.Context) {
 		Location:  req.Location,
 		FlagID:    req.FlagID,
 		SubflagID: req.SubflagID,
+
+		Notification: toNotificationOverride(req.Notification),
 	})
 	if err != nil {
 		writeUsecaseError(c, err)
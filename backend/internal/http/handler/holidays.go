@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inbota/backend/internal/app/usecase"
+	"inbota/backend/internal/http/dto"
+)
+
+type HolidaysHandler struct {
+	Usecase *usecase.HolidayUsecase
+}
+
+func NewHolidaysHandler(uc *usecase.HolidayUsecase) *HolidaysHandler {
+	return &HolidaysHandler{Usecase: uc}
+}
+
+// ListCalendars lists the embedded holiday calendars.
+// @Summary Listar calendarios de feriados
+// @Tags Holidays
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.ListHolidayCalendarsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/holiday-calendars [get]
+func (h *HolidaysHandler) ListCalendars(c *gin.Context) {
+	if _, ok := getUserID(c); !ok {
+		return
+	}
+
+	calendars := h.Usecase.Calendars()
+	resp := dto.ListHolidayCalendarsResponse{Items: make([]dto.HolidayCalendarResponse, 0, len(calendars))}
+	for _, cal := range calendars {
+		resp.Items = append(resp.Items, toHolidayCalendarResponse(cal))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// List holidays of the user's calendar.
+// @Summary Listar feriados do calendario do usuario
+// @Tags Holidays
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Data inicial (YYYY-MM-DD). Padrao: hoje."
+// @Param to query string false "Data final (YYYY-MM-DD). Padrao: from + 30 dias. Max 366 dias."
+// @Success 200 {object} dto.ListHolidaysResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/holidays [get]
+func (h *HolidaysHandler) List(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	cal, holidays, err := h.Usecase.List(c.Request.Context(), userID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	resp := dto.ListHolidaysResponse{
+		Calendar: toHolidayCalendarResponse(cal),
+		Items:    make([]dto.HolidayResponse, 0, len(holidays)),
+	}
+	for _, day := range holidays {
+		resp.Items = append(resp.Items, toHolidayResponse(day))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetCalendar picks the user's holiday calendar.
+// @Summary Definir calendario de feriados do usuario
+// @Tags Holidays
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.UpdateHolidayCalendarRequest true "Calendario"
+// @Success 200 {object} dto.HolidayCalendarResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/me/holiday-calendar [put]
+func (h *HolidaysHandler) SetCalendar(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateHolidayCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	cal, err := h.Usecase.SetCalendar(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toHolidayCalendarResponse(cal))
+}
@@ -44,7 +44,7 @@ func (h *HomeHandler) GetDashboard(c *gin.Context) {
 
 	focusTasks := make([]dto.TaskResponse, 0, len(dashboard.FocusTasks))
 	for _, task := range dashboard.FocusTasks {
-		focusTasks = append(focusTasks, toTaskResponse(task, nil, nil, nil))
+		focusTasks = append(focusTasks, toTaskResponse(task, nil, nil, nil, nil, nil))
 	}
 
 	timeline := make([]dto.HomeTimelineItemResponse, 0, len(dashboard.Timeline))
@@ -76,6 +76,7 @@ func (h *HomeHandler) GetDashboard(c *gin.Context) {
 		DayProgress: dto.HomeDayProgressResponse{
 			RoutinesDone:    dashboard.DayProgress.RoutinesDone,
 			RoutinesTotal:   dashboard.DayProgress.RoutinesTotal,
+			RoutinesPartial: dashboard.DayProgress.RoutinesPartial,
 			TasksDone:       dashboard.DayProgress.TasksDone,
 			TasksTotal:      dashboard.DayProgress.TasksTotal,
 			ProgressPercent: dashboard.DayProgress.ProgressPercent,
@@ -343,7 +343,7 @@ func (h *InboxHandler) Confirm(c *gin.Context) {
 
 	resp := dto.ConfirmInboxItemResponse{Type: string(result.Type)}
 	if result.Task != nil {
-		task := toTaskResponse(*result.Task, nil, nil, nil)
+		task := toTaskResponse(*result.Task, nil, nil, nil, nil, result.TaskChecklist)
 		resp.Task = &task
 	}
 	if result.Reminder != nil {
@@ -469,7 +469,7 @@ func (h *InboxHandler) Dismiss(c *gin.Context) {
 func toConfirmInboxItemResponse(result usecase.ConfirmResult) dto.ConfirmInboxItemResponse {
 	resp := dto.ConfirmInboxItemResponse{Type: string(result.Type)}
 	if result.Task != nil {
-		task := toTaskResponse(*result.Task, nil, nil, nil)
+		task := toTaskResponse(*result.Task, nil, nil, nil, nil, result.TaskChecklist)
 		resp.Task = &task
 	}
 	if result.Reminder != nil {
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/holiday"
 	"inbota/backend/internal/http/dto"
 )
 
@@ -145,7 +146,7 @@ func toInboxItemObject(item domain.InboxItem) dto.InboxItemObject {
 	}
 }
 
-func toTaskResponse(task domain.Task, source *domain.InboxItem, flag *domain.Flag, subflag *domain.Subflag) dto.TaskResponse {
+func toTaskResponse(task domain.Task, source *domain.InboxItem, flag *domain.Flag, subflag *domain.Subflag, project *domain.Project, checklist []domain.TaskChecklistItem) dto.TaskResponse {
 	var sourceObj *dto.InboxItemObject
 	if source != nil {
 		obj := toInboxItemObject(*source)
@@ -161,6 +162,30 @@ func toTaskResponse(task domain.Task, source *domain.InboxItem, flag *domain.Fla
 		obj := toSubflagObject(*subflag, flag)
 		subflagObj = &obj
 	}
+	var projectObj *dto.ProjectObject
+	if project != nil {
+		obj := toProjectObject(*project)
+		projectObj = &obj
+	}
+	var checklistResp []dto.TaskChecklistItemResponse
+	var progress *dto.TaskProgressObject
+	if len(checklist) > 0 {
+		checklistResp = make([]dto.TaskChecklistItemResponse, 0, len(checklist))
+		for _, item := range checklist {
+			checklistResp = append(checklistResp, toTaskChecklistItemResponse(item))
+		}
+		obj := toTaskProgressObject(checklist)
+		progress = &obj
+	}
+	var priority *string
+	if task.Priority != nil {
+		value := string(*task.Priority)
+		priority = &value
+	}
+	var recurrence *dto.TaskRecurrenceObject
+	if task.Recurrence != nil {
+		recurrence = &dto.TaskRecurrenceObject{Rule: task.Recurrence.Rule, AfterDays: task.Recurrence.AfterDays}
+	}
 	return dto.TaskResponse{
 		ID:              task.ID,
 		Title:           task.Title,
@@ -170,11 +195,80 @@ func toTaskResponse(task domain.Task, source *domain.InboxItem, flag *domain.Fla
 		Flag:            flagObj,
 		Subflag:         subflagObj,
 		SourceInboxItem: sourceObj,
+		Project:         projectObj,
+		Priority:        priority,
+		EffortMins:      task.EffortMins,
+		Recurrence:      recurrence,
+		SeriesID:        task.SeriesID,
+		BlockedBy:       task.BlockedBy,
+		Blocked:         task.Blocked,
+		Notification:    toNotificationOverrideObject(task.Notification),
+		Checklist:       checklistResp,
+		Progress:        progress,
 		CreatedAt:       task.CreatedAt,
 		UpdatedAt:       task.UpdatedAt,
 	}
 }
 
+func toProjectObject(project domain.Project) dto.ProjectObject {
+	return dto.ProjectObject{
+		ID:     project.ID,
+		Name:   project.Name,
+		Status: string(project.Status),
+	}
+}
+
+func toProjectResponse(project domain.Project, flag *domain.Flag, subflag *domain.Subflag, progress domain.ProjectProgress) dto.ProjectResponse {
+	var flagObj *dto.FlagObject
+	if flag != nil {
+		obj := toFlagObject(*flag)
+		flagObj = &obj
+	}
+	var subflagObj *dto.SubflagObject
+	if subflag != nil {
+		obj := toSubflagObject(*subflag, flag)
+		subflagObj = &obj
+	}
+	progressObj := dto.ProjectProgressObject{Done: progress.Done, Total: progress.Total}
+	if progress.Total > 0 {
+		progressObj.Percent = progress.Done * 100 / progress.Total
+	}
+	return dto.ProjectResponse{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		Status:      string(project.Status),
+		TargetDate:  project.TargetDate,
+		Flag:        flagObj,
+		Subflag:     subflagObj,
+		Progress:    progressObj,
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+	}
+}
+
+func toTaskChecklistItemResponse(item domain.TaskChecklistItem) dto.TaskChecklistItemResponse {
+	return dto.TaskChecklistItemResponse{
+		ID:        item.ID,
+		TaskID:    item.TaskID,
+		Title:     item.Title,
+		Checked:   item.Checked,
+		SortOrder: item.SortOrder,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+func toTaskProgressObject(checklist []domain.TaskChecklistItem) dto.TaskProgressObject {
+	progress := dto.TaskProgressObject{Total: len(checklist)}
+	for _, item := range checklist {
+		if item.Checked {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
 func toReminderResponse(reminder domain.Reminder, source *domain.InboxItem, flag *domain.Flag, subflag *domain.Subflag) dto.ReminderResponse {
 	var sourceObj *dto.InboxItemObject
 	if source != nil {
@@ -199,6 +293,8 @@ func toReminderResponse(reminder domain.Reminder, source *domain.InboxItem, flag
 		Flag:            flagObj,
 		Subflag:         subflagObj,
 		SourceInboxItem: sourceObj,
+		Notification:    toNotificationOverrideObject(reminder.Notification),
+		Escalation:      toReminderEscalationObject(reminder.Escalation),
 		CreatedAt:       reminder.CreatedAt,
 		UpdatedAt:       reminder.UpdatedAt,
 	}
@@ -230,6 +326,7 @@ func toEventResponse(event domain.Event, source *domain.InboxItem, flag *domain.
 		Flag:            flagObj,
 		Subflag:         subflagObj,
 		SourceInboxItem: sourceObj,
+		Notification:    toNotificationOverrideObject(event.Notification),
 		CreatedAt:       event.CreatedAt,
 		UpdatedAt:       event.UpdatedAt,
 	}
@@ -277,6 +374,35 @@ func toShoppingItemResponse(item domain.ShoppingItem, list *domain.ShoppingList)
 	}
 }
 
+func toNotificationOverrideObject(override domain.NotificationOverride) dto.NotificationOverrideObject {
+	return dto.NotificationOverrideObject{
+		LeadMins: override.LeadMins,
+		Disabled: override.Disabled,
+	}
+}
+
+func toNotificationOverride(req *dto.NotificationOverrideRequest) *domain.NotificationOverride {
+	if req == nil {
+		return nil
+	}
+	return &domain.NotificationOverride{LeadMins: req.LeadMins, Disabled: req.Disabled}
+}
+
+func toReminderEscalationObject(escalation domain.ReminderEscalation) dto.ReminderEscalationObject {
+	return dto.ReminderEscalationObject{
+		IntervalMins:    escalation.IntervalMins,
+		MaxRepeats:      escalation.MaxRepeats,
+		EmailFromRepeat: escalation.EmailFromRepeat,
+	}
+}
+
+func toReminderEscalation(req *dto.ReminderEscalationObject) *domain.ReminderEscalation {
+	if req == nil {
+		return nil
+	}
+	return &domain.ReminderEscalation{IntervalMins: req.IntervalMins, MaxRepeats: req.MaxRepeats, EmailFromRepeat: req.EmailFromRepeat}
+}
+
 func toRoutineResponse(routine domain.Routine, flag *domain.Flag, subflag *domain.Subflag) dto.RoutineResponse {
 	var flagObj *dto.FlagObject
 	if flag != nil {
@@ -288,6 +414,11 @@ func toRoutineResponse(routine domain.Routine, flag *domain.Flag, subflag *domai
 		obj := toSubflagObject(*subflag, flag)
 		subflagObj = &obj
 	}
+	var progressToday *float64
+	if routine.TargetValue != nil {
+		progress := routine.ProgressToday
+		progressToday = &progress
+	}
 	return dto.RoutineResponse{
 		ID:               routine.ID,
 		Title:            routine.Title,
@@ -297,14 +428,94 @@ func toRoutineResponse(routine domain.Routine, flag *domain.Flag, subflag *domai
 		StartTime:        routine.StartTime,
 		EndTime:          routine.EndTime,
 		WeekOfMonth:      routine.WeekOfMonth,
+		RRule:            routine.RRule,
+		Target:           toRoutineTargetObject(routine),
 		StartsOn:         routine.StartsOn,
 		EndsOn:           routine.EndsOn,
 		Color:            routine.Color,
 		IsActive:         routine.IsActive,
 		IsCompletedToday: routine.IsCompletedToday,
+		ProgressToday:    progressToday,
 		Flag:             flagObj,
 		Subflag:          subflagObj,
+		Notification:     toNotificationOverrideObject(routine.Notification),
+		StreakProtection: toStreakProtectionObject(routine.StreakProtection),
+		SkipHolidays:     routine.SkipHolidays,
 		CreatedAt:        routine.CreatedAt,
 		UpdatedAt:        routine.UpdatedAt,
 	}
 }
+
+func toRoutineTargetObject(routine domain.Routine) *dto.RoutineTargetObject {
+	if routine.TargetKind == nil || routine.TargetValue == nil {
+		return nil
+	}
+	return &dto.RoutineTargetObject{
+		Kind:  *routine.TargetKind,
+		Value: *routine.TargetValue,
+		Unit:  routine.TargetUnit,
+	}
+}
+
+func fromRoutineTargetRequest(target *dto.RoutineTargetRequest) (*string, *float64, *string) {
+	if target == nil {
+		return nil, nil, nil
+	}
+	return target.Kind, target.Value, target.Unit
+}
+
+func toRoutineStreakProtection(req *dto.StreakProtectionObject) *domain.RoutineStreakProtection {
+	if req == nil {
+		return nil
+	}
+	return &domain.RoutineStreakProtection{
+		GracePerMonth: req.GracePerMonth,
+		FreezeEvery:   req.FreezeEvery,
+	}
+}
+
+func toStreakProtectionObject(protection domain.RoutineStreakProtection) dto.StreakProtectionObject {
+	return dto.StreakProtectionObject{
+		GracePerMonth: protection.GracePerMonth,
+		FreezeEvery:   protection.FreezeEvery,
+	}
+}
+
+func toAwayPeriodResponse(period domain.AwayPeriod, flag *domain.Flag) dto.AwayPeriodResponse {
+	var flagObj *dto.FlagObject
+	if flag != nil {
+		obj := toFlagObject(*flag)
+		flagObj = &obj
+	}
+	return dto.AwayPeriodResponse{
+		ID:        period.ID,
+		StartsOn:  period.StartsOn,
+		EndsOn:    period.EndsOn,
+		Flag:      flagObj,
+		Reason:    period.Reason,
+		CreatedAt: period.CreatedAt,
+		UpdatedAt: period.UpdatedAt,
+	}
+}
+
+func toHolidayResponse(h holiday.Holiday) dto.HolidayResponse {
+	return dto.HolidayResponse{
+		Date:  h.Date,
+		Name:  h.Name,
+		Scope: h.Scope,
+	}
+}
+
+func toHolidayCalendarResponse(cal holiday.Calendar) dto.HolidayCalendarResponse {
+	return dto.HolidayCalendarResponse{
+		Code: cal.Code(),
+		Name: cal.Name(),
+	}
+}
+
+func toTaskRecurrence(req *dto.TaskRecurrenceObject) *domain.TaskRecurrence {
+	if req == nil {
+		return nil
+	}
+	return &domain.TaskRecurrence{Rule: req.Rule, AfterDays: req.AfterDays}
+}
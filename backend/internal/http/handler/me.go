@@ -47,5 +47,6 @@ func (h *MeHandler) Me(c *gin.Context) {
 	resp.User.DisplayName = user.DisplayName
 	resp.User.Locale = user.Locale
 	resp.User.Timezone = user.Timezone
+	resp.User.HolidayCalendar = user.HolidayCalendar
 	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/service"
+	"inbota/backend/internal/app/usecase"
+	"inbota/backend/internal/http/dto"
+)
+
+type NotificationTemplatesHandler struct {
+	Usecase *usecase.NotificationTemplateUsecase
+}
+
+func NewNotificationTemplatesHandler(uc *usecase.NotificationTemplateUsecase) *NotificationTemplatesHandler {
+	return &NotificationTemplatesHandler{Usecase: uc}
+}
+
+// List returns all notification templates.
+// @Summary Listar templates de notificação (admin)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.ListNotificationTemplatesResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /v1/admin/notification-templates [get]
+func (h *NotificationTemplatesHandler) List(c *gin.Context) {
+	templates, err := h.Usecase.List(c.Request.Context())
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	items := make([]dto.NotificationTemplateResponse, len(templates))
+	for i, t := range templates {
+		items[i] = toNotificationTemplateResponse(t)
+	}
+	c.JSON(http.StatusOK, dto.ListNotificationTemplatesResponse{Items: items})
+}
+
+// Create creates a notification template.
+// @Summary Criar template de notificação (admin)
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateNotificationTemplateRequest true "Create template request"
+// @Success 201 {object} dto.NotificationTemplateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /v1/admin/notification-templates [post]
+func (h *NotificationTemplatesHandler) Create(c *gin.Context) {
+	var req dto.CreateNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	tpl, err := h.Usecase.Create(c.Request.Context(), usecase.NotificationTemplateInput{
+		Type:          req.Type,
+		TriggerKey:    req.TriggerKey,
+		Locale:        req.Locale,
+		TitleTemplate: req.TitleTemplate,
+		BodyTemplate:  req.BodyTemplate,
+		IsActive:      req.IsActive,
+	})
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toNotificationTemplateResponse(tpl))
+}
+
+// Update updates a notification template.
+// @Summary Atualizar template de notificação (admin)
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param body body dto.UpdateNotificationTemplateRequest true "Update template request"
+// @Success 200 {object} dto.NotificationTemplateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/admin/notification-templates/{id} [patch]
+func (h *NotificationTemplatesHandler) Update(c *gin.Context) {
+	var req dto.UpdateNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	tpl, err := h.Usecase.Update(c.Request.Context(), c.Param("id"), usecase.NotificationTemplateUpdateInput{
+		TitleTemplate: req.TitleTemplate,
+		BodyTemplate:  req.BodyTemplate,
+		IsActive:      req.IsActive,
+	})
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toNotificationTemplateResponse(tpl))
+}
+
+// Delete deletes a notification template.
+// @Summary Remover template de notificação (admin)
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/admin/notification-templates/{id} [delete]
+func (h *NotificationTemplatesHandler) Delete(c *gin.Context) {
+	if err := h.Usecase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Preview renders a template with sample variables.
+// @Summary Pré-visualizar template de notificação (admin)
+// @Description Sem titleTemplate/bodyTemplate, renderiza o template salvo para type/triggerKey/locale (com fallbacks).
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.PreviewNotificationTemplateRequest true "Preview request"
+// @Success 200 {object} dto.PreviewNotificationTemplateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /v1/admin/notification-templates/preview [post]
+func (h *NotificationTemplatesHandler) Preview(c *gin.Context) {
+	var req dto.PreviewNotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	title, body, err := h.Usecase.Preview(c.Request.Context(), usecase.NotificationTemplatePreviewInput{
+		Type:          req.Type,
+		TriggerKey:    req.TriggerKey,
+		Locale:        req.Locale,
+		TitleTemplate: req.TitleTemplate,
+		BodyTemplate:  req.BodyTemplate,
+		Vars: service.NotificationTemplateVars{
+			Title:    req.Vars.Title,
+			LeadMins: req.Vars.LeadMins,
+			Location: req.Vars.Location,
+			FlagName: req.Vars.FlagName,
+			Time:     req.Vars.Time,
+		},
+	})
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreviewNotificationTemplateResponse{Title: title, Body: body})
+}
+
+// Reload reloads the template cache from the database.
+// @Summary Recarregar cache de templates (admin)
+// @Tags Admin
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /v1/admin/notification-templates/reload [post]
+func (h *NotificationTemplatesHandler) Reload(c *gin.Context) {
+	if err := h.Usecase.Reload(c.Request.Context()); err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func toNotificationTemplateResponse(t domain.NotificationTemplate) dto.NotificationTemplateResponse {
+	return dto.NotificationTemplateResponse{
+		ID:            t.ID,
+		Type:          string(t.Type),
+		TriggerKey:    t.TriggerKey,
+		Locale:        t.Locale,
+		TitleTemplate: t.TitleTemplate,
+		BodyTemplate:  t.BodyTemplate,
+		IsActive:      t.IsActive,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+	}
+}
@@ -144,12 +144,36 @@ func (h *NotificationsHandler) UpdatePreferences(c *gin.Context) {
 		if req.QuietEnd != nil {
 			prefs.QuietEnd = req.QuietEnd
 		}
+		if req.BundleWindowMins != nil {
+			prefs.BundleWindowMins = *req.BundleWindowMins
+		}
+		if req.MorningBriefingEnabled != nil {
+			prefs.MorningBriefingEnabled = *req.MorningBriefingEnabled
+		}
+		if req.MorningBriefingTime != nil {
+			prefs.MorningBriefingTime = *req.MorningBriefingTime
+		}
+		if req.EveningReviewEnabled != nil {
+			prefs.EveningReviewEnabled = *req.EveningReviewEnabled
+		}
+		if req.EveningReviewTime != nil {
+			prefs.EveningReviewTime = *req.EveningReviewTime
+		}
 		if req.DailyDigestEnabled != nil {
 			prefs.DailyDigestEnabled = *req.DailyDigestEnabled
 		}
 		if req.DailyDigestHour != nil {
 			prefs.DailyDigestHour = *req.DailyDigestHour
 		}
+		if req.WeeklyDigestEnabled != nil {
+			prefs.WeeklyDigestEnabled = *req.WeeklyDigestEnabled
+		}
+		if req.WeeklyDigestWeekday != nil {
+			prefs.WeeklyDigestWeekday = *req.WeeklyDigestWeekday
+		}
+		if req.WeeklyDigestHour != nil {
+			prefs.WeeklyDigestHour = *req.WeeklyDigestHour
+		}
 	})
 
 	if err != nil {
@@ -287,24 +311,32 @@ func (h *NotificationsHandler) SendTestNotification(c *gin.Context) {
 
 func toNotificationPreferencesResponse(p domain.NotificationPreferences) dto.NotificationPreferencesResponse {
 	return dto.NotificationPreferencesResponse{
-		RemindersEnabled:  p.RemindersEnabled,
-		ReminderAtTime:    p.ReminderAtTime,
-		ReminderLeadMins:  p.ReminderLeadMins,
-		EventsEnabled:     p.EventsEnabled,
-		EventAtTime:       p.EventAtTime,
-		EventLeadMins:     p.EventLeadMins,
-		TasksEnabled:      p.TasksEnabled,
-		TaskAtTime:        p.TaskAtTime,
-		TaskLeadMins:      p.TaskLeadMins,
-		RoutinesEnabled:   p.RoutinesEnabled,
-		RoutineAtTime:     p.RoutineAtTime,
-		RoutineLeadMins:   p.RoutineLeadMins,
-		QuietHoursEnabled: p.QuietHoursEnabled,
-		QuietStart:        p.QuietStart,
-		QuietEnd:          p.QuietEnd,
-		DailyDigestEnabled: p.DailyDigestEnabled,
-		DailyDigestHour:    p.DailyDigestHour,
-		UpdatedAt:         p.UpdatedAt,
+		RemindersEnabled:       p.RemindersEnabled,
+		ReminderAtTime:         p.ReminderAtTime,
+		ReminderLeadMins:       p.ReminderLeadMins,
+		EventsEnabled:          p.EventsEnabled,
+		EventAtTime:            p.EventAtTime,
+		EventLeadMins:          p.EventLeadMins,
+		TasksEnabled:           p.TasksEnabled,
+		TaskAtTime:             p.TaskAtTime,
+		TaskLeadMins:           p.TaskLeadMins,
+		RoutinesEnabled:        p.RoutinesEnabled,
+		RoutineAtTime:          p.RoutineAtTime,
+		RoutineLeadMins:        p.RoutineLeadMins,
+		QuietHoursEnabled:      p.QuietHoursEnabled,
+		QuietStart:             p.QuietStart,
+		QuietEnd:               p.QuietEnd,
+		BundleWindowMins:       p.BundleWindowMins,
+		MorningBriefingEnabled: p.MorningBriefingEnabled,
+		MorningBriefingTime:    p.MorningBriefingTime,
+		EveningReviewEnabled:   p.EveningReviewEnabled,
+		EveningReviewTime:      p.EveningReviewTime,
+		DailyDigestEnabled:     p.DailyDigestEnabled,
+		DailyDigestHour:        p.DailyDigestHour,
+		WeeklyDigestEnabled:    p.WeeklyDigestEnabled,
+		WeeklyDigestWeekday:    p.WeeklyDigestWeekday,
+		WeeklyDigestHour:       p.WeeklyDigestHour,
+		UpdatedAt:              p.UpdatedAt,
 	}
 }
 
@@ -316,6 +348,7 @@ func toNotificationLogResponse(l domain.NotificationLog) dto.NotificationLogResp
 		Title:        l.Title,
 		Body:         l.Body,
 		LeadMins:     l.LeadMins,
+		BundleID:     l.BundleID,
 		Status:       string(l.Status),
 		ScheduledFor: l.ScheduledFor,
 		SentAt:       l.SentAt,
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/usecase"
+	"inbota/backend/internal/http/dto"
+)
+
+type ProjectsHandler struct {
+	Usecase  *usecase.ProjectUsecase
+	Flags    *usecase.FlagUsecase
+	Subflags *usecase.SubflagUsecase
+}
+
+func NewProjectsHandler(uc *usecase.ProjectUsecase, flags *usecase.FlagUsecase, subflags *usecase.SubflagUsecase) *ProjectsHandler {
+	return &ProjectsHandler{Usecase: uc, Flags: flags, Subflags: subflags}
+}
+
+// List projects.
+// @Summary Listar projetos
+// @Tags Projects
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "OPEN | DONE | ARCHIVED"
+// @Param limit query int false "Limite"
+// @Param cursor query string false "Cursor"
+// @Success 200 {object} dto.ListProjectsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/projects [get]
+func (h *ProjectsHandler) List(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+	var status *string
+	if value := c.Query("status"); value != "" {
+		status = &value
+	}
+
+	projects, next, err := h.Usecase.List(c.Request.Context(), userID, status, opts)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	items, ok := h.toResponses(c, userID, projects)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, dto.ListProjectsResponse{Items: items, NextCursor: next})
+}
+
+// Get project.
+// @Summary Obter projeto
+// @Tags Projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} dto.ProjectResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/projects/{id} [get]
+func (h *ProjectsHandler) Get(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	project, err := h.Usecase.Get(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	items, ok := h.toResponses(c, userID, []domain.Project{project})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, items[0])
+}
+
+// Create project.
+// @Summary Criar projeto
+// @Tags Projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateProjectRequest true "Project payload"
+// @Success 201 {object} dto.ProjectResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/projects [post]
+func (h *ProjectsHandler) Create(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	project, err := h.Usecase.Create(c.Request.Context(), userID, req.Name, req.Description, req.Status, req.TargetDate, req.FlagID, req.SubflagID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	items, ok := h.toResponses(c, userID, []domain.Project{project})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusCreated, items[0])
+}
+
+// Update project.
+// @Summary Atualizar projeto
+// @Tags Projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param body body dto.UpdateProjectRequest true "Project payload"
+// @Success 200 {object} dto.ProjectResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/projects/{id} [patch]
+func (h *ProjectsHandler) Update(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	project, err := h.Usecase.Update(c.Request.Context(), userID, c.Param("id"), usecase.ProjectUpdateInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      req.Status,
+		TargetDate:  req.TargetDate,
+		FlagID:      req.FlagID,
+		SubflagID:   req.SubflagID,
+	})
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	items, ok := h.toResponses(c, userID, []domain.Project{project})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, items[0])
+}
+
+// Delete project. Its tasks are kept without a project.
+// @Summary Excluir projeto
+// @Tags Projects
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/projects/{id} [delete]
+func (h *ProjectsHandler) Delete(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Usecase.Delete(c.Request.Context(), userID, c.Param("id")); err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// toResponses resolves flags, subflags and task progress in batch; it writes the error and returns false on failure.
+func (h *ProjectsHandler) toResponses(c *gin.Context, userID string, projects []domain.Project) ([]dto.ProjectResponse, bool) {
+	projectIDs := make([]string, 0, len(projects))
+	subflagIDs := make([]string, 0)
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+		if project.SubflagID != nil {
+			subflagIDs = append(subflagIDs, *project.SubflagID)
+		}
+	}
+
+	progressByID, err := h.Usecase.Progress(c.Request.Context(), userID, projectIDs)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return nil, false
+	}
+
+	subflagsByID := make(map[string]domain.Subflag)
+	if h.Subflags != nil {
+		ids := uniqueStrings(subflagIDs)
+		if len(ids) > 0 {
+			subflags, err := h.Subflags.GetByIDs(c.Request.Context(), userID, ids)
+			if err != nil {
+				writeUsecaseError(c, err)
+				return nil, false
+			}
+			subflagsByID = subflags
+		}
+	}
+
+	flagIDs := make([]string, 0)
+	for _, project := range projects {
+		if project.FlagID != nil {
+			flagIDs = append(flagIDs, *project.FlagID)
+		}
+	}
+	for _, subflag := range subflagsByID {
+		flagIDs = append(flagIDs, subflag.FlagID)
+	}
+
+	flagsByID := make(map[string]domain.Flag)
+	if h.Flags != nil {
+		ids := uniqueStrings(flagIDs)
+		if len(ids) > 0 {
+			flags, err := h.Flags.GetByIDs(c.Request.Context(), userID, ids)
+			if err != nil {
+				writeUsecaseError(c, err)
+				return nil, false
+			}
+			flagsByID = flags
+		}
+	}
+
+	items := make([]dto.ProjectResponse, 0, len(projects))
+	for _, project := range projects {
+		var flag *domain.Flag
+		if project.FlagID != nil {
+			if f, ok := flagsByID[*project.FlagID]; ok {
+				flag = &f
+			}
+		}
+		var subflag *domain.Subflag
+		if project.SubflagID != nil {
+			if sf, ok := subflagsByID[*project.SubflagID]; ok {
+				subflag = &sf
+			}
+		}
+		if flag == nil && subflag != nil {
+			if f, ok := flagsByID[subflag.FlagID]; ok {
+				flag = &f
+			}
+		}
+		items = append(items, toProjectResponse(project, flag, subflag, progressByID[project.ID]))
+	}
+	return items, true
+}
@@ -165,7 +165,7 @@ func (h *RemindersHandler) Create(c *gin.Context) {
 		return
 	}
 
-	reminder, err := h.Usecase.Create(c.Request.Context(), userID, req.Title, req.Status, req.RemindAt, req.FlagID, req.SubflagID, nil)
+	reminder, err := h.Usecase.Create(c.Request.Context(), userID, req.Title, req.Status, req.RemindAt, req.FlagID, req.SubflagID, nil, toNotificationOverride(req.Notification), toReminderEscalation(req.Escalation))
 	if err != nil {
 		writeUsecaseError(c, err)
 		return
@@ -233,6 +233,9 @@ func (h *RemindersHandler) Update(c *gin.Context) {
 		RemindAt:  req.RemindAt,
 		FlagID:    req.FlagID,
 		SubflagID: req.SubflagID,
+
+		Notification: toNotificationOverride(req.Notification),
+		Escalation:   toReminderEscalation(req.Escalation),
 	})
 	if err != nil {
 		writeUsecaseError(c, err)
@@ -255,6 +255,7 @@ func (h *RoutinesHandler) Create(c *gin.Context) {
 		return
 	}
 
+	targetKind, targetValue, targetUnit := fromRoutineTargetRequest(req.Target)
 	routine, err := h.Usecase.Create(c.Request.Context(), userID, usecase.RoutineInput{
 		Title:          req.Title,
 		Description:    req.Description,
@@ -263,11 +264,18 @@ func (h *RoutinesHandler) Create(c *gin.Context) {
 		StartTime:      req.StartTime,
 		EndTime:        req.EndTime,
 		WeekOfMonth:    req.WeekOfMonth,
+		RRule:          req.RRule,
+		TargetKind:     targetKind,
+		TargetValue:    targetValue,
+		TargetUnit:     targetUnit,
 		StartsOn:       req.StartsOn,
 		EndsOn:         req.EndsOn,
 		Color:          req.Color,
 		FlagID:         req.FlagID,
 		SubflagID:      req.SubflagID,
+		Notification:   toNotificationOverride(req.Notification),
+		Streak:         toRoutineStreakProtection(req.StreakProtection),
+		SkipHolidays:   req.SkipHolidays != nil && *req.SkipHolidays,
 	})
 	if err != nil {
 		writeUsecaseError(c, err)
@@ -316,6 +324,7 @@ func (h *RoutinesHandler) Update(c *gin.Context) {
 		return
 	}
 
+	targetKind, targetValue, targetUnit := fromRoutineTargetRequest(req.Target)
 	routine, err := h.Usecase.Update(c.Request.Context(), userID, id, usecase.RoutineUpdateInput{
 		Title:          req.Title,
 		Description:    req.Description,
@@ -324,11 +333,18 @@ func (h *RoutinesHandler) Update(c *gin.Context) {
 		StartTime:      req.StartTime,
 		EndTime:        req.EndTime,
 		WeekOfMonth:    req.WeekOfMonth,
+		RRule:          req.RRule,
+		TargetKind:     targetKind,
+		TargetValue:    targetValue,
+		TargetUnit:     targetUnit,
 		StartsOn:       req.StartsOn,
 		EndsOn:         req.EndsOn,
 		Color:          req.Color,
 		FlagID:         req.FlagID,
 		SubflagID:      req.SubflagID,
+		Notification:   toNotificationOverride(req.Notification),
+		Streak:         toRoutineStreakProtection(req.StreakProtection),
+		SkipHolidays:   req.SkipHolidays,
 	})
 	if err != nil {
 		writeUsecaseError(c, err)
@@ -411,11 +427,12 @@ func (h *RoutinesHandler) Toggle(c *gin.Context) {
 
 // Complete routine.
 // @Summary Marcar rotina como concluída
+// @Description Rotinas com meta somam value ao progresso do dia; a rotina conta como feita ao atingir a meta.
 // @Tags Routines
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Routine ID"
-// @Param body body dto.CompleteRoutineRequest false "Data (opcional, padrão: hoje)"
+// @Param body body dto.CompleteRoutineRequest false "Data (opcional, padrão: hoje) e value do check-in"
 // @Success 201 {object} dto.RoutineCompletionResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -431,7 +448,7 @@ func (h *RoutinesHandler) Complete(c *gin.Context) {
 	var req dto.CompleteRoutineRequest
 	c.ShouldBindJSON(&req)
 
-	completion, err := h.Usecase.Complete(c.Request.Context(), userID, id, req.Date)
+	completion, err := h.Usecase.Complete(c.Request.Context(), userID, id, req.Date, req.Value)
 	if err != nil {
 		writeUsecaseError(c, err)
 		return
@@ -441,6 +458,7 @@ func (h *RoutinesHandler) Complete(c *gin.Context) {
 		ID:          completion.ID,
 		RoutineID:   completion.RoutineID,
 		CompletedOn: completion.CompletedOn,
+		Value:       completion.Value,
 		CompletedAt: completion.CompletedAt,
 	})
 }
@@ -502,6 +520,7 @@ func (h *RoutinesHandler) GetHistory(c *gin.Context) {
 			ID:          completion.ID,
 			RoutineID:   completion.RoutineID,
 			CompletedOn: completion.CompletedOn,
+			Value:       completion.Value,
 			CompletedAt: completion.CompletedAt,
 		})
 	}
@@ -527,32 +546,93 @@ func (h *RoutinesHandler) GetStreak(c *gin.Context) {
 	}
 	id := c.Param("id")
 
-	currentStreak, totalCompletions, streakText, activity, err := h.Usecase.GetStreak(c.Request.Context(), userID, id)
+	streak, err := h.Usecase.GetStreak(c.Request.Context(), userID, id)
 	if err != nil {
 		writeUsecaseError(c, err)
 		return
 	}
 
-	activityDTO := make([]dto.RoutineActivityDay, 0, len(activity))
-	for _, a := range activity {
+	activityDTO := make([]dto.RoutineActivityDay, 0, len(streak.Activity))
+	for _, a := range streak.Activity {
 		activityDTO = append(activityDTO, dto.RoutineActivityDay{
 			Date:         a.Date,
 			IsCompleted:  a.IsCompleted,
+			IsPartial:    a.IsPartial,
+			Progress:     a.Progress,
 			IsScheduled:  a.IsScheduled,
 			IsToday:      a.IsToday,
 			IsSkipped:    a.IsSkipped,
+			IsRest:       a.IsRest,
+			IsFrozen:     a.IsFrozen,
+			IsAway:       a.IsAway,
+			IsHoliday:    a.IsHoliday,
 			WeekdayLabel: a.WeekdayLabel,
 		})
 	}
 
 	c.JSON(http.StatusOK, dto.RoutineStreakResponse{
-		CurrentStreak:    currentStreak,
-		TotalCompletions: totalCompletions,
-		StreakText:       streakText,
+		CurrentStreak:    streak.Current,
+		BestStreak:       streak.Best,
+		TotalCompletions: streak.TotalCompletions,
+		StreakText:       streak.Text,
+		FreezesAvailable: streak.FreezesAvailable,
+		GraceLeft:        streak.GraceLeft,
 		Activity:         activityDTO,
 	})
 }
 
+// GetStats routine.
+// @Summary Estatísticas da rotina
+// @Description Taxa de conclusão por semana e mês, heatmap do último ano, melhor dia da semana e horário e tendência frente ao período anterior. Só contam os dias agendados (recorrência + exceções).
+// @Tags Routines
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Routine ID"
+// @Success 200 {object} dto.RoutineStatsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/routines/{id}/stats [get]
+func (h *RoutinesHandler) GetStats(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+
+	stats, err := h.Usecase.GetStats(c.Request.Context(), userID, id)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRoutineStatsResponse(stats, true))
+}
+
+// GetAllStats routine.
+// @Summary Estatísticas de todas as rotinas
+// @Description Mesmas métricas de /routines/{id}/stats somando as rotinas ativas, com o resumo de cada uma em routines.
+// @Tags Routines
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.RoutineStatsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/routines/stats [get]
+func (h *RoutinesHandler) GetAllStats(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.Usecase.GetAllStats(c.Request.Context(), userID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRoutineStatsResponse(stats, false))
+}
+
 // CreateException routine.
 // @Summary Criar exceção de rotina
 // @Tags Routines
@@ -662,3 +742,65 @@ func getStringOrDefault(ptr *string, defaultValue string) string {
 	}
 	return *ptr
 }
+
+func toRoutineStatsResponse(stats usecase.RoutineStats, single bool) dto.RoutineStatsResponse {
+	resp := dto.RoutineStatsResponse{
+		From:           stats.From,
+		To:             stats.To,
+		Scheduled:      stats.Scheduled,
+		Completed:      stats.Completed,
+		Skipped:        stats.Skipped,
+		CompletionRate: stats.CompletionRate,
+		BestWeekday:    stats.BestWeekday,
+		BestTimeSlot:   stats.BestTimeSlot,
+		Weekly:         toRoutinePeriodStats(stats.Weekly),
+		Monthly:        toRoutinePeriodStats(stats.Monthly),
+		WeekTrend:      toRoutineTrend(stats.WeekTrend),
+		MonthTrend:     toRoutineTrend(stats.MonthTrend),
+		Heatmap:        make([]dto.RoutineHeatmapDay, 0, len(stats.Heatmap)),
+	}
+	if single {
+		bestStreak := stats.BestStreak
+		resp.BestStreak = &bestStreak
+	}
+	for _, day := range stats.Heatmap {
+		resp.Heatmap = append(resp.Heatmap, dto.RoutineHeatmapDay{
+			Date:      day.Date,
+			Scheduled: day.Scheduled,
+			Completed: day.Completed,
+			Skipped:   day.Skipped,
+		})
+	}
+	for _, summary := range stats.Routines {
+		resp.Routines = append(resp.Routines, dto.RoutineStatsSummary{
+			RoutineID:      summary.RoutineID,
+			Title:          summary.Title,
+			Scheduled:      summary.Scheduled,
+			Completed:      summary.Completed,
+			CompletionRate: summary.CompletionRate,
+		})
+	}
+	return resp
+}
+
+func toRoutinePeriodStats(periods []usecase.RoutinePeriodStats) []dto.RoutinePeriodStats {
+	out := make([]dto.RoutinePeriodStats, 0, len(periods))
+	for _, p := range periods {
+		out = append(out, dto.RoutinePeriodStats{
+			Start:          p.Start,
+			End:            p.End,
+			Scheduled:      p.Scheduled,
+			Completed:      p.Completed,
+			CompletionRate: p.CompletionRate,
+		})
+	}
+	return out
+}
+
+func toRoutineTrend(trend usecase.RoutineTrend) dto.RoutineTrend {
+	return dto.RoutineTrend{
+		Current:  trend.Current,
+		Previous: trend.Previous,
+		Delta:    trend.Delta,
+	}
+}
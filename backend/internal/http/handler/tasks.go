@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -11,14 +12,16 @@ import (
 )
 
 type TasksHandler struct {
-	Usecase  *usecase.TaskUsecase
-	Inbox    *usecase.InboxUsecase
-	Flags    *usecase.FlagUsecase
-	Subflags *usecase.SubflagUsecase
+	Usecase   *usecase.TaskUsecase
+	Inbox     *usecase.InboxUsecase
+	Flags     *usecase.FlagUsecase
+	Subflags  *usecase.SubflagUsecase
+	Checklist *usecase.TaskChecklistUsecase
+	Projects  *usecase.ProjectUsecase
 }
 
-func NewTasksHandler(uc *usecase.TaskUsecase, inbox *usecase.InboxUsecase, flags *usecase.FlagUsecase, subflags *usecase.SubflagUsecase) *TasksHandler {
-	return &TasksHandler{Usecase: uc, Inbox: inbox, Flags: flags, Subflags: subflags}
+func NewTasksHandler(uc *usecase.TaskUsecase, inbox *usecase.InboxUsecase, flags *usecase.FlagUsecase, subflags *usecase.SubflagUsecase, checklist *usecase.TaskChecklistUsecase, projects *usecase.ProjectUsecase) *TasksHandler {
+	return &TasksHandler{Usecase: uc, Inbox: inbox, Flags: flags, Subflags: subflags, Checklist: checklist, Projects: projects}
 }
 
 // List tasks.
@@ -28,6 +31,7 @@ func NewTasksHandler(uc *usecase.TaskUsecase, inbox *usecase.InboxUsecase, flags
 // @Produce json
 // @Param limit query int false "Limite"
 // @Param cursor query string false "Cursor"
+// @Param projectId query string false "Filtrar por projeto"
 // @Success 200 {object} dto.ListTasksResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -41,6 +45,9 @@ func (h *TasksHandler) List(c *gin.Context) {
 	if !ok {
 		return
 	}
+	if projectID := strings.TrimSpace(c.Query("projectId")); projectID != "" {
+		opts.ProjectID = &projectID
+	}
 
 	tasks, next, err := h.Usecase.List(c.Request.Context(), userID, opts)
 	if err != nil {
@@ -48,98 +55,84 @@ func (h *TasksHandler) List(c *gin.Context) {
 		return
 	}
 
-	sourceIDs := make([]string, 0)
-	for _, task := range tasks {
-		if task.SourceInboxItemID != nil {
-			sourceIDs = append(sourceIDs, *task.SourceInboxItemID)
-		}
+	items, ok := h.toTaskResponses(c, userID, tasks)
+	if !ok {
+		return
 	}
 
-	sourcesByID := make(map[string]domain.InboxItem)
-	if h.Inbox != nil {
-		ids := uniqueStrings(sourceIDs)
-		if len(ids) > 0 {
-			items, err := h.Inbox.GetInboxItemsByIDs(c.Request.Context(), userID, ids)
-			if err != nil {
-				writeUsecaseError(c, err)
-				return
-			}
-			sourcesByID = items
-		}
-	}
+	c.JSON(http.StatusOK, dto.ListTasksResponse{Items: items, NextCursor: next})
+}
 
-	subflagIDs := make([]string, 0)
-	for _, task := range tasks {
-		if task.SubflagID != nil {
-			subflagIDs = append(subflagIDs, *task.SubflagID)
-		}
+// Matrix groups the open tasks into the Eisenhower quadrants.
+// @Summary Matriz de Eisenhower das tarefas
+// @Tags Tasks
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.TaskMatrixResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /v1/tasks/matrix [get]
+func (h *TasksHandler) Matrix(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
 	}
 
-	subflagsByID := make(map[string]domain.Subflag)
-	if h.Subflags != nil {
-		ids := uniqueStrings(subflagIDs)
-		if len(ids) > 0 {
-			subflags, err := h.Subflags.GetByIDs(c.Request.Context(), userID, ids)
-			if err != nil {
-				writeUsecaseError(c, err)
-				return
-			}
-			subflagsByID = subflags
-		}
+	matrix, err := h.Usecase.Matrix(c.Request.Context(), userID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
 	}
 
-	flagIDs := make([]string, 0)
-	for _, task := range tasks {
-		if task.FlagID != nil {
-			flagIDs = append(flagIDs, *task.FlagID)
-		}
-	}
-	for _, subflag := range subflagsByID {
-		flagIDs = append(flagIDs, subflag.FlagID)
+	// Um único lote para as quatro listas; depois reparte pelos tamanhos.
+	tasks := make([]domain.Task, 0, len(matrix.UrgentImportant)+len(matrix.NotUrgentImportant)+len(matrix.UrgentNotImportant)+len(matrix.NotUrgentNotImportant))
+	tasks = append(tasks, matrix.UrgentImportant...)
+	tasks = append(tasks, matrix.NotUrgentImportant...)
+	tasks = append(tasks, matrix.UrgentNotImportant...)
+	tasks = append(tasks, matrix.NotUrgentNotImportant...)
+
+	items, ok := h.toTaskResponses(c, userID, tasks)
+	if !ok {
+		return
 	}
 
-	flagsByID := make(map[string]domain.Flag)
-	if h.Flags != nil {
-		ids := uniqueStrings(flagIDs)
-		if len(ids) > 0 {
-			flags, err := h.Flags.GetByIDs(c.Request.Context(), userID, ids)
-			if err != nil {
-				writeUsecaseError(c, err)
-				return
-			}
-			flagsByID = flags
-		}
+	first := len(matrix.UrgentImportant)
+	second := first + len(matrix.NotUrgentImportant)
+	third := second + len(matrix.UrgentNotImportant)
+	c.JSON(http.StatusOK, dto.TaskMatrixResponse{
+		UrgentImportant:       items[:first],
+		NotUrgentImportant:    items[first:second],
+		UrgentNotImportant:    items[second:third],
+		NotUrgentNotImportant: items[third:],
+	})
+}
+
+// History lists every instance of the recurring series a task belongs to.
+// @Summary Historico da tarefa recorrente
+// @Tags Tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} dto.ListTasksResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/tasks/{id}/history [get]
+func (h *TasksHandler) History(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
 	}
 
-	items := make([]dto.TaskResponse, 0, len(tasks))
-	for _, task := range tasks {
-		var source *domain.InboxItem
-		if task.SourceInboxItemID != nil {
-			if item, ok := sourcesByID[*task.SourceInboxItemID]; ok {
-				source = &item
-			}
-		}
-		var flag *domain.Flag
-		if task.FlagID != nil {
-			if f, ok := flagsByID[*task.FlagID]; ok {
-				flag = &f
-			}
-		}
-		var subflag *domain.Subflag
-		if task.SubflagID != nil {
-			if sf, ok := subflagsByID[*task.SubflagID]; ok {
-				subflag = &sf
-			}
-		}
-		if flag == nil && subflag != nil {
-			if f, ok := flagsByID[subflag.FlagID]; ok {
-				flag = &f
-			}
-		}
-		items = append(items, toTaskResponse(task, source, flag, subflag))
+	tasks, err := h.Usecase.Series(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, dto.ListTasksResponse{Items: items, NextCursor: next})
+	items, ok := h.toTaskResponses(c, userID, tasks)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, dto.ListTasksResponse{Items: items})
 }
 
 // Create task.
@@ -152,6 +145,7 @@ func (h *TasksHandler) List(c *gin.Context) {
 // @Success 201 {object} dto.TaskResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Router /v1/tasks [post]
 func (h *TasksHandler) Create(c *gin.Context) {
 	userID, ok := getUserID(c)
@@ -165,7 +159,20 @@ func (h *TasksHandler) Create(c *gin.Context) {
 		return
 	}
 
-	task, err := h.Usecase.Create(c.Request.Context(), userID, req.Title, req.Description, req.Status, req.DueAt, req.FlagID, req.SubflagID, nil)
+	task, err := h.Usecase.Create(c.Request.Context(), userID, usecase.TaskCreateInput{
+		Title:        req.Title,
+		Description:  req.Description,
+		Status:       req.Status,
+		DueAt:        req.DueAt,
+		FlagID:       req.FlagID,
+		SubflagID:    req.SubflagID,
+		ProjectID:    req.ProjectID,
+		Priority:     req.Priority,
+		EffortMins:   req.EffortMins,
+		Recurrence:   toTaskRecurrence(req.Recurrence),
+		BlockedBy:    req.BlockedBy,
+		Notification: toNotificationOverride(req.Notification),
+	})
 	if err != nil {
 		writeUsecaseError(c, err)
 		return
@@ -198,7 +205,12 @@ func (h *TasksHandler) Create(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusCreated, toTaskResponse(task, nil, flag, subflag))
+	project, ok := h.taskProject(c, userID, task)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTaskResponse(task, nil, flag, subflag, project, nil))
 }
 
 // Update task.
@@ -213,6 +225,7 @@ func (h *TasksHandler) Create(c *gin.Context) {
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Router /v1/tasks/{id} [patch]
 func (h *TasksHandler) Update(c *gin.Context) {
 	userID, ok := getUserID(c)
@@ -234,6 +247,13 @@ func (h *TasksHandler) Update(c *gin.Context) {
 		DueAt:       req.DueAt,
 		FlagID:      req.FlagID,
 		SubflagID:   req.SubflagID,
+		ProjectID:   req.ProjectID,
+		Priority:    req.Priority,
+		EffortMins:  req.EffortMins,
+		Recurrence:  toTaskRecurrence(req.Recurrence),
+		BlockedBy:   req.BlockedBy,
+
+		Notification: toNotificationOverride(req.Notification),
 	})
 	if err != nil {
 		writeUsecaseError(c, err)
@@ -276,7 +296,21 @@ func (h *TasksHandler) Update(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, toTaskResponse(task, source, flag, subflag))
+	project, ok := h.taskProject(c, userID, task)
+	if !ok {
+		return
+	}
+	var checklist []domain.TaskChecklistItem
+	if h.Checklist != nil {
+		items, err := h.Checklist.ListByTask(c.Request.Context(), userID, task.ID)
+		if err != nil {
+			writeUsecaseError(c, err)
+			return
+		}
+		checklist = items
+	}
+
+	c.JSON(http.StatusOK, toTaskResponse(task, source, flag, subflag, project, checklist))
 }
 
 // Delete task.
@@ -303,3 +337,298 @@ func (h *TasksHandler) Delete(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// toTaskResponses resolves sources, flags, subflags, projects and checklists in batch;
+// it writes the error and returns false on failure.
+func (h *TasksHandler) toTaskResponses(c *gin.Context, userID string, tasks []domain.Task) ([]dto.TaskResponse, bool) {
+	sourceIDs := make([]string, 0)
+	for _, task := range tasks {
+		if task.SourceInboxItemID != nil {
+			sourceIDs = append(sourceIDs, *task.SourceInboxItemID)
+		}
+	}
+
+	sourcesByID := make(map[string]domain.InboxItem)
+	if h.Inbox != nil {
+		ids := uniqueStrings(sourceIDs)
+		if len(ids) > 0 {
+			items, err := h.Inbox.GetInboxItemsByIDs(c.Request.Context(), userID, ids)
+			if err != nil {
+				writeUsecaseError(c, err)
+				return nil, false
+			}
+			sourcesByID = items
+		}
+	}
+
+	subflagIDs := make([]string, 0)
+	for _, task := range tasks {
+		if task.SubflagID != nil {
+			subflagIDs = append(subflagIDs, *task.SubflagID)
+		}
+	}
+
+	subflagsByID := make(map[string]domain.Subflag)
+	if h.Subflags != nil {
+		ids := uniqueStrings(subflagIDs)
+		if len(ids) > 0 {
+			subflags, err := h.Subflags.GetByIDs(c.Request.Context(), userID, ids)
+			if err != nil {
+				writeUsecaseError(c, err)
+				return nil, false
+			}
+			subflagsByID = subflags
+		}
+	}
+
+	flagIDs := make([]string, 0)
+	for _, task := range tasks {
+		if task.FlagID != nil {
+			flagIDs = append(flagIDs, *task.FlagID)
+		}
+	}
+	for _, subflag := range subflagsByID {
+		flagIDs = append(flagIDs, subflag.FlagID)
+	}
+
+	flagsByID := make(map[string]domain.Flag)
+	if h.Flags != nil {
+		ids := uniqueStrings(flagIDs)
+		if len(ids) > 0 {
+			flags, err := h.Flags.GetByIDs(c.Request.Context(), userID, ids)
+			if err != nil {
+				writeUsecaseError(c, err)
+				return nil, false
+			}
+			flagsByID = flags
+		}
+	}
+
+	projectIDs := make([]string, 0)
+	for _, task := range tasks {
+		if task.ProjectID != nil {
+			projectIDs = append(projectIDs, *task.ProjectID)
+		}
+	}
+
+	projectsByID := make(map[string]domain.Project)
+	if h.Projects != nil {
+		ids := uniqueStrings(projectIDs)
+		if len(ids) > 0 {
+			projects, err := h.Projects.GetByIDs(c.Request.Context(), userID, ids)
+			if err != nil {
+				writeUsecaseError(c, err)
+				return nil, false
+			}
+			projectsByID = projects
+		}
+	}
+
+	checklistByTask := make(map[string][]domain.TaskChecklistItem)
+	if h.Checklist != nil && len(tasks) > 0 {
+		taskIDs := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		checklists, err := h.Checklist.ListByTasks(c.Request.Context(), userID, taskIDs)
+		if err != nil {
+			writeUsecaseError(c, err)
+			return nil, false
+		}
+		checklistByTask = checklists
+	}
+
+	items := make([]dto.TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		var source *domain.InboxItem
+		if task.SourceInboxItemID != nil {
+			if item, ok := sourcesByID[*task.SourceInboxItemID]; ok {
+				source = &item
+			}
+		}
+		var flag *domain.Flag
+		if task.FlagID != nil {
+			if f, ok := flagsByID[*task.FlagID]; ok {
+				flag = &f
+			}
+		}
+		var subflag *domain.Subflag
+		if task.SubflagID != nil {
+			if sf, ok := subflagsByID[*task.SubflagID]; ok {
+				subflag = &sf
+			}
+		}
+		if flag == nil && subflag != nil {
+			if f, ok := flagsByID[subflag.FlagID]; ok {
+				flag = &f
+			}
+		}
+		var project *domain.Project
+		if task.ProjectID != nil {
+			if p, ok := projectsByID[*task.ProjectID]; ok {
+				project = &p
+			}
+		}
+		items = append(items, toTaskResponse(task, source, flag, subflag, project, checklistByTask[task.ID]))
+	}
+	return items, true
+}
+
+// taskProject loads the project of a task for the response; it writes the error and returns false on failure.
+func (h *TasksHandler) taskProject(c *gin.Context, userID string, task domain.Task) (*domain.Project, bool) {
+	if h.Projects == nil || task.ProjectID == nil {
+		return nil, true
+	}
+	project, err := h.Projects.Get(c.Request.Context(), userID, *task.ProjectID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return nil, false
+	}
+	return &project, true
+}
+
+// ListChecklist lists the checklist items of a task.
+// @Summary Listar checklist da tarefa
+// @Tags Tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} dto.ListTaskChecklistItemsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/tasks/{id}/checklist [get]
+func (h *TasksHandler) ListChecklist(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	if h.Checklist == nil {
+		writeUsecaseError(c, usecase.ErrDependencyMissing)
+		return
+	}
+	taskID := c.Param("id")
+
+	checklist, err := h.Checklist.ListByTask(c.Request.Context(), userID, taskID)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	items := make([]dto.TaskChecklistItemResponse, 0, len(checklist))
+	for _, item := range checklist {
+		items = append(items, toTaskChecklistItemResponse(item))
+	}
+	c.JSON(http.StatusOK, dto.ListTaskChecklistItemsResponse{Items: items, Progress: toTaskProgressObject(checklist)})
+}
+
+// CreateChecklistItem adds a step to a task checklist.
+// @Summary Criar item do checklist da tarefa
+// @Tags Tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param body body dto.CreateTaskChecklistItemRequest true "Checklist item payload"
+// @Success 201 {object} dto.TaskChecklistItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/tasks/{id}/checklist [post]
+func (h *TasksHandler) CreateChecklistItem(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	if h.Checklist == nil {
+		writeUsecaseError(c, usecase.ErrDependencyMissing)
+		return
+	}
+	taskID := c.Param("id")
+
+	var req dto.CreateTaskChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	item, err := h.Checklist.Create(c.Request.Context(), userID, taskID, req.Title, req.Checked, req.SortOrder)
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTaskChecklistItemResponse(item))
+}
+
+// UpdateChecklistItem updates a task checklist item.
+// @Summary Atualizar item do checklist da tarefa
+// @Tags Tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Checklist item ID"
+// @Param body body dto.UpdateTaskChecklistItemRequest true "Checklist item payload"
+// @Success 200 {object} dto.TaskChecklistItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/task-checklist-items/{id} [patch]
+func (h *TasksHandler) UpdateChecklistItem(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	if h.Checklist == nil {
+		writeUsecaseError(c, usecase.ErrDependencyMissing)
+		return
+	}
+	id := c.Param("id")
+
+	var req dto.UpdateTaskChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_payload")
+		return
+	}
+
+	item, err := h.Checklist.Update(c.Request.Context(), userID, id, usecase.TaskChecklistItemUpdateInput{
+		Title:     req.Title,
+		Checked:   req.Checked,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTaskChecklistItemResponse(item))
+}
+
+// DeleteChecklistItem removes a task checklist item.
+// @Summary Excluir item do checklist da tarefa
+// @Tags Tasks
+// @Security BearerAuth
+// @Param id path string true "Checklist item ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /v1/task-checklist-items/{id} [delete]
+func (h *TasksHandler) DeleteChecklistItem(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	if h.Checklist == nil {
+		writeUsecaseError(c, usecase.ErrDependencyMissing)
+		return
+	}
+	id := c.Param("id")
+
+	if err := h.Checklist.Delete(c.Request.Context(), userID, id); err != nil {
+		writeUsecaseError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin allows only the configured admin user ids. Must run after Auth.
+func RequireAdmin(adminUserIDs []string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		allowed[id] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := allowed[GetUserID(c)]; !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
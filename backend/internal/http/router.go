@@ -1,6 +1,7 @@
 package http
 
 import (
+	"expvar"
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,9 @@ func NewRouter(cfg config.Config, log *slog.Logger, authHandler *handler.AuthHan
 
 	engine.GET("/healthz", handler.HealthHandler)
 	engine.GET("/readyz", handler.ReadinessHandler(readinessCheckers...))
+	if cfg.MetricsEnabled {
+		engine.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	}
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	v1 := engine.Group("/v1")
@@ -31,6 +35,12 @@ func NewRouter(cfg config.Config, log *slog.Logger, authHandler *handler.AuthHan
 	if apiHandlers != nil && apiHandlers.Digest != nil {
 		v1.GET("/daily-summary", apiHandlers.Digest.GetDailySummary)
 	}
+	// Public e-mail endpoints: signed unsubscribe links and provider webhooks
+	if apiHandlers != nil && apiHandlers.Email != nil {
+		v1.GET("/email/unsubscribe", apiHandlers.Email.UnsubscribePage)
+		v1.POST("/email/unsubscribe", apiHandlers.Email.Unsubscribe)
+		v1.POST("/webhooks/resend", apiHandlers.Email.ResendWebhook)
+	}
 	if authHandler != nil {
 		v1.POST("/auth/signup", authHandler.Signup)
 		v1.POST("/auth/login", authHandler.Login)
@@ -75,9 +85,22 @@ func NewRouter(cfg config.Config, log *slog.Logger, authHandler *handler.AuthHan
 		}
 		if apiHandlers.Tasks != nil {
 			authGroup.GET("/tasks", apiHandlers.Tasks.List)
+			authGroup.GET("/tasks/matrix", apiHandlers.Tasks.Matrix)
 			authGroup.POST("/tasks", apiHandlers.Tasks.Create)
 			authGroup.PATCH("/tasks/:id", apiHandlers.Tasks.Update)
 			authGroup.DELETE("/tasks/:id", apiHandlers.Tasks.Delete)
+			authGroup.GET("/tasks/:id/history", apiHandlers.Tasks.History)
+			authGroup.GET("/tasks/:id/checklist", apiHandlers.Tasks.ListChecklist)
+			authGroup.POST("/tasks/:id/checklist", apiHandlers.Tasks.CreateChecklistItem)
+			authGroup.PATCH("/task-checklist-items/:id", apiHandlers.Tasks.UpdateChecklistItem)
+			authGroup.DELETE("/task-checklist-items/:id", apiHandlers.Tasks.DeleteChecklistItem)
+		}
+		if apiHandlers.Projects != nil {
+			authGroup.GET("/projects", apiHandlers.Projects.List)
+			authGroup.POST("/projects", apiHandlers.Projects.Create)
+			authGroup.GET("/projects/:id", apiHandlers.Projects.Get)
+			authGroup.PATCH("/projects/:id", apiHandlers.Projects.Update)
+			authGroup.DELETE("/projects/:id", apiHandlers.Projects.Delete)
 		}
 		if apiHandlers.Reminders != nil {
 			authGroup.GET("/reminders", apiHandlers.Reminders.List)
@@ -107,6 +130,7 @@ func NewRouter(cfg config.Config, log *slog.Logger, authHandler *handler.AuthHan
 			authGroup.GET("/routines", apiHandlers.Routines.List)
 			authGroup.GET("/routines/day/:weekday", apiHandlers.Routines.ListByWeekday)
 			authGroup.GET("/routines/today/summary", apiHandlers.Routines.GetTodaySummary)
+			authGroup.GET("/routines/stats", apiHandlers.Routines.GetAllStats)
 			authGroup.GET("/routines/:id", apiHandlers.Routines.Get)
 			authGroup.POST("/routines", apiHandlers.Routines.Create)
 			authGroup.PATCH("/routines/:id", apiHandlers.Routines.Update)
@@ -116,12 +140,24 @@ func NewRouter(cfg config.Config, log *slog.Logger, authHandler *handler.AuthHan
 			authGroup.DELETE("/routines/:id/complete/:date", apiHandlers.Routines.Uncomplete)
 			authGroup.GET("/routines/:id/history", apiHandlers.Routines.GetHistory)
 			authGroup.GET("/routines/:id/streak", apiHandlers.Routines.GetStreak)
+			authGroup.GET("/routines/:id/stats", apiHandlers.Routines.GetStats)
 			authGroup.POST("/routines/:id/exceptions", apiHandlers.Routines.CreateException)
 			authGroup.DELETE("/routines/:id/exceptions/:date", apiHandlers.Routines.DeleteException)
 		}
+		if apiHandlers.Away != nil {
+			authGroup.GET("/away-periods", apiHandlers.Away.List)
+			authGroup.POST("/away-periods", apiHandlers.Away.Create)
+			authGroup.DELETE("/away-periods/:id", apiHandlers.Away.Delete)
+		}
+		if apiHandlers.Holidays != nil {
+			authGroup.GET("/holiday-calendars", apiHandlers.Holidays.ListCalendars)
+			authGroup.GET("/holidays", apiHandlers.Holidays.List)
+			authGroup.PUT("/me/holiday-calendar", apiHandlers.Holidays.SetCalendar)
+		}
 		if apiHandlers.Devices != nil {
 			authGroup.POST("/devices/token", apiHandlers.Devices.RegisterToken)
 			authGroup.DELETE("/devices/token", apiHandlers.Devices.UnregisterToken)
+			authGroup.POST("/devices/topic/rotate", apiHandlers.Devices.RotateTopic)
 		}
 		if apiHandlers.Notifications != nil {
 			authGroup.GET("/notification-preferences", apiHandlers.Notifications.GetPreferences)
@@ -135,6 +171,20 @@ func NewRouter(cfg config.Config, log *slog.Logger, authHandler *handler.AuthHan
 		}
 		if apiHandlers.Digest != nil {
 			authGroup.POST("/digest/test", apiHandlers.Digest.SendTestEmail)
+			authGroup.POST("/digest/weekly/test", apiHandlers.Digest.SendTestWeeklyEmail)
+			authGroup.GET("/digest/settings", apiHandlers.Digest.GetSettings)
+			authGroup.PUT("/digest/settings", apiHandlers.Digest.UpdateSettings)
+			authGroup.GET("/digest/preview", apiHandlers.Digest.Preview)
+		}
+
+		adminGroup := authGroup.Group("/admin", middleware.RequireAdmin(cfg.AdminUserIDs))
+		if apiHandlers.NotificationTemplates != nil {
+			adminGroup.GET("/notification-templates", apiHandlers.NotificationTemplates.List)
+			adminGroup.POST("/notification-templates", apiHandlers.NotificationTemplates.Create)
+			adminGroup.POST("/notification-templates/preview", apiHandlers.NotificationTemplates.Preview)
+			adminGroup.POST("/notification-templates/reload", apiHandlers.NotificationTemplates.Reload)
+			adminGroup.PATCH("/notification-templates/:id", apiHandlers.NotificationTemplates.Update)
+			adminGroup.DELETE("/notification-templates/:id", apiHandlers.NotificationTemplates.Delete)
 		}
 	}
 
@@ -7,6 +7,8 @@ type SendRequest struct {
 	Subject string
 	Html    string
 	Text    string
+	// Headers are extra message headers (e.g. List-Unsubscribe).
+	Headers map[string]string
 }
 
 type Mailer interface {
@@ -24,6 +24,8 @@ type resendRequest struct {
 	Subject string   `json:"subject"`
 	Html    string   `json:"html,omitempty"`
 	Text    string   `json:"text,omitempty"`
+
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 type resendResponse struct {
@@ -68,6 +70,7 @@ func (m *ResendMailer) Send(ctx context.Context, req SendRequest) (string, error
 		Subject: req.Subject,
 		Html:    req.Html,
 		Text:    req.Text,
+		Headers: req.Headers,
 	}
 
 	body, err := json.Marshal(payload)
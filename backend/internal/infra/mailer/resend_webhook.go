@@ -0,0 +1,143 @@
+package mailer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resend assina os webhooks no formato Svix (svix-id, svix-timestamp, svix-signature).
+const resendWebhookTolerance = 5 * time.Minute
+
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// Tipos de evento normalizados, independentes do provedor.
+const (
+	EmailEventSent       = "sent"
+	EmailEventDelivered  = "delivered"
+	EmailEventDelayed    = "delivery_delayed"
+	EmailEventBounced    = "bounced"
+	EmailEventComplained = "complained"
+	EmailEventOpened     = "opened"
+	EmailEventClicked    = "clicked"
+)
+
+// EmailEvent is a delivery event reported by the e-mail provider.
+type EmailEvent struct {
+	Type       string
+	ProviderID string
+	Recipients []string
+	// HardBounce is true for permanent bounces; transient ones only update the status.
+	HardBounce bool
+	Reason     string
+	OccurredAt time.Time
+}
+
+type resendWebhookPayload struct {
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      struct {
+		EmailID string   `json:"email_id"`
+		To      []string `json:"to"`
+		Bounce  *struct {
+			Type    string `json:"type"`
+			SubType string `json:"subType"`
+			Message string `json:"message"`
+		} `json:"bounce"`
+	} `json:"data"`
+}
+
+// VerifyResendWebhook checks the Svix signature headers against the raw body.
+// secret is the signing secret shown by Resend ("whsec_...").
+func VerifyResendWebhook(secret string, header http.Header, body []byte, now time.Time) error {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return fmt.Errorf("resend webhook secret is not configured")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, "whsec_"))
+	if err != nil {
+		return fmt.Errorf("invalid resend webhook secret: %w", err)
+	}
+
+	id := firstHeader(header, "svix-id", "webhook-id")
+	timestamp := firstHeader(header, "svix-timestamp", "webhook-timestamp")
+	signatures := firstHeader(header, "svix-signature", "webhook-signature")
+	if id == "" || timestamp == "" || signatures == "" {
+		return ErrInvalidWebhookSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+	sentAt := time.Unix(unix, 0)
+	if now.Sub(sentAt) > resendWebhookTolerance || sentAt.Sub(now) > resendWebhookTolerance {
+		return ErrInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	for _, candidate := range strings.Fields(signatures) {
+		version, sig, ok := strings.Cut(candidate, ",")
+		if !ok || version != "v1" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(sig)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidWebhookSignature
+}
+
+// ParseResendEvent converts a Resend webhook body into an EmailEvent.
+// Events other than email.* come back with an empty Type.
+func ParseResendEvent(body []byte) (EmailEvent, error) {
+	var payload resendWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return EmailEvent{}, fmt.Errorf("decode resend webhook: %w", err)
+	}
+
+	eventType, ok := strings.CutPrefix(payload.Type, "email.")
+	if !ok {
+		return EmailEvent{}, nil
+	}
+
+	event := EmailEvent{
+		Type:       eventType,
+		ProviderID: strings.TrimSpace(payload.Data.EmailID),
+		Recipients: payload.Data.To,
+		OccurredAt: payload.CreatedAt,
+	}
+	if eventType == EmailEventBounced {
+		// Resend só emite email.bounced para rejeições permanentes; "Transient" é tratado como soft bounce.
+		event.HardBounce = true
+		if b := payload.Data.Bounce; b != nil {
+			event.HardBounce = !strings.EqualFold(b.Type, "Transient")
+			event.Reason = strings.TrimSpace(b.Message)
+		}
+	}
+	return event, nil
+}
+
+func firstHeader(header http.Header, names ...string) string {
+	for _, name := range names {
+		if v := strings.TrimSpace(header.Get(name)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package mailer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signResendWebhook(key []byte, id string, sentAt time.Time, body string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + strconv.FormatInt(sentAt.Unix(), 10) + "." + body))
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyResendWebhook(t *testing.T) {
+	key := []byte("resend-webhook-signing-key-32b!!")
+	secret := "whsec_" + base64.StdEncoding.EncodeToString(key)
+	otherSecret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("another-signing-key-of-32-bytes!"))
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	body := `{"type":"email.delivered","data":{"email_id":"e1"}}`
+
+	headers := func(id string, sentAt time.Time, signature string) http.Header {
+		h := http.Header{}
+		if id != "" {
+			h.Set("svix-id", id)
+		}
+		if !sentAt.IsZero() {
+			h.Set("svix-timestamp", strconv.FormatInt(sentAt.Unix(), 10))
+		}
+		if signature != "" {
+			h.Set("svix-signature", signature)
+		}
+		return h
+	}
+	valid := signResendWebhook(key, "msg_1", now, body)
+
+	cases := []struct {
+		name    string
+		secret  string
+		header  http.Header
+		body    string
+		wantErr bool
+	}{
+		{name: "valid v1 signature", secret: secret, header: headers("msg_1", now, valid), body: body},
+		{name: "secret without prefix", secret: base64.StdEncoding.EncodeToString(key), header: headers("msg_1", now, valid), body: body},
+		{name: "webhook-* headers", secret: secret, header: http.Header{
+			"Webhook-Id":        {"msg_1"},
+			"Webhook-Timestamp": {strconv.FormatInt(now.Unix(), 10)},
+			"Webhook-Signature": {valid},
+		}, body: body},
+		{name: "wrong secret", secret: otherSecret, header: headers("msg_1", now, valid), body: body, wantErr: true},
+		{name: "tampered body", secret: secret, header: headers("msg_1", now, valid), body: `{"type":"email.bounced","data":{"email_id":"e1"}}`, wantErr: true},
+		{name: "tampered id", secret: secret, header: headers("msg_2", now, valid), body: body, wantErr: true},
+		{
+			name:   "timestamp inside tolerance",
+			secret: secret,
+			header: headers("msg_1", now.Add(-4*time.Minute), signResendWebhook(key, "msg_1", now.Add(-4*time.Minute), body)),
+			body:   body,
+		},
+		{
+			name:    "timestamp too old",
+			secret:  secret,
+			header:  headers("msg_1", now.Add(-6*time.Minute), signResendWebhook(key, "msg_1", now.Add(-6*time.Minute), body)),
+			body:    body,
+			wantErr: true,
+		},
+		{
+			name:    "timestamp in the future",
+			secret:  secret,
+			header:  headers("msg_1", now.Add(6*time.Minute), signResendWebhook(key, "msg_1", now.Add(6*time.Minute), body)),
+			body:    body,
+			wantErr: true,
+		},
+		{name: "missing id", secret: secret, header: headers("", now, valid), body: body, wantErr: true},
+		{name: "missing timestamp", secret: secret, header: headers("msg_1", time.Time{}, valid), body: body, wantErr: true},
+		{name: "missing signature", secret: secret, header: headers("msg_1", now, ""), body: body, wantErr: true},
+		{
+			name:   "one of several signatures matches",
+			secret: secret,
+			header: headers("msg_1", now, "v1,bm90LXRoZS1zaWduYXR1cmU= v2,ignored "+valid),
+			body:   body,
+		},
+		{
+			name:    "none of several signatures matches",
+			secret:  secret,
+			header:  headers("msg_1", now, "v1,bm90LXRoZS1zaWduYXR1cmU= "+signResendWebhook(key, "msg_2", now, body)),
+			body:    body,
+			wantErr: true,
+		},
+		{name: "signature without version", secret: secret, header: headers("msg_1", now, valid[len("v1,"):]), body: body, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyResendWebhook(tc.secret, tc.header, []byte(tc.body), now)
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidWebhookSignature) {
+					t.Fatalf("expected ErrInvalidWebhookSignature, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyResendWebhookRejectsBadSecret(t *testing.T) {
+	for _, secret := range []string{"", "  ", "whsec_not base64!"} {
+		err := VerifyResendWebhook(secret, http.Header{}, nil, time.Now())
+		if err == nil || errors.Is(err, ErrInvalidWebhookSignature) {
+			t.Fatalf("%q: expected configuration error, got %v", secret, err)
+		}
+	}
+}
+
+func TestParseResendEvent(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want EmailEvent
+	}{
+		{
+			name: "hard bounce",
+			body: `{"type":"email.bounced","created_at":"2026-03-10T12:00:00Z","data":{"email_id":" e1 ","to":["ana@example.com"],"bounce":{"type":"Permanent","subType":"General","message":" Mailbox does not exist "}}}`,
+			want: EmailEvent{Type: EmailEventBounced, ProviderID: "e1", Recipients: []string{"ana@example.com"}, HardBounce: true, Reason: "Mailbox does not exist"},
+		},
+		{
+			name: "transient bounce",
+			body: `{"type":"email.bounced","created_at":"2026-03-10T12:00:00Z","data":{"email_id":"e2","to":["ana@example.com"],"bounce":{"type":"transient","message":"Mailbox full"}}}`,
+			want: EmailEvent{Type: EmailEventBounced, ProviderID: "e2", Recipients: []string{"ana@example.com"}, Reason: "Mailbox full"},
+		},
+		{
+			name: "bounce without details is permanent",
+			body: `{"type":"email.bounced","created_at":"2026-03-10T12:00:00Z","data":{"email_id":"e3"}}`,
+			want: EmailEvent{Type: EmailEventBounced, ProviderID: "e3", HardBounce: true},
+		},
+		{
+			name: "delivered",
+			body: `{"type":"email.delivered","created_at":"2026-03-10T12:00:00Z","data":{"email_id":"e4","to":["ana@example.com"]}}`,
+			want: EmailEvent{Type: EmailEventDelivered, ProviderID: "e4", Recipients: []string{"ana@example.com"}},
+		},
+		{
+			name: "non-email event",
+			body: `{"type":"domain.updated","created_at":"2026-03-10T12:00:00Z","data":{}}`,
+		},
+	}
+
+	occurredAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseResendEvent([]byte(tc.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.want.Type == "" {
+				if got.Type != "" {
+					t.Fatalf("expected empty event, got %+v", got)
+				}
+				return
+			}
+			if got.Type != tc.want.Type || got.ProviderID != tc.want.ProviderID || got.HardBounce != tc.want.HardBounce || got.Reason != tc.want.Reason {
+				t.Fatalf("ParseResendEvent = %+v, want %+v", got, tc.want)
+			}
+			if len(got.Recipients) != len(tc.want.Recipients) || (len(got.Recipients) > 0 && got.Recipients[0] != tc.want.Recipients[0]) {
+				t.Fatalf("recipients = %v, want %v", got.Recipients, tc.want.Recipients)
+			}
+			if !got.OccurredAt.Equal(occurredAt) {
+				t.Fatalf("occurred at = %s", got.OccurredAt)
+			}
+		})
+	}
+
+	if _, err := ParseResendEvent([]byte("not json")); err == nil {
+		t.Fatalf("expected error for invalid body")
+	}
+}
@@ -0,0 +1,210 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig configures a plain SMTP server (self-hosted relay, mailpit, ...).
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// StartTLS upgrades the connection before auth; it fails when the server does not offer it.
+	StartTLS bool
+	Timeout  time.Duration
+}
+
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	cfg.Host = strings.TrimSpace(cfg.Host)
+	cfg.From = strings.TrimSpace(cfg.From)
+	if cfg.Port <= 0 {
+		cfg.Port = 587
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SMTPMailer{cfg: cfg}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, req SendRequest) (string, error) {
+	if m.cfg.Host == "" {
+		return "", fmt.Errorf("smtp host is not configured")
+	}
+	if m.cfg.From == "" {
+		return "", fmt.Errorf("smtp from is not configured")
+	}
+	if len(req.To) == 0 {
+		return "", fmt.Errorf("send request must have at least one recipient")
+	}
+
+	from, err := mail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return "", fmt.Errorf("invalid smtp from: %w", err)
+	}
+
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return "", err
+	}
+	msg, err := buildMessage(m.cfg.From, req, messageID, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return "", fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if m.cfg.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return "", fmt.Errorf("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return "", fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return "", fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return "", fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, to := range req.To {
+		if err := client.Rcpt(to); err != nil {
+			return "", fmt.Errorf("smtp RCPT TO %s failed: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return "", fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return "", fmt.Errorf("failed to write smtp message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("smtp server rejected message: %w", err)
+	}
+
+	_ = client.Quit()
+	return messageID, nil
+}
+
+// buildMessage renders a multipart/alternative message (text first, then HTML).
+func buildMessage(from string, req SendRequest, messageID string, now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", req.Text},
+		{"text/html; charset=UTF-8", req.Html},
+	}
+	for _, part := range parts {
+		if part.content == "" {
+			continue
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email part: %w", err)
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to encode email part: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode email part: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email body: %w", err)
+	}
+
+	var msg bytes.Buffer
+	headers := [][2]string{
+		{"From", from},
+		{"To", strings.Join(req.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("UTF-8", req.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	extra := make([]string, 0, len(req.Headers))
+	for name := range req.Headers {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		headers = append(headers, [2]string{textproto.CanonicalMIMEHeaderKey(name), headerValueSanitizer.Replace(req.Headers[name])})
+	}
+	for _, h := range headers {
+		msg.WriteString(h[0] + ": " + h[1] + "\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// headerValueSanitizer drops line breaks so a header value cannot inject new headers.
+var headerValueSanitizer = strings.NewReplacer("\r", "", "\n", "")
+
+func newMessageID(fromAddress string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate message id: %w", err)
+	}
+	domain := "inbota.local"
+	if _, host, ok := strings.Cut(fromAddress, "@"); ok && host != "" {
+		domain = host
+	}
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">", nil
+}
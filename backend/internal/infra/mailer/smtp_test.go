@@ -0,0 +1,344 @@
+package mailer
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBuildMessage(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	req := SendRequest{
+		To:      []string{"ana@example.com", "bia@example.com"},
+		Subject: "Resumo do dia às 9h",
+		Text:    "Olá, Ana! Você tem 3 tarefas hoje.",
+		Html:    "<p>Olá, <b>Ana</b>!</p>",
+		Headers: map[string]string{
+			"list-unsubscribe":      "<https://inbota.app/unsubscribe?token=abc>",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}
+
+	raw, err := buildMessage("Inbota <no-reply@inbota.app>", req, "<id-1@inbota.app>", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("message does not parse: %v", err)
+	}
+
+	if got := msg.Header.Get("From"); got != "Inbota <no-reply@inbota.app>" {
+		t.Fatalf("From = %q", got)
+	}
+	if got := msg.Header.Get("To"); got != "ana@example.com, bia@example.com" {
+		t.Fatalf("To = %q", got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != req.Subject {
+		t.Fatalf("Subject = %q (%v), want %q", subject, err, req.Subject)
+	}
+	if raw := msg.Header.Get("Subject"); !strings.HasPrefix(raw, "=?UTF-8?q?") {
+		t.Fatalf("expected Q-encoded subject, got %q", raw)
+	}
+	if got := msg.Header.Get("Date"); got != now.Format(time.RFC1123Z) {
+		t.Fatalf("Date = %q", got)
+	}
+	if got := msg.Header.Get("Message-Id"); got != "<id-1@inbota.app>" {
+		t.Fatalf("Message-ID = %q", got)
+	}
+	if got := msg.Header.Get("Mime-Version"); got != "1.0" {
+		t.Fatalf("MIME-Version = %q", got)
+	}
+	if got := msg.Header.Get("List-Unsubscribe"); got != "<https://inbota.app/unsubscribe?token=abc>" {
+		t.Fatalf("List-Unsubscribe = %q", got)
+	}
+	if got := msg.Header.Get("List-Unsubscribe-Post"); got != "List-Unsubscribe=One-Click" {
+		t.Fatalf("List-Unsubscribe-Post = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v)", msg.Header.Get("Content-Type"), err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	wantParts := []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", req.Text},
+		{"text/html; charset=UTF-8", req.Html},
+	}
+	for _, want := range wantParts {
+		part, err := mr.NextRawPart()
+		if err != nil {
+			t.Fatalf("missing %s part: %v", want.contentType, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != want.contentType {
+			t.Fatalf("part Content-Type = %q, want %q", got, want.contentType)
+		}
+		if got := part.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+			t.Fatalf("part Content-Transfer-Encoding = %q", got)
+		}
+		encoded, _ := io.ReadAll(part)
+		if strings.Contains(string(encoded), "á") {
+			t.Fatalf("expected non-ASCII to be quoted-printable encoded, got %q", encoded)
+		}
+		decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(string(encoded))))
+		if err != nil || string(decoded) != want.content {
+			t.Fatalf("decoded part = %q (%v), want %q", decoded, err, want.content)
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Fatalf("expected only two parts, got %v", err)
+	}
+}
+
+func TestBuildMessageSkipsEmptyParts(t *testing.T) {
+	raw, err := buildMessage("no-reply@inbota.app", SendRequest{To: []string{"ana@example.com"}, Text: "Só texto"}, "<id@inbota.app>", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(raw), "text/html") {
+		t.Fatalf("expected no HTML part when Html is empty")
+	}
+}
+
+func TestBuildMessageStripsHeaderInjection(t *testing.T) {
+	req := SendRequest{
+		To:   []string{"ana@example.com"},
+		Text: "oi",
+		Headers: map[string]string{
+			"List-Unsubscribe": "<https://inbota.app/u>\r\nBcc: intruso@example.com\nX-Injected: 1",
+		},
+	}
+	raw, err := buildMessage("no-reply@inbota.app", req, "<id@inbota.app>", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("message does not parse: %v", err)
+	}
+	if msg.Header.Get("Bcc") != "" || msg.Header.Get("X-Injected") != "" {
+		t.Fatalf("expected no injected headers, got %v", msg.Header)
+	}
+	if got := msg.Header.Get("List-Unsubscribe"); got != "<https://inbota.app/u>Bcc: intruso@example.comX-Injected: 1" {
+		t.Fatalf("List-Unsubscribe = %q", got)
+	}
+}
+
+// fakeSMTPServer is a minimal in-process SMTP server that records the session.
+type fakeSMTPServer struct {
+	listener   net.Listener
+	extensions []string
+
+	mu       sync.Mutex
+	commands []string
+	auth     string
+	data     string
+	done     chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T, extensions ...string) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: listener, extensions: extensions, done: make(chan struct{})}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	tp := textproto.NewConn(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			_ = tp.PrintfLine("%s", line)
+		}
+	}
+	reply("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		verb = strings.ToUpper(verb)
+		s.mu.Lock()
+		s.commands = append(s.commands, verb)
+		s.mu.Unlock()
+
+		switch verb {
+		case "EHLO":
+			lines := []string{"fake"}
+			lines = append(lines, s.extensions...)
+			for i, ext := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				reply("250" + sep + ext)
+			}
+		case "STARTTLS":
+			reply("454 TLS not available")
+		case "AUTH":
+			s.mu.Lock()
+			s.auth = arg
+			s.mu.Unlock()
+			reply("235 authenticated")
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = strings.Join(lines, "\n")
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) session() (commands []string, auth, data string) {
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commands, s.auth, s.data
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	req := SendRequest{To: []string{"ana@example.com"}, Subject: "Oi", Text: "Olá"}
+
+	cases := []struct {
+		name         string
+		extensions   []string
+		startTLS     bool
+		username     string
+		wantErr      string
+		wantCommands []string
+		wantAuth     string
+	}{
+		{
+			name:         "plain session without auth",
+			wantCommands: []string{"EHLO", "MAIL", "RCPT", "DATA", "QUIT"},
+		},
+		{
+			name:         "optional STARTTLS is not attempted",
+			extensions:   []string{"STARTTLS"},
+			wantCommands: []string{"EHLO", "MAIL", "RCPT", "DATA", "QUIT"},
+		},
+		{
+			name:         "required STARTTLS not offered",
+			startTLS:     true,
+			wantErr:      "does not support STARTTLS",
+			wantCommands: []string{"EHLO"},
+		},
+		{
+			name:         "required STARTTLS refused by the server",
+			extensions:   []string{"STARTTLS"},
+			startTLS:     true,
+			wantErr:      "smtp starttls failed",
+			wantCommands: []string{"EHLO", "STARTTLS"},
+		},
+		{
+			name:         "auth with credentials",
+			extensions:   []string{"AUTH PLAIN"},
+			username:     "inbota",
+			wantCommands: []string{"EHLO", "AUTH", "MAIL", "RCPT", "DATA", "QUIT"},
+			wantAuth:     "PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00inbota\x00s3cret")),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeSMTPServer(t, tc.extensions...)
+			mailer := NewSMTPMailer(SMTPConfig{
+				Host:     "127.0.0.1",
+				Port:     server.port(),
+				Username: tc.username,
+				Password: "s3cret",
+				From:     "Inbota <no-reply@inbota.app>",
+				StartTLS: tc.startTLS,
+				Timeout:  5 * time.Second,
+			})
+
+			messageID, err := mailer.Send(context.Background(), req)
+			commands, auth, data := server.session()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				if data != "" {
+					t.Fatalf("expected no message to be sent, got %q", data)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !strings.HasSuffix(messageID, "@inbota.app>") {
+					t.Fatalf("unexpected message id %q", messageID)
+				}
+				if !strings.Contains(data, "Message-ID: "+messageID) {
+					t.Fatalf("expected sent message to carry the returned id, got %q", data)
+				}
+			}
+			if strings.Join(commands, " ") != strings.Join(tc.wantCommands, " ") {
+				t.Fatalf("commands = %v, want %v", commands, tc.wantCommands)
+			}
+			if auth != tc.wantAuth {
+				t.Fatalf("auth = %q, want %q", auth, tc.wantAuth)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerSendRequiresConfig(t *testing.T) {
+	req := SendRequest{To: []string{"ana@example.com"}, Text: "oi"}
+	cases := []struct {
+		name string
+		cfg  SMTPConfig
+		req  SendRequest
+	}{
+		{name: "missing host", cfg: SMTPConfig{From: "no-reply@inbota.app"}, req: req},
+		{name: "missing from", cfg: SMTPConfig{Host: "127.0.0.1"}, req: req},
+		{name: "invalid from", cfg: SMTPConfig{Host: "127.0.0.1", From: "not an address"}, req: req},
+		{name: "no recipients", cfg: SMTPConfig{Host: "127.0.0.1", From: "no-reply@inbota.app"}, req: SendRequest{Text: "oi"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewSMTPMailer(tc.cfg).Send(context.Background(), tc.req); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrRecipientSuppressed is returned when every recipient is on the suppression list.
+var ErrRecipientSuppressed = errors.New("recipient suppressed")
+
+// SuppressionChecker reports addresses that must not receive e-mail (hard bounces, complaints).
+type SuppressionChecker interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
+
+type SuppressingMailer struct {
+	next    Mailer
+	checker SuppressionChecker
+}
+
+// NewSuppressingMailer drops suppressed recipients before handing the message to next.
+func NewSuppressingMailer(next Mailer, checker SuppressionChecker) *SuppressingMailer {
+	return &SuppressingMailer{next: next, checker: checker}
+}
+
+func (m *SuppressingMailer) Send(ctx context.Context, req SendRequest) (string, error) {
+	if m.checker == nil {
+		return m.next.Send(ctx, req)
+	}
+
+	allowed := make([]string, 0, len(req.To))
+	for _, to := range req.To {
+		suppressed, err := m.checker.IsSuppressed(ctx, to)
+		if err != nil {
+			return "", fmt.Errorf("check email suppression: %w", err)
+		}
+		if !suppressed {
+			allowed = append(allowed, to)
+		}
+	}
+	if len(req.To) > 0 && len(allowed) == 0 {
+		return "", ErrRecipientSuppressed
+	}
+
+	req.To = allowed
+	return m.next.Send(ctx, req)
+}
@@ -7,16 +7,33 @@ import (
 	texttemplate "text/template"
 )
 
-//go:embed templates/*.html templates/*.txt
+//go:embed templates/*.html templates/*.txt templates/*.md
 var templatesFS embed.FS
 
 func ParseDailyDigestTemplates() (*htmltemplate.Template, *texttemplate.Template, error) {
-	htmlTmpl, err := htmltemplate.ParseFS(templatesFS, "templates/daily_digest.html")
+	return parseDigestTemplates("daily_digest")
+}
+
+// ParseDailyDigestMarkdownTemplate parses the Markdown variant served by the public daily summary.
+func ParseDailyDigestMarkdownTemplate() (*texttemplate.Template, error) {
+	tmpl, err := texttemplate.ParseFS(templatesFS, "templates/daily_digest.md")
+	if err != nil {
+		return nil, fmt.Errorf("parse markdown template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func ParseWeeklyDigestTemplates() (*htmltemplate.Template, *texttemplate.Template, error) {
+	return parseDigestTemplates("weekly_digest")
+}
+
+func parseDigestTemplates(name string) (*htmltemplate.Template, *texttemplate.Template, error) {
+	htmlTmpl, err := htmltemplate.ParseFS(templatesFS, "templates/"+name+".html")
 	if err != nil {
 		return nil, nil, fmt.Errorf("parse html template: %w", err)
 	}
 
-	textTmpl, err := texttemplate.ParseFS(templatesFS, "templates/daily_digest.txt")
+	textTmpl, err := texttemplate.ParseFS(templatesFS, "templates/"+name+".txt")
 	if err != nil {
 		return nil, nil, fmt.Errorf("parse text template: %w", err)
 	}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// AdvisoryLock is a session-level pg_advisory_lock held on a dedicated connection.
+// If the process dies the connection closes and Postgres releases the lock, so
+// another instance takes over on its next tick.
+// Requires a direct (session) connection: transaction-mode poolers do not keep advisory locks.
+type AdvisoryLock struct {
+	db  *DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewAdvisoryLock(db *DB, key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: db, key: key}
+}
+
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		var held bool
+		err := l.conn.QueryRowContext(ctx, `
+			SELECT EXISTS(
+				SELECT 1 FROM pg_locks
+				WHERE locktype = 'advisory' AND objsubid = 1 AND pid = pg_backend_pid() AND granted
+				  AND ((classid::bigint << 32) | objid::bigint) = $1
+			)
+		`, l.key).Scan(&held)
+		if err == nil && held {
+			return true, nil
+		}
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, err
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	_, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key)
+	_ = l.conn.Close()
+	l.conn = nil
+	return err
+}
@@ -25,16 +25,17 @@ func (r *AgendaRepository) List(ctx context.Context, userID string, opts reposit
 		SELECT item_type, id, user_id, title, description, status, scheduled_at,
 		       due_at, remind_at, start_at, end_at, all_day, location,
 		       flag_id, subflag_id, resolved_flag_id, flag_name, flag_color, subflag_name, subflag_color,
-		       created_at, updated_at
+		       created_at, updated_at, project_id, project_name, blocked
 		FROM inbota.view_agenda_consolidada
 		WHERE user_id = $1
 		  AND ($2::timestamptz IS NULL OR scheduled_at >= $2::timestamptz)
 		  AND ($3::timestamptz IS NULL OR scheduled_at < $3::timestamptz)
+		  AND ($6::uuid IS NULL OR project_id = $6::uuid)
 		ORDER BY scheduled_at, created_at
 		LIMIT $4 OFFSET $5
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, opts.StartAt, opts.EndAt, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, userID, opts.StartAt, opts.EndAt, limit, offset, opts.ProjectID)
 	if err != nil {
 		return nil, err
 	}
@@ -48,12 +49,13 @@ func (r *AgendaRepository) List(ctx context.Context, userID string, opts reposit
 		var allDay sql.NullBool
 		var flagID, subflagID, resolvedFlagID sql.NullString
 		var flagName, flagColor, subflagName, subflagColor sql.NullString
+		var projectID, projectName sql.NullString
 
 		if err := rows.Scan(
 			&item.ItemType, &item.ID, &item.UserID, &item.Title, &description, &item.Status, &item.ScheduledAt,
 			&dueAt, &remindAt, &startAt, &endAt, &allDay, &location,
 			&flagID, &subflagID, &resolvedFlagID, &flagName, &flagColor, &subflagName, &subflagColor,
-			&item.CreatedAt, &item.UpdatedAt,
+			&item.CreatedAt, &item.UpdatedAt, &projectID, &projectName, &item.Blocked,
 		); err != nil {
 			return nil, err
 		}
@@ -87,6 +89,8 @@ func (r *AgendaRepository) List(ctx context.Context, userID string, opts reposit
 		item.FlagColor = stringPtrFromNull(flagColor)
 		item.SubflagName = stringPtrFromNull(subflagName)
 		item.SubflagColor = stringPtrFromNull(subflagColor)
+		item.ProjectID = stringPtrFromNull(projectID)
+		item.ProjectName = stringPtrFromNull(projectName)
 
 		items = append(items, item)
 	}
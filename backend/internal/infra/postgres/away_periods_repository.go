@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type AwayPeriodRepository struct {
+	db dbtx
+}
+
+func NewAwayPeriodRepository(db *DB) *AwayPeriodRepository {
+	return &AwayPeriodRepository{db: db}
+}
+
+const awayPeriodColumns = `id, user_id, starts_on::text, ends_on::text, flag_id, reason, created_at, updated_at`
+
+func (r *AwayPeriodRepository) Create(ctx context.Context, period domain.AwayPeriod) (domain.AwayPeriod, error) {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO inbota.away_periods (user_id, starts_on, ends_on, flag_id, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+awayPeriodColumns+`
+	`, period.UserID, period.StartsOn, period.EndsOn, period.FlagID, period.Reason)
+	return scanAwayPeriod(row)
+}
+
+func (r *AwayPeriodRepository) Delete(ctx context.Context, userID, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM inbota.away_periods
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *AwayPeriodRepository) ListByUser(ctx context.Context, userID string) ([]domain.AwayPeriod, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+awayPeriodColumns+`
+		FROM inbota.away_periods
+		WHERE user_id = $1
+		ORDER BY starts_on DESC, created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAwayPeriods(rows)
+}
+
+func (r *AwayPeriodRepository) ListOverlapping(ctx context.Context, userIDs []string, from, to string) ([]domain.AwayPeriod, error) {
+	if len(userIDs) == 0 {
+		return []domain.AwayPeriod{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+awayPeriodColumns+`
+		FROM inbota.away_periods
+		WHERE user_id = ANY($1::uuid[]) AND starts_on <= $3 AND ends_on >= $2
+		ORDER BY starts_on
+	`, pq.Array(userIDs), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAwayPeriods(rows)
+}
+
+func scanAwayPeriods(rows *sql.Rows) ([]domain.AwayPeriod, error) {
+	items := make([]domain.AwayPeriod, 0)
+	for rows.Next() {
+		period, err := scanAwayPeriod(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, period)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanAwayPeriod(row rowScanner) (domain.AwayPeriod, error) {
+	var period domain.AwayPeriod
+	var flagID, reason sql.NullString
+	if err := row.Scan(
+		&period.ID,
+		&period.UserID,
+		&period.StartsOn,
+		&period.EndsOn,
+		&flagID,
+		&reason,
+		&period.CreatedAt,
+		&period.UpdatedAt,
+	); err != nil {
+		return domain.AwayPeriod{}, err
+	}
+	period.FlagID = stringPtrFromNull(flagID)
+	period.Reason = stringPtrFromNull(reason)
+	return period, nil
+}
@@ -2,6 +2,9 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
 
 	"inbota/backend/internal/app/domain"
 )
@@ -14,6 +17,18 @@ func NewDeviceTokenRepository(db *DB) *DeviceTokenRepository {
 	return &DeviceTokenRepository{db: db}
 }
 
+const deviceTokenColumns = `dt.id, dt.user_id, dt.device_id, dt.ntfy_topic, dt.platform, dt.device_name, dt.app_version, dt.is_active, dt.last_seen_at, dt.created_at, pt.encryption_key`
+
+func scanDeviceToken(row rowScanner) (domain.DeviceToken, error) {
+	var t domain.DeviceToken
+	var encryptionKey sql.NullString
+	if err := row.Scan(&t.ID, &t.UserID, &t.DeviceID, &t.Topic, &t.Platform, &t.DeviceName, &t.AppVersion, &t.IsActive, &t.LastSeenAt, &t.CreatedAt, &encryptionKey); err != nil {
+		return domain.DeviceToken{}, err
+	}
+	t.EncryptionKey = stringPtrFromNull(encryptionKey)
+	return t, nil
+}
+
 func (r *DeviceTokenRepository) Upsert(ctx context.Context, dt domain.DeviceToken) error {
 	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO inbota.device_tokens (user_id, device_id, ntfy_topic, platform, device_name, app_version, is_active, last_seen_at)
@@ -37,9 +52,10 @@ func (r *DeviceTokenRepository) Delete(ctx context.Context, deviceID, userID str
 
 func (r *DeviceTokenRepository) ListByUserID(ctx context.Context, userID string) ([]domain.DeviceToken, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, device_id, ntfy_topic, platform, device_name, app_version, is_active, last_seen_at, created_at
-		FROM inbota.device_tokens
-		WHERE user_id = $1 AND is_active = true
+		SELECT `+deviceTokenColumns+`
+		FROM inbota.device_tokens dt
+		LEFT JOIN inbota.push_topics pt ON pt.user_id = dt.user_id AND pt.topic = dt.ntfy_topic
+		WHERE dt.user_id = $1 AND dt.is_active = true
 	`, userID)
 	if err != nil {
 		return nil, err
@@ -48,8 +64,34 @@ func (r *DeviceTokenRepository) ListByUserID(ctx context.Context, userID string)
 
 	var tokens []domain.DeviceToken
 	for rows.Next() {
-		var t domain.DeviceToken
-		if err := rows.Scan(&t.ID, &t.UserID, &t.DeviceID, &t.Topic, &t.Platform, &t.DeviceName, &t.AppVersion, &t.IsActive, &t.LastSeenAt, &t.CreatedAt); err != nil {
+		t, err := scanDeviceToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *DeviceTokenRepository) ListByUserIDs(ctx context.Context, userIDs []string) ([]domain.DeviceToken, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+deviceTokenColumns+`
+		FROM inbota.device_tokens dt
+		LEFT JOIN inbota.push_topics pt ON pt.user_id = dt.user_id AND pt.topic = dt.ntfy_topic
+		WHERE dt.user_id = ANY($1::uuid[]) AND dt.is_active = true
+	`, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []domain.DeviceToken
+	for rows.Next() {
+		t, err := scanDeviceToken(rows)
+		if err != nil {
 			return nil, err
 		}
 		tokens = append(tokens, t)
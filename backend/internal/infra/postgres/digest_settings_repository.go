@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type DigestSettingsRepository struct {
+	db *DB
+}
+
+func NewDigestSettingsRepository(db *DB) *DigestSettingsRepository {
+	return &DigestSettingsRepository{db: db}
+}
+
+const digestSettingsColumns = `user_id, sections, max_items, include_flag_ids::text[], exclude_flag_ids::text[], skip_empty, updated_at`
+
+func (r *DigestSettingsRepository) GetByUserID(ctx context.Context, userID string) (*domain.DigestSettings, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+digestSettingsColumns+`
+		FROM inbota.digest_settings
+		WHERE user_id = $1
+	`, userID)
+	settings, err := scanDigestSettings(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *DigestSettingsRepository) Upsert(ctx context.Context, settings domain.DigestSettings) (domain.DigestSettings, error) {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO inbota.digest_settings (user_id, sections, max_items, include_flag_ids, exclude_flag_ids, skip_empty, updated_at)
+		VALUES ($1, $2, $3, $4::uuid[], $5::uuid[], $6, now())
+		ON CONFLICT (user_id) DO UPDATE SET
+			sections = EXCLUDED.sections,
+			max_items = EXCLUDED.max_items,
+			include_flag_ids = EXCLUDED.include_flag_ids,
+			exclude_flag_ids = EXCLUDED.exclude_flag_ids,
+			skip_empty = EXCLUDED.skip_empty,
+			updated_at = now()
+		RETURNING `+digestSettingsColumns+`
+	`, settings.UserID, pq.Array(settings.Sections), settings.MaxItems,
+		pq.Array(settings.IncludeFlagIDs), pq.Array(settings.ExcludeFlagIDs), settings.SkipEmpty)
+	return scanDigestSettings(row)
+}
+
+func scanDigestSettings(row rowScanner) (domain.DigestSettings, error) {
+	var s domain.DigestSettings
+	if err := row.Scan(
+		&s.UserID,
+		pq.Array(&s.Sections),
+		&s.MaxItems,
+		pq.Array(&s.IncludeFlagIDs),
+		pq.Array(&s.ExcludeFlagIDs),
+		&s.SkipEmpty,
+		&s.UpdatedAt,
+	); err != nil {
+		return domain.DigestSettings{}, err
+	}
+	return s, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"inbota/backend/internal/app/domain"
 )
@@ -22,16 +23,20 @@ func (r *EmailDigestRepository) Create(ctx context.Context, digest *domain.Email
 	}
 
 	const query = `
-		INSERT INTO inbota.email_digests (user_id, digest_date, type, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, now(), now())
+		INSERT INTO inbota.email_digests (user_id, digest_date, type, status, attempts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 1, now(), now())
 		ON CONFLICT (user_id, digest_date, type) DO UPDATE
 		SET status = 'pending',
 		    sent_at = NULL,
 		    error_msg = NULL,
 		    provider_id = NULL,
+		    attempts = inbota.email_digests.attempts + 1,
+		    next_attempt_at = NULL,
 		    updated_at = now()
 		WHERE inbota.email_digests.status = 'failed'
-		RETURNING id, created_at, updated_at
+		  AND inbota.email_digests.next_attempt_at IS NOT NULL
+		  AND inbota.email_digests.next_attempt_at <= now()
+		RETURNING id, attempts, created_at, updated_at
 	`
 
 	err := r.db.QueryRowContext(
@@ -41,7 +46,7 @@ func (r *EmailDigestRepository) Create(ctx context.Context, digest *domain.Email
 		digest.DigestDate.Format("2006-01-02"),
 		digest.Type,
 		digest.Status,
-	).Scan(&digest.ID, &digest.CreatedAt, &digest.UpdatedAt)
+	).Scan(&digest.ID, &digest.Attempts, &digest.CreatedAt, &digest.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false, nil
@@ -62,14 +67,67 @@ func (r *EmailDigestRepository) Update(ctx context.Context, digest *domain.Email
 
 	const query = `
 		UPDATE inbota.email_digests
-		SET status = $1, sent_at = $2, error_msg = $3, provider_id = $4, updated_at = now()
-		WHERE id = $5
+		SET status = $1, sent_at = $2, error_msg = $3, provider_id = $4, next_attempt_at = $5, updated_at = now()
+		WHERE id = $6
 	`
 
-	_, err := r.db.ExecContext(ctx, query, digest.Status, digest.SentAt, digest.ErrorMsg, digest.ProviderID, digest.ID)
+	_, err := r.db.ExecContext(ctx, query, digest.Status, digest.SentAt, digest.ErrorMsg, digest.ProviderID, digest.NextAttemptAt, digest.ID)
 	if err != nil {
 		return fmt.Errorf("update email digest: %w", err)
 	}
 
 	return nil
 }
+
+func (r *EmailDigestRepository) ListRetryable(ctx context.Context, now time.Time, limit int) ([]domain.EmailDigest, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	const query = `
+		SELECT id, user_id, digest_date, type, status, attempts, next_attempt_at, error_msg, created_at, updated_at
+		FROM inbota.email_digests
+		WHERE status = 'failed'
+		  AND next_attempt_at IS NOT NULL
+		  AND next_attempt_at <= $1
+		ORDER BY next_attempt_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list retryable email digests: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]domain.EmailDigest, 0)
+	for rows.Next() {
+		var d domain.EmailDigest
+		var nextAttemptAt sql.NullTime
+		var errorMsg sql.NullString
+		if err := rows.Scan(&d.ID, &d.UserID, &d.DigestDate, &d.Type, &d.Status, &d.Attempts, &nextAttemptAt, &errorMsg, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan email digest: %w", err)
+		}
+		d.NextAttemptAt = timePtrFromNull(nextAttemptAt)
+		d.ErrorMsg = stringPtrFromNull(errorMsg)
+		items = append(items, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *EmailDigestRepository) UpdateProviderStatus(ctx context.Context, providerID, status string, at time.Time) error {
+	const query = `
+		UPDATE inbota.email_digests
+		SET provider_status = $1, provider_status_at = $2, updated_at = now()
+		WHERE provider_id = $3
+		  AND (provider_status_at IS NULL OR provider_status_at <= $2)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, status, at, providerID); err != nil {
+		return fmt.Errorf("update email digest provider status: %w", err)
+	}
+	return nil
+}
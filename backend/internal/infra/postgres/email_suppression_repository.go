@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type EmailSuppressionRepository struct {
+	db *DB
+}
+
+func NewEmailSuppressionRepository(db *DB) *EmailSuppressionRepository {
+	return &EmailSuppressionRepository{db: db}
+}
+
+func (r *EmailSuppressionRepository) Suppress(ctx context.Context, suppression domain.EmailSuppression) error {
+	email := normalizeEmail(suppression.Email)
+	if email == "" {
+		return fmt.Errorf("suppression email is required")
+	}
+
+	const query = `
+		INSERT INTO inbota.email_suppressions (email, reason, provider_id, detail, created_at)
+		VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT (email) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, email, suppression.Reason, suppression.ProviderID, suppression.Detail); err != nil {
+		return fmt.Errorf("suppress email: %w", err)
+	}
+	return nil
+}
+
+func (r *EmailSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM inbota.email_suppressions WHERE email = $1)
+	`, normalizeEmail(email)).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check email suppression: %w", err)
+	}
+	return exists, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/lib/pq"
+
 	"inbota/backend/internal/app/domain"
 	"inbota/backend/internal/app/repository"
 )
@@ -23,10 +25,10 @@ func NewEventRepositoryTx(tx *sql.Tx) *EventRepository {
 
 func (r *EventRepository) Create(ctx context.Context, event domain.Event) (domain.Event, error) {
 	row := r.db.QueryRowContext(ctx, `
-		INSERT INTO inbota.events (user_id, title, start_at, end_at, all_day, location, flag_id, subflag_id, source_inbox_item_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO inbota.events (user_id, title, start_at, end_at, all_day, location, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at, updated_at
-	`, event.UserID, event.Title, event.StartAt, event.EndAt, event.AllDay, event.Location, event.FlagID, event.SubflagID, event.SourceInboxItemID)
+	`, event.UserID, event.Title, event.StartAt, event.EndAt, event.AllDay, event.Location, event.FlagID, event.SubflagID, event.SourceInboxItemID, pq.Array(event.Notification.LeadMins), event.Notification.Disabled)
 
 	if err := row.Scan(&event.ID, &event.CreatedAt, &event.UpdatedAt); err != nil {
 		return domain.Event{}, err
@@ -37,10 +39,10 @@ func (r *EventRepository) Create(ctx context.Context, event domain.Event) (domai
 func (r *EventRepository) Update(ctx context.Context, event domain.Event) (domain.Event, error) {
 	row := r.db.QueryRowContext(ctx, `
 		UPDATE inbota.events
-		SET title = $1, start_at = $2, end_at = $3, all_day = $4, location = $5, flag_id = $6, subflag_id = $7, updated_at = now()
-		WHERE id = $8 AND user_id = $9
+		SET title = $1, start_at = $2, end_at = $3, all_day = $4, location = $5, flag_id = $6, subflag_id = $7, notify_lead_mins = $8, notify_disabled = $9, updated_at = now()
+		WHERE id = $10 AND user_id = $11
 		RETURNING created_at, updated_at
-	`, event.Title, event.StartAt, event.EndAt, event.AllDay, event.Location, event.FlagID, event.SubflagID, event.ID, event.UserID)
+	`, event.Title, event.StartAt, event.EndAt, event.AllDay, event.Location, event.FlagID, event.SubflagID, pq.Array(event.Notification.LeadMins), event.Notification.Disabled, event.ID, event.UserID)
 
 	if err := row.Scan(&event.CreatedAt, &event.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
@@ -72,7 +74,7 @@ func (r *EventRepository) Delete(ctx context.Context, userID, id string) error {
 
 func (r *EventRepository) Get(ctx context.Context, userID, id string) (domain.Event, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, user_id, title, start_at, end_at, all_day, location, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT id, user_id, title, start_at, end_at, all_day, location, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, created_at, updated_at
 		FROM inbota.events
 		WHERE id = $1 AND user_id = $2
 		LIMIT 1
@@ -84,8 +86,9 @@ func (r *EventRepository) Get(ctx context.Context, userID, id string) (domain.Ev
 	var flagID sql.NullString
 	var subflagID sql.NullString
 	var sourceInboxID sql.NullString
+	var notifyLeadMins pq.Int64Array
 	var event domain.Event
-	if err := row.Scan(&event.ID, &event.UserID, &event.Title, &startAt, &endAt, &event.AllDay, &location, &flagID, &subflagID, &sourceInboxID, &event.CreatedAt, &event.UpdatedAt); err != nil {
+	if err := row.Scan(&event.ID, &event.UserID, &event.Title, &startAt, &endAt, &event.AllDay, &location, &flagID, &subflagID, &sourceInboxID, &notifyLeadMins, &event.Notification.Disabled, &event.CreatedAt, &event.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Event{}, ErrNotFound
 		}
@@ -97,6 +100,7 @@ func (r *EventRepository) Get(ctx context.Context, userID, id string) (domain.Ev
 	event.FlagID = stringPtrFromNull(flagID)
 	event.SubflagID = stringPtrFromNull(subflagID)
 	event.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
+	event.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 	return event, nil
 }
 
@@ -107,7 +111,7 @@ func (r *EventRepository) List(ctx context.Context, userID string, opts reposito
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, title, start_at, end_at, all_day, location, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT id, user_id, title, start_at, end_at, all_day, location, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, created_at, updated_at
 		FROM inbota.events
 		WHERE user_id = $1
 		ORDER BY start_at NULLS LAST, created_at DESC
@@ -126,8 +130,9 @@ func (r *EventRepository) List(ctx context.Context, userID string, opts reposito
 		var flagID sql.NullString
 		var subflagID sql.NullString
 		var sourceInboxID sql.NullString
+		var notifyLeadMins pq.Int64Array
 		var event domain.Event
-		if err := rows.Scan(&event.ID, &event.UserID, &event.Title, &startAt, &endAt, &event.AllDay, &location, &flagID, &subflagID, &sourceInboxID, &event.CreatedAt, &event.UpdatedAt); err != nil {
+		if err := rows.Scan(&event.ID, &event.UserID, &event.Title, &startAt, &endAt, &event.AllDay, &location, &flagID, &subflagID, &sourceInboxID, &notifyLeadMins, &event.Notification.Disabled, &event.CreatedAt, &event.UpdatedAt); err != nil {
 			return nil, nil, err
 		}
 		event.StartAt = timePtrFromNull(startAt)
@@ -136,6 +141,7 @@ func (r *EventRepository) List(ctx context.Context, userID string, opts reposito
 		event.FlagID = stringPtrFromNull(flagID)
 		event.SubflagID = stringPtrFromNull(subflagID)
 		event.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
+		event.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 		items = append(items, event)
 	}
 	if err := rows.Err(); err != nil {
@@ -148,10 +154,12 @@ func (r *EventRepository) List(ctx context.Context, userID string, opts reposito
 
 func (r *EventRepository) ListUpcoming(ctx context.Context, start, end time.Time) ([]domain.Event, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, title, start_at, end_at, all_day, location, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT id, user_id, title, start_at, end_at, all_day, location, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, created_at, updated_at
 		FROM inbota.events
-		WHERE start_at >= $1 AND start_at <= $2
-	`, start, end)
+		WHERE start_at >= $1
+		  AND start_at <= $2 + make_interval(mins => $3)
+		  AND (start_at <= $2 OR start_at - make_interval(mins => COALESCE((SELECT max(m) FROM unnest(notify_lead_mins) m), 0)) <= $2)
+	`, start, end, domain.MaxNotificationLeadMins)
 	if err != nil {
 		return nil, err
 	}
@@ -165,8 +173,9 @@ func (r *EventRepository) ListUpcoming(ctx context.Context, start, end time.Time
 		var flagID sql.NullString
 		var subflagID sql.NullString
 		var sourceInboxID sql.NullString
+		var notifyLeadMins pq.Int64Array
 		var event domain.Event
-		if err := rows.Scan(&event.ID, &event.UserID, &event.Title, &startAt, &endAt, &event.AllDay, &location, &flagID, &subflagID, &sourceInboxID, &event.CreatedAt, &event.UpdatedAt); err != nil {
+		if err := rows.Scan(&event.ID, &event.UserID, &event.Title, &startAt, &endAt, &event.AllDay, &location, &flagID, &subflagID, &sourceInboxID, &notifyLeadMins, &event.Notification.Disabled, &event.CreatedAt, &event.UpdatedAt); err != nil {
 			return nil, err
 		}
 		event.StartAt = timePtrFromNull(startAt)
@@ -175,6 +184,7 @@ func (r *EventRepository) ListUpcoming(ctx context.Context, start, end time.Time
 		event.FlagID = stringPtrFromNull(flagID)
 		event.SubflagID = stringPtrFromNull(subflagID)
 		event.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
+		event.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 		items = append(items, event)
 	}
 	if err := rows.Err(); err != nil {
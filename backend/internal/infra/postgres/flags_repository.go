@@ -104,6 +104,35 @@ func (r *FlagRepository) GetByIDs(ctx context.Context, userID string, ids []stri
 	if err != nil {
 		return nil, err
 	}
+	return scanFlags(rows)
+}
+
+func (r *FlagRepository) GetByIDsForUsers(ctx context.Context, idsByUser map[string][]string) ([]domain.Flag, error) {
+	userIDs := make([]string, 0, len(idsByUser))
+	flagIDs := make([]string, 0, len(idsByUser))
+	for userID, ids := range idsByUser {
+		for _, id := range ids {
+			userIDs = append(userIDs, userID)
+			flagIDs = append(flagIDs, id)
+		}
+	}
+	if len(flagIDs) == 0 {
+		return []domain.Flag{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT f.id, f.user_id, f.name, f.color, f.sort_order, f.created_at, f.updated_at
+		FROM inbota.flags f
+		JOIN (SELECT DISTINCT * FROM unnest($1::uuid[], $2::uuid[])) AS wanted(user_id, id)
+		  ON wanted.user_id = f.user_id AND wanted.id = f.id
+	`, pq.Array(userIDs), pq.Array(flagIDs))
+	if err != nil {
+		return nil, err
+	}
+	return scanFlags(rows)
+}
+
+func scanFlags(rows *sql.Rows) ([]domain.Flag, error) {
 	defer rows.Close()
 
 	flags := make([]domain.Flag, 0)
@@ -126,7 +126,9 @@ func (r *HomeRepository) ListFocusTasks(ctx context.Context, userID string, limi
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, title, description, status, due_at, created_at, updated_at
+		SELECT id, title, description, status, due_at, priority, effort_mins,
+		       EXISTS (SELECT 1 FROM inbota.tasks b WHERE b.id = ANY(tasks.blocked_by) AND b.status <> 'DONE') AS blocked,
+		       created_at, updated_at
 		FROM inbota.tasks
 		WHERE user_id = $1
 		  AND status = 'OPEN'
@@ -143,12 +145,17 @@ func (r *HomeRepository) ListFocusTasks(ctx context.Context, userID string, limi
 		var item repository.HomeFocusTask
 		var description sql.NullString
 		var dueAt sql.NullTime
+		var priority sql.NullString
+		var effortMins sql.NullInt64
 		if err := rows.Scan(
 			&item.ID,
 			&item.Title,
 			&description,
 			&item.Status,
 			&dueAt,
+			&priority,
+			&effortMins,
+			&item.Blocked,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 		); err != nil {
@@ -156,6 +163,8 @@ func (r *HomeRepository) ListFocusTasks(ctx context.Context, userID string, limi
 		}
 		item.Description = stringPtrFromNull(description)
 		item.DueAt = timePtrFromNull(dueAt)
+		item.Priority = stringPtrFromNull(priority)
+		item.EffortMins = intPtrFromNull(effortMins)
 		items = append(items, item)
 	}
 
@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
+	"github.com/lib/pq"
+
 	"inbota/backend/internal/app/domain"
 )
 
@@ -15,12 +18,28 @@ func NewNotificationLogRepository(db *DB) *NotificationLogRepository {
 	return &NotificationLogRepository{db: db}
 }
 
+const notificationLogColumns = `id, user_id, type, reference_id, title, body, lead_mins, status, scheduled_for, sent_at, read_at, error_msg, bundle_id, channel, created_at`
+
+func scanNotificationLog(row rowScanner) (domain.NotificationLog, error) {
+	var l domain.NotificationLog
+	var bundleID sql.NullString
+	if err := row.Scan(&l.ID, &l.UserID, &l.Type, &l.ReferenceID, &l.Title, &l.Body, &l.LeadMins, &l.Status, &l.ScheduledFor, &l.SentAt, &l.ReadAt, &l.ErrorMsg, &bundleID, &l.Channel, &l.CreatedAt); err != nil {
+		return domain.NotificationLog{}, err
+	}
+	l.BundleID = stringPtrFromNull(bundleID)
+	return l, nil
+}
+
 func (r *NotificationLogRepository) Create(ctx context.Context, log domain.NotificationLog) (domain.NotificationLog, error) {
+	if log.Channel == "" {
+		log.Channel = domain.NotificationChannelPush
+	}
+
 	row := r.db.QueryRowContext(ctx, `
-		INSERT INTO inbota.notification_log (user_id, type, reference_id, title, body, lead_mins, status, scheduled_for)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO inbota.notification_log (user_id, type, reference_id, title, body, lead_mins, status, scheduled_for, channel)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at
-	`, log.UserID, log.Type, log.ReferenceID, log.Title, log.Body, log.LeadMins, log.Status, log.ScheduledFor)
+	`, log.UserID, log.Type, log.ReferenceID, log.Title, log.Body, log.LeadMins, log.Status, log.ScheduledFor, log.Channel)
 
 	if err := row.Scan(&log.ID, &log.CreatedAt); err != nil {
 		return domain.NotificationLog{}, err
@@ -30,7 +49,7 @@ func (r *NotificationLogRepository) Create(ctx context.Context, log domain.Notif
 
 func (r *NotificationLogRepository) ListPending(ctx context.Context, scheduledBefore time.Time) ([]domain.NotificationLog, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, type, reference_id, title, body, lead_mins, status, scheduled_for, sent_at, read_at, error_msg, created_at
+		SELECT `+notificationLogColumns+`
 		FROM inbota.notification_log
 		WHERE status = 'pending' AND scheduled_for <= $1
 	`, scheduledBefore)
@@ -41,8 +60,39 @@ func (r *NotificationLogRepository) ListPending(ctx context.Context, scheduledBe
 
 	var logs []domain.NotificationLog
 	for rows.Next() {
-		var l domain.NotificationLog
-		if err := rows.Scan(&l.ID, &l.UserID, &l.Type, &l.ReferenceID, &l.Title, &l.Body, &l.LeadMins, &l.Status, &l.ScheduledFor, &l.SentAt, &l.ReadAt, &l.ErrorMsg, &l.CreatedAt); err != nil {
+		l, err := scanNotificationLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+func (r *NotificationLogRepository) ClaimPending(ctx context.Context, scheduledBefore time.Time, limit int, lease time.Duration, owner string) ([]domain.NotificationLog, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE inbota.notification_log
+		SET claimed_at = now(), claimed_by = $4
+		WHERE id IN (
+			SELECT id
+			FROM inbota.notification_log
+			WHERE status = 'pending' AND scheduled_for <= $1
+			  AND (claimed_at IS NULL OR claimed_at < now() - make_interval(secs => $3))
+			ORDER BY scheduled_for
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+notificationLogColumns+`
+	`, scheduledBefore, limit, lease.Seconds(), owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []domain.NotificationLog
+	for rows.Next() {
+		l, err := scanNotificationLog(rows)
+		if err != nil {
 			return nil, err
 		}
 		logs = append(logs, l)
@@ -50,6 +100,69 @@ func (r *NotificationLogRepository) ListPending(ctx context.Context, scheduledBe
 	return logs, rows.Err()
 }
 
+// ClaimPendingForUser leases the user's pending push rows due up to scheduledBefore (bundling lookahead).
+func (r *NotificationLogRepository) ClaimPendingForUser(ctx context.Context, userID string, scheduledBefore time.Time, lease time.Duration, owner string) ([]domain.NotificationLog, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE inbota.notification_log
+		SET claimed_at = now(), claimed_by = $4
+		WHERE id IN (
+			SELECT id
+			FROM inbota.notification_log
+			WHERE user_id = $1 AND status = 'pending' AND channel = 'push' AND scheduled_for <= $2
+			  AND (claimed_at IS NULL OR claimed_at < now() - make_interval(secs => $3) OR claimed_by = $4)
+			ORDER BY scheduled_for
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+notificationLogColumns+`
+	`, userID, scheduledBefore, lease.Seconds(), owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []domain.NotificationLog
+	for rows.Next() {
+		l, err := scanNotificationLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+// MarkBundleSent marks the rows as sent together and links them to a new bundle id.
+func (r *NotificationLogRepository) MarkBundleSent(ctx context.Context, ids []string) (string, error) {
+	var bundleID string
+	err := r.db.QueryRowContext(ctx, `
+		WITH bundle AS (SELECT gen_random_uuid() AS id),
+		updated AS (
+			UPDATE inbota.notification_log
+			SET status = 'sent', sent_at = now(), error_msg = NULL, bundle_id = bundle.id
+			FROM bundle
+			WHERE inbota.notification_log.id = ANY($1::uuid[])
+			RETURNING inbota.notification_log.id
+		)
+		SELECT bundle.id FROM bundle
+	`, pq.Array(ids)).Scan(&bundleID)
+	return bundleID, err
+}
+
+// PendingBacklog returns how many pending rows are already due and the oldest scheduled_for among them.
+func (r *NotificationLogRepository) PendingBacklog(ctx context.Context, scheduledBefore time.Time) (int, *time.Time, error) {
+	var count int
+	var oldest sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT count(*), min(scheduled_for)
+		FROM inbota.notification_log
+		WHERE status = 'pending' AND scheduled_for <= $1
+	`, scheduledBefore).Scan(&count, &oldest)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, timePtrFromNull(oldest), nil
+}
+
 func (r *NotificationLogRepository) UpdateStatus(ctx context.Context, id string, status domain.NotificationStatus, errorMsg *string) error {
 	var sentAt *time.Time
 	if status == domain.NotificationStatusSent {
@@ -66,7 +179,7 @@ func (r *NotificationLogRepository) UpdateStatus(ctx context.Context, id string,
 
 func (r *NotificationLogRepository) ListByUserID(ctx context.Context, userID string, limit, offset int) ([]domain.NotificationLog, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, type, reference_id, title, body, lead_mins, status, scheduled_for, sent_at, read_at, error_msg, created_at
+		SELECT `+notificationLogColumns+`
 		FROM inbota.notification_log
 		WHERE user_id = $1
 		  AND status IN ('sent', 'delivered', 'read')
@@ -80,8 +193,8 @@ func (r *NotificationLogRepository) ListByUserID(ctx context.Context, userID str
 
 	var logs []domain.NotificationLog
 	for rows.Next() {
-		var l domain.NotificationLog
-		if err := rows.Scan(&l.ID, &l.UserID, &l.Type, &l.ReferenceID, &l.Title, &l.Body, &l.LeadMins, &l.Status, &l.ScheduledFor, &l.SentAt, &l.ReadAt, &l.ErrorMsg, &l.CreatedAt); err != nil {
+		l, err := scanNotificationLog(rows)
+		if err != nil {
 			return nil, err
 		}
 		logs = append(logs, l)
@@ -125,8 +238,102 @@ func (r *NotificationLogRepository) Exists(ctx context.Context, referenceID stri
 func (r *NotificationLogRepository) UpdateScheduledFor(ctx context.Context, id string, scheduledFor time.Time) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE inbota.notification_log
-		SET scheduled_for = $1, status = 'pending'
+		SET scheduled_for = $1, status = 'pending', claimed_at = NULL, claimed_by = NULL
 		WHERE id = $2
 	`, scheduledFor, id)
 	return err
 }
+
+func (r *NotificationLogRepository) CancelPending(ctx context.Context, referenceID string, from, to time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE inbota.notification_log
+		SET status = 'cancelled', claimed_at = NULL, claimed_by = NULL
+		WHERE reference_id = $1 AND status = 'pending'
+		  AND scheduled_for >= $2 AND scheduled_for < $3
+	`, referenceID, from, to)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+func (r *NotificationLogRepository) Release(ctx context.Context, referenceID string, leadMins int) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE inbota.notification_log
+		SET status = CASE WHEN status = 'pending' THEN 'cancelled'::inbota.notification_status ELSE 'read'::inbota.notification_status END,
+		    read_at = CASE WHEN status = 'pending' THEN read_at ELSE COALESCE(read_at, now()) END,
+		    claimed_at = NULL, claimed_by = NULL
+		WHERE reference_id = $1 AND lead_mins = $2
+		  AND status IN ('pending', 'sent', 'delivered')
+	`, referenceID, leadMins)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+func (r *NotificationLogRepository) CancelPendingByType(ctx context.Context, userID string, types []domain.NotificationType, flagID *string, from, to time.Time) (int, error) {
+	if len(types) == 0 {
+		return 0, nil
+	}
+	typeNames := make([]string, 0, len(types))
+	for _, t := range types {
+		typeNames = append(typeNames, string(t))
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE inbota.notification_log
+		SET status = 'cancelled', claimed_at = NULL, claimed_by = NULL
+		WHERE user_id = $1 AND status = 'pending'
+		  AND type::text = ANY($2)
+		  AND scheduled_for >= $3 AND scheduled_for < $4
+		  AND ($5::uuid IS NULL OR reference_id IN (
+			SELECT id FROM inbota.routines WHERE user_id = $1 AND flag_id = $5
+			UNION ALL
+			SELECT id FROM inbota.tasks WHERE user_id = $1 AND flag_id = $5
+		  ))
+	`, userID, pq.Array(typeNames), from, to, flagID)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+// ListAcknowledged returns which of the reference ids already have a notification read by the user.
+func (r *NotificationLogRepository) ListAcknowledged(ctx context.Context, referenceIDs []string) (map[string]bool, error) {
+	acked := make(map[string]bool)
+	if len(referenceIDs) == 0 {
+		return acked, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT reference_id
+		FROM inbota.notification_log
+		WHERE reference_id = ANY($1::uuid[]) AND (read_at IS NOT NULL OR status = 'read')
+	`, pq.Array(referenceIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		acked[id] = true
+	}
+	return acked, rows.Err()
+}
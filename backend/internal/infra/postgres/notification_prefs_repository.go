@@ -18,20 +18,20 @@ func NewNotificationPreferencesRepository(db *DB) *NotificationPreferencesReposi
 	return &NotificationPreferencesRepository{db: db}
 }
 
-func (r *NotificationPreferencesRepository) GetByUserID(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
-	row := r.db.QueryRowContext(ctx, `
-		SELECT id, user_id, reminders_enabled, reminder_at_time, reminder_lead_mins,
-			events_enabled, event_at_time, event_lead_mins,
-			tasks_enabled, task_at_time, task_lead_mins,
-			routines_enabled, routine_at_time, routine_lead_mins,
-			quiet_hours_enabled, to_char(quiet_start, 'HH24:MI'), to_char(quiet_end, 'HH24:MI'),
-			daily_digest_enabled, daily_digest_hour, daily_summary_token,
-			created_at, updated_at
-		FROM inbota.notification_preferences
-		WHERE user_id = $1
-		LIMIT 1
-	`, userID)
-
+// notificationPreferencesColumns is shared by every SELECT so scanNotificationPreferences stays in sync.
+const notificationPreferencesColumns = `
+	id, user_id, reminders_enabled, reminder_at_time, reminder_lead_mins,
+	events_enabled, event_at_time, event_lead_mins,
+	tasks_enabled, task_at_time, task_lead_mins,
+	routines_enabled, routine_at_time, routine_lead_mins,
+	quiet_hours_enabled, to_char(quiet_start, 'HH24:MI'), to_char(quiet_end, 'HH24:MI'), bundle_window_mins,
+	morning_briefing_enabled, to_char(morning_briefing_time, 'HH24:MI'),
+	evening_review_enabled, to_char(evening_review_time, 'HH24:MI'),
+	daily_digest_enabled, daily_digest_hour, daily_summary_token,
+	weekly_digest_enabled, weekly_digest_weekday, weekly_digest_hour,
+	created_at, updated_at`
+
+func scanNotificationPreferences(row rowScanner) (domain.NotificationPreferences, error) {
 	var prefs domain.NotificationPreferences
 	var reminderLeadMins, eventLeadMins, taskLeadMins, routineLeadMins pq.Int64Array
 	var quietStart, quietEnd sql.NullString
@@ -41,15 +41,14 @@ func (r *NotificationPreferencesRepository) GetByUserID(ctx context.Context, use
 		&prefs.EventsEnabled, &prefs.EventAtTime, &eventLeadMins,
 		&prefs.TasksEnabled, &prefs.TaskAtTime, &taskLeadMins,
 		&prefs.RoutinesEnabled, &prefs.RoutineAtTime, &routineLeadMins,
-		&prefs.QuietHoursEnabled, &quietStart, &quietEnd,
+		&prefs.QuietHoursEnabled, &quietStart, &quietEnd, &prefs.BundleWindowMins,
+		&prefs.MorningBriefingEnabled, &prefs.MorningBriefingTime,
+		&prefs.EveningReviewEnabled, &prefs.EveningReviewTime,
 		&prefs.DailyDigestEnabled, &prefs.DailyDigestHour, &prefs.DailySummaryToken,
+		&prefs.WeeklyDigestEnabled, &prefs.WeeklyDigestWeekday, &prefs.WeeklyDigestHour,
 		&prefs.CreatedAt, &prefs.UpdatedAt,
 	)
-
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return domain.NotificationPreferences{}, ErrNotFound
-		}
 		return domain.NotificationPreferences{}, err
 	}
 
@@ -57,14 +56,27 @@ func (r *NotificationPreferencesRepository) GetByUserID(ctx context.Context, use
 	prefs.EventLeadMins = intArrayFromInt64Array(eventLeadMins)
 	prefs.TaskLeadMins = intArrayFromInt64Array(taskLeadMins)
 	prefs.RoutineLeadMins = intArrayFromInt64Array(routineLeadMins)
+	prefs.QuietStart = stringPtrFromNull(quietStart)
+	prefs.QuietEnd = stringPtrFromNull(quietEnd)
 
-	if quietStart.Valid {
-		prefs.QuietStart = &quietStart.String
-	}
-	if quietEnd.Valid {
-		prefs.QuietEnd = &quietEnd.String
-	}
+	return prefs, nil
+}
+
+func (r *NotificationPreferencesRepository) GetByUserID(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+notificationPreferencesColumns+`
+		FROM inbota.notification_preferences
+		WHERE user_id = $1
+		LIMIT 1
+	`, userID)
 
+	prefs, err := scanNotificationPreferences(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NotificationPreferences{}, ErrNotFound
+		}
+		return domain.NotificationPreferences{}, err
+	}
 	return prefs, nil
 }
 
@@ -75,9 +87,13 @@ func (r *NotificationPreferencesRepository) Upsert(ctx context.Context, prefs do
 			events_enabled, event_at_time, event_lead_mins,
 			tasks_enabled, task_at_time, task_lead_mins,
 			routines_enabled, routine_at_time, routine_lead_mins,
-			quiet_hours_enabled, quiet_start, quiet_end,
-			daily_digest_enabled, daily_digest_hour, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, now())
+			quiet_hours_enabled, quiet_start, quiet_end, bundle_window_mins,
+			morning_briefing_enabled, morning_briefing_time, evening_review_enabled, evening_review_time,
+			daily_digest_enabled, daily_digest_hour,
+			weekly_digest_enabled, weekly_digest_weekday, weekly_digest_hour, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+			$18, COALESCE(NULLIF($19, '')::time, '07:30'), $20, COALESCE(NULLIF($21, '')::time, '21:00'),
+			$22, $23, $24, $25, $26, now())
 		ON CONFLICT (user_id) DO UPDATE SET
 			reminders_enabled = EXCLUDED.reminders_enabled,
 			reminder_at_time = EXCLUDED.reminder_at_time,
@@ -94,29 +110,33 @@ func (r *NotificationPreferencesRepository) Upsert(ctx context.Context, prefs do
 			quiet_hours_enabled = EXCLUDED.quiet_hours_enabled,
 			quiet_start = EXCLUDED.quiet_start,
 			quiet_end = EXCLUDED.quiet_end,
+			bundle_window_mins = EXCLUDED.bundle_window_mins,
+			morning_briefing_enabled = EXCLUDED.morning_briefing_enabled,
+			morning_briefing_time = EXCLUDED.morning_briefing_time,
+			evening_review_enabled = EXCLUDED.evening_review_enabled,
+			evening_review_time = EXCLUDED.evening_review_time,
 			daily_digest_enabled = EXCLUDED.daily_digest_enabled,
 			daily_digest_hour = EXCLUDED.daily_digest_hour,
+			weekly_digest_enabled = EXCLUDED.weekly_digest_enabled,
+			weekly_digest_weekday = EXCLUDED.weekly_digest_weekday,
+			weekly_digest_hour = EXCLUDED.weekly_digest_hour,
 			updated_at = now()
 	`,
 		prefs.UserID, prefs.RemindersEnabled, prefs.ReminderAtTime, pq.Array(prefs.ReminderLeadMins),
 		prefs.EventsEnabled, prefs.EventAtTime, pq.Array(prefs.EventLeadMins),
 		prefs.TasksEnabled, prefs.TaskAtTime, pq.Array(prefs.TaskLeadMins),
 		prefs.RoutinesEnabled, prefs.RoutineAtTime, pq.Array(prefs.RoutineLeadMins),
-		prefs.QuietHoursEnabled, prefs.QuietStart, prefs.QuietEnd,
+		prefs.QuietHoursEnabled, prefs.QuietStart, prefs.QuietEnd, prefs.BundleWindowMins,
+		prefs.MorningBriefingEnabled, prefs.MorningBriefingTime, prefs.EveningReviewEnabled, prefs.EveningReviewTime,
 		prefs.DailyDigestEnabled, prefs.DailyDigestHour,
+		prefs.WeeklyDigestEnabled, prefs.WeeklyDigestWeekday, prefs.WeeklyDigestHour,
 	)
 	return err
 }
 
 func (r *NotificationPreferencesRepository) ListEnabled(ctx context.Context) ([]domain.NotificationPreferences, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, reminders_enabled, reminder_at_time, reminder_lead_mins,
-			events_enabled, event_at_time, event_lead_mins,
-			tasks_enabled, task_at_time, task_lead_mins,
-			routines_enabled, routine_at_time, routine_lead_mins,
-			quiet_hours_enabled, to_char(quiet_start, 'HH24:MI'), to_char(quiet_end, 'HH24:MI'),
-			daily_digest_enabled, daily_digest_hour, daily_summary_token,
-			created_at, updated_at
+		SELECT `+notificationPreferencesColumns+`
 		FROM inbota.notification_preferences
 		WHERE daily_digest_enabled = true
 	`)
@@ -127,33 +147,85 @@ func (r *NotificationPreferencesRepository) ListEnabled(ctx context.Context) ([]
 
 	var results []domain.NotificationPreferences
 	for rows.Next() {
-		var prefs domain.NotificationPreferences
-		var reminderLeadMins, eventLeadMins, taskLeadMins, routineLeadMins pq.Int64Array
-		var quietStart, quietEnd sql.NullString
-
-		err := rows.Scan(
-			&prefs.ID, &prefs.UserID, &prefs.RemindersEnabled, &prefs.ReminderAtTime, &reminderLeadMins,
-			&prefs.EventsEnabled, &prefs.EventAtTime, &eventLeadMins,
-			&prefs.TasksEnabled, &prefs.TaskAtTime, &taskLeadMins,
-			&prefs.RoutinesEnabled, &prefs.RoutineAtTime, &routineLeadMins,
-			&prefs.QuietHoursEnabled, &quietStart, &quietEnd,
-			&prefs.DailyDigestEnabled, &prefs.DailyDigestHour, &prefs.DailySummaryToken,
-			&prefs.CreatedAt, &prefs.UpdatedAt,
-		)
+		prefs, err := scanNotificationPreferences(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, prefs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ListWithBriefings returns preferences with the morning briefing or evening review enabled.
+func (r *NotificationPreferencesRepository) ListWithBriefings(ctx context.Context) ([]domain.NotificationPreferences, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+notificationPreferencesColumns+`
+		FROM inbota.notification_preferences
+		WHERE morning_briefing_enabled = true OR evening_review_enabled = true
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []domain.NotificationPreferences
+	for rows.Next() {
+		prefs, err := scanNotificationPreferences(rows)
 		if err != nil {
 			return nil, err
 		}
+		results = append(results, prefs)
+	}
+	return results, rows.Err()
+}
 
-		prefs.ReminderLeadMins = intArrayFromInt64Array(reminderLeadMins)
-		prefs.EventLeadMins = intArrayFromInt64Array(eventLeadMins)
-		prefs.TaskLeadMins = intArrayFromInt64Array(taskLeadMins)
-		prefs.RoutineLeadMins = intArrayFromInt64Array(routineLeadMins)
+// ListWeeklyDigestEnabled returns preferences with the weekly review e-mail enabled.
+func (r *NotificationPreferencesRepository) ListWeeklyDigestEnabled(ctx context.Context) ([]domain.NotificationPreferences, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+notificationPreferencesColumns+`
+		FROM inbota.notification_preferences
+		WHERE weekly_digest_enabled = true
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		if quietStart.Valid {
-			prefs.QuietStart = &quietStart.String
+	var results []domain.NotificationPreferences
+	for rows.Next() {
+		prefs, err := scanNotificationPreferences(rows)
+		if err != nil {
+			return nil, err
 		}
-		if quietEnd.Valid {
-			prefs.QuietEnd = &quietEnd.String
+		results = append(results, prefs)
+	}
+	return results, rows.Err()
+}
+
+// ListByUserIDs loads preferences for many users at once (scheduler batch path).
+// Users without a preferences row are simply absent from the result.
+func (r *NotificationPreferencesRepository) ListByUserIDs(ctx context.Context, userIDs []string) ([]domain.NotificationPreferences, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+notificationPreferencesColumns+`
+		FROM inbota.notification_preferences
+		WHERE user_id = ANY($1::uuid[])
+	`, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]domain.NotificationPreferences, 0, len(userIDs))
+	for rows.Next() {
+		prefs, err := scanNotificationPreferences(rows)
+		if err != nil {
+			return nil, err
 		}
 		results = append(results, prefs)
 	}
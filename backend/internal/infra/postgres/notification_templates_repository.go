@@ -2,24 +2,104 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 
 	"inbota/backend/internal/app/domain"
 )
 
 type NotificationTemplateRepository struct {
-	db *DB
+	db dbtx
 }
 
 func NewNotificationTemplateRepository(db *DB) *NotificationTemplateRepository {
 	return &NotificationTemplateRepository{db: db}
 }
 
+const notificationTemplateColumns = `id, type, trigger_key, locale, title_template, body_template, is_active, created_at, updated_at`
+
 func (r *NotificationTemplateRepository) GetAll(ctx context.Context) ([]domain.NotificationTemplate, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, type, trigger_key, title_template, body_template, is_active, created_at, updated_at
+	return r.query(ctx, `
+		SELECT `+notificationTemplateColumns+`
 		FROM inbota.notification_templates
 		WHERE is_active = true
 	`)
+}
+
+func (r *NotificationTemplateRepository) List(ctx context.Context) ([]domain.NotificationTemplate, error) {
+	return r.query(ctx, `
+		SELECT `+notificationTemplateColumns+`
+		FROM inbota.notification_templates
+		ORDER BY type, trigger_key, locale
+	`)
+}
+
+func (r *NotificationTemplateRepository) Get(ctx context.Context, id string) (domain.NotificationTemplate, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+notificationTemplateColumns+`
+		FROM inbota.notification_templates
+		WHERE id = $1
+	`, id)
+	t, err := scanNotificationTemplate(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.NotificationTemplate{}, ErrNotFound
+		}
+		return domain.NotificationTemplate{}, err
+	}
+	return t, nil
+}
+
+func (r *NotificationTemplateRepository) Create(ctx context.Context, tpl domain.NotificationTemplate) (domain.NotificationTemplate, error) {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO inbota.notification_templates (type, trigger_key, locale, title_template, body_template, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`, tpl.Type, tpl.TriggerKey, tpl.Locale, tpl.TitleTemplate, tpl.BodyTemplate, tpl.IsActive)
+
+	if err := row.Scan(&tpl.ID, &tpl.CreatedAt, &tpl.UpdatedAt); err != nil {
+		return domain.NotificationTemplate{}, err
+	}
+	return tpl, nil
+}
+
+func (r *NotificationTemplateRepository) Update(ctx context.Context, tpl domain.NotificationTemplate) (domain.NotificationTemplate, error) {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE inbota.notification_templates
+		SET title_template = $1, body_template = $2, is_active = $3, updated_at = now()
+		WHERE id = $4
+		RETURNING created_at, updated_at
+	`, tpl.TitleTemplate, tpl.BodyTemplate, tpl.IsActive, tpl.ID)
+
+	if err := row.Scan(&tpl.CreatedAt, &tpl.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.NotificationTemplate{}, ErrNotFound
+		}
+		return domain.NotificationTemplate{}, err
+	}
+	return tpl, nil
+}
+
+func (r *NotificationTemplateRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM inbota.notification_templates
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *NotificationTemplateRepository) query(ctx context.Context, query string, args ...any) ([]domain.NotificationTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -27,11 +107,17 @@ func (r *NotificationTemplateRepository) GetAll(ctx context.Context) ([]domain.N
 
 	var templates []domain.NotificationTemplate
 	for rows.Next() {
-		var t domain.NotificationTemplate
-		if err := rows.Scan(&t.ID, &t.Type, &t.TriggerKey, &t.TitleTemplate, &t.BodyTemplate, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		t, err := scanNotificationTemplate(rows)
+		if err != nil {
 			return nil, err
 		}
 		templates = append(templates, t)
 	}
 	return templates, rows.Err()
 }
+
+func scanNotificationTemplate(row rowScanner) (domain.NotificationTemplate, error) {
+	var t domain.NotificationTemplate
+	err := row.Scan(&t.ID, &t.Type, &t.TriggerKey, &t.Locale, &t.TitleTemplate, &t.BodyTemplate, &t.IsActive, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
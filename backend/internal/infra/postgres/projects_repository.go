@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+)
+
+type ProjectRepository struct {
+	db dbtx
+}
+
+func NewProjectRepository(db *DB) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+const projectColumns = `id, user_id, name, description, status, target_date::text, flag_id, subflag_id, created_at, updated_at`
+
+func (r *ProjectRepository) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
+	if project.Status == "" {
+		project.Status = domain.ProjectStatusOpen
+	}
+
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO inbota.projects (user_id, name, description, status, target_date, flag_id, subflag_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+projectColumns+`
+	`, project.UserID, project.Name, project.Description, string(project.Status), project.TargetDate, project.FlagID, project.SubflagID)
+	return scanProject(row)
+}
+
+func (r *ProjectRepository) Update(ctx context.Context, project domain.Project) (domain.Project, error) {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE inbota.projects
+		SET name = $1, description = $2, status = $3, target_date = $4, flag_id = $5, subflag_id = $6, updated_at = now()
+		WHERE id = $7 AND user_id = $8
+		RETURNING `+projectColumns+`
+	`, project.Name, project.Description, string(project.Status), project.TargetDate, project.FlagID, project.SubflagID, project.ID, project.UserID)
+
+	updated, err := scanProject(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Project{}, ErrNotFound
+		}
+		return domain.Project{}, err
+	}
+	return updated, nil
+}
+
+func (r *ProjectRepository) Delete(ctx context.Context, userID, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM inbota.projects
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProjectRepository) Get(ctx context.Context, userID, id string) (domain.Project, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+projectColumns+`
+		FROM inbota.projects
+		WHERE id = $1 AND user_id = $2
+		LIMIT 1
+	`, id, userID)
+
+	project, err := scanProject(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Project{}, ErrNotFound
+		}
+		return domain.Project{}, err
+	}
+	return project, nil
+}
+
+func (r *ProjectRepository) GetByIDs(ctx context.Context, userID string, ids []string) ([]domain.Project, error) {
+	if len(ids) == 0 {
+		return []domain.Project{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+projectColumns+`
+		FROM inbota.projects
+		WHERE user_id = $1 AND id = ANY($2)
+	`, userID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanProjects(rows)
+}
+
+func (r *ProjectRepository) FindByName(ctx context.Context, userID, name string) (domain.Project, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+projectColumns+`
+		FROM inbota.projects
+		WHERE user_id = $1 AND status <> 'ARCHIVED' AND lower(name) = lower($2)
+		ORDER BY status = 'OPEN' DESC, updated_at DESC
+		LIMIT 1
+	`, userID, strings.TrimSpace(name))
+
+	project, err := scanProject(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Project{}, ErrNotFound
+		}
+		return domain.Project{}, err
+	}
+	return project, nil
+}
+
+func (r *ProjectRepository) List(ctx context.Context, userID string, status *domain.ProjectStatus, opts repository.ListOptions) ([]domain.Project, *string, error) {
+	limit, offset, err := limitOffset(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var statusFilter *string
+	if status != nil {
+		value := string(*status)
+		statusFilter = &value
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+projectColumns+`
+		FROM inbota.projects
+		WHERE user_id = $1 AND ($2::text IS NULL OR status = $2::text)
+		ORDER BY target_date NULLS LAST, created_at DESC
+		LIMIT $3 OFFSET $4
+	`, userID, statusFilter, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	items, err := scanProjects(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next := nextOffsetCursor(offset, len(items), limit)
+	return items, next, nil
+}
+
+func (r *ProjectRepository) Progress(ctx context.Context, userID string, projectIDs []string) (map[string]domain.ProjectProgress, error) {
+	progress := make(map[string]domain.ProjectProgress)
+	if len(projectIDs) == 0 {
+		return progress, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT project_id, COUNT(*) FILTER (WHERE status = 'DONE'), COUNT(*)
+		FROM inbota.tasks
+		WHERE user_id = $1 AND project_id = ANY($2)
+		GROUP BY project_id
+	`, userID, pq.Array(projectIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectID string
+		var item domain.ProjectProgress
+		if err := rows.Scan(&projectID, &item.Done, &item.Total); err != nil {
+			return nil, err
+		}
+		progress[projectID] = item
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func scanProjects(rows *sql.Rows) ([]domain.Project, error) {
+	items := make([]domain.Project, 0)
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanProject(row rowScanner) (domain.Project, error) {
+	var project domain.Project
+	var status string
+	var description, targetDate, flagID, subflagID sql.NullString
+	if err := row.Scan(
+		&project.ID,
+		&project.UserID,
+		&project.Name,
+		&description,
+		&status,
+		&targetDate,
+		&flagID,
+		&subflagID,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	); err != nil {
+		return domain.Project{}, err
+	}
+	project.Description = stringPtrFromNull(description)
+	project.Status = domain.ProjectStatus(status)
+	project.TargetDate = stringPtrFromNull(targetDate)
+	project.FlagID = stringPtrFromNull(flagID)
+	project.SubflagID = stringPtrFromNull(subflagID)
+	return project, nil
+}
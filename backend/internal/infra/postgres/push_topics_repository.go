@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type PushTopicRepository struct {
+	db *DB
+}
+
+func NewPushTopicRepository(db *DB) *PushTopicRepository {
+	return &PushTopicRepository{db: db}
+}
+
+func (r *PushTopicRepository) GetOrCreate(ctx context.Context, candidate domain.PushTopic) (domain.PushTopic, error) {
+	// o DO UPDATE sem mudança faz o RETURNING devolver a linha existente
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO inbota.push_topics (user_id, topic, encryption_key)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET topic = inbota.push_topics.topic
+		RETURNING user_id, topic, encryption_key, created_at, rotated_at
+	`, candidate.UserID, candidate.Topic, candidate.EncryptionKey)
+	return scanPushTopic(row)
+}
+
+func (r *PushTopicRepository) Rotate(ctx context.Context, topic domain.PushTopic) (domain.PushTopic, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.PushTopic{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO inbota.push_topics (user_id, topic, encryption_key)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			topic = EXCLUDED.topic,
+			encryption_key = EXCLUDED.encryption_key,
+			rotated_at = now()
+		RETURNING user_id, topic, encryption_key, created_at, rotated_at
+	`, topic.UserID, topic.Topic, topic.EncryptionKey)
+	rotated, err := scanPushTopic(row)
+	if err != nil {
+		return domain.PushTopic{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE inbota.device_tokens SET ntfy_topic = $2 WHERE user_id = $1
+	`, topic.UserID, rotated.Topic); err != nil {
+		return domain.PushTopic{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.PushTopic{}, err
+	}
+	return rotated, nil
+}
+
+func scanPushTopic(row rowScanner) (domain.PushTopic, error) {
+	var t domain.PushTopic
+	var rotatedAt sql.NullTime
+	if err := row.Scan(&t.UserID, &t.Topic, &t.EncryptionKey, &t.CreatedAt, &rotatedAt); err != nil {
+		return domain.PushTopic{}, err
+	}
+	t.RotatedAt = timePtrFromNull(rotatedAt)
+	return t, nil
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/lib/pq"
+
 	"inbota/backend/internal/app/domain"
 	"inbota/backend/internal/app/repository"
 )
@@ -27,10 +29,10 @@ func (r *ReminderRepository) Create(ctx context.Context, reminder domain.Reminde
 	}
 
 	row := r.db.QueryRowContext(ctx, `
-		INSERT INTO inbota.reminders (user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO inbota.reminders (user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, nag_interval_mins, nag_max_repeats, nag_email_from_repeat)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id, created_at, updated_at
-	`, reminder.UserID, reminder.Title, string(reminder.Status), reminder.RemindAt, reminder.FlagID, reminder.SubflagID, reminder.SourceInboxItemID)
+	`, reminder.UserID, reminder.Title, string(reminder.Status), reminder.RemindAt, reminder.FlagID, reminder.SubflagID, reminder.SourceInboxItemID, pq.Array(reminder.Notification.LeadMins), reminder.Notification.Disabled, reminder.Escalation.IntervalMins, reminder.Escalation.MaxRepeats, reminder.Escalation.EmailFromRepeat)
 
 	if err := row.Scan(&reminder.ID, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
 		return domain.Reminder{}, err
@@ -41,10 +43,10 @@ func (r *ReminderRepository) Create(ctx context.Context, reminder domain.Reminde
 func (r *ReminderRepository) Update(ctx context.Context, reminder domain.Reminder) (domain.Reminder, error) {
 	row := r.db.QueryRowContext(ctx, `
 		UPDATE inbota.reminders
-		SET title = $1, status = $2, remind_at = $3, flag_id = $4, subflag_id = $5, updated_at = now()
-		WHERE id = $6 AND user_id = $7
+		SET title = $1, status = $2, remind_at = $3, flag_id = $4, subflag_id = $5, notify_lead_mins = $6, notify_disabled = $7, nag_interval_mins = $8, nag_max_repeats = $9, nag_email_from_repeat = $10, updated_at = now()
+		WHERE id = $11 AND user_id = $12
 		RETURNING created_at, updated_at
-	`, reminder.Title, string(reminder.Status), reminder.RemindAt, reminder.FlagID, reminder.SubflagID, reminder.ID, reminder.UserID)
+	`, reminder.Title, string(reminder.Status), reminder.RemindAt, reminder.FlagID, reminder.SubflagID, pq.Array(reminder.Notification.LeadMins), reminder.Notification.Disabled, reminder.Escalation.IntervalMins, reminder.Escalation.MaxRepeats, reminder.Escalation.EmailFromRepeat, reminder.ID, reminder.UserID)
 
 	if err := row.Scan(&reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
@@ -76,7 +78,7 @@ func (r *ReminderRepository) Delete(ctx context.Context, userID, id string) erro
 
 func (r *ReminderRepository) Get(ctx context.Context, userID, id string) (domain.Reminder, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT id, user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, nag_interval_mins, nag_max_repeats, nag_email_from_repeat, created_at, updated_at
 		FROM inbota.reminders
 		WHERE id = $1 AND user_id = $2
 		LIMIT 1
@@ -86,9 +88,10 @@ func (r *ReminderRepository) Get(ctx context.Context, userID, id string) (domain
 	var flagID sql.NullString
 	var subflagID sql.NullString
 	var sourceInboxID sql.NullString
+	var notifyLeadMins pq.Int64Array
 	var status string
 	var reminder domain.Reminder
-	if err := row.Scan(&reminder.ID, &reminder.UserID, &reminder.Title, &status, &remindAt, &flagID, &subflagID, &sourceInboxID, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
+	if err := row.Scan(&reminder.ID, &reminder.UserID, &reminder.Title, &status, &remindAt, &flagID, &subflagID, &sourceInboxID, &notifyLeadMins, &reminder.Notification.Disabled, &reminder.Escalation.IntervalMins, &reminder.Escalation.MaxRepeats, &reminder.Escalation.EmailFromRepeat, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Reminder{}, ErrNotFound
 		}
@@ -99,6 +102,7 @@ func (r *ReminderRepository) Get(ctx context.Context, userID, id string) (domain
 	reminder.FlagID = stringPtrFromNull(flagID)
 	reminder.SubflagID = stringPtrFromNull(subflagID)
 	reminder.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
+	reminder.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 	return reminder, nil
 }
 
@@ -109,7 +113,7 @@ func (r *ReminderRepository) List(ctx context.Context, userID string, opts repos
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT id, user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, nag_interval_mins, nag_max_repeats, nag_email_from_repeat, created_at, updated_at
 		FROM inbota.reminders
 		WHERE user_id = $1
 		ORDER BY remind_at NULLS LAST, created_at DESC
@@ -126,9 +130,10 @@ func (r *ReminderRepository) List(ctx context.Context, userID string, opts repos
 		var flagID sql.NullString
 		var subflagID sql.NullString
 		var sourceInboxID sql.NullString
+		var notifyLeadMins pq.Int64Array
 		var status string
 		var reminder domain.Reminder
-		if err := rows.Scan(&reminder.ID, &reminder.UserID, &reminder.Title, &status, &remindAt, &flagID, &subflagID, &sourceInboxID, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
+		if err := rows.Scan(&reminder.ID, &reminder.UserID, &reminder.Title, &status, &remindAt, &flagID, &subflagID, &sourceInboxID, &notifyLeadMins, &reminder.Notification.Disabled, &reminder.Escalation.IntervalMins, &reminder.Escalation.MaxRepeats, &reminder.Escalation.EmailFromRepeat, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
 			return nil, nil, err
 		}
 		reminder.Status = domain.ReminderStatus(status)
@@ -136,6 +141,7 @@ func (r *ReminderRepository) List(ctx context.Context, userID string, opts repos
 		reminder.FlagID = stringPtrFromNull(flagID)
 		reminder.SubflagID = stringPtrFromNull(subflagID)
 		reminder.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
+		reminder.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 		items = append(items, reminder)
 	}
 	if err := rows.Err(); err != nil {
@@ -148,10 +154,51 @@ func (r *ReminderRepository) List(ctx context.Context, userID string, opts repos
 
 func (r *ReminderRepository) ListUpcoming(ctx context.Context, start, end time.Time) ([]domain.Reminder, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT id, user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, nag_interval_mins, nag_max_repeats, nag_email_from_repeat, created_at, updated_at
+		FROM inbota.reminders
+		WHERE status = 'OPEN' AND remind_at >= $1
+		  AND remind_at <= $2 + make_interval(mins => $3)
+		  AND (remind_at <= $2 OR remind_at - make_interval(mins => COALESCE((SELECT max(m) FROM unnest(notify_lead_mins) m), 0)) <= $2)
+	`, start, end, domain.MaxNotificationLeadMins)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.Reminder, 0)
+	for rows.Next() {
+		var remindAt sql.NullTime
+		var flagID sql.NullString
+		var subflagID sql.NullString
+		var sourceInboxID sql.NullString
+		var notifyLeadMins pq.Int64Array
+		var status string
+		var reminder domain.Reminder
+		if err := rows.Scan(&reminder.ID, &reminder.UserID, &reminder.Title, &status, &remindAt, &flagID, &subflagID, &sourceInboxID, &notifyLeadMins, &reminder.Notification.Disabled, &reminder.Escalation.IntervalMins, &reminder.Escalation.MaxRepeats, &reminder.Escalation.EmailFromRepeat, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
+			return nil, err
+		}
+		reminder.Status = domain.ReminderStatus(status)
+		reminder.RemindAt = timePtrFromNull(remindAt)
+		reminder.FlagID = stringPtrFromNull(flagID)
+		reminder.SubflagID = stringPtrFromNull(subflagID)
+		reminder.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
+		reminder.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
+		items = append(items, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *ReminderRepository) ListEscalating(ctx context.Context, now time.Time) ([]domain.Reminder, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, title, status, remind_at, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, nag_interval_mins, nag_max_repeats, nag_email_from_repeat, created_at, updated_at
 		FROM inbota.reminders
-		WHERE status = 'OPEN' AND remind_at >= $1 AND remind_at <= $2
-	`, start, end)
+		WHERE status = 'OPEN' AND nag_interval_mins > 0 AND nag_max_repeats > 0
+		  AND remind_at <= $1
+		  AND remind_at + make_interval(mins => nag_interval_mins * (nag_max_repeats + 1)) > $1
+	`, now)
 	if err != nil {
 		return nil, err
 	}
@@ -163,9 +210,10 @@ func (r *ReminderRepository) ListUpcoming(ctx context.Context, start, end time.T
 		var flagID sql.NullString
 		var subflagID sql.NullString
 		var sourceInboxID sql.NullString
+		var notifyLeadMins pq.Int64Array
 		var status string
 		var reminder domain.Reminder
-		if err := rows.Scan(&reminder.ID, &reminder.UserID, &reminder.Title, &status, &remindAt, &flagID, &subflagID, &sourceInboxID, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
+		if err := rows.Scan(&reminder.ID, &reminder.UserID, &reminder.Title, &status, &remindAt, &flagID, &subflagID, &sourceInboxID, &notifyLeadMins, &reminder.Notification.Disabled, &reminder.Escalation.IntervalMins, &reminder.Escalation.MaxRepeats, &reminder.Escalation.EmailFromRepeat, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
 			return nil, err
 		}
 		reminder.Status = domain.ReminderStatus(status)
@@ -173,6 +221,7 @@ func (r *ReminderRepository) ListUpcoming(ctx context.Context, start, end time.T
 		reminder.FlagID = stringPtrFromNull(flagID)
 		reminder.SubflagID = stringPtrFromNull(subflagID)
 		reminder.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
+		reminder.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 		items = append(items, reminder)
 	}
 	if err := rows.Err(); err != nil {
@@ -28,10 +28,10 @@ func (r *RoutineRepositoryImpl) Create(ctx context.Context, routine domain.Routi
 	}
 
 	row := r.db.QueryRowContext(ctx, `
-		INSERT INTO inbota.routines (user_id, title, description, recurrence_type, weekdays, start_time, end_time, week_of_month, starts_on, ends_on, color, is_active, flag_id, subflag_id, source_inbox_item_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		INSERT INTO inbota.routines (user_id, title, description, recurrence_type, weekdays, start_time, end_time, week_of_month, rrule, target_kind, target_value, target_unit, starts_on, ends_on, color, is_active, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, streak_grace_per_month, streak_freeze_every, skip_holidays)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 		RETURNING id, created_at, updated_at
-	`, routine.UserID, routine.Title, routine.Description, routine.RecurrenceType, pq.Array(routine.Weekdays), routine.StartTime, nullStringFromStr(routine.EndTime), routine.WeekOfMonth, routine.StartsOn, routine.EndsOn, routine.Color, routine.IsActive, routine.FlagID, routine.SubflagID, routine.SourceInboxItemID)
+	`, routine.UserID, routine.Title, routine.Description, routine.RecurrenceType, pq.Array(routine.Weekdays), routine.StartTime, nullStringFromStr(routine.EndTime), routine.WeekOfMonth, routine.RRule, routine.TargetKind, routine.TargetValue, routine.TargetUnit, routine.StartsOn, routine.EndsOn, routine.Color, routine.IsActive, routine.FlagID, routine.SubflagID, routine.SourceInboxItemID, pq.Array(routine.Notification.LeadMins), routine.Notification.Disabled, routine.StreakProtection.GracePerMonth, routine.StreakProtection.FreezeEvery, routine.SkipHolidays)
 
 	if err := row.Scan(&routine.ID, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
 		return domain.Routine{}, err
@@ -42,10 +42,10 @@ func (r *RoutineRepositoryImpl) Create(ctx context.Context, routine domain.Routi
 func (r *RoutineRepositoryImpl) Update(ctx context.Context, routine domain.Routine) (domain.Routine, error) {
 	row := r.db.QueryRowContext(ctx, `
 		UPDATE inbota.routines
-		SET title = $1, description = $2, recurrence_type = $3, weekdays = $4, start_time = $5, end_time = $6, week_of_month = $7, starts_on = $8, ends_on = $9, color = $10, is_active = $11, flag_id = $12, subflag_id = $13, updated_at = now()
-		WHERE id = $14 AND user_id = $15
+		SET title = $1, description = $2, recurrence_type = $3, weekdays = $4, start_time = $5, end_time = $6, week_of_month = $7, rrule = $8, target_kind = $9, target_value = $10, target_unit = $11, starts_on = $12, ends_on = $13, color = $14, is_active = $15, flag_id = $16, subflag_id = $17, notify_lead_mins = $18, notify_disabled = $19, streak_grace_per_month = $20, streak_freeze_every = $21, skip_holidays = $22, updated_at = now()
+		WHERE id = $23 AND user_id = $24
 		RETURNING created_at, updated_at
-	`, routine.Title, routine.Description, routine.RecurrenceType, pq.Array(routine.Weekdays), routine.StartTime, nullStringFromStr(routine.EndTime), routine.WeekOfMonth, routine.StartsOn, routine.EndsOn, routine.Color, routine.IsActive, routine.FlagID, routine.SubflagID, routine.ID, routine.UserID)
+	`, routine.Title, routine.Description, routine.RecurrenceType, pq.Array(routine.Weekdays), routine.StartTime, nullStringFromStr(routine.EndTime), routine.WeekOfMonth, routine.RRule, routine.TargetKind, routine.TargetValue, routine.TargetUnit, routine.StartsOn, routine.EndsOn, routine.Color, routine.IsActive, routine.FlagID, routine.SubflagID, pq.Array(routine.Notification.LeadMins), routine.Notification.Disabled, routine.StreakProtection.GracePerMonth, routine.StreakProtection.FreezeEvery, routine.SkipHolidays, routine.ID, routine.UserID)
 
 	if err := row.Scan(&routine.CreatedAt, &routine.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
@@ -80,18 +80,20 @@ func (r *RoutineRepositoryImpl) Get(ctx context.Context, userID, id string) (dom
 		SELECT id, user_id, title, description, recurrence_type, weekdays,
 			to_char(start_time, 'HH24:MI') as start_time,
 			to_char(end_time, 'HH24:MI') as end_time,
-			week_of_month, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+			week_of_month, rrule, target_kind, target_value, target_unit, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, streak_grace_per_month, streak_freeze_every, skip_holidays, created_at, updated_at
 		FROM inbota.routines
 		WHERE id = $1 AND user_id = $2
 		LIMIT 1
 	`, id, userID)
 
 	var routine domain.Routine
-	var description, endTime, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
+	var description, endTime, rrule, targetKind, targetUnit, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
 	var weekOfMonth sql.NullInt64
+	var targetValue sql.NullFloat64
 	var weekdays pq.Int64Array
+	var notifyLeadMins pq.Int64Array
 
-	if err := row.Scan(&routine.ID, &routine.UserID, &routine.Title, &description, &routine.RecurrenceType, &weekdays, &routine.StartTime, &endTime, &weekOfMonth, &routine.StartsOn, &endsOn, &color, &routine.IsActive, &flagID, &subflagID, &sourceInboxItemID, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
+	if err := row.Scan(&routine.ID, &routine.UserID, &routine.Title, &description, &routine.RecurrenceType, &weekdays, &routine.StartTime, &endTime, &weekOfMonth, &rrule, &targetKind, &targetValue, &targetUnit, &routine.StartsOn, &endsOn, &color, &routine.IsActive, &flagID, &subflagID, &sourceInboxItemID, &notifyLeadMins, &routine.Notification.Disabled, &routine.StreakProtection.GracePerMonth, &routine.StreakProtection.FreezeEvery, &routine.SkipHolidays, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Routine{}, ErrNotFound
 		}
@@ -108,11 +110,16 @@ func (r *RoutineRepositoryImpl) Get(ctx context.Context, userID, id string) (dom
 		v := int(weekOfMonth.Int64)
 		routine.WeekOfMonth = &v
 	}
+	routine.RRule = stringPtrFromNull(rrule)
+	routine.TargetKind = stringPtrFromNull(targetKind)
+	routine.TargetValue = floatPtrFromNull(targetValue)
+	routine.TargetUnit = stringPtrFromNull(targetUnit)
 	routine.EndsOn = stringPtrFromNull(endsOn)
 	routine.Color = stringPtrFromNull(color)
 	routine.FlagID = stringPtrFromNull(flagID)
 	routine.SubflagID = stringPtrFromNull(subflagID)
 	routine.SourceInboxItemID = stringPtrFromNull(sourceInboxItemID)
+	routine.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 
 	if len(weekdays) > 0 {
 		routine.Weekdays = make([]int, len(weekdays))
@@ -134,7 +141,7 @@ func (r *RoutineRepositoryImpl) List(ctx context.Context, userID string, opts re
 		SELECT id, user_id, title, description, recurrence_type, weekdays,
 			to_char(start_time, 'HH24:MI') as start_time,
 			to_char(end_time, 'HH24:MI') as end_time,
-			week_of_month, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+			week_of_month, rrule, target_kind, target_value, target_unit, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, streak_grace_per_month, streak_freeze_every, skip_holidays, created_at, updated_at
 		FROM inbota.routines
 		WHERE user_id = $1 AND is_active = true
 		ORDER BY start_time, created_at
@@ -148,11 +155,13 @@ func (r *RoutineRepositoryImpl) List(ctx context.Context, userID string, opts re
 	items := make([]domain.Routine, 0)
 	for rows.Next() {
 		var routine domain.Routine
-		var description, endTime, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
+		var description, endTime, rrule, targetKind, targetUnit, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
 		var weekOfMonth sql.NullInt64
+		var targetValue sql.NullFloat64
 		var weekdays pq.Int64Array
+		var notifyLeadMins pq.Int64Array
 
-		if err := rows.Scan(&routine.ID, &routine.UserID, &routine.Title, &description, &routine.RecurrenceType, &weekdays, &routine.StartTime, &endTime, &weekOfMonth, &routine.StartsOn, &endsOn, &color, &routine.IsActive, &flagID, &subflagID, &sourceInboxItemID, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
+		if err := rows.Scan(&routine.ID, &routine.UserID, &routine.Title, &description, &routine.RecurrenceType, &weekdays, &routine.StartTime, &endTime, &weekOfMonth, &rrule, &targetKind, &targetValue, &targetUnit, &routine.StartsOn, &endsOn, &color, &routine.IsActive, &flagID, &subflagID, &sourceInboxItemID, &notifyLeadMins, &routine.Notification.Disabled, &routine.StreakProtection.GracePerMonth, &routine.StreakProtection.FreezeEvery, &routine.SkipHolidays, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
 			return nil, nil, err
 		}
 
@@ -166,11 +175,16 @@ func (r *RoutineRepositoryImpl) List(ctx context.Context, userID string, opts re
 			v := int(weekOfMonth.Int64)
 			routine.WeekOfMonth = &v
 		}
+		routine.RRule = stringPtrFromNull(rrule)
+		routine.TargetKind = stringPtrFromNull(targetKind)
+		routine.TargetValue = floatPtrFromNull(targetValue)
+		routine.TargetUnit = stringPtrFromNull(targetUnit)
 		routine.EndsOn = stringPtrFromNull(endsOn)
 		routine.Color = stringPtrFromNull(color)
 		routine.FlagID = stringPtrFromNull(flagID)
 		routine.SubflagID = stringPtrFromNull(subflagID)
 		routine.SourceInboxItemID = stringPtrFromNull(sourceInboxItemID)
+		routine.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 
 		if len(weekdays) > 0 {
 			routine.Weekdays = make([]int, len(weekdays))
@@ -194,7 +208,7 @@ func (r *RoutineRepositoryImpl) ListByWeekday(ctx context.Context, userID string
 		SELECT id, user_id, title, description, recurrence_type, weekdays,
 			to_char(start_time, 'HH24:MI') as start_time,
 			to_char(end_time, 'HH24:MI') as end_time,
-			week_of_month, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+			week_of_month, rrule, target_kind, target_value, target_unit, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, streak_grace_per_month, streak_freeze_every, skip_holidays, created_at, updated_at
 		FROM inbota.routines
 		WHERE user_id = $1 AND is_active = true AND $2 = ANY(weekdays)
 		ORDER BY start_time, created_at
@@ -207,11 +221,13 @@ func (r *RoutineRepositoryImpl) ListByWeekday(ctx context.Context, userID string
 	items := make([]domain.Routine, 0)
 	for rows.Next() {
 		var routine domain.Routine
-		var description, endTime, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
+		var description, endTime, rrule, targetKind, targetUnit, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
 		var weekOfMonth sql.NullInt64
+		var targetValue sql.NullFloat64
 		var weekdays pq.Int64Array
+		var notifyLeadMins pq.Int64Array
 
-		if err := rows.Scan(&routine.ID, &routine.UserID, &routine.Title, &description, &routine.RecurrenceType, &weekdays, &routine.StartTime, &endTime, &weekOfMonth, &routine.StartsOn, &endsOn, &color, &routine.IsActive, &flagID, &subflagID, &sourceInboxItemID, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
+		if err := rows.Scan(&routine.ID, &routine.UserID, &routine.Title, &description, &routine.RecurrenceType, &weekdays, &routine.StartTime, &endTime, &weekOfMonth, &rrule, &targetKind, &targetValue, &targetUnit, &routine.StartsOn, &endsOn, &color, &routine.IsActive, &flagID, &subflagID, &sourceInboxItemID, &notifyLeadMins, &routine.Notification.Disabled, &routine.StreakProtection.GracePerMonth, &routine.StreakProtection.FreezeEvery, &routine.SkipHolidays, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
 			return nil, err
 		}
 
@@ -225,11 +241,16 @@ func (r *RoutineRepositoryImpl) ListByWeekday(ctx context.Context, userID string
 			v := int(weekOfMonth.Int64)
 			routine.WeekOfMonth = &v
 		}
+		routine.RRule = stringPtrFromNull(rrule)
+		routine.TargetKind = stringPtrFromNull(targetKind)
+		routine.TargetValue = floatPtrFromNull(targetValue)
+		routine.TargetUnit = stringPtrFromNull(targetUnit)
 		routine.EndsOn = stringPtrFromNull(endsOn)
 		routine.Color = stringPtrFromNull(color)
 		routine.FlagID = stringPtrFromNull(flagID)
 		routine.SubflagID = stringPtrFromNull(subflagID)
 		routine.SourceInboxItemID = stringPtrFromNull(sourceInboxItemID)
+		routine.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 
 		if len(weekdays) > 0 {
 			routine.Weekdays = make([]int, len(weekdays))
@@ -251,8 +272,8 @@ func (r *RoutineRepositoryImpl) ListDailyStatus(ctx context.Context, userID stri
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, user_id, title, description, recurrence_type, weekdays,
 			start_time, end_time,
-			week_of_month, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at,
-			completed_at, is_completed, exception_action
+			week_of_month, rrule, target_kind, target_value, target_unit, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, streak_grace_per_month, streak_freeze_every, skip_holidays, created_at, updated_at,
+			completed_at, is_completed, progress, exception_action
 		FROM inbota.fnc_routine_daily_status($1, $2, $3::date)
 	`, userID, weekday, date)
 	if err != nil {
@@ -263,14 +284,16 @@ func (r *RoutineRepositoryImpl) ListDailyStatus(ctx context.Context, userID stri
 	items := make([]repository.RoutineDailyStatus, 0)
 	for rows.Next() {
 		var item repository.RoutineDailyStatus
-		var description, endTime, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
+		var description, endTime, rrule, targetKind, targetUnit, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
 		var weekOfMonth sql.NullInt64
+		var targetValue sql.NullFloat64
 		var weekdays pq.Int64Array
+		var notifyLeadMins pq.Int64Array
 		var completedAt, exceptionAction sql.NullString
 
 		if err := rows.Scan(
-			&item.ID, &item.UserID, &item.Title, &description, &item.RecurrenceType, &weekdays, &item.StartTime, &endTime, &weekOfMonth, &item.StartsOn, &endsOn, &color, &item.IsActive, &flagID, &subflagID, &sourceInboxItemID, &item.CreatedAt, &item.UpdatedAt,
-			&completedAt, &item.IsCompleted, &exceptionAction,
+			&item.ID, &item.UserID, &item.Title, &description, &item.RecurrenceType, &weekdays, &item.StartTime, &endTime, &weekOfMonth, &rrule, &targetKind, &targetValue, &targetUnit, &item.StartsOn, &endsOn, &color, &item.IsActive, &flagID, &subflagID, &sourceInboxItemID, &notifyLeadMins, &item.Notification.Disabled, &item.StreakProtection.GracePerMonth, &item.StreakProtection.FreezeEvery, &item.SkipHolidays, &item.CreatedAt, &item.UpdatedAt,
+			&completedAt, &item.IsCompleted, &item.Progress, &exceptionAction,
 		); err != nil {
 			return nil, err
 		}
@@ -285,11 +308,16 @@ func (r *RoutineRepositoryImpl) ListDailyStatus(ctx context.Context, userID stri
 			v := int(weekOfMonth.Int64)
 			item.WeekOfMonth = &v
 		}
+		item.RRule = stringPtrFromNull(rrule)
+		item.TargetKind = stringPtrFromNull(targetKind)
+		item.TargetValue = floatPtrFromNull(targetValue)
+		item.TargetUnit = stringPtrFromNull(targetUnit)
 		item.EndsOn = stringPtrFromNull(endsOn)
 		item.Color = stringPtrFromNull(color)
 		item.FlagID = stringPtrFromNull(flagID)
 		item.SubflagID = stringPtrFromNull(subflagID)
 		item.SourceInboxItemID = stringPtrFromNull(sourceInboxItemID)
+		item.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 		item.CompletedAt = stringPtrFromNull(completedAt)
 		item.ExceptionAction = stringPtrFromNull(exceptionAction)
 
@@ -449,6 +477,79 @@ func (r *RoutineExceptionRepositoryImpl) GetForDate(ctx context.Context, userID,
 	return &exception, nil
 }
 
+func (r *RoutineExceptionRepositoryImpl) ListByDate(ctx context.Context, userID, date string) ([]domain.RoutineException, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.id, e.routine_id, e.exception_date::text, e.action,
+			to_char(e.new_start_time, 'HH24:MI'), to_char(e.new_end_time, 'HH24:MI'), e.reason, e.created_at
+		FROM inbota.routine_exceptions e
+		JOIN inbota.routines r ON r.id = e.routine_id
+		WHERE r.user_id = $1 AND e.exception_date = $2::date
+	`, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.RoutineException, 0)
+	for rows.Next() {
+		var exception domain.RoutineException
+		var newStartTime, newEndTime, reason sql.NullString
+
+		if err := rows.Scan(&exception.ID, &exception.RoutineID, &exception.ExceptionDate, &exception.Action, &newStartTime, &newEndTime, &reason, &exception.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		exception.NewStartTime = stringPtrFromNull(newStartTime)
+		exception.NewEndTime = stringPtrFromNull(newEndTime)
+		exception.Reason = stringPtrFromNull(reason)
+
+		items = append(items, exception)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *RoutineExceptionRepositoryImpl) ListForRoutines(ctx context.Context, routineIDs []string, from, to string) ([]domain.RoutineException, error) {
+	items := make([]domain.RoutineException, 0)
+	if len(routineIDs) == 0 {
+		return items, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, routine_id, exception_date::text, action,
+			to_char(new_start_time, 'HH24:MI'), to_char(new_end_time, 'HH24:MI'), reason, created_at
+		FROM inbota.routine_exceptions
+		WHERE routine_id = ANY($1::uuid[]) AND exception_date BETWEEN $2::date AND $3::date
+	`, pq.Array(routineIDs), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exception domain.RoutineException
+		var newStartTime, newEndTime, reason sql.NullString
+
+		if err := rows.Scan(&exception.ID, &exception.RoutineID, &exception.ExceptionDate, &exception.Action, &newStartTime, &newEndTime, &reason, &exception.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		exception.NewStartTime = stringPtrFromNull(newStartTime)
+		exception.NewEndTime = stringPtrFromNull(newEndTime)
+		exception.Reason = stringPtrFromNull(reason)
+
+		items = append(items, exception)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
 type RoutineCompletionRepositoryImpl struct {
 	db dbtx
 }
@@ -462,12 +563,16 @@ func NewRoutineCompletionRepositoryTx(tx *sql.Tx) *RoutineCompletionRepositoryIm
 }
 
 func (r *RoutineCompletionRepositoryImpl) Create(ctx context.Context, userID string, completion domain.RoutineCompletion) (domain.RoutineCompletion, error) {
+	if completion.Value == 0 {
+		completion.Value = 1
+	}
+
 	row := r.db.QueryRowContext(ctx, `
-		INSERT INTO inbota.routine_completions (routine_id, completed_on)
-		SELECT $1, $2
+		INSERT INTO inbota.routine_completions (routine_id, completed_on, value)
+		SELECT $1, $2, $4
 		WHERE EXISTS (SELECT 1 FROM inbota.routines WHERE id = $1 AND user_id = $3)
 		RETURNING id, completed_at
-	`, completion.RoutineID, completion.CompletedOn, userID)
+	`, completion.RoutineID, completion.CompletedOn, userID, completion.Value)
 
 	if err := row.Scan(&completion.ID, &completion.CompletedAt); err != nil {
 		if err == sql.ErrNoRows {
@@ -478,6 +583,26 @@ func (r *RoutineCompletionRepositoryImpl) Create(ctx context.Context, userID str
 	return completion, nil
 }
 
+// AddProgress adds completion.Value to the day's progress, creating the row on the first check-in.
+func (r *RoutineCompletionRepositoryImpl) AddProgress(ctx context.Context, userID string, completion domain.RoutineCompletion) (domain.RoutineCompletion, error) {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO inbota.routine_completions (routine_id, completed_on, value)
+		SELECT $1, $2, $4
+		WHERE EXISTS (SELECT 1 FROM inbota.routines WHERE id = $1 AND user_id = $3)
+		ON CONFLICT (routine_id, completed_on)
+		DO UPDATE SET value = inbota.routine_completions.value + EXCLUDED.value, completed_at = now()
+		RETURNING id, value, completed_at
+	`, completion.RoutineID, completion.CompletedOn, userID, completion.Value)
+
+	if err := row.Scan(&completion.ID, &completion.Value, &completion.CompletedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.RoutineCompletion{}, ErrNotFound
+		}
+		return domain.RoutineCompletion{}, err
+	}
+	return completion, nil
+}
+
 func (r *RoutineCompletionRepositoryImpl) Delete(ctx context.Context, userID, routineID, completedOn string) error {
 	result, err := r.db.ExecContext(ctx, `
 		DELETE FROM inbota.routine_completions
@@ -500,7 +625,7 @@ func (r *RoutineCompletionRepositoryImpl) Delete(ctx context.Context, userID, ro
 
 func (r *RoutineCompletionRepositoryImpl) GetByRoutine(ctx context.Context, userID, routineID string) ([]domain.RoutineCompletion, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, routine_id, completed_on::text, completed_at
+		SELECT id, routine_id, completed_on::text, value, completed_at
 		FROM inbota.routine_completions
 		WHERE routine_id = $1 AND EXISTS (SELECT 1 FROM inbota.routines WHERE id = $1 AND user_id = $2)
 		ORDER BY completed_on DESC
@@ -513,7 +638,7 @@ func (r *RoutineCompletionRepositoryImpl) GetByRoutine(ctx context.Context, user
 	items := make([]domain.RoutineCompletion, 0)
 	for rows.Next() {
 		var completion domain.RoutineCompletion
-		if err := rows.Scan(&completion.ID, &completion.RoutineID, &completion.CompletedOn, &completion.CompletedAt); err != nil {
+		if err := rows.Scan(&completion.ID, &completion.RoutineID, &completion.CompletedOn, &completion.Value, &completion.CompletedAt); err != nil {
 			return nil, err
 		}
 		items = append(items, completion)
@@ -527,7 +652,7 @@ func (r *RoutineCompletionRepositoryImpl) GetByRoutine(ctx context.Context, user
 
 func (r *RoutineCompletionRepositoryImpl) GetByDate(ctx context.Context, userID, date string) ([]domain.RoutineCompletion, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, routine_id, completed_on, completed_at
+		SELECT id, routine_id, completed_on, value, completed_at
 		FROM inbota.routine_completions
 		WHERE completed_on = $1 AND EXISTS (SELECT 1 FROM inbota.routines WHERE id = routine_id AND user_id = $2)
 	`, date, userID)
@@ -539,7 +664,7 @@ func (r *RoutineCompletionRepositoryImpl) GetByDate(ctx context.Context, userID,
 	items := make([]domain.RoutineCompletion, 0)
 	for rows.Next() {
 		var completion domain.RoutineCompletion
-		if err := rows.Scan(&completion.ID, &completion.RoutineID, &completion.CompletedOn, &completion.CompletedAt); err != nil {
+		if err := rows.Scan(&completion.ID, &completion.RoutineID, &completion.CompletedOn, &completion.Value, &completion.CompletedAt); err != nil {
 			return nil, err
 		}
 		items = append(items, completion)
@@ -551,22 +676,32 @@ func (r *RoutineCompletionRepositoryImpl) GetByDate(ctx context.Context, userID,
 	return items, nil
 }
 
-func (r *RoutineCompletionRepositoryImpl) GetStreak(ctx context.Context, userID, routineID string) (int, int, error) {
-	row := r.db.QueryRowContext(ctx, `
-		SELECT current_streak, total_completions
-		FROM inbota.fnc_get_routine_streak($1)
-		WHERE EXISTS (SELECT 1 FROM inbota.routines WHERE id = $1 AND user_id = $2)
-	`, routineID, userID)
+func (r *RoutineCompletionRepositoryImpl) ListBetween(ctx context.Context, userID, from, to string) ([]domain.RoutineCompletion, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.routine_id, c.completed_on::text, c.value, c.completed_at
+		FROM inbota.routine_completions c
+		JOIN inbota.routines r ON r.id = c.routine_id
+		WHERE r.user_id = $1 AND c.completed_on BETWEEN $2::date AND $3::date
+		ORDER BY c.completed_on
+	`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	var currentStreak, totalCompletions int
-	if err := row.Scan(&currentStreak, &totalCompletions); err != nil {
-		if err == sql.ErrNoRows {
-			return 0, 0, nil
+	items := make([]domain.RoutineCompletion, 0)
+	for rows.Next() {
+		var completion domain.RoutineCompletion
+		if err := rows.Scan(&completion.ID, &completion.RoutineID, &completion.CompletedOn, &completion.Value, &completion.CompletedAt); err != nil {
+			return nil, err
 		}
-		return 0, 0, err
+		items = append(items, completion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return currentStreak, totalCompletions, nil
+	return items, nil
 }
 
 func (r *RoutineRepositoryImpl) ListAllByWeekday(ctx context.Context, weekday int) ([]domain.Routine, error) {
@@ -574,7 +709,7 @@ func (r *RoutineRepositoryImpl) ListAllByWeekday(ctx context.Context, weekday in
 		SELECT id, user_id, title, description, recurrence_type, weekdays,
 			to_char(start_time, 'HH24:MI') as start_time,
 			to_char(end_time, 'HH24:MI') as end_time,
-			week_of_month, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+			week_of_month, rrule, target_kind, target_value, target_unit, starts_on::text, ends_on::text, color, is_active, flag_id, subflag_id, source_inbox_item_id, notify_lead_mins, notify_disabled, streak_grace_per_month, streak_freeze_every, skip_holidays, created_at, updated_at
 		FROM inbota.routines
 		WHERE is_active = true AND $1 = ANY(weekdays)
 		ORDER BY start_time, created_at
@@ -587,11 +722,13 @@ func (r *RoutineRepositoryImpl) ListAllByWeekday(ctx context.Context, weekday in
 	items := make([]domain.Routine, 0)
 	for rows.Next() {
 		var routine domain.Routine
-		var description, endTime, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
+		var description, endTime, rrule, targetKind, targetUnit, endsOn, color, flagID, subflagID, sourceInboxItemID sql.NullString
 		var weekOfMonth sql.NullInt64
+		var targetValue sql.NullFloat64
 		var weekdays pq.Int64Array
+		var notifyLeadMins pq.Int64Array
 
-		if err := rows.Scan(&routine.ID, &routine.UserID, &routine.Title, &description, &routine.RecurrenceType, &weekdays, &routine.StartTime, &endTime, &weekOfMonth, &routine.StartsOn, &endsOn, &color, &routine.IsActive, &flagID, &subflagID, &sourceInboxItemID, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
+		if err := rows.Scan(&routine.ID, &routine.UserID, &routine.Title, &description, &routine.RecurrenceType, &weekdays, &routine.StartTime, &endTime, &weekOfMonth, &rrule, &targetKind, &targetValue, &targetUnit, &routine.StartsOn, &endsOn, &color, &routine.IsActive, &flagID, &subflagID, &sourceInboxItemID, &notifyLeadMins, &routine.Notification.Disabled, &routine.StreakProtection.GracePerMonth, &routine.StreakProtection.FreezeEvery, &routine.SkipHolidays, &routine.CreatedAt, &routine.UpdatedAt); err != nil {
 			return nil, err
 		}
 
@@ -605,11 +742,16 @@ func (r *RoutineRepositoryImpl) ListAllByWeekday(ctx context.Context, weekday in
 			v := int(weekOfMonth.Int64)
 			routine.WeekOfMonth = &v
 		}
+		routine.RRule = stringPtrFromNull(rrule)
+		routine.TargetKind = stringPtrFromNull(targetKind)
+		routine.TargetValue = floatPtrFromNull(targetValue)
+		routine.TargetUnit = stringPtrFromNull(targetUnit)
 		routine.EndsOn = stringPtrFromNull(endsOn)
 		routine.Color = stringPtrFromNull(color)
 		routine.FlagID = stringPtrFromNull(flagID)
 		routine.SubflagID = stringPtrFromNull(subflagID)
 		routine.SourceInboxItemID = stringPtrFromNull(sourceInboxItemID)
+		routine.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
 
 		if len(weekdays) > 0 {
 			routine.Weekdays = make([]int, len(weekdays))
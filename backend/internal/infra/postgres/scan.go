@@ -3,8 +3,15 @@ package postgres
 import (
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
 func stringPtrFromNull(value sql.NullString) *string {
 	if !value.Valid {
 		return nil
@@ -29,6 +36,22 @@ func floatPtrFromNull(value sql.NullFloat64) *float64 {
 	return &v
 }
 
+func intPtrFromNull(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
+
 func nullStringFromStr(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: s != ""}
 }
+
+// intSliceFromNullArray keeps NULL arrays as nil (unlike intArrayFromInt64Array).
+func intSliceFromNullArray(a pq.Int64Array) []int {
+	if a == nil {
+		return nil
+	}
+	return intArrayFromInt64Array(a)
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"inbota/backend/internal/app/domain"
+)
+
+type TaskChecklistItemRepository struct {
+	db dbtx
+}
+
+func NewTaskChecklistItemRepository(db *DB) *TaskChecklistItemRepository {
+	return &TaskChecklistItemRepository{db: db}
+}
+
+func NewTaskChecklistItemRepositoryTx(tx *sql.Tx) *TaskChecklistItemRepository {
+	return &TaskChecklistItemRepository{db: tx}
+}
+
+func (r *TaskChecklistItemRepository) Create(ctx context.Context, item domain.TaskChecklistItem) (domain.TaskChecklistItem, error) {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO inbota.task_checklist_items (user_id, task_id, title, checked, sort_order)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, item.UserID, item.TaskID, item.Title, item.Checked, item.SortOrder)
+
+	if err := row.Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		return domain.TaskChecklistItem{}, err
+	}
+	return item, nil
+}
+
+func (r *TaskChecklistItemRepository) Update(ctx context.Context, item domain.TaskChecklistItem) (domain.TaskChecklistItem, error) {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE inbota.task_checklist_items
+		SET title = $1, checked = $2, sort_order = $3, updated_at = now()
+		WHERE id = $4 AND user_id = $5
+		RETURNING created_at, updated_at
+	`, item.Title, item.Checked, item.SortOrder, item.ID, item.UserID)
+
+	if err := row.Scan(&item.CreatedAt, &item.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.TaskChecklistItem{}, ErrNotFound
+		}
+		return domain.TaskChecklistItem{}, err
+	}
+	return item, nil
+}
+
+func (r *TaskChecklistItemRepository) Delete(ctx context.Context, userID, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM inbota.task_checklist_items
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *TaskChecklistItemRepository) Get(ctx context.Context, userID, id string) (domain.TaskChecklistItem, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, task_id, title, checked, sort_order, created_at, updated_at
+		FROM inbota.task_checklist_items
+		WHERE id = $1 AND user_id = $2
+		LIMIT 1
+	`, id, userID)
+
+	var item domain.TaskChecklistItem
+	if err := row.Scan(&item.ID, &item.UserID, &item.TaskID, &item.Title, &item.Checked, &item.SortOrder, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.TaskChecklistItem{}, ErrNotFound
+		}
+		return domain.TaskChecklistItem{}, err
+	}
+	return item, nil
+}
+
+func (r *TaskChecklistItemRepository) ListByTask(ctx context.Context, userID, taskID string) ([]domain.TaskChecklistItem, error) {
+	return r.ListByTasks(ctx, userID, []string{taskID})
+}
+
+func (r *TaskChecklistItemRepository) ListByTasks(ctx context.Context, userID string, taskIDs []string) ([]domain.TaskChecklistItem, error) {
+	if len(taskIDs) == 0 {
+		return []domain.TaskChecklistItem{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, task_id, title, checked, sort_order, created_at, updated_at
+		FROM inbota.task_checklist_items
+		WHERE user_id = $1 AND task_id = ANY($2)
+		ORDER BY task_id, sort_order ASC, created_at ASC
+	`, userID, pq.Array(taskIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.TaskChecklistItem, 0)
+	for rows.Next() {
+		var item domain.TaskChecklistItem
+		if err := rows.Scan(&item.ID, &item.UserID, &item.TaskID, &item.Title, &item.Checked, &item.SortOrder, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
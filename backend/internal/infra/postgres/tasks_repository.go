@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/lib/pq"
+
 	"inbota/backend/internal/app/domain"
 	"inbota/backend/internal/app/repository"
 )
@@ -21,16 +23,23 @@ func NewTaskRepositoryTx(tx *sql.Tx) *TaskRepository {
 	return &TaskRepository{db: tx}
 }
 
+// taskColumns is shared by every SELECT so scanTask stays in sync.
+// blocked is computed: some task in blocked_by is not done yet.
+const taskColumns = `id, user_id, title, description, status, due_at, flag_id, subflag_id, source_inbox_item_id, project_id, priority, effort_mins, recurrence_rule, recurrence_after_days, series_id, blocked_by,
+	EXISTS (SELECT 1 FROM inbota.tasks b WHERE b.id = ANY(tasks.blocked_by) AND b.status <> 'DONE') AS blocked,
+	notify_lead_mins, notify_disabled, created_at, updated_at`
+
 func (r *TaskRepository) Create(ctx context.Context, task domain.Task) (domain.Task, error) {
 	if task.Status == "" {
 		task.Status = domain.TaskStatusOpen
 	}
 
+	rule, afterDays := taskRecurrenceValues(task.Recurrence)
 	row := r.db.QueryRowContext(ctx, `
-		INSERT INTO inbota.tasks (user_id, title, description, status, due_at, flag_id, subflag_id, source_inbox_item_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO inbota.tasks (user_id, title, description, status, due_at, flag_id, subflag_id, source_inbox_item_id, project_id, priority, effort_mins, recurrence_rule, recurrence_after_days, series_id, blocked_by, notify_lead_mins, notify_disabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 		RETURNING id, created_at, updated_at
-	`, task.UserID, task.Title, task.Description, string(task.Status), task.DueAt, task.FlagID, task.SubflagID, task.SourceInboxItemID)
+	`, task.UserID, task.Title, task.Description, string(task.Status), task.DueAt, task.FlagID, task.SubflagID, task.SourceInboxItemID, task.ProjectID, task.Priority, task.EffortMins, rule, afterDays, task.SeriesID, pq.Array(taskBlockedByValue(task.BlockedBy)), pq.Array(task.Notification.LeadMins), task.Notification.Disabled)
 
 	if err := row.Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt); err != nil {
 		return domain.Task{}, err
@@ -39,36 +48,61 @@ func (r *TaskRepository) Create(ctx context.Context, task domain.Task) (domain.T
 }
 
 func (r *TaskRepository) Update(ctx context.Context, task domain.Task) (domain.Task, error) {
+	updated, ok, err := r.update(ctx, task, false)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if !ok {
+		return domain.Task{}, ErrNotFound
+	}
+	return updated, nil
+}
+
+func (r *TaskRepository) UpdateIfOpen(ctx context.Context, task domain.Task) (domain.Task, bool, error) {
+	return r.update(ctx, task, true)
+}
+
+// update reports false when no row matched; onlyOpen adds the status <> 'DONE' guard.
+func (r *TaskRepository) update(ctx context.Context, task domain.Task, onlyOpen bool) (domain.Task, bool, error) {
+	rule, afterDays := taskRecurrenceValues(task.Recurrence)
 	row := r.db.QueryRowContext(ctx, `
 		UPDATE inbota.tasks
-		SET title = $1, description = $2, status = $3, due_at = $4, flag_id = $5, subflag_id = $6, updated_at = now()
-		WHERE id = $7 AND user_id = $8
+		SET title = $1, description = $2, status = $3, due_at = $4, flag_id = $5, subflag_id = $6, project_id = $7, priority = $8, effort_mins = $9,
+		    recurrence_rule = $10, recurrence_after_days = $11, series_id = $12, blocked_by = $13, notify_lead_mins = $14, notify_disabled = $15, updated_at = now()
+		WHERE id = $16 AND user_id = $17
+		  AND (NOT $18 OR status <> 'DONE')
 		RETURNING created_at, updated_at
-	`, task.Title, task.Description, string(task.Status), task.DueAt, task.FlagID, task.SubflagID, task.ID, task.UserID)
+	`, task.Title, task.Description, string(task.Status), task.DueAt, task.FlagID, task.SubflagID, task.ProjectID, task.Priority, task.EffortMins, rule, afterDays, task.SeriesID, pq.Array(taskBlockedByValue(task.BlockedBy)), pq.Array(task.Notification.LeadMins), task.Notification.Disabled, task.ID, task.UserID, onlyOpen)
 
 	if err := row.Scan(&task.CreatedAt, &task.UpdatedAt); err != nil {
 		if err == sql.ErrNoRows {
-			return domain.Task{}, ErrNotFound
+			return domain.Task{}, false, nil
 		}
-		return domain.Task{}, err
+		return domain.Task{}, false, err
 	}
-	return task, nil
+	return task, true, nil
 }
 
 func (r *TaskRepository) Delete(ctx context.Context, userID, id string) error {
-	result, err := r.db.ExecContext(ctx, `
-		DELETE FROM inbota.tasks
-		WHERE id = $1 AND user_id = $2
-	`, id, userID)
+	// blocked_by has no FK, so the task leaves its dependents in the same statement.
+	var deleted int
+	err := r.db.QueryRowContext(ctx, `
+		WITH deleted AS (
+			DELETE FROM inbota.tasks
+			WHERE id = $1::uuid AND user_id = $2
+			RETURNING id
+		), unblocked AS (
+			UPDATE inbota.tasks
+			SET blocked_by = array_remove(blocked_by, $1::uuid)
+			WHERE user_id = $2 AND $1::uuid = ANY(blocked_by)
+			  AND EXISTS (SELECT 1 FROM deleted)
+		)
+		SELECT count(*) FROM deleted
+	`, id, userID).Scan(&deleted)
 	if err != nil {
 		return err
 	}
-
-	affected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
+	if deleted == 0 {
 		return ErrNotFound
 	}
 	return nil
@@ -76,31 +110,19 @@ func (r *TaskRepository) Delete(ctx context.Context, userID, id string) error {
 
 func (r *TaskRepository) Get(ctx context.Context, userID, id string) (domain.Task, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, user_id, title, description, status, due_at, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT `+taskColumns+`
 		FROM inbota.tasks
 		WHERE id = $1 AND user_id = $2
 		LIMIT 1
 	`, id, userID)
 
-	var description sql.NullString
-	var dueAt sql.NullTime
-	var flagID sql.NullString
-	var subflagID sql.NullString
-	var sourceInboxID sql.NullString
-	var status string
-	var task domain.Task
-	if err := row.Scan(&task.ID, &task.UserID, &task.Title, &description, &status, &dueAt, &flagID, &subflagID, &sourceInboxID, &task.CreatedAt, &task.UpdatedAt); err != nil {
+	task, err := scanTask(row)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Task{}, ErrNotFound
 		}
 		return domain.Task{}, err
 	}
-	task.Description = stringPtrFromNull(description)
-	task.Status = domain.TaskStatus(status)
-	task.DueAt = timePtrFromNull(dueAt)
-	task.FlagID = stringPtrFromNull(flagID)
-	task.SubflagID = stringPtrFromNull(subflagID)
-	task.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
 	return task, nil
 }
 
@@ -111,38 +133,20 @@ func (r *TaskRepository) List(ctx context.Context, userID string, opts repositor
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, title, description, status, due_at, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT `+taskColumns+`
 		FROM inbota.tasks
 		WHERE user_id = $1
+		  AND ($2::uuid IS NULL OR project_id = $2::uuid)
 		ORDER BY due_at NULLS LAST, created_at DESC
-		LIMIT $2 OFFSET $3
-	`, userID, limit, offset)
+		LIMIT $3 OFFSET $4
+	`, userID, opts.ProjectID, limit, offset)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer rows.Close()
 
-	items := make([]domain.Task, 0)
-	for rows.Next() {
-		var description sql.NullString
-		var dueAt sql.NullTime
-		var flagID sql.NullString
-		var subflagID sql.NullString
-		var sourceInboxID sql.NullString
-		var status string
-		var task domain.Task
-		if err := rows.Scan(&task.ID, &task.UserID, &task.Title, &description, &status, &dueAt, &flagID, &subflagID, &sourceInboxID, &task.CreatedAt, &task.UpdatedAt); err != nil {
-			return nil, nil, err
-		}
-		task.Description = stringPtrFromNull(description)
-		task.Status = domain.TaskStatus(status)
-		task.DueAt = timePtrFromNull(dueAt)
-		task.FlagID = stringPtrFromNull(flagID)
-		task.SubflagID = stringPtrFromNull(subflagID)
-		task.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
-		items = append(items, task)
-	}
-	if err := rows.Err(); err != nil {
+	items, err := scanTasks(rows)
+	if err != nil {
 		return nil, nil, err
 	}
 
@@ -150,35 +154,110 @@ func (r *TaskRepository) List(ctx context.Context, userID string, opts repositor
 	return items, next, nil
 }
 
+func (r *TaskRepository) ListOpen(ctx context.Context, userID string, limit int) ([]domain.Task, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM inbota.tasks
+		WHERE user_id = $1 AND status = 'OPEN'
+		ORDER BY due_at NULLS LAST, created_at DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTasks(rows)
+}
+
+func (r *TaskRepository) ListSeries(ctx context.Context, userID, seriesID string) ([]domain.Task, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM inbota.tasks
+		WHERE user_id = $1 AND (id = $2 OR series_id = $2)
+		ORDER BY due_at DESC NULLS LAST, created_at DESC
+		LIMIT 500
+	`, userID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTasks(rows)
+}
+
 func (r *TaskRepository) ListUpcoming(ctx context.Context, start, end time.Time) ([]domain.Task, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, title, description, status, due_at, flag_id, subflag_id, source_inbox_item_id, created_at, updated_at
+		SELECT `+taskColumns+`
 		FROM inbota.tasks
-		WHERE status = 'OPEN' AND due_at >= $1 AND due_at <= $2
-	`, start, end)
+		WHERE status = 'OPEN' AND due_at >= $1
+		  AND due_at <= $2 + make_interval(mins => $3)
+		  AND (due_at <= $2 OR due_at - make_interval(mins => COALESCE((SELECT max(m) FROM unnest(notify_lead_mins) m), 0)) <= $2)
+	`, start, end, domain.MaxNotificationLeadMins)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	return scanTasks(rows)
+}
 
-	items := make([]domain.Task, 0)
+func (r *TaskRepository) ListDependencyGraph(ctx context.Context, userID string, ids []string) (map[string]repository.TaskDependencyNode, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, status, blocked_by
+		FROM inbota.tasks
+		WHERE user_id = $1
+		  AND (id::text = ANY($2::text[]) OR cardinality(blocked_by) > 0)
+	`, userID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := make(map[string]repository.TaskDependencyNode)
 	for rows.Next() {
-		var description sql.NullString
-		var dueAt sql.NullTime
-		var flagID sql.NullString
-		var subflagID sql.NullString
-		var sourceInboxID sql.NullString
+		var node repository.TaskDependencyNode
 		var status string
-		var task domain.Task
-		if err := rows.Scan(&task.ID, &task.UserID, &task.Title, &description, &status, &dueAt, &flagID, &subflagID, &sourceInboxID, &task.CreatedAt, &task.UpdatedAt); err != nil {
+		var blockedBy pq.StringArray
+		if err := rows.Scan(&node.ID, &status, &blockedBy); err != nil {
+			return nil, err
+		}
+		node.Status = domain.TaskStatus(status)
+		node.BlockedBy = []string(blockedBy)
+		nodes[node.ID] = node
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (r *TaskRepository) ListUnblocked(ctx context.Context, since time.Time) ([]domain.Task, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM inbota.tasks
+		WHERE status = 'OPEN' AND cardinality(blocked_by) > 0
+		  AND NOT EXISTS (SELECT 1 FROM inbota.tasks b WHERE b.id = ANY(tasks.blocked_by) AND b.status <> 'DONE')
+		  AND EXISTS (SELECT 1 FROM inbota.tasks b WHERE b.id = ANY(tasks.blocked_by) AND b.updated_at >= $1)
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTasks(rows)
+}
+
+func scanTasks(rows *sql.Rows) ([]domain.Task, error) {
+	items := make([]domain.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
-		task.Description = stringPtrFromNull(description)
-		task.Status = domain.TaskStatus(status)
-		task.DueAt = timePtrFromNull(dueAt)
-		task.FlagID = stringPtrFromNull(flagID)
-		task.SubflagID = stringPtrFromNull(subflagID)
-		task.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
 		items = append(items, task)
 	}
 	if err := rows.Err(); err != nil {
@@ -186,3 +265,63 @@ func (r *TaskRepository) ListUpcoming(ctx context.Context, start, end time.Time)
 	}
 	return items, nil
 }
+
+func scanTask(row rowScanner) (domain.Task, error) {
+	var description sql.NullString
+	var dueAt sql.NullTime
+	var flagID sql.NullString
+	var subflagID sql.NullString
+	var sourceInboxID sql.NullString
+	var projectID sql.NullString
+	var priority sql.NullString
+	var effortMins sql.NullInt64
+	var recurrenceRule sql.NullString
+	var recurrenceAfterDays sql.NullInt64
+	var seriesID sql.NullString
+	var blockedBy pq.StringArray
+	var notifyLeadMins pq.Int64Array
+	var status string
+	var task domain.Task
+	if err := row.Scan(&task.ID, &task.UserID, &task.Title, &description, &status, &dueAt, &flagID, &subflagID, &sourceInboxID, &projectID, &priority, &effortMins, &recurrenceRule, &recurrenceAfterDays, &seriesID, &blockedBy, &task.Blocked, &notifyLeadMins, &task.Notification.Disabled, &task.CreatedAt, &task.UpdatedAt); err != nil {
+		return domain.Task{}, err
+	}
+	task.Description = stringPtrFromNull(description)
+	task.Status = domain.TaskStatus(status)
+	task.DueAt = timePtrFromNull(dueAt)
+	task.FlagID = stringPtrFromNull(flagID)
+	task.SubflagID = stringPtrFromNull(subflagID)
+	task.SourceInboxItemID = stringPtrFromNull(sourceInboxID)
+	task.ProjectID = stringPtrFromNull(projectID)
+	if priority.Valid {
+		value := domain.TaskPriority(priority.String)
+		task.Priority = &value
+	}
+	task.EffortMins = intPtrFromNull(effortMins)
+	if recurrenceRule.Valid || recurrenceAfterDays.Valid {
+		task.Recurrence = &domain.TaskRecurrence{
+			Rule:      stringPtrFromNull(recurrenceRule),
+			AfterDays: intPtrFromNull(recurrenceAfterDays),
+		}
+	}
+	task.SeriesID = stringPtrFromNull(seriesID)
+	if len(blockedBy) > 0 {
+		task.BlockedBy = []string(blockedBy)
+	}
+	task.Notification.LeadMins = intSliceFromNullArray(notifyLeadMins)
+	return task, nil
+}
+
+func taskRecurrenceValues(recurrence *domain.TaskRecurrence) (*string, *int) {
+	if recurrence == nil {
+		return nil, nil
+	}
+	return recurrence.Rule, recurrence.AfterDays
+}
+
+// taskBlockedByValue keeps the column NOT NULL: no blockers is stored as an empty array.
+func taskBlockedByValue(blockedBy []string) []string {
+	if blockedBy == nil {
+		return []string{}
+	}
+	return blockedBy
+}
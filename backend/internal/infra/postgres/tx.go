@@ -25,6 +25,7 @@ func (r *TxRunner) WithTx(ctx context.Context, fn func(tx repository.TxRepositor
 		Inbox:         NewInboxRepositoryTx(tx),
 		Suggestions:   NewAiSuggestionRepositoryTx(tx),
 		Tasks:         NewTaskRepositoryTx(tx),
+		TaskChecklist: NewTaskChecklistItemRepositoryTx(tx),
 		Reminders:     NewReminderRepositoryTx(tx),
 		Events:        NewEventRepositoryTx(tx),
 		ShoppingLists: NewShoppingListRepositoryTx(tx),
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/lib/pq"
+
 	"inbota/backend/internal/app/domain"
 )
 
@@ -22,11 +24,11 @@ func (r *UserRepository) Create(ctx context.Context, user domain.User) (domain.U
 	row := r.db.QueryRowContext(ctx, `
 		INSERT INTO inbota.users (email, display_name, password, locale, timezone)
 		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id
+		RETURNING id, holiday_calendar
 	`, user.Email, user.DisplayName, user.Password, user.Locale, user.Timezone)
 
 	var id string
-	if err := row.Scan(&id); err != nil {
+	if err := row.Scan(&id, &user.HolidayCalendar); err != nil {
 		return domain.User{}, err
 	}
 	user.ID = id
@@ -35,14 +37,14 @@ func (r *UserRepository) Create(ctx context.Context, user domain.User) (domain.U
 
 func (r *UserRepository) Get(ctx context.Context, id string) (domain.User, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, email, display_name, password, locale, timezone, created_at, updated_at
+		SELECT id, email, display_name, password, locale, timezone, holiday_calendar, created_at, updated_at
 		FROM inbota.users
 		WHERE id = $1
 		LIMIT 1
 	`, id)
 
 	var user domain.User
-	if err := row.Scan(&user.ID, &user.Email, &user.DisplayName, &user.Password, &user.Locale, &user.Timezone, &user.CreatedAt, &user.UpdatedAt); err != nil {
+	if err := row.Scan(&user.ID, &user.Email, &user.DisplayName, &user.Password, &user.Locale, &user.Timezone, &user.HolidayCalendar, &user.CreatedAt, &user.UpdatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.User{}, ErrUserNotFound
 		}
@@ -51,16 +53,44 @@ func (r *UserRepository) Get(ctx context.Context, id string) (domain.User, error
 	return user, nil
 }
 
+func (r *UserRepository) ListByIDs(ctx context.Context, ids []string) ([]domain.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, email, display_name, password, locale, timezone, holiday_calendar, created_at, updated_at
+		FROM inbota.users
+		WHERE id = ANY($1::uuid[])
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0, len(ids))
+	for rows.Next() {
+		var user domain.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.DisplayName, &user.Password, &user.Locale, &user.Timezone, &user.HolidayCalendar, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (domain.User, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT id, email, display_name, password, locale, timezone
+		SELECT id, email, display_name, password, locale, timezone, holiday_calendar
 		FROM inbota.users
 		WHERE email = $1
 		LIMIT 1
 	`, email)
 
 	var user domain.User
-	if err := row.Scan(&user.ID, &user.Email, &user.DisplayName, &user.Password, &user.Locale, &user.Timezone); err != nil {
+	if err := row.Scan(&user.ID, &user.Email, &user.DisplayName, &user.Password, &user.Locale, &user.Timezone, &user.HolidayCalendar); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.User{}, ErrUserNotFound
 		}
@@ -68,3 +98,23 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (domain.
 	}
 	return user, nil
 }
+
+func (r *UserRepository) UpdateHolidayCalendar(ctx context.Context, id, code string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE inbota.users
+		SET holiday_calendar = $1, updated_at = now()
+		WHERE id = $2
+	`, code, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
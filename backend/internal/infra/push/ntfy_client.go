@@ -2,6 +2,11 @@ package push
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,26 +14,135 @@ import (
 	"time"
 )
 
-type NtfyClient struct {
+// DefaultNtfyBaseURL is the public ntfy server, used when no server is configured.
+const DefaultNtfyBaseURL = "https://ntfy.sh"
+
+// EncryptedTitle replaces the real title when the message is encrypted.
+const EncryptedTitle = "Inbota"
+
+// NtfyConfig configures the ntfy server the client publishes to.
+type NtfyConfig struct {
 	BaseURL string
-	client  *http.Client
+	// Token is sent as "Authorization: Bearer"; Username/Password (basic auth) are used when it is empty.
+	Token    string
+	Username string
+	Password string
+	// Encrypt seals title, body and click URL with the destination key (AES-256-GCM).
+	Encrypt bool
+	// Priorities maps a notification type ("reminder", "bundle", ...) to an ntfy priority.
+	Priorities map[string]string
+}
+
+// Destination is an ntfy topic plus its base64 AES-256 key (empty = plain text).
+type Destination struct {
+	Topic         string
+	EncryptionKey string
+}
+
+// NewDestination builds a Destination from a stored topic and optional key.
+func NewDestination(topic string, encryptionKey *string) Destination {
+	d := Destination{Topic: topic}
+	if encryptionKey != nil {
+		d.EncryptionKey = *encryptionKey
+	}
+	return d
+}
+
+type NtfyClient struct {
+	BaseURL    string
+	token      string
+	username   string
+	password   string
+	encrypt    bool
+	priorities map[string]string
+	client     *http.Client
+}
+
+// defaultNtfyPriorities: time-sensitive items ring, summaries stay quiet.
+var defaultNtfyPriorities = map[string]string{
+	"reminder": "high",
+	"event":    "high",
+	"task":     "default",
+	"routine":  "default",
+	"bundle":   "default",
+	"briefing": "low",
+	"review":   "low",
+	"test":     "default",
+}
+
+var validNtfyPriorities = map[string]struct{}{
+	"min": {}, "low": {}, "default": {}, "high": {}, "max": {}, "urgent": {},
+	"1": {}, "2": {}, "3": {}, "4": {}, "5": {},
 }
 
 func NewNtfyClient(baseURL string) *NtfyClient {
+	return NewNtfyClientWithConfig(NtfyConfig{BaseURL: baseURL})
+}
+
+func NewNtfyClientWithConfig(cfg NtfyConfig) *NtfyClient {
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
 	if baseURL == "" {
-		baseURL = "https://ntfy.sh"
+		baseURL = DefaultNtfyBaseURL
+	}
+	priorities := make(map[string]string, len(defaultNtfyPriorities)+len(cfg.Priorities))
+	for k, v := range defaultNtfyPriorities {
+		priorities[k] = v
+	}
+	for k, v := range cfg.Priorities {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if _, ok := validNtfyPriorities[v]; ok {
+			priorities[strings.ToLower(strings.TrimSpace(k))] = v
+		}
 	}
 	return &NtfyClient{
-		BaseURL: baseURL,
+		BaseURL:    baseURL,
+		token:      cfg.Token,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		encrypt:    cfg.Encrypt,
+		priorities: priorities,
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 	}
 }
 
-func (c *NtfyClient) Send(ctx context.Context, topic, title, body string, data map[string]string) error {
-	url := fmt.Sprintf("%s/%s", c.BaseURL, topic)
-	
+// EncryptionEnabled reports whether messages are sealed with the destination key.
+func (c *NtfyClient) EncryptionEnabled() bool {
+	return c.encrypt
+}
+
+// Priority returns the ntfy priority for a notification type.
+func (c *NtfyClient) Priority(nType string) string {
+	if p, ok := c.priorities[nType]; ok {
+		return p
+	}
+	return "default"
+}
+
+// encryptedPayload is what the app decrypts from an encrypted message body.
+type encryptedPayload struct {
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Click string            `json:"click,omitempty"`
+	Data  map[string]string `json:"data,omitempty"`
+}
+
+func (c *NtfyClient) Send(ctx context.Context, dest Destination, title, body string, data map[string]string) error {
+	url := fmt.Sprintf("%s/%s", c.BaseURL, dest.Topic)
+	nType := data["type"]
+	clickURL := data["click_url"]
+
+	encrypted := c.encrypt && dest.EncryptionKey != ""
+	if encrypted {
+		sealed, err := SealMessage(dest.EncryptionKey, encryptedPayload{Title: title, Body: body, Click: clickURL, Data: data})
+		if err != nil {
+			return fmt.Errorf("failed to encrypt ntfy notification: %w", err)
+		}
+		// título, tipo e deep link ficam só dentro do payload cifrado
+		title, body, clickURL = EncryptedTitle, sealed, ""
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -37,23 +151,26 @@ func (c *NtfyClient) Send(ctx context.Context, topic, title, body string, data m
 	if title != "" {
 		req.Header.Set("Title", title)
 	}
-	
-	// Add priority
-	req.Header.Set("Priority", "high")
+	req.Header.Set("Priority", c.Priority(nType))
 
-	// Add tags/icons if needed
-	if nType, ok := data["type"]; ok {
+	switch {
+	case encrypted:
+		req.Header.Set("Tags", "encrypted")
+	case nType != "":
 		req.Header.Set("Tags", nType)
 	}
 
 	// Click URL for deep linking if available
-	if clickURL, ok := data["click_url"]; ok {
+	if clickURL != "" {
 		req.Header.Set("Click", clickURL)
 	}
 
-	// We can also send custom data as headers (X-Metadata-...) if needed,
-	// but ntfy has some limitations on header sizes.
-	// For simple deep linking, "Click" or custom tags are usually enough.
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -68,3 +185,42 @@ func (c *NtfyClient) Send(ctx context.Context, topic, title, body string, data m
 
 	return nil
 }
+
+// SealMessage encrypts the JSON payload with AES-256-GCM and returns base64(nonce || ciphertext).
+func SealMessage(key string, payload any) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	plain, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plain, nil)), nil
+}
+
+// NewTopicCredentials generates a high-entropy topic name and a base64 AES-256 key.
+func NewTopicCredentials() (topic, key string, err error) {
+	topicBytes := make([]byte, 24)
+	if _, err := rand.Read(topicBytes); err != nil {
+		return "", "", err
+	}
+	keyBytes := make([]byte, 32)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return "", "", err
+	}
+	topic = "inbota_" + base64.RawURLEncoding.EncodeToString(topicBytes)
+	return topic, base64.StdEncoding.EncodeToString(keyBytes), nil
+}
@@ -0,0 +1,176 @@
+package push
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func openMessage(t *testing.T, key, sealed string) (encryptedPayload, error) {
+	t.Helper()
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		t.Fatalf("invalid key: %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		t.Fatalf("sealed message is not base64: %v", err)
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		t.Fatalf("invalid AES key: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("unexpected GCM error: %v", err)
+	}
+	if len(data) < gcm.NonceSize() {
+		t.Fatalf("sealed message shorter than the nonce")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return encryptedPayload{}, err
+	}
+	var payload encryptedPayload
+	if err := json.Unmarshal(plain, &payload); err != nil {
+		t.Fatalf("decrypted payload is not JSON: %v", err)
+	}
+	return payload, nil
+}
+
+func TestSealMessageRoundTrip(t *testing.T) {
+	_, key, err := NewTopicCredentials()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	in := encryptedPayload{Title: "Pagar boleto", Body: "Vence hoje", Click: "inbota://reminders/r1", Data: map[string]string{"type": "reminder"}}
+
+	sealed, err := SealMessage(key, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(sealed, "Pagar") {
+		t.Fatalf("expected sealed message not to contain the plain title")
+	}
+	out, err := openMessage(t, key, sealed)
+	if err != nil {
+		t.Fatalf("expected message to decrypt, got %v", err)
+	}
+	if out.Title != in.Title || out.Body != in.Body || out.Click != in.Click || out.Data["type"] != "reminder" {
+		t.Fatalf("unexpected payload after round trip: %+v", out)
+	}
+
+	again, err := SealMessage(key, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again == sealed {
+		t.Fatalf("expected a fresh nonce for every message")
+	}
+}
+
+func TestSealMessageWrongKeyFails(t *testing.T) {
+	_, key, _ := NewTopicCredentials()
+	_, other, _ := NewTopicCredentials()
+
+	sealed, err := SealMessage(key, encryptedPayload{Title: "Pagar boleto"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := openMessage(t, other, sealed); err == nil {
+		t.Fatalf("expected decryption with another key to fail")
+	}
+
+	if _, err := SealMessage("not base64!", encryptedPayload{}); err == nil {
+		t.Fatalf("expected invalid key to be rejected")
+	}
+	short := base64.StdEncoding.EncodeToString([]byte("short"))
+	if _, err := SealMessage(short, encryptedPayload{}); err == nil {
+		t.Fatalf("expected key with invalid size to be rejected")
+	}
+}
+
+func TestNewTopicCredentials(t *testing.T) {
+	topic, key, err := NewTopicCredentials()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(topic, "inbota_") || len(topic) != len("inbota_")+32 {
+		t.Fatalf("unexpected topic format: %q", topic)
+	}
+	if strings.ContainsAny(topic, "+/=") {
+		t.Fatalf("expected URL-safe topic, got %q", topic)
+	}
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		t.Fatalf("expected base64 AES-256 key, got %q (%v)", key, err)
+	}
+
+	otherTopic, otherKey, _ := NewTopicCredentials()
+	if otherTopic == topic || otherKey == key {
+		t.Fatalf("expected random credentials on every call")
+	}
+}
+
+func TestNtfyClientPriorities(t *testing.T) {
+	client := NewNtfyClientWithConfig(NtfyConfig{Priorities: map[string]string{
+		" Task ": "HIGH",
+		"review": "loud",
+	}})
+
+	cases := map[string]string{
+		"reminder": "high",
+		"event":    "high",
+		"task":     "high",
+		"routine":  "default",
+		"bundle":   "default",
+		"briefing": "low",
+		"review":   "low",
+		"unknown":  "default",
+		"":         "default",
+	}
+	for nType, want := range cases {
+		if got := client.Priority(nType); got != want {
+			t.Fatalf("%q: expected priority %q, got %q", nType, want, got)
+		}
+	}
+}
+
+func TestNtfyClientSendEncrypted(t *testing.T) {
+	var headers http.Header
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		raw, _ := io.ReadAll(r.Body)
+		body = string(raw)
+	}))
+	defer server.Close()
+
+	topic, key, _ := NewTopicCredentials()
+	client := NewNtfyClientWithConfig(NtfyConfig{BaseURL: server.URL, Encrypt: true})
+	data := map[string]string{"type": "briefing", "click_url": "inbota://home"}
+	if err := client.Send(context.Background(), Destination{Topic: topic, EncryptionKey: key}, "Bom dia", "3 tarefas hoje", data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if headers.Get("Title") != EncryptedTitle || headers.Get("Tags") != "encrypted" || headers.Get("Click") != "" {
+		t.Fatalf("expected only neutral headers on encrypted message, got %v", headers)
+	}
+	if headers.Get("Priority") != "low" {
+		t.Fatalf("expected briefing priority low, got %q", headers.Get("Priority"))
+	}
+	payload, err := openMessage(t, key, body)
+	if err != nil {
+		t.Fatalf("expected body to decrypt, got %v", err)
+	}
+	if payload.Title != "Bom dia" || payload.Body != "3 tarefas hoje" || payload.Click != "inbota://home" {
+		t.Fatalf("unexpected decrypted payload: %+v", payload)
+	}
+}
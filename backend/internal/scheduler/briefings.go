@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"inbota/backend/internal/app/digest"
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/recurrence"
+)
+
+// BriefingSource monta os dados do dia (mesmos do digest por e-mail).
+type BriefingSource interface {
+	BuildDigestData(ctx context.Context, userID string, targetDate time.Time) (digest.DigestData, error)
+}
+
+// scheduleBriefings cria o log do briefing da manhã e do fechamento da noite quando
+// o horário local do usuário chega. O conteúdo é montado na hora para refletir o dia atual.
+func (s *NotificationScheduler) scheduleBriefings(ctx context.Context, now time.Time) {
+	if s.Briefings == nil {
+		return
+	}
+
+	prefs, err := s.Prefs.ListWithBriefings(ctx)
+	if err != nil {
+		s.Logger.Error("scheduler_list_briefing_prefs_error", slog.String("error", err.Error()))
+		return
+	}
+	if len(prefs) == 0 {
+		return
+	}
+	if s.briefingsSkipped == nil || s.briefingsSkippedOn != now.UTC().Format("2006-01-02") {
+		s.briefingsSkipped = make(map[string]struct{})
+		s.briefingsSkippedOn = now.UTC().Format("2006-01-02")
+	}
+
+	userIDs := newUserIDSet()
+	for _, p := range prefs {
+		userIDs.add(p.UserID)
+	}
+	usersByID := s.loadUsers(ctx, userIDs.list())
+	awayByUser := s.loadAwayPeriods(ctx, userIDs.list(), now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	grace := time.Duration(s.configInt("scheduler.briefing_grace_mins", 60)) * time.Minute
+	locCache := make(map[string]*time.Location)
+
+	for _, p := range prefs {
+		user, ok := usersByID[p.UserID]
+		if !ok {
+			continue
+		}
+		loc := timezoneLocation(user.Timezone, locCache)
+		userNow := now.In(loc)
+		// Férias da conta inteira pausam os briefings; períodos de uma flag não.
+		if recurrence.IsAway(awayByUser[user.ID], nil, userNow.Format("2006-01-02")) {
+			continue
+		}
+
+		if p.MorningBriefingEnabled {
+			s.scheduleBriefing(ctx, user.ID, domain.NotificationTypeBriefing, p.MorningBriefingTime, userNow, grace, digest.MorningBriefing)
+		}
+		if p.EveningReviewEnabled {
+			s.scheduleBriefing(ctx, user.ID, domain.NotificationTypeReview, p.EveningReviewTime, userNow, grace, digest.EveningReview)
+		}
+	}
+}
+
+func (s *NotificationScheduler) scheduleBriefing(
+	ctx context.Context,
+	userID string,
+	nType domain.NotificationType,
+	clock string,
+	userNow time.Time,
+	grace time.Duration,
+	build func(digest.DigestData) (string, string, bool),
+) {
+	at, err := time.Parse("15:04", clock)
+	if err != nil {
+		return
+	}
+	scheduledFor := time.Date(userNow.Year(), userNow.Month(), userNow.Day(), at.Hour(), at.Minute(), 0, 0, userNow.Location())
+	if userNow.Before(scheduledFor) || userNow.After(scheduledFor.Add(grace)) {
+		return
+	}
+
+	refID := briefingReferenceID(userID, nType, userNow.Format("2006-01-02"))
+	if _, skipped := s.briefingsSkipped[refID]; skipped {
+		return
+	}
+	exists, err := s.Log.Exists(ctx, refID, nil)
+	if err != nil || exists {
+		return
+	}
+
+	data, err := s.Briefings.BuildDigestData(ctx, userID, userNow)
+	if err != nil {
+		s.Logger.Warn("scheduler_briefing_data_error", slog.String("error", err.Error()), slog.String("user_id", userID))
+		return
+	}
+	title, body, ok := build(data)
+	if !ok {
+		// nada a enviar hoje; evita remontar o digest a cada tick dentro da janela
+		s.briefingsSkipped[refID] = struct{}{}
+		return
+	}
+
+	utc := scheduledFor.UTC()
+	s.scheduleItem(ctx, userID, nType, refID, title, body, &utc, nil)
+}
+
+// briefingReferenceID gera um UUID determinístico por usuário, tipo e dia, para que
+// o índice único de notification_log evite briefings duplicados.
+func briefingReferenceID(userID string, nType domain.NotificationType, date string) string {
+	sum := sha1.Sum([]byte(userID + "|" + string(nType) + "|" + date))
+	sum[6] = (sum[6] & 0x0f) | 0x50 // versão 5
+	sum[8] = (sum[8] & 0x3f) | 0x80 // variante RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
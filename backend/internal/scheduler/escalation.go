@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/infra/mailer"
+)
+
+// scheduleEscalations agenda a repetição atual dos lembretes em modo "nag" até o lembrete
+// ser concluído ou alguma notificação dele ser lida. Cada repetição k é gravada com
+// lead_mins = -k*intervalo, para que o índice único de notification_log evite duplicatas.
+func (s *NotificationScheduler) scheduleEscalations(ctx context.Context, now time.Time) {
+	reminders, err := s.Reminders.ListEscalating(ctx, now)
+	if err != nil {
+		s.Logger.Error("scheduler_list_escalations_error", slog.String("error", err.Error()))
+		return
+	}
+	if len(reminders) == 0 {
+		return
+	}
+
+	refIDs := make([]string, 0, len(reminders))
+	userIDs := newUserIDSet()
+	flagIDsByUser := make(map[string][]string)
+	for _, r := range reminders {
+		refIDs = append(refIDs, r.ID)
+		userIDs.add(r.UserID)
+		if r.FlagID != nil && *r.FlagID != "" {
+			flagIDsByUser[r.UserID] = append(flagIDsByUser[r.UserID], *r.FlagID)
+		}
+	}
+	acknowledged, err := s.Log.ListAcknowledged(ctx, refIDs)
+	if err != nil {
+		s.Logger.Error("scheduler_list_acknowledged_error", slog.String("error", err.Error()))
+		return
+	}
+	prefsByUser := s.loadPreferences(ctx, userIDs.list())
+	usersByID := s.loadUsers(ctx, userIDs.list())
+	flagNames := s.loadFlagNames(ctx, flagIDsByUser)
+	locCache := make(map[string]*time.Location)
+
+	for _, r := range reminders {
+		if r.RemindAt == nil || r.Notification.Disabled {
+			continue
+		}
+		prefs, ok := prefsByUser[r.UserID]
+		if !ok || !prefs.RemindersEnabled {
+			continue
+		}
+
+		user := usersByID[r.UserID]
+		loc := timezoneLocation(user.Timezone, locCache)
+		// Em quiet hours a repetição entra na fila mesmo assim; o dispatch a adia para o fim do silêncio.
+		step, ok := nextEscalation(r.Escalation, *r.RemindAt, now, acknowledged[r.ID])
+		if !ok {
+			continue
+		}
+
+		vars := withLead(templateVars(r.Title, r.RemindAt, loc, flagName(flagNames, r.FlagID)), step.ElapsedMins)
+		title, body := s.buildMessage(domain.NotificationTypeReminder, "escalation", user.Locale, vars)
+		s.scheduleLog(ctx, domain.NotificationLog{
+			UserID:       r.UserID,
+			Type:         domain.NotificationTypeReminder,
+			ReferenceID:  r.ID,
+			Title:        title,
+			Body:         body,
+			LeadMins:     &step.LeadMins,
+			Status:       domain.NotificationStatusPending,
+			ScheduledFor: step.ScheduledFor,
+			Channel:      step.Channel,
+		})
+	}
+}
+
+// escalationStep é a repetição de um lembrete "nag" que deve existir em notification_log.
+type escalationStep struct {
+	Repeat       int
+	ElapsedMins  int
+	LeadMins     int
+	ScheduledFor time.Time
+	Channel      domain.NotificationChannel
+}
+
+// nextEscalation decide a repetição atual de um lembrete: a k-ésima sai k*intervalo depois do
+// horário, até MaxRepeats, e a partir de EmailFromRepeat vai por e-mail. Não há repetição se
+// alguma notificação do lembrete foi lida (acknowledged).
+func nextEscalation(esc domain.ReminderEscalation, remindAt, now time.Time, acknowledged bool) (escalationStep, bool) {
+	if acknowledged || esc.IntervalMins <= 0 {
+		return escalationStep{}, false
+	}
+	interval := time.Duration(esc.IntervalMins) * time.Minute
+	repeat := int(now.Sub(remindAt) / interval)
+	if repeat < 1 || repeat > esc.MaxRepeats {
+		return escalationStep{}, false
+	}
+
+	elapsedMins := repeat * esc.IntervalMins
+	step := escalationStep{
+		Repeat:       repeat,
+		ElapsedMins:  elapsedMins,
+		LeadMins:     -elapsedMins,
+		ScheduledFor: remindAt.Add(time.Duration(elapsedMins) * time.Minute).UTC(),
+		Channel:      domain.NotificationChannelPush,
+	}
+	if esc.EmailFromRepeat > 0 && repeat >= esc.EmailFromRepeat {
+		step.Channel = domain.NotificationChannelEmail
+	}
+	return step, true
+}
+
+// splitEmailLogs separa as notificações do canal e-mail das de push.
+func splitEmailLogs(logs []domain.NotificationLog) (push, email []domain.NotificationLog) {
+	for _, l := range logs {
+		if l.Channel == domain.NotificationChannelEmail {
+			email = append(email, l)
+			continue
+		}
+		push = append(push, l)
+	}
+	return push, email
+}
+
+func (s *NotificationScheduler) sendEmail(ctx context.Context, l domain.NotificationLog, user domain.User) {
+	var failure string
+	switch {
+	case s.Mailer == nil:
+		failure = "mailer not configured"
+	case strings.TrimSpace(user.Email) == "":
+		failure = "user has no e-mail"
+	}
+
+	if failure == "" {
+		_, err := s.Mailer.Send(ctx, mailer.SendRequest{
+			To:      []string{user.Email},
+			Subject: l.Title,
+			Html:    "<p><strong>" + html.EscapeString(l.Title) + "</strong></p><p>" + html.EscapeString(l.Body) + "</p>",
+			Text:    l.Title + "\n\n" + l.Body,
+		})
+		if err == nil {
+			if err := s.Log.UpdateStatus(ctx, l.ID, domain.NotificationStatusSent, nil); err != nil {
+				s.Logger.Error("update_status_sent_error", slog.String("error", err.Error()))
+			}
+			s.updateStats(func(st *SchedulerStats) { st.SentTotal++ })
+			return
+		}
+		s.Logger.Warn("notification_email_send_error", slog.String("error", err.Error()), slog.String("id", l.ID))
+		failure = "failed to send e-mail"
+	}
+
+	if err := s.Log.UpdateStatus(ctx, l.ID, domain.NotificationStatusFailed, &failure); err != nil {
+		s.Logger.Error("update_status_failed_error", slog.String("error", err.Error()))
+	}
+	s.updateStats(func(st *SchedulerStats) { st.FailedTotal++ })
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+)
+
+func TestNextEscalation(t *testing.T) {
+	remindAt := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	nag := domain.ReminderEscalation{IntervalMins: 10, MaxRepeats: 3, EmailFromRepeat: 3}
+
+	cases := []struct {
+		name         string
+		esc          domain.ReminderEscalation
+		now          time.Time
+		acknowledged bool
+		want         *escalationStep
+	}{
+		{
+			name: "before the first interval",
+			esc:  nag,
+			now:  remindAt.Add(9 * time.Minute),
+		},
+		{
+			name: "first repeat goes by push",
+			esc:  nag,
+			now:  remindAt.Add(10 * time.Minute),
+			want: &escalationStep{Repeat: 1, ElapsedMins: 10, LeadMins: -10, ScheduledFor: remindAt.Add(10 * time.Minute), Channel: domain.NotificationChannelPush},
+		},
+		{
+			name: "late tick keeps the repeat slot",
+			esc:  nag,
+			now:  remindAt.Add(27 * time.Minute),
+			want: &escalationStep{Repeat: 2, ElapsedMins: 20, LeadMins: -20, ScheduledFor: remindAt.Add(20 * time.Minute), Channel: domain.NotificationChannelPush},
+		},
+		{
+			name: "switches to e-mail from the configured repeat",
+			esc:  nag,
+			now:  remindAt.Add(30 * time.Minute),
+			want: &escalationStep{Repeat: 3, ElapsedMins: 30, LeadMins: -30, ScheduledFor: remindAt.Add(30 * time.Minute), Channel: domain.NotificationChannelEmail},
+		},
+		{
+			name: "stops after max repeats",
+			esc:  nag,
+			now:  remindAt.Add(40 * time.Minute),
+		},
+		{
+			name: "e-mail disabled keeps push",
+			esc:  domain.ReminderEscalation{IntervalMins: 10, MaxRepeats: 3},
+			now:  remindAt.Add(30 * time.Minute),
+			want: &escalationStep{Repeat: 3, ElapsedMins: 30, LeadMins: -30, ScheduledFor: remindAt.Add(30 * time.Minute), Channel: domain.NotificationChannelPush},
+		},
+		{
+			name:         "acknowledged stops repeats",
+			esc:          nag,
+			now:          remindAt.Add(20 * time.Minute),
+			acknowledged: true,
+		},
+		{
+			name: "without interval there is no repeat",
+			esc:  domain.ReminderEscalation{MaxRepeats: 3},
+			now:  remindAt.Add(time.Hour),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := nextEscalation(tc.esc, remindAt, tc.now, tc.acknowledged)
+			if tc.want == nil {
+				if ok {
+					t.Fatalf("expected no repeat, got %+v", got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("expected repeat %+v, got none", *tc.want)
+			}
+			if got != *tc.want {
+				t.Fatalf("nextEscalation = %+v, want %+v", got, *tc.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,22 @@ package scheduler
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"log/slog"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/holiday"
+	"inbota/backend/internal/app/recurrence"
 	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/app/service"
+	"inbota/backend/internal/infra/mailer"
 	"inbota/backend/internal/infra/push"
 )
 
@@ -22,46 +30,200 @@ type NotificationScheduler struct {
 	Events    repository.EventRepository
 	Tasks     repository.TaskRepository
 	Routines  repository.RoutineRepository
+	Flags     repository.FlagRepository
+
+	// RoutineExceptions aplica pulos e remarcações das rotinas (nil = ignora exceções).
+	RoutineExceptions repository.RoutineExceptionRepository
+
+	// Away segura pushes de rotinas e tarefas e os briefings durante as férias (nil = ignora).
+	Away repository.AwayPeriodRepository
+
 	Templates repository.NotificationTemplateRepository
 	Config    repository.AppConfigRepository
 	Ntfy      *push.NtfyClient
 	Logger    *slog.Logger
 
-	// carregados em memória no startup
-	templates map[string]domain.NotificationTemplate // "type_triggerKey" -> template
-	config    map[string]string
+	// Mailer envia as repetições de lembretes escalonadas para e-mail (nil = falham).
+	Mailer mailer.Mailer
+
+	// Briefings monta o briefing da manhã e o fechamento da noite (nil = desativado).
+	Briefings BriefingSource
+
+	// Renderer é compartilhado com a API de templates para que alterações valham sem restart.
+	Renderer *service.NotificationRenderer
+
+	// Lock garante que só uma instância agenda e despacha por vez (nil = instância única).
+	Lock repository.LeaderLock
+
+	// carregados em memória no startup e recarregados periodicamente
+	config        map[string]string
+	cacheLoadedAt time.Time
+
+	instanceID string
+
+	briefingsSkipped   map[string]struct{}
+	briefingsSkippedOn string
+	statsMu            sync.Mutex
+	stats              SchedulerStats
+}
+
+// SchedulerStats é exposto via expvar ("notification_scheduler") para monitorar o scheduler.
+type SchedulerStats struct {
+	InstanceID             string     `json:"instance_id"`
+	IsLeader               bool       `json:"is_leader"`
+	TicksTotal             int64      `json:"ticks_total"`
+	SkippedTicksTotal      int64      `json:"skipped_ticks_total"`
+	LastTickAt             *time.Time `json:"last_tick_at,omitempty"`
+	LastTickDurationMs     int64      `json:"last_tick_duration_ms"`
+	LastScheduleDurationMs int64      `json:"last_schedule_duration_ms"`
+	LastDispatchDurationMs int64      `json:"last_dispatch_duration_ms"`
+	ScheduledTotal         int64      `json:"scheduled_total"`
+	SentTotal              int64      `json:"sent_total"`
+	FailedTotal            int64      `json:"failed_total"`
+	PostponedTotal         int64      `json:"postponed_total"`
+	BacklogPending         int        `json:"backlog_pending"`
+	BacklogOldestAgeSecs   int64      `json:"backlog_oldest_age_seconds"`
 }
 
+const schedulerLockKey int64 = 0x696e626f7461 // "inbota"
+
+// SchedulerLockKey é a chave do pg_advisory_lock usada pelo NotificationScheduler.
+func SchedulerLockKey() int64 {
+	return schedulerLockKey
+}
+
+var publishStatsOnce sync.Once
+
 func (s *NotificationScheduler) Run(ctx context.Context) {
+	if s.Renderer == nil {
+		s.Renderer = service.NewNotificationRenderer()
+	}
 	if err := s.loadCache(ctx); err != nil {
 		s.Logger.Warn("scheduler_cache_load_failed", slog.String("error", err.Error()))
 	}
 
+	s.instanceID = schedulerInstanceID()
+	s.statsMu.Lock()
+	s.stats.InstanceID = s.instanceID
+	s.statsMu.Unlock()
+	publishStatsOnce.Do(func() {
+		expvar.Publish("notification_scheduler", expvar.Func(func() any { return s.Stats() }))
+	})
+
 	ticker := time.NewTicker(s.tickerInterval())
 	defer ticker.Stop()
 
-	s.Logger.Info("notification_scheduler_started")
+	s.Logger.Info("notification_scheduler_started", slog.String("instance_id", s.instanceID))
 
 	for {
 		select {
 		case <-ticker.C:
-			s.scheduleUpcoming(ctx)
-			s.dispatch(ctx)
+			s.tick(ctx)
 		case <-ctx.Done():
+			s.releaseLock()
 			return
 		}
 	}
 }
 
+// Stats retorna uma cópia das métricas atuais.
+func (s *NotificationScheduler) Stats() SchedulerStats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	return s.stats
+}
+
+func (s *NotificationScheduler) tick(ctx context.Context) {
+	if s.Lock != nil {
+		leader, err := s.Lock.TryAcquire(ctx)
+		if err != nil {
+			s.Logger.Warn("scheduler_lock_error", slog.String("error", err.Error()))
+		}
+		s.updateStats(func(st *SchedulerStats) {
+			if st.IsLeader != leader {
+				s.Logger.Info("scheduler_leadership_changed", slog.Bool("is_leader", leader), slog.String("instance_id", s.instanceID))
+			}
+			st.IsLeader = leader
+			if !leader {
+				st.SkippedTicksTotal++
+			}
+		})
+		if !leader {
+			return
+		}
+	} else {
+		s.updateStats(func(st *SchedulerStats) { st.IsLeader = true })
+	}
+
+	s.refreshCache(ctx)
+
+	start := time.Now()
+	s.scheduleUpcoming(ctx)
+	scheduled := time.Now()
+	s.dispatch(ctx)
+	finished := time.Now()
+
+	backlog, oldest, err := s.Log.PendingBacklog(ctx, finished)
+	if err != nil {
+		s.Logger.Warn("scheduler_backlog_error", slog.String("error", err.Error()))
+	}
+	var oldestAge time.Duration
+	if oldest != nil {
+		oldestAge = finished.Sub(*oldest)
+	}
+
+	s.updateStats(func(st *SchedulerStats) {
+		st.TicksTotal++
+		st.LastTickAt = &start
+		st.LastTickDurationMs = finished.Sub(start).Milliseconds()
+		st.LastScheduleDurationMs = scheduled.Sub(start).Milliseconds()
+		st.LastDispatchDurationMs = finished.Sub(scheduled).Milliseconds()
+		if err == nil {
+			st.BacklogPending = backlog
+			st.BacklogOldestAgeSecs = int64(oldestAge.Seconds())
+		}
+	})
+
+	s.Logger.Debug("scheduler_tick",
+		slog.Int64("duration_ms", finished.Sub(start).Milliseconds()),
+		slog.Int("backlog_pending", backlog),
+		slog.Int64("backlog_oldest_age_seconds", int64(oldestAge.Seconds())),
+	)
+}
+
+func (s *NotificationScheduler) updateStats(fn func(*SchedulerStats)) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	fn(&s.stats)
+}
+
+func (s *NotificationScheduler) releaseLock() {
+	if s.Lock == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Lock.Release(ctx); err != nil {
+		s.Logger.Warn("scheduler_lock_release_error", slog.String("error", err.Error()))
+	}
+}
+
+func schedulerInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return host + "-" + strconv.Itoa(os.Getpid())
+}
+
 // loadCache carrega templates e configs do banco para memória.
 func (s *NotificationScheduler) loadCache(ctx context.Context) error {
 	tmpls, err := s.Templates.GetAll(ctx)
 	if err != nil {
 		return fmt.Errorf("load templates: %w", err)
 	}
-	s.templates = make(map[string]domain.NotificationTemplate, len(tmpls))
-	for _, t := range tmpls {
-		s.templates[string(t.Type)+"_"+t.TriggerKey] = t
+	for _, err := range s.Renderer.Load(tmpls) {
+		s.Logger.Warn("scheduler_template_invalid", slog.String("error", err.Error()))
 	}
 
 	cfg, err := s.Config.GetAll(ctx)
@@ -69,9 +231,22 @@ func (s *NotificationScheduler) loadCache(ctx context.Context) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 	s.config = cfg
+	s.cacheLoadedAt = time.Now()
 	return nil
 }
 
+// refreshCache recarrega templates e configs quando o cache expira
+// (cobre alterações feitas por outras instâncias).
+func (s *NotificationScheduler) refreshCache(ctx context.Context) {
+	ttl := time.Duration(s.configInt("scheduler.cache_refresh_seconds", 300)) * time.Second
+	if time.Since(s.cacheLoadedAt) < ttl {
+		return
+	}
+	if err := s.loadCache(ctx); err != nil {
+		s.Logger.Warn("scheduler_cache_refresh_failed", slog.String("error", err.Error()))
+	}
+}
+
 func (s *NotificationScheduler) tickerInterval() time.Duration {
 	secs := s.configInt("scheduler.ticker_interval_seconds", 60)
 	return time.Duration(secs) * time.Second
@@ -103,34 +278,24 @@ func (s *NotificationScheduler) configStr(key, defaultVal string) string {
 	return v
 }
 
-// renderTemplate substitui placeholders {{key}} com os valores fornecidos.
-func (s *NotificationScheduler) renderTemplate(tpl string, vars map[string]string) string {
-	for k, v := range vars {
-		tpl = strings.ReplaceAll(tpl, "{{"+k+"}}", v)
-	}
-	return tpl
-}
-
-// buildMessage retorna (title, body) para o tipo e gatilho especificados.
+// buildMessage retorna (title, body) para o tipo, gatilho e locale do usuário.
 // Fallback para strings fixas caso o template não exista no cache.
-func (s *NotificationScheduler) buildMessage(nType domain.NotificationType, triggerKey, itemTitle string, vars map[string]string) (title, body string) {
-	if vars == nil {
-		vars = map[string]string{}
-	}
-
-	cacheKey := string(nType) + "_" + triggerKey
-	vars["title"] = itemTitle
+func (s *NotificationScheduler) buildMessage(nType domain.NotificationType, triggerKey, locale string, vars service.NotificationTemplateVars) (title, body string) {
+	return s.Renderer.Render(nType, triggerKey, locale, vars)
+}
 
-	if tpl, ok := s.templates[cacheKey]; ok {
-		title = s.renderTemplate(tpl.TitleTemplate, vars)
-		body = s.renderTemplate(tpl.BodyTemplate, vars)
-		if isLeadTemplateInvalid(triggerKey, title, body, vars) {
-			return defaultNotificationMessage(nType, triggerKey, itemTitle, vars)
-		}
-		return
+// templateVars monta as variáveis de template de um item, formatando o horário no timezone do usuário.
+func templateVars(title string, at *time.Time, loc *time.Location, flagName string) service.NotificationTemplateVars {
+	vars := service.NotificationTemplateVars{Title: title, FlagName: flagName}
+	if at != nil {
+		vars.Time = at.In(loc).Format("15:04")
 	}
+	return vars
+}
 
-	return defaultNotificationMessage(nType, triggerKey, itemTitle, vars)
+func withLead(vars service.NotificationTemplateVars, mins int) service.NotificationTemplateVars {
+	vars.LeadMins = mins
+	return vars
 }
 
 func (s *NotificationScheduler) scheduleUpcoming(ctx context.Context) {
@@ -142,99 +307,19 @@ func (s *NotificationScheduler) scheduleUpcoming(ctx context.Context) {
 	eventLookahead := time.Duration(s.configInt("scheduler.event_lookahead_hours", 24)) * time.Hour
 	taskLookahead := time.Duration(s.configInt("scheduler.task_lookahead_hours", 24)) * time.Hour
 
-	// 1. Reminders
 	reminders, err := s.Reminders.ListUpcoming(ctx, now, now.Add(reminderLookahead))
 	if err != nil {
 		s.Logger.Error("scheduler_list_reminders_error", slog.String("error", err.Error()))
-	} else {
-		for _, r := range reminders {
-			prefs, err := s.Prefs.GetByUserID(ctx, r.UserID)
-			if err != nil || !prefs.RemindersEnabled {
-				continue
-			}
-
-			if prefs.ReminderAtTime {
-				title, body := s.buildMessage(domain.NotificationTypeReminder, "at_time", r.Title, map[string]string{})
-				s.scheduleItem(ctx, r.UserID, domain.NotificationTypeReminder, r.ID, title, body, r.RemindAt, nil)
-			}
-
-			for _, mins := range prefs.ReminderLeadMins {
-				scheduledFor := r.RemindAt.Add(time.Duration(-mins) * time.Minute)
-				if !scheduledFor.Before(nowMinute) {
-					title, body := s.buildMessage(domain.NotificationTypeReminder, "lead_time", r.Title, map[string]string{
-						"lead_mins": strconv.Itoa(mins),
-					})
-					s.scheduleItem(ctx, r.UserID, domain.NotificationTypeReminder, r.ID, title, body, &scheduledFor, &mins)
-				}
-			}
-		}
 	}
-
-	// 2. Events
 	events, err := s.Events.ListUpcoming(ctx, now, now.Add(eventLookahead))
 	if err != nil {
 		s.Logger.Error("scheduler_list_events_error", slog.String("error", err.Error()))
-	} else {
-		for _, e := range events {
-			prefs, err := s.Prefs.GetByUserID(ctx, e.UserID)
-			if err != nil || !prefs.EventsEnabled {
-				continue
-			}
-
-			if prefs.EventAtTime {
-				title, body := s.buildMessage(domain.NotificationTypeEvent, "at_time", e.Title, map[string]string{})
-				s.scheduleItem(ctx, e.UserID, domain.NotificationTypeEvent, e.ID, title, body, e.StartAt, nil)
-			}
-
-			for _, mins := range prefs.EventLeadMins {
-				scheduledFor := e.StartAt.Add(time.Duration(-mins) * time.Minute)
-				if !scheduledFor.Before(nowMinute) {
-					triggerKey := "lead_time"
-					if mins >= dayThreshold {
-						triggerKey = "lead_time_day"
-					}
-					title, body := s.buildMessage(domain.NotificationTypeEvent, triggerKey, e.Title, map[string]string{
-						"lead_mins": strconv.Itoa(mins),
-					})
-					s.scheduleItem(ctx, e.UserID, domain.NotificationTypeEvent, e.ID, title, body, &scheduledFor, &mins)
-				}
-			}
-		}
 	}
-
-	// 3. Tasks
 	tasks, err := s.Tasks.ListUpcoming(ctx, now, now.Add(taskLookahead))
 	if err != nil {
 		s.Logger.Error("scheduler_list_tasks_error", slog.String("error", err.Error()))
-	} else {
-		for _, t := range tasks {
-			prefs, err := s.Prefs.GetByUserID(ctx, t.UserID)
-			if err != nil || !prefs.TasksEnabled {
-				continue
-			}
-
-			if prefs.TaskAtTime {
-				title, body := s.buildMessage(domain.NotificationTypeTask, "at_time", t.Title, map[string]string{})
-				s.scheduleItem(ctx, t.UserID, domain.NotificationTypeTask, t.ID, title, body, t.DueAt, nil)
-			}
-
-			for _, mins := range prefs.TaskLeadMins {
-				scheduledFor := t.DueAt.Add(time.Duration(-mins) * time.Minute)
-				if !scheduledFor.Before(nowMinute) {
-					triggerKey := "lead_time"
-					if mins >= dayThreshold {
-						triggerKey = "lead_time_day"
-					}
-					title, body := s.buildMessage(domain.NotificationTypeTask, triggerKey, t.Title, map[string]string{
-						"lead_mins": strconv.Itoa(mins),
-					})
-					s.scheduleItem(ctx, t.UserID, domain.NotificationTypeTask, t.ID, title, body, &scheduledFor, &mins)
-				}
-			}
-		}
 	}
 
-	// 4. Routines (considerando timezone do usuário e recorrência)
 	candidateWeekdays := schedulerCandidateWeekdays(now)
 	routinesByID := make(map[string]domain.Routine)
 	for _, weekday := range candidateWeekdays {
@@ -248,73 +333,370 @@ func (s *NotificationScheduler) scheduleUpcoming(ctx context.Context) {
 		}
 	}
 
-	userCache := make(map[string]domain.User)
-	locCache := make(map[string]*time.Location)
+	// Carrega preferências, usuários (timezone/locale) e flags uma vez por tick.
+	userIDs := newUserIDSet()
+	flagIDsByUser := make(map[string][]string)
+	collect := func(userID string, flagID *string) {
+		userIDs.add(userID)
+		if flagID != nil && *flagID != "" {
+			flagIDsByUser[userID] = append(flagIDsByUser[userID], *flagID)
+		}
+	}
+	for _, r := range reminders {
+		collect(r.UserID, r.FlagID)
+	}
+	for _, e := range events {
+		collect(e.UserID, e.FlagID)
+	}
+	for _, t := range tasks {
+		collect(t.UserID, t.FlagID)
+	}
 	for _, r := range routinesByID {
-		prefs, err := s.Prefs.GetByUserID(ctx, r.UserID)
-		if err != nil || !prefs.RoutinesEnabled {
+		collect(r.UserID, r.FlagID)
+	}
+	prefsByUser := s.loadPreferences(ctx, userIDs.list())
+	usersByID := s.loadUsers(ctx, userIDs.list())
+	flagNames := s.loadFlagNames(ctx, flagIDsByUser)
+	routineExceptions := s.loadRoutineExceptions(ctx, routinesByID, now)
+	// Leads próprios dos itens (até uma semana) trazem tarefas com prazo além do lookahead.
+	awayUntil := now.Add(taskLookahead+time.Duration(domain.MaxNotificationLeadMins)*time.Minute).AddDate(0, 0, 1)
+	awayByUser := s.loadAwayPeriods(ctx, userIDs.list(), now.AddDate(0, 0, -1), awayUntil)
+	locCache := make(map[string]*time.Location)
+
+	// 1. Reminders
+	for _, r := range reminders {
+		prefs, ok := prefsByUser[r.UserID]
+		if !ok || !prefs.RemindersEnabled {
+			continue
+		}
+		atTime, leadMins, enabled := resolveLeadTimes(prefs.ReminderAtTime, prefs.ReminderLeadMins, r.Notification)
+		if !enabled {
 			continue
 		}
 
-		user, ok := userCache[r.UserID]
-		if !ok {
-			loadedUser, err := s.Users.Get(ctx, r.UserID)
-			if err != nil {
-				continue
+		user := usersByID[r.UserID]
+		vars := templateVars(r.Title, r.RemindAt, timezoneLocation(user.Timezone, locCache), flagName(flagNames, r.FlagID))
+
+		if atTime {
+			title, body := s.buildMessage(domain.NotificationTypeReminder, "at_time", user.Locale, vars)
+			s.scheduleItem(ctx, r.UserID, domain.NotificationTypeReminder, r.ID, title, body, r.RemindAt, nil)
+		}
+
+		for _, mins := range leadMins {
+			scheduledFor := r.RemindAt.Add(time.Duration(-mins) * time.Minute)
+			if !scheduledFor.Before(nowMinute) {
+				title, body := s.buildMessage(domain.NotificationTypeReminder, "lead_time", user.Locale, withLead(vars, mins))
+				s.scheduleItem(ctx, r.UserID, domain.NotificationTypeReminder, r.ID, title, body, &scheduledFor, &mins)
 			}
-			user = loadedUser
-			userCache[r.UserID] = user
 		}
+	}
 
-		loc := timezoneLocation(user.Timezone, locCache)
-		userNow := now.In(loc)
+	// 2. Events
+	for _, e := range events {
+		prefs, ok := prefsByUser[e.UserID]
+		if !ok || !prefs.EventsEnabled {
+			continue
+		}
+		atTime, leadMins, enabled := resolveLeadTimes(prefs.EventAtTime, prefs.EventLeadMins, e.Notification)
+		if !enabled {
+			continue
+		}
+
+		user := usersByID[e.UserID]
+		vars := templateVars(e.Title, e.StartAt, timezoneLocation(user.Timezone, locCache), flagName(flagNames, e.FlagID))
+		if e.Location != nil {
+			vars.Location = *e.Location
+		}
+
+		if atTime {
+			title, body := s.buildMessage(domain.NotificationTypeEvent, "at_time", user.Locale, vars)
+			s.scheduleItem(ctx, e.UserID, domain.NotificationTypeEvent, e.ID, title, body, e.StartAt, nil)
+		}
+
+		for _, mins := range leadMins {
+			scheduledFor := e.StartAt.Add(time.Duration(-mins) * time.Minute)
+			if !scheduledFor.Before(nowMinute) {
+				triggerKey := "lead_time"
+				if mins >= dayThreshold {
+					triggerKey = "lead_time_day"
+				}
+				title, body := s.buildMessage(domain.NotificationTypeEvent, triggerKey, user.Locale, withLead(vars, mins))
+				s.scheduleItem(ctx, e.UserID, domain.NotificationTypeEvent, e.ID, title, body, &scheduledFor, &mins)
+			}
+		}
+	}
 
-		if !routineHasWeekday(r.Weekdays, int(userNow.Weekday())) {
+	// 3. Tasks (bloqueadas só notificam depois que as bloqueadoras forem concluídas)
+	for _, t := range tasks {
+		if t.Blocked {
 			continue
 		}
-		if !shouldShowRoutineForDate(r, userNow) {
+		prefs, ok := prefsByUser[t.UserID]
+		if !ok || !prefs.TasksEnabled {
+			continue
+		}
+		atTime, leadMins, enabled := resolveLeadTimes(prefs.TaskAtTime, prefs.TaskLeadMins, t.Notification)
+		if !enabled {
 			continue
 		}
 
-		startTime, err := time.Parse("15:04", r.StartTime)
-		if err != nil {
+		user := usersByID[t.UserID]
+		loc := timezoneLocation(user.Timezone, locCache)
+		if recurrence.IsAway(awayByUser[t.UserID], t.FlagID, t.DueAt.In(loc).Format("2006-01-02")) {
 			continue
 		}
+		vars := templateVars(t.Title, t.DueAt, loc, flagName(flagNames, t.FlagID))
 
-		scheduledForLocal := time.Date(userNow.Year(), userNow.Month(), userNow.Day(), startTime.Hour(), startTime.Minute(), 0, 0, loc)
-		scheduledForUTC := scheduledForLocal.UTC()
+		if atTime {
+			title, body := s.buildMessage(domain.NotificationTypeTask, "at_time", user.Locale, vars)
+			s.scheduleItem(ctx, t.UserID, domain.NotificationTypeTask, t.ID, title, body, t.DueAt, nil)
+		}
+
+		for _, mins := range leadMins {
+			scheduledFor := t.DueAt.Add(time.Duration(-mins) * time.Minute)
+			if !scheduledFor.Before(nowMinute) {
+				triggerKey := "lead_time"
+				if mins >= dayThreshold {
+					triggerKey = "lead_time_day"
+				}
+				title, body := s.buildMessage(domain.NotificationTypeTask, triggerKey, user.Locale, withLead(vars, mins))
+				s.scheduleItem(ctx, t.UserID, domain.NotificationTypeTask, t.ID, title, body, &scheduledFor, &mins)
+			}
+		}
+	}
+
+	// 4. Routines (considerando timezone do usuário e recorrência)
+	for _, r := range routinesByID {
+		prefs, ok := prefsByUser[r.UserID]
+		if !ok || !prefs.RoutinesEnabled {
+			continue
+		}
+		atTime, leadMins, enabled := resolveLeadTimes(prefs.RoutineAtTime, prefs.RoutineLeadMins, r.Notification)
+		if !enabled {
+			continue
+		}
 
-		if prefs.RoutineAtTime && !scheduledForUTC.Before(nowUTCMinute) {
-			title, body := s.buildMessage(domain.NotificationTypeRoutine, "at_time", r.Title, map[string]string{})
-			s.scheduleItem(ctx, r.UserID, domain.NotificationTypeRoutine, r.ID, title, body, &scheduledForUTC, nil)
+		user, ok := usersByID[r.UserID]
+		if !ok {
+			continue
 		}
 
-		for _, mins := range prefs.RoutineLeadMins {
-			if mins <= 0 {
+		loc := timezoneLocation(user.Timezone, locCache)
+		userNow := now.In(loc)
+
+		// A ocorrência de amanhã só gera avisos antecipados que já caem hoje (leads de até
+		// domain.MaxRoutineLeadMins); o aviso na hora sai no próprio dia.
+		for dayOffset := 0; dayOffset <= 1; dayOffset++ {
+			day := userNow.AddDate(0, 0, dayOffset)
+
+			// Pulos, feriados e férias somem do dia; remarcações trocam o horário da ocorrência
+			// (inclusive num feriado).
+			exceptions := routineExceptions[r.ID]
+			if holidays := recurrence.HolidayExceptions(r, holiday.For(user.HolidayCalendar), day, day); len(holidays) > 0 {
+				exceptions = append(holidays, exceptions...)
+			}
+			if away := awayByUser[r.UserID]; len(away) > 0 {
+				exceptions = append(append([]domain.RoutineException(nil), exceptions...), recurrence.AwayExceptions(r, away, day, day)...)
+			}
+			occurrence, ok := recurrence.ForRoutine(r, exceptions...).On(day)
+			if !ok {
 				continue
 			}
-			leadScheduledForUTC := scheduledForUTC.Add(time.Duration(-mins) * time.Minute)
-			if !leadScheduledForUTC.Before(nowUTCMinute) {
-				title, body := s.buildMessage(domain.NotificationTypeRoutine, "lead_time", r.Title, map[string]string{
-					"lead_mins": strconv.Itoa(mins),
-				})
-				s.scheduleItem(ctx, r.UserID, domain.NotificationTypeRoutine, r.ID, title, body, &leadScheduledForUTC, &mins)
+
+			startTime, err := time.Parse("15:04", occurrence.StartTime)
+			if err != nil {
+				continue
+			}
+
+			scheduledForLocal := time.Date(day.Year(), day.Month(), day.Day(), startTime.Hour(), startTime.Minute(), 0, 0, loc)
+			scheduledForUTC := scheduledForLocal.UTC()
+			vars := templateVars(r.Title, &scheduledForLocal, loc, flagName(flagNames, r.FlagID))
+
+			if dayOffset == 0 && atTime && !scheduledForUTC.Before(nowUTCMinute) {
+				title, body := s.buildMessage(domain.NotificationTypeRoutine, "at_time", user.Locale, vars)
+				s.scheduleItem(ctx, r.UserID, domain.NotificationTypeRoutine, r.ID, title, body, &scheduledForUTC, nil)
+			}
+
+			for _, mins := range leadMins {
+				if mins <= 0 {
+					continue
+				}
+				leadScheduledForUTC := scheduledForUTC.Add(time.Duration(-mins) * time.Minute)
+				if !leadScheduledForUTC.Before(nowUTCMinute) && leadScheduledForUTC.Before(nowUTCMinute.Add(24*time.Hour)) {
+					title, body := s.buildMessage(domain.NotificationTypeRoutine, "lead_time", user.Locale, withLead(vars, mins))
+					s.scheduleItem(ctx, r.UserID, domain.NotificationTypeRoutine, r.ID, title, body, &leadScheduledForUTC, &mins)
+				}
 			}
 		}
 	}
+
+	// 5. Briefing da manhã e fechamento da noite
+	s.scheduleBriefings(ctx, now)
+
+	// 6. Repetições de lembretes em modo "nag"
+	s.scheduleEscalations(ctx, now)
+
+	// 7. Tarefas desbloqueadas
+	s.scheduleUnblocked(ctx, now)
 }
 
-func (s *NotificationScheduler) scheduleItem(ctx context.Context, userID string, nType domain.NotificationType, refID string, title, body string, scheduledFor *time.Time, leadMins *int) {
-	if scheduledFor == nil {
+// userIDSet deduplica user ids preservando a ordem de inserção.
+type userIDSet struct {
+	seen map[string]struct{}
+	ids  []string
+}
+
+func newUserIDSet() *userIDSet {
+	return &userIDSet{seen: make(map[string]struct{})}
+}
+
+func (u *userIDSet) add(id string) {
+	if id == "" {
 		return
 	}
+	if _, ok := u.seen[id]; ok {
+		return
+	}
+	u.seen[id] = struct{}{}
+	u.ids = append(u.ids, id)
+}
+
+func (u *userIDSet) list() []string {
+	return u.ids
+}
+
+func (s *NotificationScheduler) loadPreferences(ctx context.Context, userIDs []string) map[string]domain.NotificationPreferences {
+	result := make(map[string]domain.NotificationPreferences, len(userIDs))
+	prefs, err := s.Prefs.ListByUserIDs(ctx, userIDs)
+	if err != nil {
+		s.Logger.Error("scheduler_load_prefs_error", slog.String("error", err.Error()))
+		return result
+	}
+	for _, p := range prefs {
+		result[p.UserID] = p
+	}
+	return result
+}
 
-	exists, err := s.Log.Exists(ctx, refID, leadMins)
-	if err != nil || exists {
+func (s *NotificationScheduler) loadUsers(ctx context.Context, userIDs []string) map[string]domain.User {
+	result := make(map[string]domain.User, len(userIDs))
+	users, err := s.Users.ListByIDs(ctx, userIDs)
+	if err != nil {
+		s.Logger.Error("scheduler_load_users_error", slog.String("error", err.Error()))
+		return result
+	}
+	for _, u := range users {
+		result[u.ID] = u
+	}
+	return result
+}
+
+// loadFlagNames resolve o nome das flags (id -> nome) para a variável FlagName dos templates,
+// numa única consulta para todos os usuários do tick.
+func (s *NotificationScheduler) loadFlagNames(ctx context.Context, flagIDsByUser map[string][]string) map[string]string {
+	result := make(map[string]string)
+	if s.Flags == nil {
+		return result
+	}
+	flags, err := s.Flags.GetByIDsForUsers(ctx, flagIDsByUser)
+	if err != nil {
+		s.Logger.Warn("scheduler_load_flags_error", slog.String("error", err.Error()))
+		return result
+	}
+	for _, f := range flags {
+		result[f.ID] = f.Name
+	}
+	return result
+}
+
+// loadRoutineExceptions busca as exceções das rotinas candidatas de ontem a depois de amanhã (cobre o
+// "hoje" e o "amanhã" de qualquer timezone).
+func (s *NotificationScheduler) loadRoutineExceptions(ctx context.Context, routinesByID map[string]domain.Routine, now time.Time) map[string][]domain.RoutineException {
+	result := make(map[string][]domain.RoutineException)
+	if s.RoutineExceptions == nil || len(routinesByID) == 0 {
+		return result
+	}
+	ids := make([]string, 0, len(routinesByID))
+	for id := range routinesByID {
+		ids = append(ids, id)
+	}
+	from := now.UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	to := now.UTC().AddDate(0, 0, 2).Format("2006-01-02")
+	exceptions, err := s.RoutineExceptions.ListForRoutines(ctx, ids, from, to)
+	if err != nil {
+		s.Logger.Warn("scheduler_load_routine_exceptions_error", slog.String("error", err.Error()))
+		return result
+	}
+	for _, e := range exceptions {
+		result[e.RoutineID] = append(result[e.RoutineID], e)
+	}
+	return result
+}
+
+// loadAwayPeriods busca os períodos de férias dos usuários entre from e to, por usuário.
+func (s *NotificationScheduler) loadAwayPeriods(ctx context.Context, userIDs []string, from, to time.Time) map[string][]domain.AwayPeriod {
+	result := make(map[string][]domain.AwayPeriod)
+	if s.Away == nil || len(userIDs) == 0 {
+		return result
+	}
+	periods, err := s.Away.ListOverlapping(ctx, userIDs, from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"))
+	if err != nil {
+		s.Logger.Warn("scheduler_load_away_periods_error", slog.String("error", err.Error()))
+		return result
+	}
+	for _, p := range periods {
+		result[p.UserID] = append(result[p.UserID], p)
+	}
+	return result
+}
+
+func flagName(names map[string]string, flagID *string) string {
+	if flagID == nil {
+		return ""
+	}
+	return names[*flagID]
+}
+
+// loadTokens devolve nil quando a consulta falha, para distinguir "sem dispositivos" de erro.
+func (s *NotificationScheduler) loadTokens(ctx context.Context, userIDs []string) map[string][]domain.DeviceToken {
+	tokens, err := s.Tokens.ListByUserIDs(ctx, userIDs)
+	if err != nil {
+		s.Logger.Error("scheduler_load_tokens_error", slog.String("error", err.Error()))
+		return nil
+	}
+	result := make(map[string][]domain.DeviceToken, len(userIDs))
+	for _, t := range tokens {
+		result[t.UserID] = append(result[t.UserID], t)
+	}
+	return result
+}
+
+// resolveLeadTimes aplica o override do item sobre as preferências do usuário.
+// Retorna enabled=false quando o item pediu para não ser notificado.
+func resolveLeadTimes(defaultAtTime bool, defaultLeadMins []int, override domain.NotificationOverride) (atTime bool, leadMins []int, enabled bool) {
+	if override.Disabled {
+		return false, nil, false
+	}
+	if override.LeadMins == nil {
+		return defaultAtTime, defaultLeadMins, true
+	}
+	leadMins = make([]int, 0, len(override.LeadMins))
+	for _, mins := range override.LeadMins {
+		if mins == 0 {
+			atTime = true
+			continue
+		}
+		leadMins = append(leadMins, mins)
+	}
+	return atTime, leadMins, true
+}
+
+func (s *NotificationScheduler) scheduleItem(ctx context.Context, userID string, nType domain.NotificationType, refID string, title, body string, scheduledFor *time.Time, leadMins *int) {
+	if scheduledFor == nil {
 		return
 	}
 
-	_, err = s.Log.Create(ctx, domain.NotificationLog{
+	s.scheduleLog(ctx, domain.NotificationLog{
 		UserID:       userID,
 		Type:         nType,
 		ReferenceID:  refID,
@@ -324,95 +706,19 @@ func (s *NotificationScheduler) scheduleItem(ctx context.Context, userID string,
 		Status:       domain.NotificationStatusPending,
 		ScheduledFor: *scheduledFor,
 	})
-	if err != nil {
-		s.Logger.Error("schedule_item_error", slog.String("error", err.Error()), slog.String("ref_id", refID))
-	}
 }
 
-func isLeadTemplateInvalid(triggerKey, title, body string, vars map[string]string) bool {
-	combined := strings.ToLower(strings.TrimSpace(title + " " + body))
-	switch triggerKey {
-	case "lead_time":
-		lead := strings.TrimSpace(vars["lead_mins"])
-		if lead == "" {
-			return false
-		}
-		if strings.Contains(combined, "agora") {
-			return true
-		}
-		return !strings.Contains(combined, lead)
-	case "lead_time_day":
-		return !strings.Contains(combined, "amanh")
-	default:
-		return false
-	}
-}
-
-func defaultNotificationMessage(nType domain.NotificationType, triggerKey, itemTitle string, vars map[string]string) (string, string) {
-	lead := strings.TrimSpace(vars["lead_mins"])
-	leadLabel := humanLeadLabel(lead)
-
-	switch nType {
-	case domain.NotificationTypeReminder:
-		switch triggerKey {
-		case "at_time":
-			return "Lembrete agora", itemTitle
-		case "lead_time":
-			return "Lembrete em " + leadLabel, itemTitle
-		case "lead_time_day":
-			return "Lembrete amanhã", itemTitle
-		}
-	case domain.NotificationTypeEvent:
-		switch triggerKey {
-		case "at_time":
-			return "Evento começando", itemTitle + " começa agora."
-		case "lead_time":
-			return "Evento em " + leadLabel, itemTitle + " começa em " + leadLabel + "."
-		case "lead_time_day":
-			return "Evento amanhã", itemTitle + " começa amanhã."
-		}
-	case domain.NotificationTypeTask:
-		switch triggerKey {
-		case "at_time":
-			return "Prazo agora", itemTitle + " vence agora."
-		case "lead_time":
-			return "Prazo em " + leadLabel, itemTitle + " vence em " + leadLabel + "."
-		case "lead_time_day":
-			return "Prazo amanhã", itemTitle + " vence amanhã."
-		}
-	case domain.NotificationTypeRoutine:
-		switch triggerKey {
-		case "at_time":
-			return "Hora da rotina", itemTitle + " começa agora."
-		case "lead_time":
-			return "Rotina em " + leadLabel, itemTitle + " começa em " + leadLabel + "."
-		case "lead_time_day":
-			return "Rotina amanhã", itemTitle + " começa amanhã."
-		}
-	}
-
-	// fallback final
-	switch triggerKey {
-	case "at_time":
-		return itemTitle, "Agora"
-	case "lead_time":
-		return itemTitle, "Em " + leadLabel
-	case "lead_time_day":
-		return itemTitle, "Amanhã"
-	default:
-		return itemTitle, ""
+func (s *NotificationScheduler) scheduleLog(ctx context.Context, log domain.NotificationLog) {
+	exists, err := s.Log.Exists(ctx, log.ReferenceID, log.LeadMins)
+	if err != nil || exists {
+		return
 	}
-}
 
-func humanLeadLabel(raw string) string {
-	mins, err := strconv.Atoi(raw)
-	if err != nil || mins <= 0 {
-		return "breve"
-	}
-	if mins == 1 {
-		return "1 minuto"
+	if _, err := s.Log.Create(ctx, log); err != nil {
+		s.Logger.Error("schedule_item_error", slog.String("error", err.Error()), slog.String("ref_id", log.ReferenceID))
+		return
 	}
-	return strconv.Itoa(mins) + " minutos"
+	s.updateStats(func(st *SchedulerStats) { st.ScheduledTotal++ })
 }
 
 func schedulerCandidateWeekdays(now time.Time) []int {
@@ -420,6 +726,7 @@ func schedulerCandidateWeekdays(now time.Time) []int {
 		int(now.Add(-24 * time.Hour).Weekday()),
 		int(now.Weekday()),
 		int(now.Add(24 * time.Hour).Weekday()),
+		int(now.Add(48 * time.Hour).Weekday()),
 	}
 	seen := make(map[int]struct{})
 	result := make([]int, 0, len(values))
@@ -450,106 +757,125 @@ func timezoneLocation(timezone string, cache map[string]*time.Location) *time.Lo
 	return loc
 }
 
-func routineHasWeekday(weekdays []int, weekday int) bool {
-	for _, w := range weekdays {
-		if w == weekday {
-			return true
-		}
-	}
-	return false
+// dispatchBatch agrupa o que foi carregado em lote para os logs reivindicados no tick.
+type dispatchBatch struct {
+	tokens   map[string][]domain.DeviceToken
+	prefs    map[string]domain.NotificationPreferences
+	users    map[string]domain.User
+	locCache map[string]*time.Location
+	lease    time.Duration
+	now      time.Time
 }
 
-func shouldShowRoutineForDate(r domain.Routine, targetDate time.Time) bool {
-	// Always respect StartsOn: never show routines on dates prior to their start.
-	startsOnStr := r.StartsOn
-	if len(startsOnStr) > 10 {
-		startsOnStr = startsOnStr[:10]
+func (s *NotificationScheduler) dispatch(ctx context.Context) {
+	now := time.Now()
+	batchSize := s.configInt("scheduler.dispatch_batch_size", 200)
+	lease := time.Duration(s.configInt("scheduler.claim_lease_seconds", 300)) * time.Second
+
+	pending, err := s.Log.ClaimPending(ctx, now, batchSize, lease, s.instanceID)
+	if err != nil {
+		s.Logger.Error("scheduler_claim_pending_error", slog.String("error", err.Error()))
+		return
 	}
-	startsOn, err := time.Parse("2006-01-02", startsOnStr)
-	if err == nil {
-		startsOnDate := time.Date(startsOn.Year(), startsOn.Month(), startsOn.Day(), 0, 0, 0, 0, time.UTC)
-		targetDateOnly := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, time.UTC)
-		if targetDateOnly.Before(startsOnDate) {
-			return false
-		}
+	if len(pending) == 0 {
+		return
 	}
 
-	if r.RecurrenceType == "weekly" || r.RecurrenceType == "" {
-		return true
+	userIDs := newUserIDSet()
+	logsByUser := make(map[string][]domain.NotificationLog)
+	for _, l := range pending {
+		userIDs.add(l.UserID)
+		logsByUser[l.UserID] = append(logsByUser[l.UserID], l)
+	}
+	batch := dispatchBatch{
+		tokens:   s.loadTokens(ctx, userIDs.list()),
+		prefs:    s.loadPreferences(ctx, userIDs.list()),
+		users:    s.loadUsers(ctx, userIDs.list()),
+		locCache: make(map[string]*time.Location),
+		lease:    lease,
+		now:      now,
 	}
 
-	// For multi-week recurrences we anchor the cadence to the start week.
-	startsOnMonday := mondayOf(startsOn)
-	targetMonday := mondayOf(targetDate)
-
-	if targetMonday.Before(startsOnMonday) {
-		return false
+	for _, userID := range userIDs.list() {
+		s.dispatchUser(ctx, userID, logsByUser[userID], batch)
 	}
+}
 
-	weeksDiff := int(targetMonday.Sub(startsOnMonday).Hours()) / (24 * 7)
+func (s *NotificationScheduler) dispatchUser(ctx context.Context, userID string, logs []domain.NotificationLog, batch dispatchBatch) {
+	prefs, hasPrefs := batch.prefs[userID]
+	user, hasUser := batch.users[userID]
 
-	switch r.RecurrenceType {
-	case "biweekly":
-		return weeksDiff%2 == 0
-	case "triweekly":
-		return weeksDiff%3 == 0
-	case "monthly_week":
-		if r.WeekOfMonth == nil {
-			return true
+	// 1. Verifica quiet hours (vale para push e e-mail)
+	if hasPrefs && hasUser && prefs.QuietHoursEnabled && prefs.QuietStart != nil && prefs.QuietEnd != nil {
+		loc := timezoneLocation(user.Timezone, batch.locCache)
+		if s.isInsideQuietHours(time.Now().In(loc), *prefs.QuietStart, *prefs.QuietEnd) {
+			for _, l := range logs {
+				s.postpone(ctx, l, *prefs.QuietEnd, loc)
+			}
+			return
 		}
-		targetWeek := (targetDate.Day()-1)/7 + 1
-		return targetWeek == *r.WeekOfMonth
-	default:
-		return true
 	}
-}
 
-func mondayOf(t time.Time) time.Time {
-	weekday := int(t.Weekday())
-	if weekday == 0 {
-		weekday = 7
+	// 2. E-mail (lembretes escalonados) não depende de dispositivo nem entra no bundling
+	logs, emails := splitEmailLogs(logs)
+	for _, l := range emails {
+		s.sendEmail(ctx, l, user)
+	}
+	if len(logs) == 0 {
+		return
 	}
-	monday := t.AddDate(0, 0, -(weekday - 1))
-	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, time.UTC)
-}
 
-func (s *NotificationScheduler) dispatch(ctx context.Context) {
-	now := time.Now()
-	pending, err := s.Log.ListPending(ctx, now)
-	if err != nil {
-		s.Logger.Error("scheduler_list_pending_error", slog.String("error", err.Error()))
+	// 3. Tópicos do usuário. Sem dispositivo o push nunca será entregue: a linha é marcada como
+	// falha para não voltar a cada lease e ocupar o lote na frente das que podem ser enviadas.
+	// Se a consulta de tokens falhou, o claim expira e a notificação volta para a fila.
+	tokens := batch.tokens[userID]
+	if len(tokens) == 0 {
+		if batch.tokens != nil {
+			s.failLogs(ctx, logs, "no_devices")
+		}
 		return
 	}
 
-	for _, l := range pending {
-		s.dispatchOne(ctx, l)
+	// 4. Bundling: junta o que vence dentro da janela do usuário em um único push
+	if hasPrefs && prefs.BundleWindowMins > 0 {
+		window := time.Duration(prefs.BundleWindowMins) * time.Minute
+		upcoming, err := s.Log.ClaimPendingForUser(ctx, userID, batch.now.Add(window), batch.lease, s.instanceID)
+		if err != nil {
+			s.Logger.Warn("scheduler_bundle_claim_error", slog.String("error", err.Error()), slog.String("user_id", userID))
+		}
+		logs = mergeNotificationLogs(logs, upcoming)
+		if len(logs) > 1 {
+			s.sendBundle(ctx, logs, tokens, user.Locale, prefs.BundleWindowMins)
+			return
+		}
 	}
-}
 
-func (s *NotificationScheduler) dispatchOne(ctx context.Context, l domain.NotificationLog) {
-	// 1. Busca tópicos do usuário
-	tokens, err := s.Tokens.ListByUserID(ctx, l.UserID)
-	if err != nil || len(tokens) == 0 {
-		return
+	// 5. Envio individual
+	for _, l := range logs {
+		s.sendOne(ctx, l, tokens)
 	}
+}
 
-	// 2. Verifica quiet hours
-	prefs, err := s.Prefs.GetByUserID(ctx, l.UserID)
-	if err == nil && prefs.QuietHoursEnabled && prefs.QuietStart != nil && prefs.QuietEnd != nil {
-		user, err := s.Users.Get(ctx, l.UserID)
-		if err == nil {
-			loc, err := time.LoadLocation(user.Timezone)
-			if err == nil {
-				nowUser := time.Now().In(loc)
-				if s.isInsideQuietHours(nowUser, *prefs.QuietStart, *prefs.QuietEnd) {
-					s.postpone(ctx, l, *prefs.QuietEnd, loc)
-					return
-				}
+// mergeNotificationLogs junta as listas sem repetir ids, mantendo a ordem por scheduled_for.
+func mergeNotificationLogs(claimed, extra []domain.NotificationLog) []domain.NotificationLog {
+	seen := make(map[string]struct{}, len(claimed)+len(extra))
+	merged := make([]domain.NotificationLog, 0, len(claimed)+len(extra))
+	for _, list := range [][]domain.NotificationLog{claimed, extra} {
+		for _, l := range list {
+			if _, ok := seen[l.ID]; ok {
+				continue
 			}
+			seen[l.ID] = struct{}{}
+			merged = append(merged, l)
 		}
 	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].ScheduledFor.Before(merged[j].ScheduledFor)
+	})
+	return merged
+}
 
-	// 3. Envia via ntfy.sh
+func (s *NotificationScheduler) sendOne(ctx context.Context, l domain.NotificationLog, tokens []domain.DeviceToken) {
 	data := map[string]string{
 		"type":                string(l.Type),
 		"reference_id":        l.ReferenceID,
@@ -560,10 +886,75 @@ func (s *NotificationScheduler) dispatchOne(ctx context.Context, l domain.Notifi
 		data["lead_mins"] = strconv.Itoa(*l.LeadMins)
 	}
 
+	if s.sendToDevices(ctx, tokens, l.Title, l.Body, data) {
+		if err := s.Log.UpdateStatus(ctx, l.ID, domain.NotificationStatusSent, nil); err != nil {
+			s.Logger.Error("update_status_sent_error", slog.String("error", err.Error()))
+		}
+		s.updateStats(func(st *SchedulerStats) { st.SentTotal++ })
+		return
+	}
+
+	msg := "failed to send to all devices via ntfy"
+	if err := s.Log.UpdateStatus(ctx, l.ID, domain.NotificationStatusFailed, &msg); err != nil {
+		s.Logger.Error("update_status_failed_error", slog.String("error", err.Error()))
+	}
+	s.updateStats(func(st *SchedulerStats) { st.FailedTotal++ })
+}
+
+// failLogs marca os logs como falha com a mesma mensagem.
+func (s *NotificationScheduler) failLogs(ctx context.Context, logs []domain.NotificationLog, msg string) {
+	for _, l := range logs {
+		if err := s.Log.UpdateStatus(ctx, l.ID, domain.NotificationStatusFailed, &msg); err != nil {
+			s.Logger.Error("update_status_failed_error", slog.String("error", err.Error()))
+		}
+	}
+	s.updateStats(func(st *SchedulerStats) { st.FailedTotal += int64(len(logs)) })
+}
+
+func (s *NotificationScheduler) sendBundle(ctx context.Context, logs []domain.NotificationLog, tokens []domain.DeviceToken, locale string, windowMins int) {
+	ids := make([]string, len(logs))
+	lines := make([]string, len(logs))
+	for i, l := range logs {
+		ids[i] = l.ID
+		lines[i] = l.Body
+		if strings.TrimSpace(lines[i]) == "" {
+			lines[i] = l.Title
+		}
+	}
+	title, body := service.BundleNotificationMessage(locale, windowMins, lines)
+
+	data := map[string]string{
+		"type":                 "bundle",
+		"notification_log_ids": strings.Join(ids, ","),
+		"click_url":            "/notifications",
+	}
+
+	if !s.sendToDevices(ctx, tokens, title, body, data) {
+		s.failLogs(ctx, logs, "failed to send bundle to all devices via ntfy")
+		return
+	}
+
+	bundleID, err := s.Log.MarkBundleSent(ctx, ids)
+	if err != nil {
+		s.Logger.Error("update_bundle_sent_error", slog.String("error", err.Error()))
+		return
+	}
+	s.updateStats(func(st *SchedulerStats) { st.SentTotal += int64(len(logs)) })
+	s.Logger.Info("notification_bundle_sent", slog.String("bundle_id", bundleID), slog.Int("count", len(logs)))
+}
+
+// sendToDevices envia para todos os tópicos e retorna true se ao menos um recebeu.
+// Dispositivos do mesmo usuário compartilham o tópico, então cada tópico recebe uma vez.
+func (s *NotificationScheduler) sendToDevices(ctx context.Context, tokens []domain.DeviceToken, title, body string, data map[string]string) bool {
 	success := false
+	sent := make(map[string]struct{}, len(tokens))
 	for _, t := range tokens {
+		if _, ok := sent[t.Topic]; ok {
+			continue
+		}
+		sent[t.Topic] = struct{}{}
 		if s.Ntfy != nil {
-			err := s.Ntfy.Send(ctx, t.Topic, l.Title, l.Body, data)
+			err := s.Ntfy.Send(ctx, push.NewDestination(t.Topic, t.EncryptionKey), title, body, data)
 			if err == nil {
 				success = true
 			} else {
@@ -571,17 +962,7 @@ func (s *NotificationScheduler) dispatchOne(ctx context.Context, l domain.Notifi
 			}
 		}
 	}
-
-	if success {
-		if err := s.Log.UpdateStatus(ctx, l.ID, domain.NotificationStatusSent, nil); err != nil {
-			s.Logger.Error("update_status_sent_error", slog.String("error", err.Error()))
-		}
-	} else {
-		msg := "failed to send to all devices via ntfy"
-		if err := s.Log.UpdateStatus(ctx, l.ID, domain.NotificationStatusFailed, &msg); err != nil {
-			s.Logger.Error("update_status_failed_error", slog.String("error", err.Error()))
-		}
-	}
+	return success
 }
 
 func (s *NotificationScheduler) generateClickURL(l domain.NotificationLog) string {
@@ -594,6 +975,10 @@ func (s *NotificationScheduler) generateClickURL(l domain.NotificationLog) strin
 		return "/home?id=" + l.ReferenceID
 	case domain.NotificationTypeRoutine:
 		return "/schedule"
+	case domain.NotificationTypeBriefing:
+		return "/home"
+	case domain.NotificationTypeReview:
+		return "/review"
 	default:
 		return "/"
 	}
@@ -638,5 +1023,6 @@ func (s *NotificationScheduler) postpone(ctx context.Context, l domain.Notificat
 		s.Logger.Error("postpone_update_error", slog.String("error", err.Error()))
 		return
 	}
+	s.updateStats(func(st *SchedulerStats) { st.PostponedTotal++ })
 	s.Logger.Info("notification_postponed", slog.String("id", l.ID), slog.Time("new_scheduled_for", scheduledFor))
 }
@@ -0,0 +1,380 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/repository"
+	"inbota/backend/internal/infra/push"
+)
+
+type dispatchLogRepoStub struct {
+	repository.NotificationLogRepository
+	pending   []domain.NotificationLog
+	upcoming  []domain.NotificationLog
+	statuses  map[string]domain.NotificationStatus
+	errors    map[string]string
+	bundles   [][]string
+	postponed map[string]time.Time
+}
+
+func (s *dispatchLogRepoStub) ClaimPending(_ context.Context, _ time.Time, limit int, _ time.Duration, _ string) ([]domain.NotificationLog, error) {
+	if len(s.pending) > limit {
+		return s.pending[:limit], nil
+	}
+	return s.pending, nil
+}
+
+func (s *dispatchLogRepoStub) ClaimPendingForUser(_ context.Context, userID string, _ time.Time, _ time.Duration, _ string) ([]domain.NotificationLog, error) {
+	var out []domain.NotificationLog
+	for _, l := range s.upcoming {
+		if l.UserID == userID {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (s *dispatchLogRepoStub) UpdateStatus(_ context.Context, id string, status domain.NotificationStatus, errorMsg *string) error {
+	if s.statuses == nil {
+		s.statuses = make(map[string]domain.NotificationStatus)
+		s.errors = make(map[string]string)
+	}
+	s.statuses[id] = status
+	if errorMsg != nil {
+		s.errors[id] = *errorMsg
+	}
+	return nil
+}
+
+func (s *dispatchLogRepoStub) UpdateScheduledFor(_ context.Context, id string, scheduledFor time.Time) error {
+	if s.postponed == nil {
+		s.postponed = make(map[string]time.Time)
+	}
+	s.postponed[id] = scheduledFor
+	return nil
+}
+
+func (s *dispatchLogRepoStub) MarkBundleSent(_ context.Context, ids []string) (string, error) {
+	s.bundles = append(s.bundles, ids)
+	return "bundle-1", nil
+}
+
+type dispatchTokenRepoStub struct {
+	repository.DeviceTokenRepository
+	tokens []domain.DeviceToken
+	err    error
+}
+
+func (s *dispatchTokenRepoStub) ListByUserIDs(_ context.Context, _ []string) ([]domain.DeviceToken, error) {
+	return s.tokens, s.err
+}
+
+type dispatchPrefsRepoStub struct {
+	repository.NotificationPreferencesRepository
+	prefs []domain.NotificationPreferences
+}
+
+func (s *dispatchPrefsRepoStub) ListByUserIDs(_ context.Context, _ []string) ([]domain.NotificationPreferences, error) {
+	return s.prefs, nil
+}
+
+type dispatchUserRepoStub struct {
+	repository.UserRepository
+	users []domain.User
+}
+
+func (s *dispatchUserRepoStub) ListByIDs(_ context.Context, _ []string) ([]domain.User, error) {
+	return s.users, nil
+}
+
+func newDispatchScheduler(logs *dispatchLogRepoStub, tokens *dispatchTokenRepoStub, prefs ...domain.NotificationPreferences) *NotificationScheduler {
+	return &NotificationScheduler{
+		Log:    logs,
+		Tokens: tokens,
+		Prefs:  &dispatchPrefsRepoStub{prefs: prefs},
+		Users:  &dispatchUserRepoStub{users: []domain.User{{ID: "u1", Timezone: "UTC"}, {ID: "u2", Timezone: "UTC"}}},
+		Logger: slog.Default(),
+	}
+}
+
+func TestDispatchFailsPushesOfUsersWithoutDevices(t *testing.T) {
+	due := time.Now().Add(-time.Minute)
+	logs := &dispatchLogRepoStub{pending: []domain.NotificationLog{
+		{ID: "l1", UserID: "u1", Title: "Pagar boleto", ScheduledFor: due},
+		{ID: "l2", UserID: "u1", Title: "Treino", ScheduledFor: due},
+	}}
+	s := newDispatchScheduler(logs, &dispatchTokenRepoStub{})
+
+	s.dispatch(context.Background())
+	for _, id := range []string{"l1", "l2"} {
+		if logs.statuses[id] != domain.NotificationStatusFailed || logs.errors[id] != "no_devices" {
+			t.Fatalf("expected %s to fail with no_devices, got %q (%q)", id, logs.statuses[id], logs.errors[id])
+		}
+	}
+}
+
+func TestDispatchKeepsClaimWhenTokensCannotBeLoaded(t *testing.T) {
+	logs := &dispatchLogRepoStub{pending: []domain.NotificationLog{
+		{ID: "l1", UserID: "u1", Title: "Pagar boleto", ScheduledFor: time.Now().Add(-time.Minute)},
+	}}
+	s := newDispatchScheduler(logs, &dispatchTokenRepoStub{err: context.DeadlineExceeded})
+
+	s.dispatch(context.Background())
+	if len(logs.statuses) != 0 {
+		t.Fatalf("expected the row to stay claimed for a retry, got %v", logs.statuses)
+	}
+}
+
+func TestDispatchPostponesEscalationsInsideQuietHours(t *testing.T) {
+	now := time.Now().UTC()
+	quietStart, quietEnd := now.Add(-time.Hour).Format("15:04"), now.Add(time.Hour).Format("15:04")
+	repeat := -10
+	logs := &dispatchLogRepoStub{pending: []domain.NotificationLog{
+		{ID: "push", UserID: "u1", Title: "Pagar boleto", LeadMins: &repeat, ScheduledFor: now.Add(-time.Minute)},
+		{ID: "email", UserID: "u1", Title: "Pagar boleto", LeadMins: &repeat, Channel: domain.NotificationChannelEmail, ScheduledFor: now.Add(-time.Minute)},
+	}}
+	s := newDispatchScheduler(logs, &dispatchTokenRepoStub{tokens: []domain.DeviceToken{{UserID: "u1", Topic: "topic-a"}}},
+		domain.NotificationPreferences{UserID: "u1", QuietHoursEnabled: true, QuietStart: &quietStart, QuietEnd: &quietEnd})
+
+	s.dispatch(context.Background())
+	if len(logs.statuses) != 0 {
+		t.Fatalf("expected nothing sent or failed during quiet hours, got %v", logs.statuses)
+	}
+	for _, id := range []string{"push", "email"} {
+		got, ok := logs.postponed[id]
+		if !ok || got.Format("15:04") != quietEnd {
+			t.Fatalf("expected %s postponed to %s, got %v", id, quietEnd, got)
+		}
+	}
+}
+
+type ntfyRequest struct {
+	topic, title, tags, click, body string
+}
+
+// newNtfyRecorder sobe um ntfy falso que registra cada push e responde com status.
+func newNtfyRecorder(t *testing.T, status int) (*push.NtfyClient, func() []ntfyRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []ntfyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, ntfyRequest{
+			topic: strings.TrimPrefix(r.URL.Path, "/"),
+			title: r.Header.Get("Title"),
+			tags:  r.Header.Get("Tags"),
+			click: r.Header.Get("Click"),
+			body:  string(raw),
+		})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return push.NewNtfyClientWithConfig(push.NtfyConfig{BaseURL: server.URL}), func() []ntfyRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]ntfyRequest(nil), requests...)
+	}
+}
+
+func TestMergeNotificationLogs(t *testing.T) {
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	claimed := []domain.NotificationLog{
+		{ID: "l2", ScheduledFor: base.Add(5 * time.Minute)},
+		{ID: "l1", ScheduledFor: base},
+	}
+	extra := []domain.NotificationLog{
+		{ID: "l1", ScheduledFor: base},
+		{ID: "l4", ScheduledFor: base.Add(5 * time.Minute)},
+		{ID: "l3", ScheduledFor: base.Add(2 * time.Minute)},
+	}
+
+	merged := mergeNotificationLogs(claimed, extra)
+	var ids []string
+	for _, l := range merged {
+		ids = append(ids, l.ID)
+	}
+	// l2 e l4 vencem juntos: a ordem estável mantém o reivindicado primeiro
+	if want := []string{"l1", "l3", "l2", "l4"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("merged ids = %v, want %v", ids, want)
+	}
+	if got := mergeNotificationLogs(claimed, nil); len(got) != 2 || got[0].ID != "l1" {
+		t.Fatalf("expected claimed logs sorted without extras, got %v", got)
+	}
+}
+
+func TestDispatchBundlesLogsInsideTheWindow(t *testing.T) {
+	due := time.Now().Add(-time.Minute)
+	logs := &dispatchLogRepoStub{
+		pending: []domain.NotificationLog{
+			{ID: "l1", UserID: "u1", Type: domain.NotificationTypeReminder, Title: "Lembrete", Body: "Pagar boleto", ScheduledFor: due},
+		},
+		upcoming: []domain.NotificationLog{
+			{ID: "l1", UserID: "u1", Type: domain.NotificationTypeReminder, Title: "Lembrete", Body: "Pagar boleto", ScheduledFor: due},
+			{ID: "l2", UserID: "u1", Type: domain.NotificationTypeTask, Title: "Treino", ScheduledFor: due.Add(5 * time.Minute)},
+		},
+	}
+	ntfy, requests := newNtfyRecorder(t, http.StatusOK)
+	s := newDispatchScheduler(logs, &dispatchTokenRepoStub{tokens: []domain.DeviceToken{
+		{UserID: "u1", Topic: "topic-a"},
+		{UserID: "u1", Topic: "topic-a"},
+	}}, domain.NotificationPreferences{UserID: "u1", BundleWindowMins: 15})
+	s.Ntfy = ntfy
+
+	s.dispatch(context.Background())
+
+	sent := requests()
+	if len(sent) != 1 {
+		t.Fatalf("expected one bundled push per topic, got %d: %+v", len(sent), sent)
+	}
+	if sent[0].topic != "topic-a" || sent[0].tags != "bundle" || sent[0].click != "/notifications" {
+		t.Fatalf("unexpected bundle push: %+v", sent[0])
+	}
+	if sent[0].body != "Pagar boleto\nTreino" {
+		t.Fatalf("bundle body = %q, want the bodies (or titles) in scheduled order", sent[0].body)
+	}
+	if len(logs.bundles) != 1 || !reflect.DeepEqual(logs.bundles[0], []string{"l1", "l2"}) {
+		t.Fatalf("expected MarkBundleSent(l1, l2) once, got %v", logs.bundles)
+	}
+	if len(logs.statuses) != 0 {
+		t.Fatalf("expected bundled logs to be marked only through MarkBundleSent, got %v", logs.statuses)
+	}
+}
+
+func TestDispatchFailsBundleWhenNoDeviceReceivesIt(t *testing.T) {
+	due := time.Now().Add(-time.Minute)
+	logs := &dispatchLogRepoStub{
+		pending: []domain.NotificationLog{{ID: "l1", UserID: "u1", Title: "Pagar boleto", ScheduledFor: due}},
+		upcoming: []domain.NotificationLog{
+			{ID: "l2", UserID: "u1", Title: "Treino", ScheduledFor: due.Add(time.Minute)},
+		},
+	}
+	ntfy, _ := newNtfyRecorder(t, http.StatusInternalServerError)
+	s := newDispatchScheduler(logs, &dispatchTokenRepoStub{tokens: []domain.DeviceToken{{UserID: "u1", Topic: "topic-a"}}},
+		domain.NotificationPreferences{UserID: "u1", BundleWindowMins: 15})
+	s.Ntfy = ntfy
+
+	s.dispatch(context.Background())
+	if len(logs.bundles) != 0 {
+		t.Fatalf("expected no bundle to be marked, got %v", logs.bundles)
+	}
+	for _, id := range []string{"l1", "l2"} {
+		if logs.statuses[id] != domain.NotificationStatusFailed {
+			t.Fatalf("expected %s to fail, got %q", id, logs.statuses[id])
+		}
+	}
+}
+
+func TestDispatchSendsSingleLogIndividually(t *testing.T) {
+	due := time.Now().Add(-time.Minute)
+	lead := 30
+	cases := []struct {
+		name  string
+		prefs []domain.NotificationPreferences
+	}{
+		{name: "bundling disabled"},
+		{name: "alone in the bundle window", prefs: []domain.NotificationPreferences{{UserID: "u1", BundleWindowMins: 15}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := &dispatchLogRepoStub{pending: []domain.NotificationLog{
+				{ID: "l1", UserID: "u1", Type: domain.NotificationTypeReminder, ReferenceID: "r1", Title: "Lembrete", Body: "Pagar boleto", LeadMins: &lead, ScheduledFor: due},
+			}}
+			ntfy, requests := newNtfyRecorder(t, http.StatusOK)
+			s := newDispatchScheduler(logs, &dispatchTokenRepoStub{tokens: []domain.DeviceToken{{UserID: "u1", Topic: "topic-a"}}}, tc.prefs...)
+			s.Ntfy = ntfy
+
+			s.dispatch(context.Background())
+
+			sent := requests()
+			if len(sent) != 1 {
+				t.Fatalf("expected one push, got %d", len(sent))
+			}
+			if sent[0].title != "Lembrete" || sent[0].body != "Pagar boleto" || sent[0].tags != "reminder" || sent[0].click != "/reminders?id=r1" {
+				t.Fatalf("unexpected push: %+v", sent[0])
+			}
+			if logs.statuses["l1"] != domain.NotificationStatusSent {
+				t.Fatalf("expected l1 sent, got %q", logs.statuses["l1"])
+			}
+			if len(logs.bundles) != 0 {
+				t.Fatalf("expected no bundle, got %v", logs.bundles)
+			}
+		})
+	}
+}
+
+type flagBatchRepoStub struct {
+	repository.FlagRepository
+	calls int
+	flags []domain.Flag
+}
+
+func (s *flagBatchRepoStub) GetByIDsForUsers(_ context.Context, idsByUser map[string][]string) ([]domain.Flag, error) {
+	s.calls++
+	var out []domain.Flag
+	for _, f := range s.flags {
+		for _, id := range idsByUser[f.UserID] {
+			if id == f.ID {
+				out = append(out, f)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestLoadFlagNamesQueriesOncePerTick(t *testing.T) {
+	flags := &flagBatchRepoStub{flags: []domain.Flag{
+		{ID: "f1", UserID: "u1", Name: "Casa"},
+		{ID: "f2", UserID: "u2", Name: "Trabalho"},
+		{ID: "f3", UserID: "u3", Name: "Outro usuário"},
+	}}
+	s := &NotificationScheduler{Flags: flags, Logger: slog.Default()}
+
+	names := s.loadFlagNames(context.Background(), map[string][]string{
+		"u1": {"f1"},
+		"u2": {"f2", "f3"},
+	})
+	if flags.calls != 1 {
+		t.Fatalf("expected one flag query for all users, got %d", flags.calls)
+	}
+	if len(names) != 2 || names["f1"] != "Casa" || names["f2"] != "Trabalho" {
+		t.Fatalf("unexpected flag names: %v", names)
+	}
+}
+
+func TestResolveLeadTimes(t *testing.T) {
+	cases := []struct {
+		name        string
+		override    domain.NotificationOverride
+		wantAtTime  bool
+		wantLeads   []int
+		wantEnabled bool
+	}{
+		{name: "defaults", wantAtTime: true, wantLeads: []int{15, 60}, wantEnabled: true},
+		{name: "disabled", override: domain.NotificationOverride{Disabled: true}},
+		{name: "zero means at time", override: domain.NotificationOverride{LeadMins: []int{0, 1440}}, wantAtTime: true, wantLeads: []int{1440}, wantEnabled: true},
+		{name: "leads only", override: domain.NotificationOverride{LeadMins: []int{30}}, wantLeads: []int{30}, wantEnabled: true},
+		{name: "empty override silences defaults", override: domain.NotificationOverride{LeadMins: []int{}}, wantLeads: []int{}, wantEnabled: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			atTime, leads, enabled := resolveLeadTimes(true, []int{15, 60}, tc.override)
+			if atTime != tc.wantAtTime || enabled != tc.wantEnabled || !reflect.DeepEqual(leads, tc.wantLeads) {
+				t.Fatalf("resolveLeadTimes = (%v, %v, %v), want (%v, %v, %v)", atTime, leads, enabled, tc.wantAtTime, tc.wantLeads, tc.wantEnabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"inbota/backend/internal/app/domain"
+	"inbota/backend/internal/app/recurrence"
+)
+
+// scheduleUnblocked avisa que uma tarefa pode começar quando a última bloqueadora é concluída.
+// Só considera bloqueadoras concluídas dentro da janela, para não avisar de desbloqueios antigos.
+// O aviso usa lead_mins = domain.TaskUnblockedLeadMins; quando a tarefa volta a ser bloqueada, o
+// TaskUsecase libera essa chave (NotificationLogRepository.Release) para que o próximo desbloqueio
+// gere um novo aviso.
+func (s *NotificationScheduler) scheduleUnblocked(ctx context.Context, now time.Time) {
+	window := time.Duration(s.configInt("scheduler.task_unblocked_window_mins", 60)) * time.Minute
+	tasks, err := s.Tasks.ListUnblocked(ctx, now.Add(-window))
+	if err != nil {
+		s.Logger.Error("scheduler_list_unblocked_tasks_error", slog.String("error", err.Error()))
+		return
+	}
+	if len(tasks) == 0 {
+		return
+	}
+
+	userIDs := newUserIDSet()
+	flagIDsByUser := make(map[string][]string)
+	for _, t := range tasks {
+		userIDs.add(t.UserID)
+		if t.FlagID != nil && *t.FlagID != "" {
+			flagIDsByUser[t.UserID] = append(flagIDsByUser[t.UserID], *t.FlagID)
+		}
+	}
+	prefsByUser := s.loadPreferences(ctx, userIDs.list())
+	usersByID := s.loadUsers(ctx, userIDs.list())
+	flagNames := s.loadFlagNames(ctx, flagIDsByUser)
+	awayByUser := s.loadAwayPeriods(ctx, userIDs.list(), now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	locCache := make(map[string]*time.Location)
+	scheduledFor := now.UTC().Truncate(time.Minute)
+
+	for _, t := range tasks {
+		if t.Notification.Disabled {
+			continue
+		}
+		prefs, ok := prefsByUser[t.UserID]
+		if !ok || !prefs.TasksEnabled {
+			continue
+		}
+
+		user := usersByID[t.UserID]
+		loc := timezoneLocation(user.Timezone, locCache)
+		if recurrence.IsAway(awayByUser[t.UserID], t.FlagID, now.In(loc).Format("2006-01-02")) {
+			continue
+		}
+
+		leadMins := domain.TaskUnblockedLeadMins
+		vars := templateVars(t.Title, t.DueAt, loc, flagName(flagNames, t.FlagID))
+		title, body := s.buildMessage(domain.NotificationTypeTask, "unblocked", user.Locale, vars)
+		s.scheduleItem(ctx, t.UserID, domain.NotificationTypeTask, t.ID, title, body, &scheduledFor, &leadMins)
+	}
+}
@@ -0,0 +1,59 @@
+-- Post-migration for v0.3.0
+
+-- Seed: configurações do scheduler em lote (v0.3.0)
+INSERT INTO inbota.app_config (key, value, description) VALUES
+    ('scheduler.dispatch_batch_size', '200',
+        'Máximo de notificações pendentes reivindicadas por tick'),
+    ('scheduler.claim_lease_seconds', '300',
+        'Tempo (s) até uma notificação reivindicada e não enviada voltar a ficar disponível')
+ON CONFLICT (key) DO NOTHING;
+
+-- Templates de notificação passam a usar text/template.
+-- Variáveis: {{.Title}}, {{.LeadMins}}, {{.LeadLabel}}, {{.Location}}, {{.FlagName}}, {{.Time}}
+UPDATE inbota.notification_templates
+   SET title_template = replace(replace(title_template, '{{title}}', '{{.Title}}'), '{{lead_mins}}', '{{.LeadMins}}'),
+       body_template  = replace(replace(body_template,  '{{title}}', '{{.Title}}'), '{{lead_mins}}', '{{.LeadMins}}'),
+       updated_at     = now()
+ WHERE title_template LIKE '%{{title}}%' OR title_template LIKE '%{{lead_mins}}%'
+    OR body_template  LIKE '%{{title}}%' OR body_template  LIKE '%{{lead_mins}}%';
+
+-- Seed: templates em inglês
+INSERT INTO inbota.notification_templates (type, trigger_key, locale, title_template, body_template) VALUES
+    ('reminder', 'at_time',       'en', 'Reminder now', '{{.Title}}'),
+    ('reminder', 'lead_time',     'en', 'Reminder in {{.LeadLabel}}', '{{.Title}}'),
+    ('event',    'at_time',       'en', 'Event starting', '{{.Title}} starts now.'),
+    ('event',    'lead_time',     'en', 'Event in {{.LeadLabel}}', '{{.Title}} starts in {{.LeadLabel}}.'),
+    ('event',    'lead_time_day', 'en', 'Event tomorrow', '{{.Title}} starts tomorrow at {{.Time}}.'),
+    ('task',     'at_time',       'en', 'Due now', '{{.Title}} is due now.'),
+    ('task',     'lead_time',     'en', 'Due in {{.LeadLabel}}', '{{.Title}} is due in {{.LeadLabel}}.'),
+    ('task',     'lead_time_day', 'en', 'Due tomorrow', '{{.Title}} is due tomorrow.'),
+    ('routine',  'at_time',       'en', 'Routine time', '{{.Title}} starts now.'),
+    ('routine',  'lead_time',     'en', 'Routine in {{.LeadLabel}}', '{{.Title}} starts in {{.LeadLabel}}.')
+ON CONFLICT (type, trigger_key, locale) DO NOTHING;
+
+INSERT INTO inbota.app_config (key, value, description) VALUES
+    ('scheduler.cache_refresh_seconds', '300',
+        'Intervalo (s) para o scheduler recarregar templates e configs do banco')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO inbota.app_config (key, value, description) VALUES
+    ('scheduler.briefing_grace_mins', '60',
+        'Tolerância (min) após o horário do briefing/fechamento para ainda enviá-lo (ex: após restart)')
+ON CONFLICT (key) DO NOTHING;
+
+-- Seed: templates das repetições de lembretes escalonados
+INSERT INTO inbota.notification_templates (type, trigger_key, locale, title_template, body_template) VALUES
+    ('reminder', 'escalation', 'pt-BR', 'Lembrete pendente há {{.LeadLabel}}', '{{.Title}}'),
+    ('reminder', 'escalation', 'en', 'Reminder pending for {{.LeadLabel}}', '{{.Title}}')
+ON CONFLICT (type, trigger_key, locale) DO NOTHING;
+
+INSERT INTO inbota.app_config (key, value, description) VALUES
+    ('scheduler.task_unblocked_window_mins', '60',
+        'Janela (min) após a conclusão da última bloqueadora em que a tarefa dependente ainda é avisada')
+ON CONFLICT (key) DO NOTHING;
+
+-- Seed: templates do aviso de tarefa desbloqueada
+INSERT INTO inbota.notification_templates (type, trigger_key, locale, title_template, body_template) VALUES
+    ('task', 'unblocked', 'pt-BR', 'Tarefa liberada', '{{.Title}} já pode começar.'),
+    ('task', 'unblocked', 'en', 'Task unblocked', '{{.Title}} is ready to start.')
+ON CONFLICT (type, trigger_key, locale) DO NOTHING;
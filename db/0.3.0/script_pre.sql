@@ -0,0 +1,265 @@
+-- Pre-migration for v0.3.0
+
+-- Per-item notification overrides (tasks, reminders, events, routines).
+-- notify_lead_mins NULL = use notification_preferences; 0 inside the array = at time.
+ALTER TABLE inbota.tasks
+  ADD COLUMN IF NOT EXISTS notify_lead_mins int[],
+  ADD COLUMN IF NOT EXISTS notify_disabled boolean NOT NULL DEFAULT false;
+
+ALTER TABLE inbota.reminders
+  ADD COLUMN IF NOT EXISTS notify_lead_mins int[],
+  ADD COLUMN IF NOT EXISTS notify_disabled boolean NOT NULL DEFAULT false;
+
+ALTER TABLE inbota.events
+  ADD COLUMN IF NOT EXISTS notify_lead_mins int[],
+  ADD COLUMN IF NOT EXISTS notify_disabled boolean NOT NULL DEFAULT false;
+
+ALTER TABLE inbota.routines
+  ADD COLUMN IF NOT EXISTS notify_lead_mins int[],
+  ADD COLUMN IF NOT EXISTS notify_disabled boolean NOT NULL DEFAULT false;
+
+-- Notification scheduler: claim/lease of pending rows (safe with more than one API instance).
+ALTER TABLE inbota.notification_log
+  ADD COLUMN IF NOT EXISTS claimed_at timestamptz,
+  ADD COLUMN IF NOT EXISTS claimed_by text;
+
+-- Notification templates: dimensão de locale (unicidade passa a ser type + trigger_key + locale).
+ALTER TABLE inbota.notification_templates
+  ADD COLUMN IF NOT EXISTS locale text NOT NULL DEFAULT 'pt-BR';
+
+DROP INDEX IF EXISTS inbota.idx_notification_templates_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_templates_unique
+    ON inbota.notification_templates(type, trigger_key, locale);
+
+-- Notification bundling: janela por usuário e vínculo dos logs enviados juntos.
+ALTER TABLE inbota.notification_preferences
+  ADD COLUMN IF NOT EXISTS bundle_window_mins int NOT NULL DEFAULT 0;
+
+ALTER TABLE inbota.notification_log
+  ADD COLUMN IF NOT EXISTS bundle_id uuid;
+
+CREATE INDEX IF NOT EXISTS idx_notification_log_bundle
+    ON inbota.notification_log(bundle_id)
+    WHERE bundle_id IS NOT NULL;
+
+-- Briefing da manhã e fechamento da noite (push montado a partir do digest diário).
+-- ALTER TYPE ... ADD VALUE não pode rodar dentro de transação em versões antigas do Postgres.
+ALTER TYPE inbota.notification_type ADD VALUE IF NOT EXISTS 'briefing';
+ALTER TYPE inbota.notification_type ADD VALUE IF NOT EXISTS 'review';
+
+ALTER TABLE inbota.notification_preferences
+  ADD COLUMN IF NOT EXISTS morning_briefing_enabled boolean NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS morning_briefing_time time NOT NULL DEFAULT '07:30',
+  ADD COLUMN IF NOT EXISTS evening_review_enabled boolean NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS evening_review_time time NOT NULL DEFAULT '21:00';
+
+-- Lembretes em modo "nag": repete a cada N minutos até DONE ou notificação lida.
+-- As repetições ficam em notification_log com lead_mins negativo (-minutos após o horário).
+ALTER TABLE inbota.reminders
+  ADD COLUMN IF NOT EXISTS nag_interval_mins int NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS nag_max_repeats int NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS nag_email_from_repeat int NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_reminders_nag
+    ON inbota.reminders(remind_at)
+    WHERE status = 'OPEN' AND nag_interval_mins > 0;
+
+ALTER TABLE inbota.notification_log
+  ADD COLUMN IF NOT EXISTS channel text NOT NULL DEFAULT 'push';
+
+-- Push: tópico ntfy aleatório por usuário (gerado pelo servidor) e chave para cifrar mensagens.
+CREATE TABLE IF NOT EXISTS inbota.push_topics (
+    user_id         UUID PRIMARY KEY REFERENCES inbota.users(id) ON DELETE CASCADE,
+    topic           TEXT NOT NULL UNIQUE,
+    encryption_key  TEXT NOT NULL,   -- base64, AES-256
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    rotated_at      TIMESTAMPTZ
+);
+
+-- Os dispositivos do usuário passam a compartilhar o mesmo tópico.
+DROP INDEX IF EXISTS inbota.idx_device_tokens_topic;
+CREATE INDEX IF NOT EXISTS idx_device_tokens_topic ON inbota.device_tokens(ntfy_topic);
+
+-- Backfill: usuários com dispositivos ainda no tópico antigo (sha256 do deviceID) ganham tópico
+-- e chave aleatórios, no mesmo formato de push.NewTopicCredentials, e os dispositivos passam para
+-- ele. O app recebe o novo tópico no próximo POST /v1/devices/token e se reinscreve.
+INSERT INTO inbota.push_topics (user_id, topic, encryption_key)
+SELECT u.user_id,
+       'inbota_' || translate(encode(gen_random_bytes(24), 'base64'), '+/', '-_'),
+       encode(gen_random_bytes(32), 'base64')
+FROM (SELECT DISTINCT user_id FROM inbota.device_tokens) u
+ON CONFLICT (user_id) DO NOTHING;
+
+UPDATE inbota.device_tokens dt
+SET ntfy_topic = pt.topic
+FROM inbota.push_topics pt
+WHERE pt.user_id = dt.user_id AND dt.ntfy_topic <> pt.topic;
+
+-- Digest semanal: revisão da semana + próxima semana, enviado no dia/hora escolhidos (timezone do usuário).
+-- Os envios usam email_digests com type = 'weekly_digest'.
+ALTER TABLE inbota.notification_preferences
+  ADD COLUMN IF NOT EXISTS weekly_digest_enabled boolean NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS weekly_digest_weekday int NOT NULL DEFAULT 0,  -- 0 = domingo
+  ADD COLUMN IF NOT EXISTS weekly_digest_hour int NOT NULL DEFAULT 18;    -- 0-23
+
+-- Reenvio de digests com falha: tentativas limitadas com backoff.
+-- next_attempt_at só fica preenchido enquanto o digest ainda pode ser reenviado.
+ALTER TABLE inbota.email_digests
+  ADD COLUMN IF NOT EXISTS attempts int NOT NULL DEFAULT 1,
+  ADD COLUMN IF NOT EXISTS next_attempt_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_email_digests_retry
+    ON inbota.email_digests(next_attempt_at)
+    WHERE status = 'failed' AND next_attempt_at IS NOT NULL;
+
+-- Digest diário personalizável: seções (na ordem de exibição), limite por seção,
+-- filtro por flags e opção de não enviar em dias vazios. Sem linha = padrão.
+CREATE TABLE IF NOT EXISTS inbota.digest_settings (
+    user_id           UUID PRIMARY KEY REFERENCES inbota.users(id) ON DELETE CASCADE,
+    sections          TEXT[] NOT NULL DEFAULT ARRAY['schedule','agenda','reminders','tasks','openTasks','shoppingLists'],
+    max_items         INT NOT NULL DEFAULT 0,          -- 0 = sem limite
+    include_flag_ids  UUID[] NOT NULL DEFAULT '{}',
+    exclude_flag_ids  UUID[] NOT NULL DEFAULT '{}',
+    skip_empty        BOOLEAN NOT NULL DEFAULT false,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Status informado pelo webhook do provedor de e-mail (delivered, bounced, complained...).
+ALTER TABLE inbota.email_digests
+    ADD COLUMN IF NOT EXISTS provider_status    TEXT,
+    ADD COLUMN IF NOT EXISTS provider_status_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_email_digests_provider_id
+    ON inbota.email_digests(provider_id)
+    WHERE provider_id IS NOT NULL;
+
+-- Endereços que não recebem mais e-mail (hard bounce ou reclamação de spam).
+CREATE TABLE IF NOT EXISTS inbota.email_suppressions (
+    email        TEXT PRIMARY KEY,               -- sempre em minúsculas
+    reason       TEXT NOT NULL,                  -- 'hard_bounce', 'complaint'
+    provider_id  TEXT,
+    detail       TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Recorrência RRULE (RFC 5545) para rotinas: recurrence_type = 'rrule' guarda a regra aqui.
+-- weekdays continua preenchido com os dias em que a regra pode cair, para os filtros por dia.
+ALTER TABLE inbota.routines
+    ADD COLUMN IF NOT EXISTS rrule TEXT;
+
+-- Exceções de rotina criadas depois do agendamento cancelam os pushes pendentes daquele dia.
+-- 'cancelled' fica fora do índice único, então o scheduler pode reagendar no novo horário.
+ALTER TYPE inbota.notification_status ADD VALUE IF NOT EXISTS 'cancelled';
+
+-- Metas quantitativas de rotina (ex.: 8 copos, 30 min). Sem target_kind a rotina é binária.
+-- Cada check-in soma em routine_completions.value; o dia conta como feito quando value >= target_value.
+ALTER TABLE inbota.routines
+    ADD COLUMN IF NOT EXISTS target_kind  TEXT,            -- 'count', 'duration', 'amount'
+    ADD COLUMN IF NOT EXISTS target_value NUMERIC,
+    ADD COLUMN IF NOT EXISTS target_unit  TEXT;            -- 'min', 'copos', 'km'...
+
+ALTER TABLE inbota.routine_completions
+    ADD COLUMN IF NOT EXISTS value NUMERIC NOT NULL DEFAULT 1;
+
+-- Proteção de sequência das rotinas: faltas perdoadas por mês e um congelamento ganho a cada
+-- N dias seguidos feitos (0 = desligado). Exceções com action = 'rest' são folgas planejadas:
+-- tiram a rotina do dia como 'skip', mas não quebram a sequência.
+ALTER TABLE inbota.routines
+    ADD COLUMN IF NOT EXISTS streak_grace_per_month INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS streak_freeze_every    INT NOT NULL DEFAULT 0;
+
+-- Modo férias: períodos em que o usuário está fora (datas inclusivas, no timezone dele).
+-- As rotinas contam como puladas sem quebrar a sequência, os pushes de rotinas e tarefas não
+-- saem e o digest pausa. flag_id limita a pausa às rotinas e tarefas daquela flag.
+CREATE TABLE IF NOT EXISTS inbota.away_periods (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL REFERENCES inbota.users(id) ON DELETE CASCADE,
+    starts_on   DATE NOT NULL,
+    ends_on     DATE NOT NULL,
+    flag_id     UUID REFERENCES inbota.flags(id) ON DELETE CASCADE,
+    reason      TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CHECK (ends_on >= starts_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_away_periods_user_dates
+    ON inbota.away_periods(user_id, ends_on, starts_on);
+
+-- Calendário de feriados do usuário ('BR', 'BR-SP', 'BR-SP-SAO-PAULO'...). Os feriados ficam
+-- embutidos na API; rotinas com skip_holidays não acontecem nos feriados desse calendário.
+ALTER TABLE inbota.users
+    ADD COLUMN IF NOT EXISTS holiday_calendar TEXT NOT NULL DEFAULT 'BR';
+
+ALTER TABLE inbota.routines
+    ADD COLUMN IF NOT EXISTS skip_holidays BOOLEAN NOT NULL DEFAULT false;
+
+-- Checklist das tarefas: passos ordenados dentro de uma tarefa, no mesmo formato dos itens de compra.
+-- O progresso da tarefa é a contagem de itens marcados sobre o total.
+CREATE TABLE IF NOT EXISTS inbota.task_checklist_items (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL REFERENCES inbota.users(id) ON DELETE CASCADE,
+    task_id     UUID NOT NULL REFERENCES inbota.tasks(id) ON DELETE CASCADE,
+    title       TEXT NOT NULL,
+    checked     BOOLEAN NOT NULL DEFAULT false,
+    sort_order  INT NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_order
+    ON inbota.task_checklist_items(task_id, sort_order);
+
+-- Projetos: agrupam tarefas acima das flags. O progresso vem do status das tarefas do projeto.
+-- Excluir o projeto mantém as tarefas, só desvincula (project_id = NULL).
+CREATE TABLE IF NOT EXISTS inbota.projects (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID NOT NULL REFERENCES inbota.users(id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    description  TEXT,
+    status       TEXT NOT NULL DEFAULT 'OPEN',   -- 'OPEN', 'DONE', 'ARCHIVED'
+    target_date  DATE,
+    flag_id      UUID REFERENCES inbota.flags(id) ON DELETE SET NULL,
+    subflag_id   UUID REFERENCES inbota.subflags(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_user_status
+    ON inbota.projects(user_id, status);
+
+ALTER TABLE inbota.tasks
+    ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES inbota.projects(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_project
+    ON inbota.tasks(user_id, project_id)
+    WHERE project_id IS NOT NULL;
+
+-- Prioridade (matriz de Eisenhower) e esforço estimado das tarefas.
+-- P1 = urgente e importante, P2 = importante, P3 = urgente, P4 = nenhum dos dois. NULL = sem prioridade.
+ALTER TABLE inbota.tasks
+    ADD COLUMN IF NOT EXISTS priority TEXT,   -- 'P1', 'P2', 'P3', 'P4'
+    ADD COLUMN IF NOT EXISTS effort_mins INT;
+
+-- Tarefas recorrentes: ao concluir, o backend cria a próxima instância e a concluída fica como histórico.
+-- recurrence_rule = agenda fixa (RRULE); recurrence_after_days = N dias após a conclusão. Só um dos dois.
+-- series_id aponta para a primeira tarefa da série (sem FK: o histórico sobrevive se ela for excluída).
+ALTER TABLE inbota.tasks
+    ADD COLUMN IF NOT EXISTS recurrence_rule TEXT,
+    ADD COLUMN IF NOT EXISTS recurrence_after_days INT,
+    ADD COLUMN IF NOT EXISTS series_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_series
+    ON inbota.tasks(user_id, series_id)
+    WHERE series_id IS NOT NULL;
+
+-- Dependências entre tarefas: blocked_by lista as tarefas que precisam ser concluídas antes.
+-- Sem FK (array); ao excluir uma tarefa o backend remove o id das dependentes. Ciclos são barrados na API.
+-- A tarefa fica bloqueada enquanto alguma bloqueadora não estiver DONE (calculado na leitura).
+ALTER TABLE inbota.tasks
+    ADD COLUMN IF NOT EXISTS blocked_by UUID[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_blocked_by
+    ON inbota.tasks USING GIN (blocked_by)
+    WHERE cardinality(blocked_by) > 0;